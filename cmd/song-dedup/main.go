@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/app"
+	"github.com/qreaqtor/music-library/internal/config"
+	"github.com/qreaqtor/music-library/internal/domain"
+	postgres "github.com/qreaqtor/music-library/internal/storage/postgres"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+)
+
+// Reports songs of the same artist whose names differ only in case and spaces,
+// with -merge merges them into the oldest song. Uses the same config as the app.
+func main() {
+	merge := flag.Bool("merge", false, "merge found duplicates into the oldest song")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	err = cfg.Postgres.Validate()
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	conn, err := app.NewPostgresConn(cfg.Postgres)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer conn.Close()
+
+	st := postgres.NewSongsStorage(conn, cfg.Search.Language)
+
+	ctx := context.WithValue(context.Background(), logmsg.OperationID, uuid.New())
+	ctx = domain.WithAuthor(ctx, "song-dedup")
+
+	duplicates, err := st.Duplicates(ctx)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	for _, group := range duplicates {
+		merged := make([]string, 0, len(group.Merge))
+		for _, song := range group.Merge {
+			merged = append(merged, fmt.Sprintf("%q (%s)", song.SongName, song.ID))
+		}
+
+		fmt.Printf("%s - %q (%s): %s\n", group.Keep.Group, group.Keep.SongName, group.Keep.ID, strings.Join(merged, ", "))
+
+		if !*merge {
+			continue
+		}
+
+		err = st.MergeDuplicates(ctx, group)
+		if err != nil {
+			log.Fatalf("merge %s: %v", group.Keep.ID, err)
+		}
+	}
+
+	switch {
+	case len(duplicates) == 0:
+		fmt.Println("no duplicates found")
+	case *merge:
+		fmt.Printf("%d songs merged\n", len(duplicates))
+	default:
+		fmt.Printf("%d songs have duplicates, run with -merge to merge them\n", len(duplicates))
+	}
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/qreaqtor/music-library/internal/clients/songDetails/fake"
+)
+
+// Runs fake song details provider for local development without external services.
+func main() {
+	addr := flag.String("addr", "localhost:50056", "address to listen on")
+	flag.Parse()
+
+	log.Printf("Start fake song details provider at %s", *addr)
+
+	err := http.ListenAndServe(*addr, fake.NewServer())
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
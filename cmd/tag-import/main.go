@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/app"
+	"github.com/qreaqtor/music-library/internal/config"
+	"github.com/qreaqtor/music-library/internal/domain"
+	"github.com/qreaqtor/music-library/internal/service"
+	postgres "github.com/qreaqtor/music-library/internal/storage/postgres"
+	audiotag "github.com/qreaqtor/music-library/pkg/audioTag"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+)
+
+// Files are imported in transactions of at most this many songs.
+const batchSize = 500
+
+// Extensions of files which are read, other files are ignored.
+var audioExtensions = map[string]bool{
+	".mp3":  true,
+	".flac": true,
+	".ogg":  true,
+	".oga":  true,
+	".opus": true,
+	".m4a":  true,
+	".mp4":  true,
+}
+
+// Creates songs from tags of audio files in the given directories and prints what was created,
+// matched or skipped. Uses the same config as the app.
+func main() {
+	mode := flag.String("mode", domain.BulkInsert, "insert leaves existing songs as is, upsert updates them")
+	dryRun := flag.Bool("dry-run", false, "report results without saving")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] dir...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 || *mode != domain.BulkInsert && *mode != domain.BulkUpsert {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	paths := make([]string, 0)
+	for _, dir := range flag.Args() {
+		err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !entry.IsDir() && audioExtensions[strings.ToLower(filepath.Ext(path))] {
+				paths = append(paths, path)
+			}
+			return nil
+		})
+		if err != nil {
+			log.Fatalln(err)
+		}
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	err = cfg.Postgres.Validate()
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	conn, err := app.NewPostgresConn(cfg.Postgres)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer conn.Close()
+
+	st := postgres.NewSongsStorage(conn, cfg.Search.Language)
+	srv := service.NewSongsService(st, nil, cfg.Search.FuzzyThreshold)
+
+	ctx := context.WithValue(context.Background(), logmsg.OperationID, uuid.New())
+	ctx = domain.WithAuthor(ctx, "tag-import")
+
+	options := &domain.BulkOptions{
+		Mode:   *mode,
+		DryRun: *dryRun,
+	}
+
+	counts := make(map[string]int)
+
+	for start := 0; start < len(paths); start += batchSize {
+		batch := paths[start:min(start+batchSize, len(paths))]
+
+		results, err := importFiles(ctx, srv, batch, options)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		for _, result := range results {
+			counts[result.Status]++
+
+			switch result.Status {
+			case domain.TagsSkipped:
+				fmt.Printf("%-8s %s: %s\n", result.Status, result.File, result.Reason)
+			default:
+				fmt.Printf("%-8s %s: %s - %s (%s)\n", result.Status, result.File, result.Group, result.SongName, result.ID)
+			}
+		}
+	}
+
+	fmt.Printf(
+		"%d files: %d created, %d matched, %d updated, %d skipped\n",
+		len(paths),
+		counts[domain.TagsCreated],
+		counts[domain.TagsMatched],
+		counts[domain.TagsUpdated],
+		counts[domain.TagsSkipped],
+	)
+}
+
+// Reads tags of the files and imports them, files which can't be read are skipped.
+func importFiles(ctx context.Context, srv *service.SongsService, paths []string, options *domain.BulkOptions) ([]*domain.TagsResult, error) {
+	results := make([]*domain.TagsResult, len(paths))
+
+	files := make([]*audiotag.Tags, 0, len(paths))
+	positions := make([]int, 0, len(paths))
+
+	for i, path := range paths {
+		tags, err := readTags(path)
+		if err != nil {
+			results[i] = domain.SkippedFile(path, err)
+			continue
+		}
+
+		files = append(files, tags)
+		positions = append(positions, i)
+	}
+
+	imported, err := srv.ImportTags(ctx, files, options)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, result := range imported {
+		result.File = paths[positions[i]]
+		results[positions[i]] = result
+	}
+
+	return results, nil
+}
+
+func readTags(path string) (*audiotag.Tags, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return audiotag.Read(file)
+}
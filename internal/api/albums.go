@@ -0,0 +1,362 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+	"github.com/qreaqtor/music-library/pkg/web"
+)
+
+type albumsService interface {
+	Create(context.Context, *domain.Album) (*domain.Album, error)
+	Get(context.Context, uuid.UUID) (*domain.Album, error)
+	List(context.Context, *domain.AlbumSearch) ([]*domain.Album, error)
+	Update(context.Context, uuid.UUID, *domain.AlbumUpdate) (*domain.Album, error)
+	Delete(context.Context, uuid.UUID) error
+	GetTracks(context.Context, uuid.UUID) ([]*domain.Track, error)
+	SetTracks(context.Context, uuid.UUID, *domain.Tracklist) ([]*domain.Track, error)
+}
+
+type AlbumsAPI struct {
+	srv albumsService
+
+	valid *validator.Validate
+}
+
+func NewAlbumsAPI(srv albumsService) *AlbumsAPI {
+	return &AlbumsAPI{
+		srv:   srv,
+		valid: validator.New(validator.WithRequiredStructEnabled()),
+	}
+}
+
+func (a *AlbumsAPI) Register(r *mux.Router) {
+	offsetAndLimit := []string{
+		"offset", `{offset:\d+}`,
+		"limit", `{limit:[1-9][\d+]?}`,
+	}
+
+	r.Path("/albums").HandlerFunc(a.create).Methods(http.MethodPost)
+
+	r.Path("/albums").HandlerFunc(a.list).Methods(http.MethodGet).
+		Queries(offsetAndLimit...)
+
+	r.Path("/albums/{id}").HandlerFunc(a.get).Methods(http.MethodGet)
+
+	r.Path("/albums/{id}").HandlerFunc(a.update).Methods(http.MethodPatch)
+
+	r.Path("/albums/{id}").HandlerFunc(a.delete).Methods(http.MethodDelete)
+
+	r.Path("/albums/{id}/tracks").HandlerFunc(a.getTracks).Methods(http.MethodGet)
+
+	r.Path("/albums/{id}/tracks").HandlerFunc(a.setTracks).Methods(http.MethodPut)
+}
+
+// @Summary Create an album
+// @Description Add a new album of the existing artist
+// @Tags albums
+// @Accept json
+// @Produce json
+// @Param album body domain.Album true "Album data"
+// @Success 201 {object} domain.Album
+// @Failure 422 {string} string "Unknown artist"
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Security BearerAuth
+// @Router /v1/albums [post]
+// @Router /v2/albums [post]
+func (a *AlbumsAPI) create(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	album := &domain.Album{}
+
+	err := web.ReadRequestBody(r, album)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	err = a.valid.StructCtx(r.Context(), album)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
+		return
+	}
+
+	created, err := a.srv.Create(r.Context(), album)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("Created", http.StatusCreated),
+		created,
+	)
+}
+
+// @Summary List albums
+// @Description List albums ordered by release date, optionally filtered by title and artist
+// @Tags albums
+// @Accept json
+// @Produce json
+// @Param by_title query string false "Search by album title"
+// @Param artist_id query string false "Albums of this artist only"
+// @Param offset query int true "Offset for batch"
+// @Param limit query int true "Limit for batch" maximum(100)
+// @Success 200 {object} albumsResponse
+// @Router /v1/albums [get]
+// @Router /v2/albums [get]
+func (a *AlbumsAPI) list(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	var artistID uuid.UUID
+	if artistIDStr := r.URL.Query().Get("artist_id"); artistIDStr != "" {
+		parsedID, err := uuid.Parse(artistIDStr)
+		if err != nil {
+			web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+			return
+		}
+		artistID = parsedID
+	}
+
+	// ignore errors, because i used regexp for this query params
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	search := &domain.AlbumSearch{
+		ByTitle:  r.URL.Query().Get("by_title"),
+		ArtistID: artistID,
+		Batch: domain.Batch{
+			Offset: offset,
+			Limit:  limit,
+		},
+	}
+
+	err := a.valid.StructCtx(r.Context(), search)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
+		return
+	}
+
+	albums, err := a.srv.List(r.Context(), search)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		albumsResponse{
+			Albums: albums,
+		},
+	)
+}
+
+// @Summary Get album
+// @Description Retrieve an album by id
+// @Tags albums
+// @Accept json
+// @Produce json
+// @Param id path string true "Album id"
+// @Success 200 {object} domain.Album
+// @Failure 404 {string} string "Unknown album"
+// @Router /v1/albums/{id} [get]
+// @Router /v2/albums/{id} [get]
+func (a *AlbumsAPI) get(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	album, err := a.srv.Get(r.Context(), id)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		album,
+	)
+}
+
+// @Summary Update album
+// @Description Update album fields
+// @Tags albums
+// @Accept json
+// @Produce json
+// @Param id path string true "Album id"
+// @Param update body domain.AlbumUpdate true "Update parameters"
+// @Success 200 {object} domain.Album
+// @Failure 404 {string} string "Unknown album"
+// @Failure 422 {string} string "Unknown artist"
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Security BearerAuth
+// @Router /v1/albums/{id} [patch]
+// @Router /v2/albums/{id} [patch]
+func (a *AlbumsAPI) update(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	update := &domain.AlbumUpdate{}
+
+	err = web.ReadRequestBody(r, update)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	err = a.valid.StructCtx(r.Context(), update)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
+		return
+	}
+
+	album, err := a.srv.Update(r.Context(), id, update)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		album,
+	)
+}
+
+// @Summary Delete album
+// @Description Remove an album with its tracklist, songs are kept
+// @Tags albums
+// @Accept json
+// @Produce json
+// @Param id path string true "Album id"
+// @Success 200 {object} messageResponse
+// @Failure 404 {string} string "Unknown album"
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Security BearerAuth
+// @Router /v1/albums/{id} [delete]
+// @Router /v2/albums/{id} [delete]
+func (a *AlbumsAPI) delete(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	err = a.srv.Delete(r.Context(), id)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		messageResponse{"ok"},
+	)
+}
+
+// @Summary Get album tracklist
+// @Description Retrieve tracks of the album ordered by disc and track number
+// @Tags albums
+// @Accept json
+// @Produce json
+// @Param id path string true "Album id"
+// @Success 200 {object} tracksResponse
+// @Failure 404 {string} string "Unknown album"
+// @Router /v1/albums/{id}/tracks [get]
+// @Router /v2/albums/{id}/tracks [get]
+func (a *AlbumsAPI) getTracks(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	tracks, err := a.srv.GetTracks(r.Context(), id)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		tracksResponse{
+			Tracks: tracks,
+		},
+	)
+}
+
+// @Summary Set album tracklist
+// @Description Replace the whole tracklist of the album, tracks link existing songs by group and song name
+// @Tags albums
+// @Accept json
+// @Produce json
+// @Param id path string true "Album id"
+// @Param tracklist body domain.Tracklist true "Tracks, duration is in seconds"
+// @Success 200 {object} tracksResponse
+// @Failure 404 {string} string "Unknown album or song"
+// @Failure 409 {string} string "Duplicate disc and track number"
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Security BearerAuth
+// @Router /v1/albums/{id}/tracks [put]
+// @Router /v2/albums/{id}/tracks [put]
+func (a *AlbumsAPI) setTracks(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	tracklist := &domain.Tracklist{}
+
+	err = web.ReadRequestBody(r, tracklist)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	err = a.valid.StructCtx(r.Context(), tracklist)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
+		return
+	}
+
+	tracks, err := a.srv.SetTracks(r.Context(), id, tracklist)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		tracksResponse{
+			Tracks: tracks,
+		},
+	)
+}
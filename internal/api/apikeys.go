@@ -0,0 +1,218 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/qreaqtor/music-library/internal/domain"
+	httpserver "github.com/qreaqtor/music-library/pkg/httpServer"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+	"github.com/qreaqtor/music-library/pkg/web"
+)
+
+type apiKeysService interface {
+	Create(context.Context, *domain.APIKeyCreate) (*domain.IssuedAPIKey, error)
+	Keys(context.Context, *domain.Batch) ([]*domain.APIKey, error)
+	Rotate(context.Context, uuid.UUID) (*domain.IssuedAPIKey, error)
+	Revoke(context.Context, uuid.UUID) (*domain.APIKey, error)
+}
+
+type apiKeysAuthenticator interface {
+	Authenticate(context.Context, string) (*domain.Principal, error)
+}
+
+type APIKeysAPI struct {
+	srv apiKeysService
+
+	auth apiKeysAuthenticator
+
+	valid *validator.Validate
+}
+
+func NewAPIKeysAPI(srv apiKeysService, auth apiKeysAuthenticator) *APIKeysAPI {
+	return &APIKeysAPI{
+		srv:   srv,
+		auth:  auth,
+		valid: validator.New(validator.WithRequiredStructEnabled()),
+	}
+}
+
+func (k *APIKeysAPI) Register(r *mux.Router) {
+	r.Path("/keys").HandlerFunc(k.create).Methods(http.MethodPost)
+
+	r.Path("/keys").HandlerFunc(k.list).Methods(http.MethodGet)
+
+	r.Path("/keys/{id}/rotate").HandlerFunc(k.rotate).Methods(http.MethodPost)
+
+	r.Path("/keys/{id}").HandlerFunc(k.revoke).Methods(http.MethodDelete)
+}
+
+// Authenticator of ApiKey scheme for httpserver.Authenticate.
+func (k *APIKeysAPI) Authenticate(ctx context.Context, key string) (*httpserver.User, error) {
+	principal, err := k.auth.Authenticate(ctx, key)
+	if errors.Is(err, domain.ErrInvalidToken) {
+		return nil, fmt.Errorf("%w: %w", httpserver.ErrUnauthorized, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &httpserver.User{
+		ID:          principal.ID,
+		Name:        principal.Name,
+		Role:        principal.Role,
+		Credential:  principal.SessionID,
+		Permissions: principal.Permissions,
+	}, nil
+}
+
+// @Summary Create API key
+// @Description Mint a key for a service client, it is sent as Authorization: ApiKey header.
+// @Description The key is returned only once, only its prefix is shown later. Requires keys:manage permission.
+// @Tags keys
+// @Accept json
+// @Produce json
+// @Param key body domain.APIKeyCreate true "Name, permissions and optional expiration time"
+// @Success 201 {object} domain.IssuedAPIKey
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Failure 422 {string} string "Invalid permissions or expiration time"
+// @Security BearerAuth
+// @Router /v2/keys [post]
+func (k *APIKeysAPI) create(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	create := &domain.APIKeyCreate{}
+
+	err := web.ReadRequestBody(r, create)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	err = k.valid.StructCtx(r.Context(), create)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
+		return
+	}
+
+	key, err := k.srv.Create(r.Context(), create)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("Created", http.StatusCreated),
+		key,
+	)
+}
+
+// @Summary List API keys
+// @Description List API keys in order of creation with time of the last use, revoked and expired keys are listed too.
+// @Description Requires keys:manage permission.
+// @Tags keys
+// @Produce json
+// @Param offset query int false "Offset, 0 by default"
+// @Param limit query int false "Limit, 20 by default" maximum(100)
+// @Success 200 {object} apiKeysResponse
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Security BearerAuth
+// @Router /v2/keys [get]
+func (k *APIKeysAPI) list(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	batch := parseBatch(r.URL.Query())
+
+	err := k.valid.StructCtx(r.Context(), batch)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
+		return
+	}
+
+	keys, err := k.srv.Keys(r.Context(), batch)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		apiKeysResponse{
+			Keys: keys,
+		},
+	)
+}
+
+// @Summary Rotate API key
+// @Description Issue a new key with the same permissions and expiration, the old key is rejected right after it.
+// @Description Requires keys:manage permission.
+// @Tags keys
+// @Produce json
+// @Param id path string true "API key id"
+// @Success 200 {object} domain.IssuedAPIKey
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Failure 404 {string} string "Unknown key"
+// @Failure 409 {string} string "Key is revoked"
+// @Security BearerAuth
+// @Router /v2/keys/{id}/rotate [post]
+func (k *APIKeysAPI) rotate(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	key, err := k.srv.Rotate(r.Context(), id)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		key,
+	)
+}
+
+// @Summary Revoke API key
+// @Description Revoke the key, it is rejected right after it. Revoking revoked key is not an error.
+// @Description Requires keys:manage permission.
+// @Tags keys
+// @Produce json
+// @Param id path string true "API key id"
+// @Success 200 {object} domain.APIKey
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Failure 404 {string} string "Unknown key"
+// @Security BearerAuth
+// @Router /v2/keys/{id} [delete]
+func (k *APIKeysAPI) revoke(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	key, err := k.srv.Revoke(r.Context(), id)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		key,
+	)
+}
@@ -0,0 +1,259 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+	"github.com/qreaqtor/music-library/pkg/web"
+)
+
+type artistsService interface {
+	Create(context.Context, *domain.Artist) (*domain.Artist, error)
+	Get(context.Context, uuid.UUID) (*domain.Artist, error)
+	List(context.Context, *domain.ArtistSearch) ([]*domain.Artist, error)
+	Update(context.Context, uuid.UUID, *domain.ArtistUpdate) (*domain.Artist, error)
+	Delete(context.Context, uuid.UUID) error
+}
+
+type ArtistsAPI struct {
+	srv artistsService
+
+	valid *validator.Validate
+}
+
+func NewArtistsAPI(srv artistsService) *ArtistsAPI {
+	return &ArtistsAPI{
+		srv:   srv,
+		valid: validator.New(validator.WithRequiredStructEnabled()),
+	}
+}
+
+func (a *ArtistsAPI) Register(r *mux.Router) {
+	offsetAndLimit := []string{
+		"offset", `{offset:\d+}`,
+		"limit", `{limit:[1-9][\d+]?}`,
+	}
+
+	r.Path("/artists").HandlerFunc(a.create).Methods(http.MethodPost)
+
+	r.Path("/artists").HandlerFunc(a.list).Methods(http.MethodGet).
+		Queries(offsetAndLimit...)
+
+	r.Path("/artists/{id}").HandlerFunc(a.get).Methods(http.MethodGet)
+
+	r.Path("/artists/{id}").HandlerFunc(a.update).Methods(http.MethodPatch)
+
+	r.Path("/artists/{id}").HandlerFunc(a.delete).Methods(http.MethodDelete)
+}
+
+// @Summary Create an artist
+// @Description Add a new artist (group), names are unique case insensitively
+// @Tags artists
+// @Accept json
+// @Produce json
+// @Param artist body domain.Artist true "Artist data"
+// @Success 201 {object} domain.Artist
+// @Failure 409 {string} string "Artist with the same name already exists"
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Security BearerAuth
+// @Router /v1/artists [post]
+// @Router /v2/artists [post]
+func (a *ArtistsAPI) create(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	artist := &domain.Artist{}
+
+	err := web.ReadRequestBody(r, artist)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	err = a.valid.StructCtx(r.Context(), artist)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
+		return
+	}
+
+	created, err := a.srv.Create(r.Context(), artist)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("Created", http.StatusCreated),
+		created,
+	)
+}
+
+// @Summary List artists
+// @Description List artists ordered by name, optionally filtered by name or alias
+// @Tags artists
+// @Accept json
+// @Produce json
+// @Param by_name query string false "Search by artist name or alias"
+// @Param offset query int true "Offset for batch"
+// @Param limit query int true "Limit for batch" maximum(100)
+// @Success 200 {object} artistsResponse
+// @Router /v1/artists [get]
+// @Router /v2/artists [get]
+func (a *ArtistsAPI) list(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	// ignore errors, because i used regexp for this query params
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	search := &domain.ArtistSearch{
+		ByName: r.URL.Query().Get("by_name"),
+		Batch: domain.Batch{
+			Offset: offset,
+			Limit:  limit,
+		},
+	}
+
+	err := a.valid.StructCtx(r.Context(), search)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
+		return
+	}
+
+	artists, err := a.srv.List(r.Context(), search)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		artistsResponse{
+			Artists: artists,
+		},
+	)
+}
+
+// @Summary Get artist
+// @Description Retrieve an artist by id
+// @Tags artists
+// @Accept json
+// @Produce json
+// @Param id path string true "Artist id"
+// @Success 200 {object} domain.Artist
+// @Failure 404 {string} string "Unknown artist"
+// @Router /v1/artists/{id} [get]
+// @Router /v2/artists/{id} [get]
+func (a *ArtistsAPI) get(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	artist, err := a.srv.Get(r.Context(), id)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		artist,
+	)
+}
+
+// @Summary Update artist
+// @Description Update artist fields, renaming the artist renames the group of all its songs
+// @Tags artists
+// @Accept json
+// @Produce json
+// @Param id path string true "Artist id"
+// @Param update body domain.ArtistUpdate true "Update parameters"
+// @Success 200 {object} domain.Artist
+// @Failure 404 {string} string "Unknown artist"
+// @Failure 409 {string} string "Artist with the same name already exists"
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Security BearerAuth
+// @Router /v1/artists/{id} [patch]
+// @Router /v2/artists/{id} [patch]
+func (a *ArtistsAPI) update(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	update := &domain.ArtistUpdate{}
+
+	err = web.ReadRequestBody(r, update)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	err = a.valid.StructCtx(r.Context(), update)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
+		return
+	}
+
+	artist, err := a.srv.Update(r.Context(), id, update)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		artist,
+	)
+}
+
+// @Summary Delete artist
+// @Description Remove an artist, artist with songs can't be deleted
+// @Tags artists
+// @Accept json
+// @Produce json
+// @Param id path string true "Artist id"
+// @Success 200 {object} messageResponse
+// @Failure 404 {string} string "Unknown artist"
+// @Failure 409 {string} string "Artist has songs"
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Security BearerAuth
+// @Router /v1/artists/{id} [delete]
+// @Router /v2/artists/{id} [delete]
+func (a *ArtistsAPI) delete(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	err = a.srv.Delete(r.Context(), id)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		messageResponse{"ok"},
+	)
+}
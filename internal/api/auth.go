@@ -0,0 +1,304 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/qreaqtor/music-library/internal/domain"
+	httpserver "github.com/qreaqtor/music-library/pkg/httpServer"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+	"github.com/qreaqtor/music-library/pkg/web"
+)
+
+var errAuthRequired = errors.New("authentication required, send access token in Authorization header")
+
+type authService interface {
+	Register(context.Context, *domain.Credentials) (*domain.User, error)
+	Login(context.Context, *domain.Credentials) (*domain.Tokens, error)
+	Refresh(context.Context, string) (*domain.Tokens, error)
+	Authenticate(context.Context, string) (*domain.Principal, error)
+	Logout(context.Context, uuid.UUID) error
+	LogoutAll(context.Context, uuid.UUID) (int, error)
+	User(context.Context, uuid.UUID) (*domain.User, error)
+}
+
+type AuthAPI struct {
+	srv authService
+
+	valid *validator.Validate
+}
+
+func NewAuthAPI(srv authService) *AuthAPI {
+	return &AuthAPI{
+		srv:   srv,
+		valid: validator.New(validator.WithRequiredStructEnabled()),
+	}
+}
+
+// Routes are registered on their own router, they are available without authentication.
+func (a *AuthAPI) Register(r *mux.Router) {
+	r.Path("/register").HandlerFunc(a.register).Methods(http.MethodPost)
+
+	r.Path("/login").HandlerFunc(a.login).Methods(http.MethodPost)
+
+	r.Path("/refresh").HandlerFunc(a.refresh).Methods(http.MethodPost)
+
+	r.Path("/logout").HandlerFunc(a.logout).Methods(http.MethodPost)
+
+	r.Path("/logout-all").HandlerFunc(a.logoutAll).Methods(http.MethodPost)
+
+	r.Path("/me").HandlerFunc(a.me).Methods(http.MethodGet)
+}
+
+// Authenticator of Bearer scheme for httpserver.Authenticate.
+func (a *AuthAPI) Authenticate(ctx context.Context, token string) (*httpserver.User, error) {
+	principal, err := a.srv.Authenticate(ctx, token)
+	if errors.Is(err, domain.ErrInvalidToken) {
+		return nil, fmt.Errorf("%w: %w", httpserver.ErrUnauthorized, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &httpserver.User{
+		ID:         principal.ID,
+		Name:       principal.Name,
+		Role:       principal.Role,
+		Credential: principal.SessionID,
+	}, nil
+}
+
+// RequireUser rejects anonymous requests which change data, reading stays public.
+func RequireUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if _, ok := httpserver.ExtractUser(r.Context()); !ok {
+			writeAuthRequired(w, logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// @Summary Register
+// @Description Create a user, name is unique case insensitively
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param credentials body domain.Credentials true "Name and password"
+// @Success 201 {object} domain.User
+// @Failure 409 {string} string "User exists"
+// @Failure 422 {string} string "Invalid name or password"
+// @Router /v2/auth/register [post]
+func (a *AuthAPI) register(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	credentials := &domain.Credentials{}
+
+	err := web.ReadRequestBody(r, credentials)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	err = a.valid.StructCtx(r.Context(), credentials)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
+		return
+	}
+
+	user, err := a.srv.Register(r.Context(), credentials)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("Created", http.StatusCreated),
+		user,
+	)
+}
+
+// @Summary Login
+// @Description Start a session and issue access and refresh tokens.
+// @Description Access token is sent as Authorization: Bearer header, refresh token is exchanged for new tokens.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param credentials body domain.Credentials true "Name and password"
+// @Success 200 {object} domain.Tokens
+// @Failure 401 {string} string "Invalid name or password"
+// @Router /v2/auth/login [post]
+func (a *AuthAPI) login(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	credentials := &domain.Credentials{}
+
+	err := web.ReadRequestBody(r, credentials)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	// credentials are not validated, rules of registration may change after users registered
+	tokens, err := a.srv.Login(r.Context(), credentials)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		tokens,
+	)
+}
+
+// @Summary Refresh tokens
+// @Description Exchange refresh token for new access and refresh tokens, the old refresh token can't be used again.
+// @Description Reuse of an exchanged refresh token revokes the whole session.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param refresh body domain.RefreshRequest true "Refresh token"
+// @Success 200 {object} domain.Tokens
+// @Failure 401 {string} string "Token is invalid, expired or revoked"
+// @Router /v2/auth/refresh [post]
+func (a *AuthAPI) refresh(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	request := &domain.RefreshRequest{}
+
+	err := web.ReadRequestBody(r, request)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	err = a.valid.StructCtx(r.Context(), request)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
+		return
+	}
+
+	tokens, err := a.srv.Refresh(r.Context(), request.RefreshToken)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		tokens,
+	)
+}
+
+// @Summary Logout
+// @Description Revoke the session of the access token, its access and refresh tokens are rejected after it
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} messageResponse
+// @Failure 401 {string} string "Authentication required"
+// @Router /v2/auth/logout [post]
+func (a *AuthAPI) logout(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	user, ok := httpserver.ExtractUser(r.Context())
+	if !ok {
+		writeAuthRequired(w, msg)
+		return
+	}
+
+	err := a.srv.Logout(r.Context(), user.Credential)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		messageResponse{"ok"},
+	)
+}
+
+// @Summary Logout everywhere
+// @Description Revoke all sessions of the user
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} revokedResponse
+// @Failure 401 {string} string "Authentication required"
+// @Router /v2/auth/logout-all [post]
+func (a *AuthAPI) logoutAll(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	user, ok := httpserver.ExtractUser(r.Context())
+	if !ok {
+		writeAuthRequired(w, msg)
+		return
+	}
+
+	revoked, err := a.srv.LogoutAll(r.Context(), user.ID)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		revokedResponse{
+			Revoked: revoked,
+		},
+	)
+}
+
+// @Summary Current user
+// @Description Retrieve the user of the access token
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} domain.User
+// @Failure 401 {string} string "Authentication required"
+// @Router /v2/auth/me [get]
+func (a *AuthAPI) me(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	principal, ok := httpserver.ExtractUser(r.Context())
+	if !ok {
+		writeAuthRequired(w, msg)
+		return
+	}
+
+	user, err := a.srv.User(r.Context(), principal.ID)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		user,
+	)
+}
+
+func writeAuthRequired(w http.ResponseWriter, msg *logmsg.LogMsg) {
+	w.Header().Set("WWW-Authenticate", domain.TokenTypeBearer)
+	web.WriteError(w, msg.With(errAuthRequired.Error(), http.StatusUnauthorized))
+}
@@ -0,0 +1,26 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/qreaqtor/music-library/internal/domain"
+	httpserver "github.com/qreaqtor/music-library/pkg/httpServer"
+)
+
+// SetPrincipal puts the authenticated user to the request context, permissions are checked by its role.
+// Name of the user is also put as author of changes, it is saved in song revisions.
+func SetPrincipal(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, ok := httpserver.ExtractUser(r.Context()); ok {
+			ctx := domain.WithPrincipal(r.Context(), &domain.Principal{
+				ID:          user.ID,
+				Name:        user.Name,
+				Role:        user.Role,
+				SessionID:   user.Credential,
+				Permissions: user.Permissions,
+			})
+			r = r.WithContext(domain.WithAuthor(ctx, user.Name))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,313 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+
+	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+	"github.com/qreaqtor/music-library/pkg/web"
+)
+
+// Songs are imported in transactions of at most this many songs,
+// so a failed batch doesn't roll back songs imported before it.
+const bulkBatchSize = 500
+
+// Max length of NDJSON line, that is of a single song with lyrics.
+const maxBulkLine = 1 << 20
+
+var (
+	errBulkPayload = errors.New("unknown payload, use application/json array or application/x-ndjson")
+	errBulkArray   = errors.New("invalid payload, json array of songs expected")
+)
+
+// Error of a single item, other items can be read after it.
+type bulkItemError struct {
+	err error
+}
+
+func (e *bulkItemError) Error() string {
+	return e.err.Error()
+}
+
+// Reads songs of bulk import one by one, so the body is not loaded into memory at once.
+// Returns io.EOF after the last song.
+type bulkReader interface {
+	Next() (*domain.BulkSong, error)
+}
+
+// Reads songs from json array.
+type arrayReader struct {
+	dec     *json.Decoder
+	started bool
+}
+
+// Reads songs from newline delimited json, empty lines are skipped.
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+}
+
+// @Summary Bulk import songs
+// @Description Import songs with lyrics from json array or NDJSON stream (Content-Type application/x-ndjson).
+// @Description Songs are imported in batches, each in a transaction. Every song gets a result with its index in the request:
+// @Description created, updated (upsert mode), skipped (insert mode, song exists) or error. Invalid songs don't stop the import.
+// @Description Existing songs are found by group and name up to case and spaces, on update empty fields are kept.
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param songs body []domain.BulkSong true "Songs"
+// @Param mode query string false "insert (default) skips existing songs, upsert updates them"
+// @Param dry_run query bool false "Compute results without saving"
+// @Success 200 {object} bulkResponse
+// @Failure 400 {object} bulkResponse "Malformed payload, Results contain songs processed before the error"
+// @Failure 500 {object} bulkResponse "Import failed, Results contain songs processed before the error"
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Security BearerAuth
+// @Router /v2/songs:bulk [post]
+func (s *SongsAPI) importSongs(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	options, err := parseBulkOptions(r.URL.Query())
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	err = s.valid.StructCtx(r.Context(), options)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
+		return
+	}
+
+	reader, err := newBulkReader(r)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	response := &bulkResponse{
+		Results: make([]*domain.BulkResult, 0),
+	}
+
+	batch := make([]*domain.BulkSong, 0, bulkBatchSize)
+	indexes := make([]int, 0, bulkBatchSize)
+
+	// keys of songs which would be created by previous batches in dry run
+	created := make(map[string]struct{})
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		results, err := s.srv.Import(r.Context(), batch, options)
+		if err != nil {
+			return err
+		}
+
+		for i, result := range results {
+			result.Index = indexes[i]
+			if options.DryRun {
+				dryRunResult(result, batch[i], options.Mode, created)
+			}
+			response.add(result)
+		}
+
+		batch = batch[:0]
+		indexes = indexes[:0]
+		return nil
+	}
+
+	for index := 0; ; index++ {
+		song, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var itemErr *bulkItemError
+		if errors.As(err, &itemErr) {
+			response.add(bulkError(index, err))
+			continue
+		}
+		if err != nil {
+			status := http.StatusBadRequest
+			if flushErr := flush(); flushErr != nil {
+				err, status = flushErr, errorStatus(flushErr)
+			}
+			response.fail(err)
+			web.WriteData(w, msg.With(err.Error(), status), response)
+			return
+		}
+
+		err = s.valid.StructCtx(r.Context(), song)
+		if err != nil {
+			response.add(bulkError(index, err))
+			continue
+		}
+
+		batch = append(batch, song)
+		indexes = append(indexes, index)
+
+		if len(batch) == bulkBatchSize {
+			err = flush()
+			if err != nil {
+				response.fail(err)
+				web.WriteData(w, msg.With(err.Error(), errorStatus(err)), response)
+				return
+			}
+		}
+	}
+
+	err = flush()
+	if err != nil {
+		response.fail(err)
+		web.WriteData(w, msg.With(err.Error(), errorStatus(err)), response)
+		return
+	}
+
+	response.sort()
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		response,
+	)
+}
+
+// Returns reader for the content type of the request.
+func newBulkReader(r *http.Request) (bulkReader, error) {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, errBulkPayload
+	}
+
+	switch mediaType {
+	case web.ContentTypeJSON:
+		return &arrayReader{dec: json.NewDecoder(r.Body)}, nil
+	case web.ContentTypeNDJSON:
+		scanner := bufio.NewScanner(r.Body)
+		scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxBulkLine)
+		return &ndjsonReader{scanner: scanner}, nil
+	default:
+		return nil, errBulkPayload
+	}
+}
+
+func (a *arrayReader) Next() (*domain.BulkSong, error) {
+	if !a.started {
+		token, err := a.dec.Token()
+		if err != nil {
+			return nil, errBulkArray
+		}
+		if delim, ok := token.(json.Delim); !ok || delim != '[' {
+			return nil, errBulkArray
+		}
+		a.started = true
+	}
+
+	if !a.dec.More() {
+		_, err := a.dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+
+	song := &domain.BulkSong{}
+	err := a.dec.Decode(song)
+
+	// decoder skips the value of wrong type, so the next songs can be read
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return nil, &bulkItemError{err}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return song, nil
+}
+
+func (n *ndjsonReader) Next() (*domain.BulkSong, error) {
+	for n.scanner.Scan() {
+		line := bytes.TrimSpace(n.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		song := &domain.BulkSong{}
+		err := json.Unmarshal(line, song)
+		if err != nil {
+			return nil, &bulkItemError{err}
+		}
+
+		return song, nil
+	}
+
+	err := n.scanner.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, io.EOF
+}
+
+// Batches of dry run are rolled back, so the storage doesn't see songs created by previous batches.
+// Results of their repeated items are changed to what they would be: updated in upsert mode or skipped in insert mode.
+func dryRunResult(result *domain.BulkResult, song *domain.BulkSong, mode string, created map[string]struct{}) {
+	if result.Status != domain.BulkCreated {
+		return
+	}
+
+	key := song.Key()
+	if _, ok := created[key]; !ok {
+		created[key] = struct{}{}
+		return
+	}
+
+	result.Status = domain.BulkSkipped
+	if mode == domain.BulkUpsert {
+		result.Status = domain.BulkUpdated
+	}
+}
+
+func bulkError(index int, err error) *domain.BulkResult {
+	return &domain.BulkResult{
+		Index:  index,
+		Status: domain.BulkError,
+		Error:  err.Error(),
+	}
+}
+
+func (b *bulkResponse) add(result *domain.BulkResult) {
+	switch result.Status {
+	case domain.BulkCreated:
+		b.Created++
+	case domain.BulkUpdated:
+		b.Updated++
+	case domain.BulkSkipped:
+		b.Skipped++
+	default:
+		b.Failed++
+	}
+
+	b.Results = append(b.Results, result)
+}
+
+// Items with errors are added as soon as they are read, others after their batch is imported.
+func (b *bulkResponse) sort() {
+	slices.SortFunc(b.Results, func(x, y *domain.BulkResult) int {
+		return x.Index - y.Index
+	})
+}
+
+func (b *bulkResponse) fail(err error) {
+	b.Error = err.Error()
+	b.sort()
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+	"github.com/qreaqtor/music-library/pkg/web"
+)
+
+// @Summary Set credits of a song
+// @Description Replace credited artists of the song, roles are primary, featuring, composer, lyricist and producer. New artists are created
+// @Tags credits
+// @Accept json
+// @Param id path string true "Song id"
+// @Param credits body domain.SongCredits true "Credits in display order"
+// @Success 204
+// @Failure 404 {string} string "Unknown song"
+// @Failure 422 {string} string "Invalid credits"
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Security BearerAuth
+// @Router /v2/songs/{id}/credits [put]
+func (s *SongsAPI) putSongCredits(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	credits := &domain.SongCredits{}
+
+	err = web.ReadRequestBody(r, credits)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	err = s.valid.StructCtx(r.Context(), credits)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
+		return
+	}
+
+	err = s.srv.SetCredits(r.Context(), &domain.Song{ID: id}, credits)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteStatus(w, msg.With("No Content", http.StatusNoContent))
+}
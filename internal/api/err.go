@@ -0,0 +1,100 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+	"github.com/qreaqtor/music-library/pkg/web"
+)
+
+var (
+	errInvalidID = errors.New("invalid id, uuid expected")
+)
+
+// Returns HTTP status for the error returned by service.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrUnknownResourse):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrForbidden),
+		errors.Is(err, domain.ErrUserRequired):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrEmptyUpdate),
+		errors.Is(err, domain.ErrArtistDates),
+		errors.Is(err, domain.ErrUnknownArtist),
+		errors.Is(err, domain.ErrTimestamps),
+		errors.Is(err, domain.ErrLyricsNotSynced),
+		errors.Is(err, domain.ErrTranslationVerses),
+		errors.Is(err, domain.ErrOwnRole),
+		errors.Is(err, domain.ErrKeyExpiry),
+		errors.Is(err, domain.ErrGenreCycle),
+		errors.Is(err, domain.ErrUnknownGenre):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, domain.ErrArtistExists),
+		errors.Is(err, domain.ErrArtistHasSongs),
+		errors.Is(err, domain.ErrTrackPosition),
+		errors.Is(err, domain.ErrSongExists),
+		errors.Is(err, domain.ErrUserExists),
+		errors.Is(err, domain.ErrKeyRevoked),
+		errors.Is(err, domain.ErrGenreExists),
+		errors.Is(err, domain.ErrGenreHasChildren):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrInvalidCredentials),
+		errors.Is(err, domain.ErrInvalidToken),
+		errors.Is(err, domain.ErrUnauthenticated):
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Writes 409 with id of the existing song if err is domain.SongExistsError.
+// Returns false for other errors, they are not written.
+func writeSongExists(w http.ResponseWriter, msg *logmsg.LogMsg, err error) bool {
+	var existsErr *domain.SongExistsError
+	if !errors.As(err, &existsErr) {
+		return false
+	}
+
+	w.Header().Set("Location", "/v2/songs/"+existsErr.ID.String())
+
+	web.WriteData(
+		w,
+		msg.With(err.Error(), http.StatusConflict),
+		createResponse{
+			Message: domain.ErrSongExists.Error(),
+			ID:      existsErr.ID,
+		},
+	)
+	return true
+}
+
+// Writes the error returned by service with status of errorStatus.
+// Missing permissions are written as forbiddenResponse, so clients can tell the reason.
+func writeError(w http.ResponseWriter, msg *logmsg.LogMsg, err error) {
+	var permissionErr *domain.PermissionError
+	if !errors.As(err, &permissionErr) {
+		web.WriteError(w, msg.With(err.Error(), errorStatus(err)))
+		return
+	}
+
+	status := errorStatus(err)
+	if status == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", domain.TokenTypeBearer)
+	}
+
+	msg = msg.With(err.Error(), status)
+	msg.Error()
+
+	web.WriteData(
+		w,
+		msg,
+		forbiddenResponse{
+			Message:    errors.Unwrap(err).Error(),
+			Permission: permissionErr.Permission,
+			Role:       permissionErr.Role,
+		},
+	)
+}
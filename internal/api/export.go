@@ -0,0 +1,185 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+	"github.com/qreaqtor/music-library/pkg/web"
+)
+
+var errInvalidFormat = errors.New("Invalid format, use ndjson or csv")
+
+var csvHeader = []string{"id", "group", "song", "release_date", "link", "created_at", "updated_at", "lyrics"}
+
+// Writes exported songs one by one, Flush must be called after the last song.
+type songWriter interface {
+	Write(*domain.ExportedSong) error
+	Flush() error
+}
+
+// Writes a song per line in the format of bulk import.
+type ndjsonWriter struct {
+	enc *json.Encoder
+}
+
+// Writes a song per row after the csvHeader row, verses of lyrics are separated by empty lines.
+type csvWriter struct {
+	w       *csv.Writer
+	started bool
+}
+
+// @Summary Export songs
+// @Description Stream all songs with lyrics as NDJSON (a song per line, the same format as bulk import) or CSV.
+// @Description Songs can be filtered and sorted the same way as by search, pagination params are ignored.
+// @Tags songs
+// @Produce application/x-ndjson
+// @Produce text/csv
+// @Param format query string false "Export format, ndjson by default" Enums(ndjson, csv)
+// @Param by_group query string false "Search by group name"
+// @Param by_song_name query string false "Search by song name"
+// @Param by_lyrics query string false "Full text search by lyrics"
+// @Param by_link query string false "Search by external link"
+// @Param date_from query string false "Search songs from this date"
+// @Param date_to query string false "Search songs up to this date"
+// @Param fuzzy query bool false "Match group and song name by trigram similarity"
+// @Param threshold query number false "Min similarity for fuzzy search, from 0 to 1"
+// @Param genre query string false "Genre name, songs of its descendant genres are found too"
+// @Param tag query []string false "Tags, songs with any of them are found" collectionFormat(multi)
+// @Param tag_match query string false "Find songs with any or all of the tags, any by default" Enums(any, all)
+// @Param credit query string false "Credited artist name or alias"
+// @Param credit_role query string false "Role of the credited artist, any artist in the role without credit" Enums(primary, featuring, composer, lyricist, producer)
+// @Param sort query string false "Sort field, relevance by default for lyrics and fuzzy search, otherwise created_at" Enums(group, song, release_date, created_at, updated_at, relevance, plays, rating)
+// @Param order query string false "Sort order, desc by default for relevance, plays and rating, otherwise asc" Enums(asc, desc)
+// @Success 200 {array} domain.ExportedSong
+// @Failure 400 {string} string "Invalid format or search params"
+// @Router /v1/export [get]
+// @Router /v2/export [get]
+func (s *SongsAPI) export(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = domain.ExportNDJSON
+	}
+
+	var writer songWriter
+	var contentType string
+
+	switch format {
+	case domain.ExportNDJSON:
+		writer, contentType = &ndjsonWriter{enc: json.NewEncoder(w)}, web.ContentTypeNDJSON
+	case domain.ExportCSV:
+		writer, contentType = &csvWriter{w: csv.NewWriter(w)}, web.ContentTypeCSV
+	default:
+		web.WriteError(w, msg.With(errInvalidFormat.Error(), http.StatusBadRequest))
+		return
+	}
+
+	search, err := parseSongSearch(r)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	// the whole result is exported
+	err = s.valid.StructExceptCtx(r.Context(), search, "Batch")
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
+		return
+	}
+
+	// status is sent with the first song, so errors before it are still reported
+	started := false
+	start := func() {
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": "songs." + format}))
+		w.WriteHeader(http.StatusOK)
+		started = true
+	}
+
+	err = s.srv.Export(r.Context(), search, func(song *domain.ExportedSong) error {
+		if !started {
+			start()
+		}
+		return writer.Write(song)
+	})
+	if err != nil && !started {
+		writeError(w, msg, err)
+		return
+	}
+	if err != nil {
+		// the response is cut off, client sees incomplete body
+		msg.With(err.Error(), http.StatusInternalServerError).Error()
+		return
+	}
+
+	if !started {
+		start()
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		msg.With(err.Error(), http.StatusInternalServerError).Error()
+		return
+	}
+
+	msg.With("OK", http.StatusOK).Info()
+}
+
+func (n *ndjsonWriter) Write(song *domain.ExportedSong) error {
+	return n.enc.Encode(song)
+}
+
+func (n *ndjsonWriter) Flush() error {
+	return nil
+}
+
+func (c *csvWriter) Write(song *domain.ExportedSong) error {
+	err := c.writeHeader()
+	if err != nil {
+		return err
+	}
+
+	verses := make([]string, 0, len(song.Lyrics))
+	for _, verse := range song.Lyrics {
+		verses = append(verses, verse.Text)
+	}
+
+	return c.w.Write([]string{
+		song.ID.String(),
+		song.Group,
+		song.SongName,
+		song.ReleaseDate.Format(dateLayout),
+		song.Link,
+		song.CreatedAt.Format(time.RFC3339),
+		song.UpdatedAt.Format(time.RFC3339),
+		strings.Join(verses, "\n\n"),
+	})
+}
+
+// Writes the header if there were no songs.
+func (c *csvWriter) Flush() error {
+	err := c.writeHeader()
+	if err != nil {
+		return err
+	}
+
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) writeHeader() error {
+	if c.started {
+		return nil
+	}
+
+	c.started = true
+	return c.w.Write(csvHeader)
+}
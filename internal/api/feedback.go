@@ -0,0 +1,282 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+	"github.com/qreaqtor/music-library/pkg/web"
+)
+
+// Period of top songs if it is not set.
+const defaultPeriod = domain.PeriodWeek
+
+// @Summary Add to favorites
+// @Description Add the song to favorites of the user, adding it again is not an error
+// @Tags feedback
+// @Param id path string true "Song id"
+// @Success 204
+// @Failure 404 {string} string "Unknown song"
+// @Failure 403 {object} forbiddenResponse "No permission or API key is used"
+// @Security BearerAuth
+// @Router /v2/songs/{id}/favorite [put]
+func (s *SongsAPI) favoriteSong(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	err = s.srv.Favorite(r.Context(), &domain.Song{ID: id})
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteStatus(w, msg.With("No Content", http.StatusNoContent))
+}
+
+// @Summary Remove from favorites
+// @Description Remove the song from favorites of the user, removing song which is not favorite is not an error
+// @Tags feedback
+// @Param id path string true "Song id"
+// @Success 204
+// @Failure 404 {string} string "Unknown song"
+// @Failure 403 {object} forbiddenResponse "No permission or API key is used"
+// @Security BearerAuth
+// @Router /v2/songs/{id}/favorite [delete]
+func (s *SongsAPI) unfavoriteSong(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	err = s.srv.Unfavorite(r.Context(), &domain.Song{ID: id})
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteStatus(w, msg.With("No Content", http.StatusNoContent))
+}
+
+// @Summary Rate a song
+// @Description Rate the song from 1 to 5, the previous rating of the user is replaced
+// @Tags feedback
+// @Accept json
+// @Param id path string true "Song id"
+// @Param rating body domain.RatingUpdate true "Rating"
+// @Success 204
+// @Failure 404 {string} string "Unknown song"
+// @Failure 422 {string} string "Rating is out of range"
+// @Failure 403 {object} forbiddenResponse "No permission or API key is used"
+// @Security BearerAuth
+// @Router /v2/songs/{id}/rating [put]
+func (s *SongsAPI) rateSong(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	update := &domain.RatingUpdate{}
+
+	err = web.ReadRequestBody(r, update)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	err = s.valid.StructCtx(r.Context(), update)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
+		return
+	}
+
+	err = s.srv.Rate(r.Context(), &domain.Song{ID: id}, update)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteStatus(w, msg.With("No Content", http.StatusNoContent))
+}
+
+// @Summary Remove rating
+// @Description Remove rating of the song by the user, removing missing rating is not an error
+// @Tags feedback
+// @Param id path string true "Song id"
+// @Success 204
+// @Failure 404 {string} string "Unknown song"
+// @Failure 403 {object} forbiddenResponse "No permission or API key is used"
+// @Security BearerAuth
+// @Router /v2/songs/{id}/rating [delete]
+func (s *SongsAPI) unrateSong(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	err = s.srv.Unrate(r.Context(), &domain.Song{ID: id})
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteStatus(w, msg.With("No Content", http.StatusNoContent))
+}
+
+// @Summary Record a play
+// @Description Add the song to listening history of the user, every call counts as one play
+// @Tags feedback
+// @Param id path string true "Song id"
+// @Success 204
+// @Failure 404 {string} string "Unknown song"
+// @Failure 403 {object} forbiddenResponse "No permission or API key is used"
+// @Security BearerAuth
+// @Router /v2/songs/{id}/plays [post]
+func (s *SongsAPI) playSong(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	err = s.srv.Play(r.Context(), &domain.Song{ID: id})
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteStatus(w, msg.With("No Content", http.StatusNoContent))
+}
+
+// @Summary List favorites
+// @Description List favorite songs of the user, recently added first
+// @Tags feedback
+// @Produce json
+// @Param offset query int false "Offset, 0 by default"
+// @Param limit query int false "Limit, 20 by default" maximum(100)
+// @Success 200 {object} favoritesResponse
+// @Failure 403 {object} forbiddenResponse "No permission or API key is used"
+// @Security BearerAuth
+// @Router /v2/me/favorites [get]
+func (s *SongsAPI) favorites(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	batch := parseBatch(r.URL.Query())
+
+	err := s.valid.StructCtx(r.Context(), batch)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
+		return
+	}
+
+	songs, err := s.srv.Favorites(r.Context(), batch)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		favoritesResponse{
+			Songs: songs,
+		},
+	)
+}
+
+// @Summary Listening history
+// @Description List songs played by the user, recent plays first
+// @Tags feedback
+// @Produce json
+// @Param offset query int false "Offset, 0 by default"
+// @Param limit query int false "Limit, 20 by default" maximum(100)
+// @Success 200 {object} historyResponse
+// @Failure 403 {object} forbiddenResponse "No permission or API key is used"
+// @Security BearerAuth
+// @Router /v2/me/history [get]
+func (s *SongsAPI) history(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	batch := parseBatch(r.URL.Query())
+
+	err := s.valid.StructCtx(r.Context(), batch)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
+		return
+	}
+
+	plays, err := s.srv.History(r.Context(), batch)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		historyResponse{
+			Plays: plays,
+		},
+	)
+}
+
+// @Summary Top songs
+// @Description List songs with most plays of all users in the period which ends now
+// @Tags feedback
+// @Produce json
+// @Param period query string false "day, week, month, year or all, week by default"
+// @Param offset query int false "Offset, 0 by default"
+// @Param limit query int false "Limit, 20 by default" maximum(100)
+// @Success 200 {object} topResponse
+// @Failure 422 {string} string "Unknown period"
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Router /v2/top [get]
+func (s *SongsAPI) top(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	search := &domain.TopSearch{
+		Batch:  *parseBatch(r.URL.Query()),
+		Period: defaultPeriod,
+	}
+	if r.URL.Query().Has("period") {
+		search.Period = r.URL.Query().Get("period")
+	}
+
+	err := s.valid.StructCtx(r.Context(), search)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
+		return
+	}
+
+	songs, err := s.srv.Top(r.Context(), search)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		topResponse{
+			Period: search.Period,
+			Songs:  songs,
+		},
+	)
+}
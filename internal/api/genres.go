@@ -0,0 +1,245 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+	"github.com/qreaqtor/music-library/pkg/web"
+)
+
+// @Summary List genres
+// @Description List the genre taxonomy ordered by name, parentId links a genre to its parent
+// @Tags genres
+// @Produce json
+// @Success 200 {object} genresResponse
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Router /v2/genres [get]
+func (s *SongsAPI) genres(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	genres, err := s.srv.Genres(r.Context())
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		genresResponse{
+			Genres: genres,
+		},
+	)
+}
+
+// @Summary Create a genre
+// @Description Add a genre to the taxonomy, names are unique case insensitively
+// @Tags genres
+// @Accept json
+// @Produce json
+// @Param genre body domain.Genre true "Genre name and optional parent id"
+// @Success 201 {object} domain.Genre
+// @Failure 409 {string} string "Genre with the same name already exists"
+// @Failure 422 {string} string "Unknown parent genre"
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Security BearerAuth
+// @Router /v2/genres [post]
+func (s *SongsAPI) createGenre(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	genre := &domain.Genre{}
+
+	err := web.ReadRequestBody(r, genre)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	err = s.valid.StructCtx(r.Context(), genre)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
+		return
+	}
+
+	created, err := s.srv.CreateGenre(r.Context(), genre)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("Created", http.StatusCreated),
+		created,
+	)
+}
+
+// @Summary Update a genre
+// @Description Rename the genre and move it to another parent, without parentId it becomes a top level genre
+// @Tags genres
+// @Accept json
+// @Produce json
+// @Param id path string true "Genre id"
+// @Param genre body domain.Genre true "New genre name and optional parent id"
+// @Success 200 {object} domain.Genre
+// @Failure 404 {string} string "Unknown genre"
+// @Failure 409 {string} string "Genre with the same name already exists"
+// @Failure 422 {string} string "Unknown parent genre or parent is a descendant of the genre"
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Security BearerAuth
+// @Router /v2/genres/{id} [put]
+func (s *SongsAPI) updateGenre(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	genre := &domain.Genre{}
+
+	err = web.ReadRequestBody(r, genre)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	genre.ID = id
+
+	err = s.valid.StructCtx(r.Context(), genre)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
+		return
+	}
+
+	updated, err := s.srv.UpdateGenre(r.Context(), genre)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		updated,
+	)
+}
+
+// @Summary Delete a genre
+// @Description Delete the genre, songs lose it. Genre with child genres can't be deleted
+// @Tags genres
+// @Param id path string true "Genre id"
+// @Success 204
+// @Failure 404 {string} string "Unknown genre"
+// @Failure 409 {string} string "Genre has child genres"
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Security BearerAuth
+// @Router /v2/genres/{id} [delete]
+func (s *SongsAPI) deleteGenre(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	err = s.srv.DeleteGenre(r.Context(), id)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteStatus(w, msg.With("No Content", http.StatusNoContent))
+}
+
+// @Summary Set genres of a song
+// @Description Replace genres of the song, genres are found by name case insensitively
+// @Tags genres
+// @Accept json
+// @Param id path string true "Song id"
+// @Param genres body domain.SongGenres true "Genre names"
+// @Success 204
+// @Failure 404 {string} string "Unknown song"
+// @Failure 422 {string} string "Unknown genre"
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Security BearerAuth
+// @Router /v2/songs/{id}/genres [put]
+func (s *SongsAPI) putSongGenres(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	genres := &domain.SongGenres{}
+
+	err = web.ReadRequestBody(r, genres)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	err = s.valid.StructCtx(r.Context(), genres)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
+		return
+	}
+
+	err = s.srv.SetGenres(r.Context(), &domain.Song{ID: id}, genres)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteStatus(w, msg.With("No Content", http.StatusNoContent))
+}
+
+// @Summary Set tags of a song
+// @Description Replace free-form tags of the song, tags are stored in lower case
+// @Tags genres
+// @Accept json
+// @Param id path string true "Song id"
+// @Param tags body domain.SongTags true "Tags"
+// @Success 204
+// @Failure 404 {string} string "Unknown song"
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Security BearerAuth
+// @Router /v2/songs/{id}/tags [put]
+func (s *SongsAPI) putSongTags(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	tags := &domain.SongTags{}
+
+	err = web.ReadRequestBody(r, tags)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	err = s.valid.StructCtx(r.Context(), tags)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
+		return
+	}
+
+	err = s.srv.SetTags(r.Context(), &domain.Song{ID: id}, tags)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteStatus(w, msg.With("No Content", http.StatusNoContent))
+}
@@ -0,0 +1,145 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+	"github.com/qreaqtor/music-library/pkg/lrc"
+	"github.com/qreaqtor/music-library/pkg/web"
+)
+
+// Max size of uploaded LRC file.
+const maxLRCSize = 1 << 20
+
+var errLRCTooLarge = errors.New("lrc file is too large")
+
+// @Summary Import LRC
+// @Description Replace lyrics of a song with lines of LRC or enhanced LRC file.
+// @Description Consecutive lines form a verse, lines without text separate verses.
+// @Description File is sent as request body or as file field of multipart form.
+// @Tags songs v2
+// @Accept plain
+// @Accept mpfd
+// @Produce json
+// @Param id path string true "Song id"
+// @Param lrc body string true "LRC file"
+// @Success 200 {object} domain.SongInfo
+// @Failure 400 {string} string "Invalid LRC"
+// @Failure 404 {string} string "Unknown song"
+// @Failure 422 {string} string "Timestamps go back"
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Security BearerAuth
+// @Router /v2/songs/{id}/lyrics/lrc [put]
+func (s *SongsAPI) importSongLRC(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	data, err := readUpload(w, r, maxLRCSize, errLRCTooLarge)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	file, err := lrc.Parse(bytes.NewReader(data))
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	song := &domain.Song{ID: id}
+
+	err = s.srv.ImportLRC(r.Context(), song, file)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	songInfo, err := s.srv.Info(r.Context(), song)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		songInfo,
+	)
+}
+
+// @Summary Export LRC
+// @Description Render synced lyrics of a song as LRC file, words are written as enhanced LRC
+// @Tags songs v2
+// @Produce plain
+// @Param id path string true "Song id"
+// @Success 200 {string} string "LRC file"
+// @Failure 404 {string} string "Unknown song"
+// @Failure 422 {string} string "Lyrics are not synced"
+// @Router /v2/songs/{id}/lyrics/lrc [get]
+func (s *SongsAPI) exportSongLRC(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	file, err := s.srv.ExportLRC(r.Context(), &domain.Song{ID: id})
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	buf := &bytes.Buffer{}
+
+	err = lrc.Write(buf, file)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusInternalServerError))
+		return
+	}
+
+	filename := file.Tag(lrc.TagArtist) + " - " + file.Tag(lrc.TagTitle) + ".lrc"
+
+	web.WriteBytes(w, msg.With("OK", http.StatusOK), web.ContentTypeText, filename, buf.Bytes())
+}
+
+// Reads file field of multipart form or the whole body otherwise.
+// tooLarge is returned if the file is bigger than maxSize.
+func readUpload(w http.ResponseWriter, r *http.Request, maxSize int64, tooLarge error) ([]byte, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize)
+
+	var body io.Reader = r.Body
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		body = file
+	}
+
+	data, err := io.ReadAll(body)
+
+	maxBytesErr := &http.MaxBytesError{}
+	if errors.As(err, &maxBytesErr) {
+		return nil, tooLarge
+	}
+
+	return data, err
+}
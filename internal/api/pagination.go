@@ -0,0 +1,187 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+	"github.com/qreaqtor/music-library/pkg/web"
+)
+
+var errInvalidCursor = errors.New("Invalid cursor, it is malformed or was issued for other criteria")
+
+// Query params which don't change found items, so cursor stays valid if they are changed.
+var pagingParams = []string{"cursor", "offset", "limit", "total"}
+
+// Cursor points to the first or the last song of a page and
+// keeps fingerprint of the search criteria it was issued for.
+type searchCursor struct {
+	Key      *domain.SearchKey `json:"k"`
+	Backward bool              `json:"b,omitempty"`
+	Query    string            `json:"q"`
+}
+
+type lyricsCursor struct {
+	Position int    `json:"p"`
+	Backward bool   `json:"b,omitempty"`
+	Query    string `json:"q"`
+}
+
+// Reads cursor query param to the search, offset is ignored if cursor is provided.
+func (s *SongsAPI) readSearchCursor(r *http.Request, search *domain.SongSearch) error {
+	token := r.URL.Query().Get("cursor")
+	if token == "" {
+		return nil
+	}
+
+	c := &searchCursor{}
+
+	err := s.cursors.Decode(token, c)
+	if err != nil || c.Key == nil || c.Query != queryFingerprint(r) {
+		return errInvalidCursor
+	}
+
+	search.Offset = 0
+	if c.Backward {
+		search.Before = c.Key
+	} else {
+		search.After = c.Key
+	}
+
+	return nil
+}
+
+// Returns next and prev cursors of the search result, empty if there is no such page.
+func (s *SongsAPI) searchCursors(r *http.Request, result *domain.SearchResult) (string, string, error) {
+	var next, prev string
+	var err error
+
+	if result.Next != nil {
+		next, err = s.cursors.Encode(searchCursor{Key: result.Next, Query: queryFingerprint(r)})
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	if result.Prev != nil {
+		prev, err = s.cursors.Encode(searchCursor{Key: result.Prev, Backward: true, Query: queryFingerprint(r)})
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	return next, prev, nil
+}
+
+// Reads cursor query param to the batch, offset is ignored if cursor is provided.
+func (s *SongsAPI) readLyricsCursor(r *http.Request, batch *domain.LyricsBatch) error {
+	token := r.URL.Query().Get("cursor")
+	if token == "" {
+		return nil
+	}
+
+	c := &lyricsCursor{}
+
+	err := s.cursors.Decode(token, c)
+	if err != nil || c.Position == 0 || c.Query != queryFingerprint(r) {
+		return errInvalidCursor
+	}
+
+	batch.Offset = 0
+	if c.Backward {
+		batch.Before = c.Position
+	} else {
+		batch.After = c.Position
+	}
+
+	return nil
+}
+
+// Returns next and prev cursors of the lyrics result, empty if there is no such page.
+func (s *SongsAPI) lyricsCursors(r *http.Request, result *domain.LyricsResult) (string, string, error) {
+	var next, prev string
+	var err error
+
+	if result.Next != 0 {
+		next, err = s.cursors.Encode(lyricsCursor{Position: result.Next, Query: queryFingerprint(r)})
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	if result.Prev != 0 {
+		prev, err = s.cursors.Encode(lyricsCursor{Position: result.Prev, Backward: true, Query: queryFingerprint(r)})
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	return next, prev, nil
+}
+
+func (s *SongsAPI) writeSearchResult(w http.ResponseWriter, r *http.Request, msg *logmsg.LogMsg, result *domain.SearchResult) {
+	next, prev, err := s.searchCursors(r, result)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusInternalServerError))
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		searchResponse{
+			Songs:       result.Songs,
+			Suggestions: result.Suggestions,
+			NextCursor:  next,
+			PrevCursor:  prev,
+			Total:       result.Total,
+			Facets:      result.Facets,
+		},
+	)
+}
+
+func (s *SongsAPI) writeLyrics(w http.ResponseWriter, r *http.Request, msg *logmsg.LogMsg, result *domain.LyricsResult) {
+	next, prev, err := s.lyricsCursors(r, result)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusInternalServerError))
+		return
+	}
+
+	lyrics := make([]string, 0, len(result.Verses))
+	for _, verse := range result.Verses {
+		lyrics = append(lyrics, verse.Text)
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		getLyricsResponse{
+			Lyrics:     lyrics,
+			Verses:     result.Verses,
+			Language:   result.Language,
+			NextCursor: next,
+			PrevCursor: prev,
+			Total:      result.Total,
+		},
+	)
+}
+
+// Hash of the path and query params except paging ones.
+func queryFingerprint(r *http.Request) string {
+	criteria := url.Values{}
+	for param, values := range r.URL.Query() {
+		if !slices.Contains(pagingParams, param) {
+			criteria[param] = values
+		}
+	}
+
+	// Encode sorts params by key
+	sum := sha256.Sum256([]byte(r.URL.Path + "?" + criteria.Encode()))
+
+	return base64.RawURLEncoding.EncodeToString(sum[:16])
+}
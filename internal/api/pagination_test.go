@@ -0,0 +1,98 @@
+package api
+
+import (
+	"errors"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+	"github.com/qreaqtor/music-library/pkg/cursor"
+)
+
+func TestReadSearchCursor(t *testing.T) {
+	s := NewSongsAPI(nil, cursor.NewSigner("secret"))
+	key := &domain.SearchKey{ID: uuid.New(), Group: "Muse"}
+
+	// cursor of the first page is issued for the criteria of this request
+	issued := httptest.NewRequest("GET", "/v2/songs?by_group=muse&sort=group&limit=10", nil)
+	next, _, err := s.searchCursors(issued, &domain.SearchResult{Next: key})
+	if err != nil {
+		t.Fatalf("searchCursors: %v", err)
+	}
+
+	foreign, err := cursor.NewSigner("other secret").Encode(searchCursor{Key: key, Query: queryFingerprint(issued)})
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		cursor  string
+		wantErr error
+	}{
+		{"SameCriteria", "/v2/songs?by_group=muse&sort=group", next, nil},
+		{"OtherPaging", "/v2/songs?sort=group&by_group=muse&limit=50&offset=20&total=true", next, nil},
+		{"OtherCriteria", "/v2/songs?by_group=queen&sort=group", next, errInvalidCursor},
+		{"ExtraCriteria", "/v2/songs?by_group=muse&sort=group&order=desc", next, errInvalidCursor},
+		{"OtherPath", "/v1/search?by_group=muse&sort=group", next, errInvalidCursor},
+		{"OtherSecret", "/v2/songs?by_group=muse&sort=group", foreign, errInvalidCursor},
+		{"Malformed", "/v2/songs?by_group=muse&sort=group", "garbage", errInvalidCursor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.path+"&cursor="+url.QueryEscape(tt.cursor), nil)
+			search := &domain.SongSearch{Batch: domain.Batch{Offset: 20}}
+
+			err := s.readSearchCursor(r, search)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("readSearchCursor returned %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if search.After == nil || search.After.ID != key.ID || search.Offset != 0 {
+				t.Errorf("search after %+v with offset %d, want after %v with zero offset", search.After, search.Offset, key.ID)
+			}
+		})
+	}
+}
+
+func TestReadLyricsCursor(t *testing.T) {
+	s := NewSongsAPI(nil, cursor.NewSigner("secret"))
+
+	issued := httptest.NewRequest("GET", "/v2/songs/1/lyrics?section=chorus&limit=2", nil)
+	_, prev, err := s.lyricsCursors(issued, &domain.LyricsResult{Prev: 3})
+	if err != nil {
+		t.Fatalf("lyricsCursors: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr error
+	}{
+		{"SameCriteria", "/v2/songs/1/lyrics?section=chorus&limit=5", nil},
+		{"OtherSong", "/v2/songs/2/lyrics?section=chorus&limit=2", errInvalidCursor},
+		{"OtherSection", "/v2/songs/1/lyrics?section=verse&limit=2", errInvalidCursor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.path+"&cursor="+url.QueryEscape(prev), nil)
+			batch := &domain.LyricsBatch{}
+
+			err := s.readLyricsCursor(r, batch)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("readLyricsCursor returned %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && batch.Before != 3 {
+				t.Errorf("batch before %d, want 3", batch.Before)
+			}
+		})
+	}
+}
@@ -0,0 +1,600 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+	"github.com/qreaqtor/music-library/pkg/playlist"
+	"github.com/qreaqtor/music-library/pkg/web"
+)
+
+// Max size of uploaded playlist file.
+const maxPlaylistSize = 4 << 20
+
+// Validation of name query param, the same as of playlist name.
+const playlistNameRule = "omitempty,max=200"
+
+var errPlaylistTooLarge = errors.New("playlist file is too large")
+
+type playlistsService interface {
+	Create(context.Context, *domain.Playlist) (*domain.Playlist, error)
+	Get(context.Context, uuid.UUID) (*domain.Playlist, error)
+	List(context.Context, *domain.PlaylistSearch) ([]*domain.Playlist, error)
+	Update(context.Context, uuid.UUID, *domain.PlaylistUpdate) (*domain.Playlist, error)
+	Delete(context.Context, uuid.UUID) error
+	GetItems(context.Context, uuid.UUID) ([]*domain.PlaylistItem, error)
+	InsertItem(context.Context, uuid.UUID, *domain.PlaylistItemInsert) ([]*domain.PlaylistItem, error)
+	MoveItem(ctx context.Context, id uuid.UUID, from, to int) ([]*domain.PlaylistItem, error)
+	RemoveItem(ctx context.Context, id uuid.UUID, position int) ([]*domain.PlaylistItem, error)
+	Duplicate(ctx context.Context, id uuid.UUID, name string) (*domain.Playlist, error)
+	Export(context.Context, uuid.UUID) (*playlist.Playlist, error)
+	Import(ctx context.Context, name string, file *playlist.Playlist) (*domain.PlaylistImport, error)
+}
+
+type PlaylistsAPI struct {
+	srv playlistsService
+
+	valid *validator.Validate
+}
+
+func NewPlaylistsAPI(srv playlistsService) *PlaylistsAPI {
+	return &PlaylistsAPI{
+		srv:   srv,
+		valid: validator.New(validator.WithRequiredStructEnabled()),
+	}
+}
+
+func (p *PlaylistsAPI) Register(r *mux.Router) {
+	r.Path("/playlists").HandlerFunc(p.create).Methods(http.MethodPost)
+
+	r.Path("/playlists").HandlerFunc(p.list).Methods(http.MethodGet)
+
+	r.Path("/playlists:import").HandlerFunc(p.importPlaylist).Methods(http.MethodPost)
+
+	r.Path("/playlists/{id}").HandlerFunc(p.get).Methods(http.MethodGet)
+
+	r.Path("/playlists/{id}").HandlerFunc(p.update).Methods(http.MethodPatch)
+
+	r.Path("/playlists/{id}").HandlerFunc(p.delete).Methods(http.MethodDelete)
+
+	r.Path("/playlists/{id}/duplicate").HandlerFunc(p.duplicate).Methods(http.MethodPost)
+
+	r.Path("/playlists/{id}/export").HandlerFunc(p.export).Methods(http.MethodGet)
+
+	r.Path("/playlists/{id}/items").HandlerFunc(p.getItems).Methods(http.MethodGet)
+
+	r.Path("/playlists/{id}/items").HandlerFunc(p.insertItem).Methods(http.MethodPost)
+
+	r.Path("/playlists/{id}/items/{position}").HandlerFunc(p.moveItem).Methods(http.MethodPatch)
+
+	r.Path("/playlists/{id}/items/{position}").HandlerFunc(p.removeItem).Methods(http.MethodDelete)
+}
+
+// @Summary Create a playlist
+// @Description Add a new empty playlist, songs are added as its items
+// @Tags playlists
+// @Accept json
+// @Produce json
+// @Param playlist body domain.Playlist true "Playlist data"
+// @Success 201 {object} domain.Playlist
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Security BearerAuth
+// @Router /v2/playlists [post]
+func (p *PlaylistsAPI) create(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	playlist := &domain.Playlist{}
+
+	err := web.ReadRequestBody(r, playlist)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	err = p.valid.StructCtx(r.Context(), playlist)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
+		return
+	}
+
+	created, err := p.srv.Create(r.Context(), playlist)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("Created", http.StatusCreated),
+		created,
+	)
+}
+
+// @Summary List playlists
+// @Description List playlists recently changed first, optionally filtered by name
+// @Tags playlists
+// @Produce json
+// @Param by_name query string false "Search by playlist name"
+// @Param offset query int false "Offset, 0 by default"
+// @Param limit query int false "Limit, 20 by default" maximum(100)
+// @Success 200 {object} playlistsResponse
+// @Router /v2/playlists [get]
+func (p *PlaylistsAPI) list(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	search := &domain.PlaylistSearch{
+		Batch:  *parseBatch(r.URL.Query()),
+		ByName: r.URL.Query().Get("by_name"),
+	}
+
+	err := p.valid.StructCtx(r.Context(), search)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
+		return
+	}
+
+	playlists, err := p.srv.List(r.Context(), search)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		playlistsResponse{
+			Playlists: playlists,
+		},
+	)
+}
+
+// @Summary Get playlist
+// @Description Retrieve a playlist by id, its songs are listed by items
+// @Tags playlists
+// @Produce json
+// @Param id path string true "Playlist id"
+// @Success 200 {object} domain.Playlist
+// @Failure 404 {string} string "Unknown playlist"
+// @Router /v2/playlists/{id} [get]
+func (p *PlaylistsAPI) get(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	playlist, err := p.srv.Get(r.Context(), id)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		playlist,
+	)
+}
+
+// @Summary Update playlist
+// @Description Update name and description of the playlist
+// @Tags playlists
+// @Accept json
+// @Produce json
+// @Param id path string true "Playlist id"
+// @Param update body domain.PlaylistUpdate true "Update parameters"
+// @Success 200 {object} domain.Playlist
+// @Failure 404 {string} string "Unknown playlist"
+// @Failure 422 {string} string "Nothing to update"
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Security BearerAuth
+// @Router /v2/playlists/{id} [patch]
+func (p *PlaylistsAPI) update(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	update := &domain.PlaylistUpdate{}
+
+	err = web.ReadRequestBody(r, update)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	err = p.valid.StructCtx(r.Context(), update)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
+		return
+	}
+
+	playlist, err := p.srv.Update(r.Context(), id, update)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		playlist,
+	)
+}
+
+// @Summary Delete playlist
+// @Description Remove a playlist with its items, songs are kept
+// @Tags playlists
+// @Produce json
+// @Param id path string true "Playlist id"
+// @Success 200 {object} messageResponse
+// @Failure 404 {string} string "Unknown playlist"
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Security BearerAuth
+// @Router /v2/playlists/{id} [delete]
+func (p *PlaylistsAPI) delete(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	err = p.srv.Delete(r.Context(), id)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		messageResponse{"ok"},
+	)
+}
+
+// @Summary Duplicate playlist
+// @Description Copy the playlist with all its items
+// @Tags playlists
+// @Produce json
+// @Param id path string true "Playlist id"
+// @Param name query string false "Name of the copy, <name> (copy) by default"
+// @Success 201 {object} domain.Playlist
+// @Failure 404 {string} string "Unknown playlist"
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Security BearerAuth
+// @Router /v2/playlists/{id}/duplicate [post]
+func (p *PlaylistsAPI) duplicate(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	name := r.URL.Query().Get("name")
+
+	err = p.valid.VarCtx(r.Context(), name, playlistNameRule)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
+		return
+	}
+
+	copied, err := p.srv.Duplicate(r.Context(), id, name)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	w.Header().Set("Location", "/v2/playlists/"+copied.ID.String())
+
+	web.WriteData(
+		w,
+		msg.With("Created", http.StatusCreated),
+		copied,
+	)
+}
+
+// @Summary Get playlist items
+// @Description Retrieve songs of the playlist ordered by position, songs in trash are hidden
+// @Tags playlists
+// @Produce json
+// @Param id path string true "Playlist id"
+// @Success 200 {object} playlistItemsResponse
+// @Failure 404 {string} string "Unknown playlist"
+// @Router /v2/playlists/{id}/items [get]
+func (p *PlaylistsAPI) getItems(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	items, err := p.srv.GetItems(r.Context(), id)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		playlistItemsResponse{
+			Items: items,
+		},
+	)
+}
+
+// @Summary Insert playlist item
+// @Description Insert existing song found by id or by group and song name at the position, items from it are shifted down.
+// @Description The song is appended if position is not set or is after the last item. A song can be added several times.
+// @Tags playlists
+// @Accept json
+// @Produce json
+// @Param id path string true "Playlist id"
+// @Param item body domain.PlaylistItemInsert true "Song and position"
+// @Success 200 {object} playlistItemsResponse
+// @Failure 404 {string} string "Unknown playlist or song"
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Security BearerAuth
+// @Router /v2/playlists/{id}/items [post]
+func (p *PlaylistsAPI) insertItem(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	item := &domain.PlaylistItemInsert{}
+
+	err = web.ReadRequestBody(r, item)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	err = p.valid.StructCtx(r.Context(), item)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
+		return
+	}
+
+	items, err := p.srv.InsertItem(r.Context(), id, item)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		playlistItemsResponse{
+			Items: items,
+		},
+	)
+}
+
+// @Summary Move playlist item
+// @Description Move the item to another position, items between them are shifted. Too big position moves it to the end.
+// @Tags playlists
+// @Accept json
+// @Produce json
+// @Param id path string true "Playlist id"
+// @Param position path int true "Current position of the item"
+// @Param move body domain.PlaylistItemMove true "New position"
+// @Success 200 {object} playlistItemsResponse
+// @Failure 404 {string} string "Unknown playlist or no item at the position"
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Security BearerAuth
+// @Router /v2/playlists/{id}/items/{position} [patch]
+func (p *PlaylistsAPI) moveItem(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	position, err := parsePosition(mux.Vars(r)["position"])
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	move := &domain.PlaylistItemMove{}
+
+	err = web.ReadRequestBody(r, move)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	err = p.valid.StructCtx(r.Context(), move)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
+		return
+	}
+
+	items, err := p.srv.MoveItem(r.Context(), id, position, move.Position)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		playlistItemsResponse{
+			Items: items,
+		},
+	)
+}
+
+// @Summary Remove playlist item
+// @Description Remove the item at the position, items after it are shifted up. The song is kept.
+// @Tags playlists
+// @Produce json
+// @Param id path string true "Playlist id"
+// @Param position path int true "Position of the item"
+// @Success 200 {object} playlistItemsResponse
+// @Failure 404 {string} string "Unknown playlist or no item at the position"
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Security BearerAuth
+// @Router /v2/playlists/{id}/items/{position} [delete]
+func (p *PlaylistsAPI) removeItem(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	position, err := parsePosition(mux.Vars(r)["position"])
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	items, err := p.srv.RemoveItem(r.Context(), id, position)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		playlistItemsResponse{
+			Items: items,
+		},
+	)
+}
+
+// @Summary Export playlist
+// @Description Render the playlist as M3U (Latin-1), M3U8 or XSPF file with links of the songs.
+// @Description Songs without link are skipped in M3U, XSPF identifies them by urn:uuid of the song.
+// @Tags playlists
+// @Produce audio/x-mpegurl
+// @Produce application/xspf+xml
+// @Param id path string true "Playlist id"
+// @Param format query string false "Playlist format, m3u8 by default" Enums(m3u, m3u8, xspf)
+// @Success 200 {string} string "Playlist file"
+// @Failure 400 {string} string "Unknown format"
+// @Failure 404 {string} string "Unknown playlist"
+// @Router /v2/playlists/{id}/export [get]
+func (p *PlaylistsAPI) export(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = playlist.FormatM3U8
+	}
+
+	contentType, ok := playlist.ContentTypes[format]
+	if !ok {
+		web.WriteError(w, msg.With(playlist.ErrUnknownFormat.Error(), http.StatusBadRequest))
+		return
+	}
+
+	file, err := p.srv.Export(r.Context(), id)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	buf := &bytes.Buffer{}
+
+	err = playlist.Write(buf, file, format)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusInternalServerError))
+		return
+	}
+
+	web.WriteBytes(w, msg.With("OK", http.StatusOK), contentType, file.Title+"."+format, buf.Bytes())
+}
+
+// @Summary Import playlist
+// @Description Create a playlist from M3U, M3U8 or XSPF file, format is detected by content if it is not set.
+// @Description Tracks are matched with songs by urn:uuid identifier, then by link and then by "Artist - Title" of the track.
+// @Description Tracks without songs in the library are skipped and returned as unmatched.
+// @Description File is sent as request body or as file field of multipart form.
+// @Tags playlists
+// @Accept plain
+// @Accept mpfd
+// @Produce json
+// @Param playlist body string true "Playlist file"
+// @Param format query string false "Playlist format" Enums(m3u, m3u8, xspf)
+// @Param name query string false "Playlist name, title of the file by default"
+// @Success 201 {object} domain.PlaylistImport
+// @Failure 400 {string} string "Invalid playlist"
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Security BearerAuth
+// @Router /v2/playlists:import [post]
+func (p *PlaylistsAPI) importPlaylist(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	name := r.URL.Query().Get("name")
+
+	err := p.valid.VarCtx(r.Context(), name, playlistNameRule)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
+		return
+	}
+
+	data, err := readUpload(w, r, maxPlaylistSize, errPlaylistTooLarge)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = playlist.Detect(data)
+	}
+
+	file, err := playlist.Read(bytes.NewReader(data), format)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	imported, err := p.srv.Import(r.Context(), name, file)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	w.Header().Set("Location", "/v2/playlists/"+imported.Playlist.ID.String())
+
+	web.WriteData(
+		w,
+		msg.With("Created", http.StatusCreated),
+		imported,
+	)
+}
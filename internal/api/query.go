@@ -0,0 +1,215 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/qreaqtor/music-library/internal/domain"
+	"golang.org/x/text/language"
+)
+
+const dateLayout = "2006-01-02"
+
+// Used by v2 routes if limit query param is not provided.
+const defaultLimit = 20
+
+var (
+	errInvalidDateFrom  = errors.New("Invalid date_from format, use YYYY-MM-DD")
+	errInvalidDateTo    = errors.New("Invalid date_to format, use YYYY-MM-DD")
+	errInvalidFuzzy     = errors.New("Invalid fuzzy, use true or false")
+	errInvalidTotal     = errors.New("Invalid total, use true or false")
+	errInvalidFacets    = errors.New("Invalid facets, use true or false")
+	errInvalidThreshold = errors.New("Invalid threshold, use a number from 0 to 1")
+	errInvalidLanguage  = errors.New("Invalid lang, use BCP 47 language code like en or pt-BR")
+	errInvalidRevision  = errors.New("Invalid revision number, use a positive integer")
+	errInvalidDryRun    = errors.New("Invalid dry_run, use true or false")
+	errInvalidPosition  = errors.New("Invalid position, use a positive integer")
+)
+
+// Reads search criteria from query params, the result must be validated.
+func parseSongSearch(r *http.Request) (*domain.SongSearch, error) {
+	var dateFrom, dateTo time.Time
+	if dateFromStr := r.URL.Query().Get("date_from"); dateFromStr != "" {
+		parsedDate, err := time.Parse(dateLayout, dateFromStr)
+		if err != nil {
+			return nil, errInvalidDateFrom
+		}
+		dateFrom = parsedDate
+	}
+
+	if dateToStr := r.URL.Query().Get("date_to"); dateToStr != "" {
+		parsedDate, err := time.Parse(dateLayout, dateToStr)
+		if err != nil {
+			return nil, errInvalidDateTo
+		}
+		dateTo = parsedDate
+	}
+
+	fuzzy, err := parseBool(r.URL.Query(), "fuzzy")
+	if err != nil {
+		return nil, errInvalidFuzzy
+	}
+
+	total, err := parseBool(r.URL.Query(), "total")
+	if err != nil {
+		return nil, errInvalidTotal
+	}
+
+	facets, err := parseBool(r.URL.Query(), "facets")
+	if err != nil {
+		return nil, errInvalidFacets
+	}
+
+	var threshold float64
+	if thresholdStr := r.URL.Query().Get("threshold"); thresholdStr != "" {
+		parsedThreshold, err := strconv.ParseFloat(thresholdStr, 64)
+		if err != nil {
+			return nil, errInvalidThreshold
+		}
+		threshold = parsedThreshold
+	}
+
+	// ignore errors, invalid values are treated as zero and fail validation if required
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	return &domain.SongSearch{
+		ByGroup:    r.URL.Query().Get("by_group"),
+		BySongName: r.URL.Query().Get("by_song_name"),
+		ByLyrics:   r.URL.Query().Get("by_lyrics"),
+		ByLink:     r.URL.Query().Get("by_link"),
+		DateFrom:   dateFrom,
+		DateTo:     dateTo,
+		Genre:      r.URL.Query().Get("genre"),
+		Tags:       r.URL.Query()["tag"],
+		TagMatch:   r.URL.Query().Get("tag_match"),
+		Credit:     r.URL.Query().Get("credit"),
+		CreditRole: r.URL.Query().Get("credit_role"),
+		Fuzzy:      fuzzy,
+		Threshold:  threshold,
+		Sort:       r.URL.Query().Get("sort"),
+		Order:      r.URL.Query().Get("order"),
+		Total:      total,
+		Facets:     facets,
+		Batch: domain.Batch{
+			Offset: offset,
+			Limit:  limit,
+		},
+	}, nil
+}
+
+// Offset is 0 and limit is defaultLimit if they are not provided.
+func parseBatch(query url.Values) *domain.Batch {
+	batch := &domain.Batch{
+		Offset: 0,
+		Limit:  defaultLimit,
+	}
+
+	// ignore errors, invalid values are treated as zero and fail validation if required
+	if query.Has("offset") {
+		batch.Offset, _ = strconv.Atoi(query.Get("offset"))
+	}
+	if query.Has("limit") {
+		batch.Limit, _ = strconv.Atoi(query.Get("limit"))
+	}
+
+	return batch
+}
+
+// Reads section and total query params, the result must be validated.
+func parseLyricsBatch(query url.Values, batch *domain.Batch) (*domain.LyricsBatch, error) {
+	total, err := parseBool(query, "total")
+	if err != nil {
+		return nil, errInvalidTotal
+	}
+
+	return &domain.LyricsBatch{
+		Batch:   *batch,
+		Section: query.Get("section"),
+		Total:   total,
+	}, nil
+}
+
+// Returns false if the param is not provided.
+func parseBool(query url.Values, key string) (bool, error) {
+	if query.Get(key) == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(query.Get(key))
+}
+
+// Returns lang query param if it is provided, otherwise languages of Accept-Language header
+// ordered by preference, malformed header is ignored. Sets Vary header,
+// because the response depends on Accept-Language.
+func parseLanguages(w http.ResponseWriter, r *http.Request) ([]string, error) {
+	if lang := r.URL.Query().Get("lang"); lang != "" {
+		code, err := parseLanguage(lang)
+		if err != nil {
+			return nil, err
+		}
+		return []string{code}, nil
+	}
+
+	w.Header().Add("Vary", "Accept-Language")
+
+	tags, _, err := language.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+	if err != nil {
+		return nil, nil
+	}
+
+	languages := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		languages = append(languages, tag.String())
+	}
+
+	return languages, nil
+}
+
+// Returns canonical form of BCP 47 language code, for example pt-BR for pt_br.
+func parseLanguage(code string) (string, error) {
+	tag, err := language.Parse(code)
+	if err != nil {
+		return "", errInvalidLanguage
+	}
+	return tag.String(), nil
+}
+
+// Revisions are numbered from 1.
+func parseRevision(value string) (int, error) {
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 1 {
+		return 0, errInvalidRevision
+	}
+	return number, nil
+}
+
+// Positions of playlist items are numbered from 1.
+func parsePosition(value string) (int, error) {
+	position, err := strconv.Atoi(value)
+	if err != nil || position < 1 {
+		return 0, errInvalidPosition
+	}
+	return position, nil
+}
+
+// Reads bulk import options from query params, mode is insert by default.
+// The result must be validated.
+func parseBulkOptions(query url.Values) (*domain.BulkOptions, error) {
+	dryRun, err := parseBool(query, "dry_run")
+	if err != nil {
+		return nil, errInvalidDryRun
+	}
+
+	mode := query.Get("mode")
+	if mode == "" {
+		mode = domain.BulkInsert
+	}
+
+	return &domain.BulkOptions{
+		Mode:   mode,
+		DryRun: dryRun,
+	}, nil
+}
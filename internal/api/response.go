@@ -1,15 +1,124 @@
 package api
 
-import "github.com/qreaqtor/music-library/internal/domain"
+import (
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+)
 
+// Lyrics are texts of Verses, they are kept for clients which don't need sections.
+// Cursors are empty if there is no next or previous page, Total is set if it was requested.
+// Language is language of translations in Verses, empty if lyrics are not translated.
 type getLyricsResponse struct {
-	Lyrics []string
+	Lyrics     []string
+	Verses     []*domain.Verse
+	Language   string `json:"language,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+	Total      *int   `json:"total,omitempty"`
 }
 
+// Suggestions are set if exact search by group or song name found nothing.
+// Cursors are empty if there is no next or previous page, Total is set if it was requested.
 type searchResponse struct {
-	Songs []*domain.Song
+	Songs       []*domain.FoundSong
+	Suggestions *domain.Suggestions `json:",omitempty"`
+	NextCursor  string              `json:"next_cursor,omitempty"`
+	PrevCursor  string              `json:"prev_cursor,omitempty"`
+	Total       *int                `json:"total,omitempty"`
+	Facets      *domain.Facets      `json:"facets,omitempty"`
 }
 
 type messageResponse struct {
 	Message string
 }
+
+type createResponse struct {
+	Message string
+	ID      uuid.UUID
+}
+
+type artistsResponse struct {
+	Artists []*domain.Artist
+}
+
+type albumsResponse struct {
+	Albums []*domain.Album
+}
+
+type tracksResponse struct {
+	Tracks []*domain.Track
+}
+
+type playlistsResponse struct {
+	Playlists []*domain.Playlist
+}
+
+type playlistItemsResponse struct {
+	Items []*domain.PlaylistItem
+}
+
+// Message is the reason, Permission is the missing one and Role is the role of the user,
+// it is empty for anonymous requests.
+type forbiddenResponse struct {
+	Message    string
+	Permission string
+	Role       string `json:",omitempty"`
+}
+
+type usersResponse struct {
+	Users []*domain.User
+}
+
+type apiKeysResponse struct {
+	Keys []*domain.APIKey
+}
+
+// Revoked is the number of sessions which were active.
+type revokedResponse struct {
+	Revoked int
+}
+
+type revisionsResponse struct {
+	Revisions []*domain.Revision
+}
+
+type trashResponse struct {
+	Songs []*domain.TrashedSong
+}
+
+type genresResponse struct {
+	Genres []*domain.Genre
+}
+
+type favoritesResponse struct {
+	Songs []*domain.FavoriteSong
+}
+
+type historyResponse struct {
+	Plays []*domain.Play
+}
+
+type topResponse struct {
+	Period string
+	Songs  []*domain.TopSong
+}
+
+// Counts are numbers of Results with each status, Results are ordered by index.
+// Error is set if the import was stopped, Results then contain items processed before it.
+type bulkResponse struct {
+	Created int
+	Updated int
+	Skipped int
+	Failed  int
+	Results []*domain.BulkResult
+	Error   string `json:",omitempty"`
+}
+
+// Counts are numbers of Results with each status, Results are in the order of uploaded files.
+type tagsResponse struct {
+	Created int
+	Matched int
+	Updated int
+	Skipped int
+	Results []*domain.TagsResult
+}
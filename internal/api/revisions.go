@@ -0,0 +1,178 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+	"github.com/qreaqtor/music-library/pkg/web"
+)
+
+// @Summary List song revisions
+// @Description List revisions of the song newest first, every change of the song is written as a revision
+// @Tags revisions
+// @Produce json
+// @Param id path string true "Song id"
+// @Param offset query int false "Offset, 0 by default"
+// @Param limit query int false "Limit, 20 by default" maximum(100)
+// @Success 200 {object} revisionsResponse
+// @Failure 404 {string} string "Unknown song"
+// @Router /v2/songs/{id}/revisions [get]
+func (s *SongsAPI) getSongRevisions(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	batch := parseBatch(r.URL.Query())
+
+	err = s.valid.StructCtx(r.Context(), batch)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
+		return
+	}
+
+	revisions, err := s.srv.Revisions(r.Context(), &domain.Song{ID: id}, batch)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		revisionsResponse{
+			Revisions: revisions,
+		},
+	)
+}
+
+// @Summary Get song revision
+// @Description Retrieve the revision with the full state of the song after it
+// @Tags revisions
+// @Produce json
+// @Param id path string true "Song id"
+// @Param number path int true "Revision number"
+// @Success 200 {object} domain.Revision
+// @Failure 404 {string} string "Unknown song or revision"
+// @Router /v2/songs/{id}/revisions/{number} [get]
+func (s *SongsAPI) getSongRevision(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	number, err := parseRevision(mux.Vars(r)["number"])
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	revision, err := s.srv.Revision(r.Context(), &domain.Song{ID: id}, number)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		revision,
+	)
+}
+
+// @Summary Diff song revisions
+// @Description Compare metadata and lyrics of the song after two revisions, lyrics are compared line by line
+// @Tags revisions
+// @Produce json
+// @Param id path string true "Song id"
+// @Param from query int true "Number of the old revision"
+// @Param to query int true "Number of the new revision"
+// @Success 200 {object} domain.RevisionDiff
+// @Failure 404 {string} string "Unknown song or revision"
+// @Router /v2/songs/{id}/diff [get]
+func (s *SongsAPI) diffSongRevisions(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	from, err := parseRevision(r.URL.Query().Get("from"))
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	to, err := parseRevision(r.URL.Query().Get("to"))
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	diff, err := s.srv.Diff(r.Context(), &domain.Song{ID: id}, from, to)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		diff,
+	)
+}
+
+// @Summary Restore song revision
+// @Description Set metadata and lyrics of the song to the state after the revision.
+// @Description Restore is written as a new revision, translations are deleted with replaced lyrics.
+// @Tags revisions
+// @Produce json
+// @Param id path string true "Song id"
+// @Param number path int true "Revision number"
+// @Success 200 {object} domain.SongInfo
+// @Failure 404 {string} string "Unknown song or revision"
+// @Failure 409 {object} createResponse "Another song has the name of the revision, ID is id of the existing song"
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Security BearerAuth
+// @Router /v2/songs/{id}/revisions/{number}/restore [post]
+func (s *SongsAPI) restoreSongRevision(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	number, err := parseRevision(mux.Vars(r)["number"])
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	songInfo, err := s.srv.Restore(r.Context(), &domain.Song{ID: id}, number)
+	if writeSongExists(w, msg, err) {
+		return
+	}
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		songInfo,
+	)
+}
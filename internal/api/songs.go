@@ -4,12 +4,15 @@ import (
 	"context"
 	"net/http"
 	"strconv"
-	"time"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/qreaqtor/music-library/internal/domain"
+	audiotag "github.com/qreaqtor/music-library/pkg/audioTag"
+	"github.com/qreaqtor/music-library/pkg/cursor"
 	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+	"github.com/qreaqtor/music-library/pkg/lrc"
 	"github.com/qreaqtor/music-library/pkg/web"
 	httpSwagger "github.com/swaggo/http-swagger"
 
@@ -17,31 +20,69 @@ import (
 )
 
 type service interface {
-	Info(context.Context, *domain.Song) (*domain.SongInfo, error)
-	Create(context.Context, *domain.Song) error
+	Info(context.Context, *domain.Song, ...string) (*domain.SongInfo, error)
+	Create(context.Context, *domain.Song) (uuid.UUID, error)
 	Delete(context.Context, *domain.Song) error
 	Update(context.Context, *domain.Song, *domain.SongUpdate) error
-	GetLyrics(context.Context, *domain.Song, *domain.Batch) ([]string, error)
-	Search(context.Context, *domain.SongSearch) ([]*domain.Song, error)
+	GetLyrics(context.Context, *domain.Song, *domain.LyricsBatch) (*domain.LyricsResult, error)
+	Search(context.Context, *domain.SongSearch) (*domain.SearchResult, error)
+	ImportLRC(context.Context, *domain.Song, *lrc.File) error
+	ExportLRC(context.Context, *domain.Song) (*lrc.File, error)
+	Languages(context.Context, *domain.Song) (*domain.Languages, error)
+	Translation(context.Context, *domain.Song, string) (*domain.Translation, error)
+	SetTranslation(context.Context, *domain.Song, *domain.Translation) error
+	DeleteTranslation(context.Context, *domain.Song, string) error
+	Revisions(context.Context, *domain.Song, *domain.Batch) ([]*domain.Revision, error)
+	Revision(context.Context, *domain.Song, int) (*domain.Revision, error)
+	Diff(context.Context, *domain.Song, int, int) (*domain.RevisionDiff, error)
+	Restore(context.Context, *domain.Song, int) (*domain.SongInfo, error)
+	Trash(context.Context, *domain.Batch) ([]*domain.TrashedSong, error)
+	Untrash(context.Context, *domain.Song) error
+	Import(context.Context, []*domain.BulkSong, *domain.BulkOptions) ([]*domain.BulkResult, error)
+	Export(context.Context, *domain.SongSearch, func(*domain.ExportedSong) error) error
+	ImportTags(context.Context, []*audiotag.Tags, *domain.BulkOptions) ([]*domain.TagsResult, error)
+	Favorite(context.Context, *domain.Song) error
+	Unfavorite(context.Context, *domain.Song) error
+	Favorites(context.Context, *domain.Batch) ([]*domain.FavoriteSong, error)
+	Rate(context.Context, *domain.Song, *domain.RatingUpdate) error
+	Unrate(context.Context, *domain.Song) error
+	Play(context.Context, *domain.Song) error
+	History(context.Context, *domain.Batch) ([]*domain.Play, error)
+	Top(context.Context, *domain.TopSearch) ([]*domain.TopSong, error)
+	CreateGenre(context.Context, *domain.Genre) (*domain.Genre, error)
+	Genres(context.Context) ([]*domain.Genre, error)
+	UpdateGenre(context.Context, *domain.Genre) (*domain.Genre, error)
+	DeleteGenre(context.Context, uuid.UUID) error
+	SetGenres(context.Context, *domain.Song, *domain.SongGenres) error
+	SetTags(context.Context, *domain.Song, *domain.SongTags) error
+	SetCredits(context.Context, *domain.Song, *domain.SongCredits) error
 }
 
 type SongsAPI struct {
 	srv service
 
 	valid *validator.Validate
+
+	// signs pagination cursors of search and lyrics
+	cursors *cursor.Signer
 }
 
-func NewSongsAPI(srv service) *SongsAPI {
+func NewSongsAPI(srv service, cursors *cursor.Signer) *SongsAPI {
 	return &SongsAPI{
-		srv:   srv,
-		valid: validator.New(validator.WithRequiredStructEnabled()),
+		srv:     srv,
+		valid:   validator.New(validator.WithRequiredStructEnabled()),
+		cursors: cursors,
 	}
 }
 
 // @title Music-library API
 // @version 1.0
 // @description This is an implementation of an online song library
-// @BasePath /v1
+// @BasePath /
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Access token of /v2/auth/login as "Bearer <token>", required for changes
 func (s *SongsAPI) Register(r *mux.Router) {
 	groupAndSong := []string{
 		"group", "{group:.+}",
@@ -69,6 +110,13 @@ func (s *SongsAPI) Register(r *mux.Router) {
 
 	r.Path("/search").HandlerFunc(s.search).Methods(http.MethodGet)
 
+	r.Path("/trash").HandlerFunc(s.trash).Methods(http.MethodGet)
+
+	r.Path("/export").HandlerFunc(s.export).Methods(http.MethodGet)
+
+	r.Path("/restore").HandlerFunc(s.untrash).Methods(http.MethodPost).
+		Queries(groupAndSong...)
+
 	r.PathPrefix("/swagger/").Handler(httpSwagger.Handler(
 		httpSwagger.URL("http://localhost:50055/v1/swagger/doc.json"), //The url pointing to API definition
 		httpSwagger.DeepLinking(true),
@@ -84,8 +132,10 @@ func (s *SongsAPI) Register(r *mux.Router) {
 // @Produce json
 // @Param group query string true "Group name"
 // @Param song query string true "Song name"
+// @Param lang query string false "Language of translation, Accept-Language header is used if it is not provided"
 // @Success 200 {object} domain.SongInfo
-// @Router /info [get]
+// @Failure 404 {string} string "Unknown song"
+// @Router /v1/info [get]
 func (s *SongsAPI) info(w http.ResponseWriter, r *http.Request) {
 	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
 
@@ -94,12 +144,18 @@ func (s *SongsAPI) info(w http.ResponseWriter, r *http.Request) {
 		SongName: r.URL.Query().Get("song"),
 	}
 
-	songInfo, err := s.srv.Info(r.Context(), song)
+	languages, err := parseLanguages(w, r)
 	if err != nil {
 		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
 		return
 	}
 
+	songInfo, err := s.srv.Info(r.Context(), song, languages...)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
 	web.WriteData(
 		w,
 		msg.With("OK", http.StatusOK),
@@ -114,10 +170,15 @@ func (s *SongsAPI) info(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param group query string true "Group name"
 // @Param song query string true "Song name"
-// @Param offset query int true "Offset for batch"
-// @Param limit query int true "Limit for batch"
+// @Param offset query int true "Offset for batch, ignored if cursor is provided"
+// @Param limit query int true "Limit for batch" maximum(100)
+// @Param cursor query string false "next_cursor or prev_cursor of the previous response"
+// @Param total query bool false "Count all verses of the song, only of the section if it is provided"
+// @Param section query string false "Return only verses of this section" Enums(intro, verse, pre-chorus, chorus, bridge, outro)
+// @Param lang query string false "Language of translation, Accept-Language header is used if it is not provided"
 // @Success 200 {object} getLyricsResponse
-// @Router /lyrics [get]
+// @Failure 404 {string} string "Unknown song"
+// @Router /v1/lyrics [get]
 func (s *SongsAPI) getLyrics(w http.ResponseWriter, r *http.Request) {
 	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
 
@@ -130,24 +191,40 @@ func (s *SongsAPI) getLyrics(w http.ResponseWriter, r *http.Request) {
 	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
 	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
 
-	batch := &domain.Batch{
+	batch, err := parseLyricsBatch(r.URL.Query(), &domain.Batch{
 		Offset: offset,
 		Limit:  limit,
+	})
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
 	}
 
-	text, err := s.srv.GetLyrics(r.Context(), song, batch)
+	err = s.readLyricsCursor(r, batch)
 	if err != nil {
 		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
 		return
 	}
 
-	web.WriteData(
-		w,
-		msg.With("OK", http.StatusOK),
-		getLyricsResponse{
-			Lyrics: text,
-		},
-	)
+	batch.Languages, err = parseLanguages(w, r)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	err = s.valid.StructCtx(r.Context(), batch)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
+		return
+	}
+
+	lyrics, err := s.srv.GetLyrics(r.Context(), song, batch)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	s.writeLyrics(w, r, msg, lyrics)
 }
 
 // @Summary Search for songs
@@ -157,72 +234,54 @@ func (s *SongsAPI) getLyrics(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param by_group query string false "Search by group name"
 // @Param by_song_name query string false "Search by song name"
-// @Param by_lyrics query string false "Search by lyrics"
+// @Param by_lyrics query string false "Full text search by lyrics, supports phrases in double quotes, or and -excluded words. Results are ordered by rank"
 // @Param by_link query string false "Search by external link"
 // @Param date_from query string false "Search songs from this date"
 // @Param date_to query string false "Search songs up to this date"
-// @Param offset query int true "Offset for batch"
-// @Param limit query int true "Limit for batch"
+// @Param fuzzy query bool false "Match group and song name by trigram similarity, tolerates typos"
+// @Param threshold query number false "Min similarity for fuzzy search and suggestions, from 0 to 1"
+// @Param sort query string false "Sort field, relevance by default for lyrics and fuzzy search, otherwise created_at" Enums(group, song, release_date, created_at, updated_at, relevance, plays, rating)
+// @Param order query string false "Sort order, desc by default for relevance, plays and rating, otherwise asc" Enums(asc, desc)
+// @Param offset query int true "Offset for batch, ignored if cursor is provided"
+// @Param limit query int true "Limit for batch" maximum(100)
+// @Param cursor query string false "next_cursor or prev_cursor of the previous response"
+// @Param genre query string false "Genre name, songs of its descendant genres are found too"
+// @Param tag query []string false "Tags, songs with any of them are found" collectionFormat(multi)
+// @Param tag_match query string false "Find songs with any or all of the tags, any by default" Enums(any, all)
+// @Param credit query string false "Credited artist name or alias"
+// @Param credit_role query string false "Role of the credited artist, any artist in the role without credit" Enums(primary, featuring, composer, lyricist, producer)
+// @Param total query bool false "Count all found songs"
+// @Param facets query bool false "Count found songs per genre, tag and decade"
 // @Success 200 {object} searchResponse
-// @Router /search [get]
+// @Router /v1/search [get]
 func (s *SongsAPI) search(w http.ResponseWriter, r *http.Request) {
 	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
 
-	var dateFrom, dateTo time.Time
-	if dateFromStr := r.URL.Query().Get("date_from"); dateFromStr != "" {
-		parsedDate, err := time.Parse("2006-01-02", dateFromStr)
-		if err != nil {
-			web.WriteError(w, msg.With("Invalid date_from format, use YYYY-MM-DD", http.StatusBadRequest))
-			return
-		}
-		dateFrom = parsedDate
-	}
-
-	if dateToStr := r.URL.Query().Get("date_to"); dateToStr != "" {
-		parsedDate, err := time.Parse("2006-01-02", dateToStr)
-		if err != nil {
-			web.WriteError(w, msg.With("Invalid date_to format, use YYYY-MM-DD", http.StatusBadRequest))
-			return
-		}
-		dateTo = parsedDate
-	}
-
-	// ignore errors, because i used regexp for this query params
-	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
-	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-
-	search := &domain.SongSearch{
-		ByGroup:    r.URL.Query().Get("by_group"),
-		BySongName: r.URL.Query().Get("by_song_name"),
-		ByLyrics:   r.URL.Query().Get("by_lyrics"),
-		ByLink:     r.URL.Query().Get("by_link"),
-		DateFrom:   dateFrom,
-		DateTo:     dateTo,
-		Batch: domain.Batch{
-			Offset: offset,
-			Limit:  limit,
-		},
+	search, err := parseSongSearch(r)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
 	}
 
-	err := s.valid.StructCtx(r.Context(), search)
+	err = s.valid.StructCtx(r.Context(), search)
 	if err != nil {
 		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
 		return
 	}
 
-	songs, err := s.srv.Search(r.Context(), search)
+	err = s.readSearchCursor(r, search)
 	if err != nil {
 		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
 		return
 	}
 
-	web.WriteData(
-		w,
-		msg.With("OK", http.StatusOK),
-		searchResponse{
-			Songs: songs,
-		},
-	)
+	result, err := s.srv.Search(r.Context(), search)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	s.writeSearchResult(w, r, msg, result)
 }
 
 // @Summary Update song information
@@ -234,7 +293,10 @@ func (s *SongsAPI) search(w http.ResponseWriter, r *http.Request) {
 // @Param song query string true "Song name"
 // @Param update body domain.SongUpdate true "Update parameters"
 // @Success 200 {object} messageResponse
-// @Router /update [patch]
+// @Failure 404 {string} string "Unknown song"
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Security BearerAuth
+// @Router /v1/update [patch]
 func (s *SongsAPI) update(w http.ResponseWriter, r *http.Request) {
 	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
 
@@ -259,7 +321,7 @@ func (s *SongsAPI) update(w http.ResponseWriter, r *http.Request) {
 
 	err = s.srv.Update(r.Context(), song, songUpdate)
 	if err != nil {
-		web.WriteError(w, msg.With(err.Error(), http.StatusNotFound))
+		writeError(w, msg, err)
 		return
 	}
 
@@ -271,14 +333,17 @@ func (s *SongsAPI) update(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Delete a song
-// @Description Remove a song from the database
+// @Description Move a song to trash, it can be restored until it is purged
 // @Tags songs
 // @Accept json
 // @Produce json
 // @Param group query string true "Group name"
 // @Param song query string true "Song name"
 // @Success 200 {object} messageResponse
-// @Router /delete [delete]
+// @Failure 404 {string} string "Unknown song"
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Security BearerAuth
+// @Router /v1/delete [delete]
 func (s *SongsAPI) delete(w http.ResponseWriter, r *http.Request) {
 	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
 
@@ -289,7 +354,7 @@ func (s *SongsAPI) delete(w http.ResponseWriter, r *http.Request) {
 
 	err := s.srv.Delete(r.Context(), song)
 	if err != nil {
-		web.WriteError(w, msg.With(err.Error(), http.StatusNotFound))
+		writeError(w, msg, err)
 		return
 	}
 
@@ -301,13 +366,16 @@ func (s *SongsAPI) delete(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Create a new song
-// @Description Add a new song to the database
+// @Description Add a new song to the database, release date, lyrics and link are requested from the song details provider
 // @Tags songs
 // @Accept json
 // @Produce json
 // @Param song body domain.Song true "Song data"
-// @Success 200 {object} messageResponse
-// @Router /create [post]
+// @Success 200 {object} createResponse
+// @Failure 409 {object} createResponse "Song already exists, ID is id of the existing song"
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Security BearerAuth
+// @Router /v1/create [post]
 func (s *SongsAPI) create(w http.ResponseWriter, r *http.Request) {
 	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
 
@@ -325,15 +393,21 @@ func (s *SongsAPI) create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = s.srv.Create(r.Context(), song)
+	id, err := s.srv.Create(r.Context(), song)
+	if writeSongExists(w, msg, err) {
+		return
+	}
 	if err != nil {
-		web.WriteError(w, msg.With(err.Error(), http.StatusNotFound))
+		writeError(w, msg, err)
 		return
 	}
 
 	web.WriteData(
 		w,
 		msg.With("OK", http.StatusOK),
-		messageResponse{"ok"},
+		createResponse{
+			Message: "ok",
+			ID:      id,
+		},
 	)
 }
@@ -0,0 +1,385 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+	"github.com/qreaqtor/music-library/pkg/web"
+)
+
+// Registers resource-style routes, songs are addressed by id.
+func (s *SongsAPI) RegisterV2(r *mux.Router) {
+	r.Path("/songs").HandlerFunc(s.createSong).Methods(http.MethodPost)
+
+	r.Path("/songs").HandlerFunc(s.searchSongs).Methods(http.MethodGet)
+
+	r.Path("/songs:bulk").HandlerFunc(s.importSongs).Methods(http.MethodPost)
+
+	r.Path("/songs:import-tags").HandlerFunc(s.importTags).Methods(http.MethodPost)
+
+	r.Path("/songs/{id}").HandlerFunc(s.getSong).Methods(http.MethodGet)
+
+	r.Path("/songs/{id}").HandlerFunc(s.updateSong).Methods(http.MethodPatch)
+
+	r.Path("/songs/{id}").HandlerFunc(s.deleteSong).Methods(http.MethodDelete)
+
+	r.Path("/songs/{id}/lyrics").HandlerFunc(s.getSongLyrics).Methods(http.MethodGet)
+
+	r.Path("/songs/{id}/lyrics/lrc").HandlerFunc(s.exportSongLRC).Methods(http.MethodGet)
+
+	r.Path("/songs/{id}/lyrics/lrc").HandlerFunc(s.importSongLRC).Methods(http.MethodPut)
+
+	r.Path("/songs/{id}/translations").HandlerFunc(s.getSongLanguages).Methods(http.MethodGet)
+
+	r.Path("/songs/{id}/translations/{lang}").HandlerFunc(s.getSongTranslation).Methods(http.MethodGet)
+
+	r.Path("/songs/{id}/translations/{lang}").HandlerFunc(s.putSongTranslation).Methods(http.MethodPut)
+
+	r.Path("/songs/{id}/translations/{lang}").HandlerFunc(s.deleteSongTranslation).Methods(http.MethodDelete)
+
+	r.Path("/songs/{id}/revisions").HandlerFunc(s.getSongRevisions).Methods(http.MethodGet)
+
+	r.Path("/songs/{id}/revisions/{number}").HandlerFunc(s.getSongRevision).Methods(http.MethodGet)
+
+	r.Path("/songs/{id}/revisions/{number}/restore").HandlerFunc(s.restoreSongRevision).Methods(http.MethodPost)
+
+	r.Path("/songs/{id}/diff").HandlerFunc(s.diffSongRevisions).Methods(http.MethodGet)
+
+	r.Path("/songs/{id}/genres").HandlerFunc(s.putSongGenres).Methods(http.MethodPut)
+
+	r.Path("/songs/{id}/tags").HandlerFunc(s.putSongTags).Methods(http.MethodPut)
+
+	r.Path("/songs/{id}/credits").HandlerFunc(s.putSongCredits).Methods(http.MethodPut)
+
+	r.Path("/songs/{id}/favorite").HandlerFunc(s.favoriteSong).Methods(http.MethodPut)
+
+	r.Path("/songs/{id}/favorite").HandlerFunc(s.unfavoriteSong).Methods(http.MethodDelete)
+
+	r.Path("/songs/{id}/rating").HandlerFunc(s.rateSong).Methods(http.MethodPut)
+
+	r.Path("/songs/{id}/rating").HandlerFunc(s.unrateSong).Methods(http.MethodDelete)
+
+	r.Path("/songs/{id}/plays").HandlerFunc(s.playSong).Methods(http.MethodPost)
+
+	r.Path("/me/favorites").HandlerFunc(s.favorites).Methods(http.MethodGet)
+
+	r.Path("/me/history").HandlerFunc(s.history).Methods(http.MethodGet)
+
+	r.Path("/top").HandlerFunc(s.top).Methods(http.MethodGet)
+
+	r.Path("/genres").HandlerFunc(s.genres).Methods(http.MethodGet)
+
+	r.Path("/genres").HandlerFunc(s.createGenre).Methods(http.MethodPost)
+
+	r.Path("/genres/{id}").HandlerFunc(s.updateGenre).Methods(http.MethodPut)
+
+	r.Path("/genres/{id}").HandlerFunc(s.deleteGenre).Methods(http.MethodDelete)
+
+	r.Path("/trash").HandlerFunc(s.trash).Methods(http.MethodGet)
+
+	r.Path("/export").HandlerFunc(s.export).Methods(http.MethodGet)
+
+	r.Path("/trash/{id}/restore").HandlerFunc(s.untrashSong).Methods(http.MethodPost)
+}
+
+// @Summary Create a new song
+// @Description Add a new song, release date, lyrics and link are requested from the song details provider
+// @Tags songs v2
+// @Accept json
+// @Produce json
+// @Param song body domain.Song true "Song data, id is ignored"
+// @Success 201 {object} domain.SongInfo
+// @Header 201 {string} Location "URL of the created song"
+// @Failure 409 {object} createResponse "Song already exists, ID is id of the existing song"
+// @Header 409 {string} Location "URL of the existing song"
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Security BearerAuth
+// @Router /v2/songs [post]
+func (s *SongsAPI) createSong(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	song := &domain.Song{}
+
+	err := web.ReadRequestBody(r, song)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	err = s.valid.StructCtx(r.Context(), song)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
+		return
+	}
+
+	song.ID = uuid.Nil
+
+	id, err := s.srv.Create(r.Context(), song)
+	if writeSongExists(w, msg, err) {
+		return
+	}
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	songInfo, err := s.srv.Info(r.Context(), &domain.Song{ID: id})
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	w.Header().Set("Location", r.URL.Path+"/"+id.String())
+
+	web.WriteData(
+		w,
+		msg.With("Created", http.StatusCreated),
+		songInfo,
+	)
+}
+
+// @Summary Search for songs
+// @Description Search for songs based on various criteria
+// @Tags songs v2
+// @Accept json
+// @Produce json
+// @Param by_group query string false "Search by group name"
+// @Param by_song_name query string false "Search by song name"
+// @Param by_lyrics query string false "Full text search by lyrics, supports phrases in double quotes, or and -excluded words. Results are ordered by rank"
+// @Param by_link query string false "Search by external link"
+// @Param date_from query string false "Search songs from this date"
+// @Param date_to query string false "Search songs up to this date"
+// @Param fuzzy query bool false "Match group and song name by trigram similarity, tolerates typos"
+// @Param threshold query number false "Min similarity for fuzzy search and suggestions, from 0 to 1"
+// @Param sort query string false "Sort field, relevance by default for lyrics and fuzzy search, otherwise created_at" Enums(group, song, release_date, created_at, updated_at, relevance, plays, rating)
+// @Param order query string false "Sort order, desc by default for relevance, plays and rating, otherwise asc" Enums(asc, desc)
+// @Param offset query int false "Offset for batch, ignored if cursor is provided" default(0)
+// @Param limit query int false "Limit for batch" default(20) maximum(100)
+// @Param cursor query string false "next_cursor or prev_cursor of the previous response"
+// @Param genre query string false "Genre name, songs of its descendant genres are found too"
+// @Param tag query []string false "Tags, songs with any of them are found" collectionFormat(multi)
+// @Param tag_match query string false "Find songs with any or all of the tags, any by default" Enums(any, all)
+// @Param credit query string false "Credited artist name or alias"
+// @Param credit_role query string false "Role of the credited artist, any artist in the role without credit" Enums(primary, featuring, composer, lyricist, producer)
+// @Param total query bool false "Count all found songs"
+// @Param facets query bool false "Count found songs per genre, tag and decade"
+// @Success 200 {object} searchResponse
+// @Router /v2/songs [get]
+func (s *SongsAPI) searchSongs(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	search, err := parseSongSearch(r)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	if !r.URL.Query().Has("limit") {
+		search.Limit = defaultLimit
+	}
+
+	err = s.valid.StructCtx(r.Context(), search)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
+		return
+	}
+
+	err = s.readSearchCursor(r, search)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	result, err := s.srv.Search(r.Context(), search)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	s.writeSearchResult(w, r, msg, result)
+}
+
+// @Summary Get song
+// @Description Retrieve detailed information about a song by id
+// @Tags songs v2
+// @Accept json
+// @Produce json
+// @Param id path string true "Song id"
+// @Param lang query string false "Language of translation, Accept-Language header is used if it is not provided"
+// @Success 200 {object} domain.SongInfo
+// @Failure 404 {string} string "Unknown song"
+// @Router /v2/songs/{id} [get]
+func (s *SongsAPI) getSong(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	languages, err := parseLanguages(w, r)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	songInfo, err := s.srv.Info(r.Context(), &domain.Song{ID: id}, languages...)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		songInfo,
+	)
+}
+
+// @Summary Update song
+// @Description Update details of a song including group, name, lyrics, link, and release date
+// @Tags songs v2
+// @Accept json
+// @Produce json
+// @Param id path string true "Song id"
+// @Param update body domain.SongUpdate true "Update parameters"
+// @Success 200 {object} domain.SongInfo
+// @Failure 404 {string} string "Unknown song"
+// @Failure 409 {object} createResponse "Song is renamed to existing one, ID is id of the existing song"
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Security BearerAuth
+// @Router /v2/songs/{id} [patch]
+func (s *SongsAPI) updateSong(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	songUpdate := &domain.SongUpdate{}
+
+	err = web.ReadRequestBody(r, songUpdate)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	err = s.valid.StructCtx(r.Context(), songUpdate)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
+		return
+	}
+
+	song := &domain.Song{ID: id}
+
+	err = s.srv.Update(r.Context(), song, songUpdate)
+	if writeSongExists(w, msg, err) {
+		return
+	}
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	songInfo, err := s.srv.Info(r.Context(), song)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		songInfo,
+	)
+}
+
+// @Summary Delete song
+// @Description Move a song to trash by id, it can be restored until it is purged
+// @Tags songs v2
+// @Param id path string true "Song id"
+// @Success 204
+// @Failure 404 {string} string "Unknown song"
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Security BearerAuth
+// @Router /v2/songs/{id} [delete]
+func (s *SongsAPI) deleteSong(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	err = s.srv.Delete(r.Context(), &domain.Song{ID: id})
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteStatus(w, msg.With("No Content", http.StatusNoContent))
+}
+
+// @Summary Get song lyrics
+// @Description Retrieve lyrics of a song by id in batches
+// @Tags songs v2
+// @Accept json
+// @Produce json
+// @Param id path string true "Song id"
+// @Param offset query int false "Offset for batch, ignored if cursor is provided" default(0)
+// @Param limit query int false "Limit for batch" default(20) maximum(100)
+// @Param cursor query string false "next_cursor or prev_cursor of the previous response"
+// @Param total query bool false "Count all verses of the song, only of the section if it is provided"
+// @Param section query string false "Return only verses of this section" Enums(intro, verse, pre-chorus, chorus, bridge, outro)
+// @Param lang query string false "Language of translation, Accept-Language header is used if it is not provided"
+// @Success 200 {object} getLyricsResponse
+// @Failure 404 {string} string "Unknown song"
+// @Router /v2/songs/{id}/lyrics [get]
+func (s *SongsAPI) getSongLyrics(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	batch, err := parseLyricsBatch(r.URL.Query(), parseBatch(r.URL.Query()))
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	err = s.readLyricsCursor(r, batch)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	batch.Languages, err = parseLanguages(w, r)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	err = s.valid.StructCtx(r.Context(), batch)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
+		return
+	}
+
+	lyrics, err := s.srv.GetLyrics(r.Context(), &domain.Song{ID: id}, batch)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	s.writeLyrics(w, r, msg, lyrics)
+}
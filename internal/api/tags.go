@@ -0,0 +1,155 @@
+package api
+
+import (
+	"errors"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/qreaqtor/music-library/internal/domain"
+	audiotag "github.com/qreaqtor/music-library/pkg/audioTag"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+	"github.com/qreaqtor/music-library/pkg/web"
+)
+
+// Max size of all audio files of a single request.
+const maxTagsUpload = 1 << 30
+
+// Uploaded files are kept in memory up to this size, the rest is written to temporary files.
+const maxTagsMemory = 32 << 20
+
+var (
+	errNoAudioFiles = errors.New("no audio files, upload them as file fields of multipart form")
+	errTagsTooLarge = errors.New("audio files are too large")
+)
+
+// @Summary Import songs from audio tags
+// @Description Create songs from tags of uploaded audio files: ID3v2 (MP3), Vorbis comments (FLAC, Ogg) and MP4 atoms (M4A).
+// @Description Artist, title, date and lyrics are read, synced lyrics (SYLT or LRC in lyrics tag) are imported with timestamps.
+// @Description Every file gets a result: created, matched (song exists), updated (upsert mode) or skipped with a reason.
+// @Tags songs v2
+// @Accept mpfd
+// @Produce json
+// @Param file formData file true "Audio files, the field can be repeated"
+// @Param mode query string false "insert (default) leaves existing songs as is, upsert updates them"
+// @Param dry_run query bool false "Compute results without saving"
+// @Success 200 {object} tagsResponse
+// @Failure 400 {string} string "No files uploaded"
+// @Failure 413 {string} string "Files are too large"
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Security BearerAuth
+// @Router /v2/songs:import-tags [post]
+func (s *SongsAPI) importTags(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	options, err := parseBulkOptions(r.URL.Query())
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	err = s.valid.StructCtx(r.Context(), options)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxTagsUpload)
+
+	err = r.ParseMultipartForm(maxTagsMemory)
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		web.WriteError(w, msg.With(errTagsTooLarge.Error(), http.StatusRequestEntityTooLarge))
+		return
+	}
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	headers := r.MultipartForm.File["file"]
+	if len(headers) == 0 {
+		web.WriteError(w, msg.With(errNoAudioFiles.Error(), http.StatusBadRequest))
+		return
+	}
+
+	response := &tagsResponse{
+		Results: make([]*domain.TagsResult, 0, len(headers)),
+	}
+
+	for start := 0; start < len(headers); start += bulkBatchSize {
+		batch := headers[start:min(start+bulkBatchSize, len(headers))]
+
+		results, err := s.importTagsBatch(r, batch, options)
+		if err != nil {
+			writeError(w, msg, err)
+			return
+		}
+
+		for _, result := range results {
+			response.add(result)
+		}
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		response,
+	)
+}
+
+// Reads tags of the files and imports them, files which can't be read are skipped.
+func (s *SongsAPI) importTagsBatch(r *http.Request, headers []*multipart.FileHeader, options *domain.BulkOptions) ([]*domain.TagsResult, error) {
+	results := make([]*domain.TagsResult, len(headers))
+
+	files := make([]*audiotag.Tags, 0, len(headers))
+	positions := make([]int, 0, len(headers))
+
+	for i, header := range headers {
+		tags, err := readTags(header)
+		if err != nil {
+			results[i] = domain.SkippedFile(header.Filename, err)
+			continue
+		}
+
+		files = append(files, tags)
+		positions = append(positions, i)
+	}
+
+	imported, err := s.srv.ImportTags(r.Context(), files, options)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, result := range imported {
+		result.File = headers[positions[i]].Filename
+		results[positions[i]] = result
+	}
+
+	return results, nil
+}
+
+func readTags(header *multipart.FileHeader) (*audiotag.Tags, error) {
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return audiotag.Read(file)
+}
+
+func (t *tagsResponse) add(result *domain.TagsResult) {
+	switch result.Status {
+	case domain.TagsCreated:
+		t.Created++
+	case domain.TagsMatched:
+		t.Matched++
+	case domain.TagsUpdated:
+		t.Updated++
+	default:
+		t.Skipped++
+	}
+
+	t.Results = append(t.Results, result)
+}
@@ -0,0 +1,170 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+	"github.com/qreaqtor/music-library/pkg/web"
+)
+
+// @Summary Get song languages
+// @Description List languages of the song lyrics and languages it is translated to
+// @Tags translations
+// @Produce json
+// @Param id path string true "Song id"
+// @Success 200 {object} domain.Languages
+// @Failure 404 {string} string "Unknown song"
+// @Router /v2/songs/{id}/translations [get]
+func (s *SongsAPI) getSongLanguages(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	languages, err := s.srv.Languages(r.Context(), &domain.Song{ID: id})
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		languages,
+	)
+}
+
+// @Summary Get translation
+// @Description Retrieve translation of the song lyrics, verses are aligned with verses of the lyrics
+// @Tags translations
+// @Produce json
+// @Param id path string true "Song id"
+// @Param lang path string true "BCP 47 language code"
+// @Success 200 {object} domain.Translation
+// @Failure 404 {string} string "Unknown song or translation"
+// @Router /v2/songs/{id}/translations/{lang} [get]
+func (s *SongsAPI) getSongTranslation(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	lang, err := parseLanguage(mux.Vars(r)["lang"])
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	translation, err := s.srv.Translation(r.Context(), &domain.Song{ID: id}, lang)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		translation,
+	)
+}
+
+// @Summary Put translation
+// @Description Add or replace translation of the song lyrics, it must have as many verses as the lyrics.
+// @Description Translations are deleted when the lyrics are replaced. Language of the body is ignored.
+// @Tags translations
+// @Accept json
+// @Produce json
+// @Param id path string true "Song id"
+// @Param lang path string true "BCP 47 language code"
+// @Param translation body domain.Translation true "Translated verses"
+// @Success 200 {object} domain.Translation
+// @Failure 404 {string} string "Unknown song"
+// @Failure 422 {string} string "Number of verses differs from the lyrics"
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Security BearerAuth
+// @Router /v2/songs/{id}/translations/{lang} [put]
+func (s *SongsAPI) putSongTranslation(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	lang, err := parseLanguage(mux.Vars(r)["lang"])
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	translation := &domain.Translation{}
+
+	err = web.ReadRequestBody(r, translation)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	translation.Language = lang
+
+	err = s.valid.StructCtx(r.Context(), translation)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
+		return
+	}
+
+	err = s.srv.SetTranslation(r.Context(), &domain.Song{ID: id}, translation)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		translation,
+	)
+}
+
+// @Summary Delete translation
+// @Tags translations
+// @Param id path string true "Song id"
+// @Param lang path string true "BCP 47 language code"
+// @Success 204
+// @Failure 404 {string} string "Unknown song or translation"
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Security BearerAuth
+// @Router /v2/songs/{id}/translations/{lang} [delete]
+func (s *SongsAPI) deleteSongTranslation(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	lang, err := parseLanguage(mux.Vars(r)["lang"])
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	err = s.srv.DeleteTranslation(r.Context(), &domain.Song{ID: id}, lang)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteStatus(w, msg.With("No Content", http.StatusNoContent))
+}
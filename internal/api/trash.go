@@ -0,0 +1,126 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+	"github.com/qreaqtor/music-library/pkg/web"
+)
+
+// @Summary List trash
+// @Description List deleted songs, recently deleted first. Songs are purged after the retention period
+// @Tags trash
+// @Produce json
+// @Param offset query int false "Offset, 0 by default"
+// @Param limit query int false "Limit, 20 by default" maximum(100)
+// @Success 200 {object} trashResponse
+// @Router /v1/trash [get]
+// @Router /v2/trash [get]
+func (s *SongsAPI) trash(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	batch := parseBatch(r.URL.Query())
+
+	err := s.valid.StructCtx(r.Context(), batch)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
+		return
+	}
+
+	songs, err := s.srv.Trash(r.Context(), batch)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		trashResponse{
+			Songs: songs,
+		},
+	)
+}
+
+// @Summary Restore a song
+// @Description Move the most recently deleted song with the group and name out of trash
+// @Tags trash
+// @Produce json
+// @Param group query string true "Group name"
+// @Param song query string true "Song name"
+// @Success 200 {object} messageResponse
+// @Failure 404 {string} string "No such song in trash"
+// @Failure 409 {object} createResponse "Another song with the same name exists, ID is id of the existing song"
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Security BearerAuth
+// @Router /v1/restore [post]
+func (s *SongsAPI) untrash(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	song := &domain.Song{
+		Group:    r.URL.Query().Get("group"),
+		SongName: r.URL.Query().Get("song"),
+	}
+
+	err := s.srv.Untrash(r.Context(), song)
+	if writeSongExists(w, msg, err) {
+		return
+	}
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		messageResponse{"ok"},
+	)
+}
+
+// @Summary Restore a song
+// @Description Move a song out of trash by id
+// @Tags trash
+// @Produce json
+// @Param id path string true "Song id"
+// @Success 200 {object} domain.SongInfo
+// @Failure 404 {string} string "No such song in trash"
+// @Failure 409 {object} createResponse "Another song with the same name exists, ID is id of the existing song"
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Security BearerAuth
+// @Router /v2/trash/{id}/restore [post]
+func (s *SongsAPI) untrashSong(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	song := &domain.Song{ID: id}
+
+	err = s.srv.Untrash(r.Context(), song)
+	if writeSongExists(w, msg, err) {
+		return
+	}
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	songInfo, err := s.srv.Info(r.Context(), song)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		songInfo,
+	)
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+	"github.com/qreaqtor/music-library/pkg/web"
+)
+
+type usersService interface {
+	Users(context.Context, *domain.Batch) ([]*domain.User, error)
+	SetRole(context.Context, uuid.UUID, *domain.RoleUpdate) (*domain.User, error)
+}
+
+type UsersAPI struct {
+	srv usersService
+
+	valid *validator.Validate
+}
+
+func NewUsersAPI(srv usersService) *UsersAPI {
+	return &UsersAPI{
+		srv:   srv,
+		valid: validator.New(validator.WithRequiredStructEnabled()),
+	}
+}
+
+func (u *UsersAPI) Register(r *mux.Router) {
+	r.Path("/users").HandlerFunc(u.list).Methods(http.MethodGet)
+
+	r.Path("/users/{id}/role").HandlerFunc(u.setRole).Methods(http.MethodPatch)
+}
+
+// @Summary List users
+// @Description List users in order of registration, requires users:manage permission
+// @Tags users
+// @Produce json
+// @Param offset query int false "Offset, 0 by default"
+// @Param limit query int false "Limit, 20 by default" maximum(100)
+// @Success 200 {object} usersResponse
+// @Failure 401 {object} forbiddenResponse "Authentication required"
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Security BearerAuth
+// @Router /v2/users [get]
+func (u *UsersAPI) list(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	batch := parseBatch(r.URL.Query())
+
+	err := u.valid.StructCtx(r.Context(), batch)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
+		return
+	}
+
+	users, err := u.srv.Users(r.Context(), batch)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		usersResponse{
+			Users: users,
+		},
+	)
+}
+
+// @Summary Set role of user
+// @Description Change the role of the user, it applies to the next request of the user.
+// @Description Users can't change their own role, so the last admin is not lost. Requires users:manage permission.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "User id"
+// @Param role body domain.RoleUpdate true "New role"
+// @Success 200 {object} domain.User
+// @Failure 403 {object} forbiddenResponse "No permission"
+// @Failure 404 {string} string "Unknown user"
+// @Failure 422 {string} string "Unknown role or own role"
+// @Security BearerAuth
+// @Router /v2/users/{id}/role [patch]
+func (u *UsersAPI) setRole(w http.ResponseWriter, r *http.Request) {
+	msg := logmsg.NewLogMsg(r.Context(), r.RequestURI, r.Method)
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		web.WriteError(w, msg.With(errInvalidID.Error(), http.StatusBadRequest))
+		return
+	}
+
+	update := &domain.RoleUpdate{}
+
+	err = web.ReadRequestBody(r, update)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusBadRequest))
+		return
+	}
+
+	err = u.valid.StructCtx(r.Context(), update)
+	if err != nil {
+		web.WriteError(w, msg.With(err.Error(), http.StatusUnprocessableEntity))
+		return
+	}
+
+	user, err := u.srv.SetRole(r.Context(), id, update)
+	if err != nil {
+		writeError(w, msg, err)
+		return
+	}
+
+	web.WriteData(
+		w,
+		msg.With("OK", http.StatusOK),
+		user,
+	)
+}
@@ -5,14 +5,25 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"slices"
 
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/qreaqtor/music-library/internal/api"
+	songdetails "github.com/qreaqtor/music-library/internal/clients/songDetails"
 	"github.com/qreaqtor/music-library/internal/config"
+	"github.com/qreaqtor/music-library/internal/domain"
+	"github.com/qreaqtor/music-library/internal/policy"
 	"github.com/qreaqtor/music-library/internal/service"
-	storage "github.com/qreaqtor/music-library/internal/storage/postgres"
+	"github.com/qreaqtor/music-library/internal/storage/memory"
+	postgres "github.com/qreaqtor/music-library/internal/storage/postgres"
 
+	apikey "github.com/qreaqtor/music-library/pkg/apiKey"
 	appserver "github.com/qreaqtor/music-library/pkg/appServer"
+	authtoken "github.com/qreaqtor/music-library/pkg/authToken"
+	"github.com/qreaqtor/music-library/pkg/cursor"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
 
 	httpserver "github.com/qreaqtor/music-library/pkg/httpServer"
 )
@@ -23,11 +34,22 @@ type server interface {
 }
 
 type App struct {
+	ctx context.Context
+
 	server server
 
+	httpServer *httpserver.HTTPServer
+
+	// deletes songs from trash in background, nil until the app is started
+	purger     *service.Purger
+	stopPurger context.CancelFunc
+
 	cfg *config.Config
 
-	router *mux.Router
+	v1, v2 *mux.Router
+
+	// routes of authentication are available without it
+	auth *mux.Router
 
 	toClose []io.Closer
 }
@@ -35,41 +57,177 @@ type App struct {
 func NewApp(ctx context.Context, cfg *config.Config) *App {
 	setupLogger(cfg.Env)
 
-	r := mux.NewRouter().PathPrefix(fmt.Sprintf("/v%d", cfg.Api.Version)).Subrouter()
+	r := mux.NewRouter()
+
+	httpServer := httpserver.NewHTTPServer(r)
 
 	appServer := appserver.NewAppServer(
 		ctx,
-		httpserver.NewHTTPServer(r),
+		httpServer,
 		net.JoinHostPort(cfg.Host, fmt.Sprint(cfg.Port)),
 	)
 
+	// must be created before v2, routes are matched in order
+	auth := r.PathPrefix("/v2/auth").Subrouter()
+
 	return &App{
-		server:  appServer,
-		cfg:     cfg,
-		router:  r,
-		toClose: make([]io.Closer, 0),
+		ctx:        ctx,
+		server:     appServer,
+		httpServer: httpServer,
+		cfg:        cfg,
+		auth:       auth,
+		v1:         r.PathPrefix("/v1").Subrouter(),
+		v2:         r.PathPrefix("/v2").Subrouter(),
+		toClose:    make([]io.Closer, 0),
 	}
 }
 
 func (a *App) Start() error {
-	conn, err := getPostgresConn(a.cfg.Postgres)
+	var details service.SongDetailsProvider
+	if a.cfg.SongDetails.URL != "" {
+		details = songdetails.NewClient(a.cfg.SongDetails)
+	}
+
+	// changes require authentication, author of changes is saved in song revisions
+	for _, r := range []*mux.Router{a.v1, a.v2} {
+		r.Use(api.RequireUser, api.SetPrincipal)
+	}
+
+	permissions, err := policy.New(map[string][]string{
+		policy.Anonymous:  a.cfg.RBAC.Anonymous,
+		domain.RoleViewer: a.cfg.RBAC.Viewer,
+		domain.RoleEditor: a.cfg.RBAC.Editor,
+		domain.RoleAdmin:  a.cfg.RBAC.Admin,
+	})
 	if err != nil {
 		return err
 	}
 
-	st := storage.NewSongsStorage(conn)
-	srv := service.NewSongsService(st)
-	api := api.NewSongsAPI(srv)
-	api.Register(a.router)
+	if !slices.Contains(domain.Roles, a.cfg.RBAC.DefaultRole) {
+		return fmt.Errorf("%w: %q", errUnknownRole, a.cfg.RBAC.DefaultRole)
+	}
+
+	var (
+		srv       *service.SongsService
+		users     *service.AuthService
+		artists   *service.ArtistsService
+		albums    *service.AlbumsService
+		playlists *service.PlaylistsService
+		keys      *service.APIKeysService
+	)
+
+	tokens := authtoken.NewIssuer(a.cfg.Auth.Secret, "music-library")
+	keysGenerator := apikey.NewGenerator(domain.APIKeyKind)
+
+	switch a.cfg.Storage.Type {
+	case config.StorageMemory:
+		songs := memory.NewSongsStorage()
+
+		srv = service.NewSongsService(songs, details, a.cfg.Search.FuzzyThreshold)
+		users = service.NewAuthService(memory.NewUsersStorage(), tokens, a.cfg.Auth.AccessTTL, a.cfg.Auth.RefreshTTL, a.cfg.RBAC.DefaultRole)
+		artists = service.NewArtistsService(memory.NewArtistsStorage(songs))
+		albums = service.NewAlbumsService(memory.NewAlbumsStorage(songs))
+		playlists = service.NewPlaylistsService(memory.NewPlaylistsStorage(songs))
+		keys = service.NewAPIKeysService(memory.NewAPIKeysStorage(), keysGenerator)
+	case config.StoragePostgres:
+		conn, err := NewPostgresConn(a.cfg.Postgres)
+		if err != nil {
+			return err
+		}
+		a.toClose = append(a.toClose, conn)
+
+		srv = service.NewSongsService(postgres.NewSongsStorage(conn, a.cfg.Search.Language), details, a.cfg.Search.FuzzyThreshold)
+		users = service.NewAuthService(postgres.NewUsersStorage(conn), tokens, a.cfg.Auth.AccessTTL, a.cfg.Auth.RefreshTTL, a.cfg.RBAC.DefaultRole)
+		artists = service.NewArtistsService(postgres.NewArtistsStorage(conn))
+		albums = service.NewAlbumsService(postgres.NewAlbumsStorage(conn))
+		playlists = service.NewPlaylistsService(postgres.NewPlaylistsStorage(conn))
+		keys = service.NewAPIKeysService(postgres.NewAPIKeysStorage(conn), keysGenerator)
+	default:
+		return fmt.Errorf("%w: %q", errUnknownStorage, a.cfg.Storage.Type)
+	}
+
+	// registered users are never admins, so the first admin comes from config
+	if a.cfg.Auth.AdminName != "" {
+		err = a.ensureAdmin(users)
+		if err != nil {
+			return err
+		}
+	}
+
+	authAPI := api.NewAuthAPI(users)
+	authAPI.Register(a.auth)
+
+	keysAPI := api.NewAPIKeysAPI(policy.NewAPIKeysPolicy(keys, permissions), keys)
+	keysAPI.Register(a.v2)
+
+	a.httpServer.AddMiddlewares(httpserver.Authenticate(map[string]httpserver.Authenticator{
+		domain.TokenTypeBearer: authAPI.Authenticate,
+		domain.TokenTypeAPIKey: keysAPI.Authenticate,
+	}))
 
-	a.toClose = append(a.toClose, conn)
+	api.NewUsersAPI(policy.NewUsersPolicy(users, permissions)).Register(a.v2)
 
-	return a.server.Start()
+	// permissions are checked per operation of songs, the purger below uses the service directly
+	songsAPI := api.NewSongsAPI(policy.NewSongsPolicy(srv, permissions), cursor.NewSigner(a.cfg.Cursor.Secret))
+	songsAPI.Register(a.v1)
+	songsAPI.RegisterV2(a.v2)
+
+	// artists and albums were served by v1 before v2 was added, so they are kept there too
+	artistsAPI := api.NewArtistsAPI(policy.NewArtistsPolicy(artists, permissions))
+	artistsAPI.Register(a.v1)
+	artistsAPI.Register(a.v2)
+
+	albumsAPI := api.NewAlbumsAPI(policy.NewAlbumsPolicy(albums, permissions))
+	albumsAPI.Register(a.v1)
+	albumsAPI.Register(a.v2)
+
+	api.NewPlaylistsAPI(policy.NewPlaylistsPolicy(playlists, permissions)).Register(a.v2)
+
+	err = a.server.Start()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(a.ctx)
+	a.stopPurger = cancel
+
+	a.purger = service.NewPurger(srv, a.cfg.Trash.Retention, a.cfg.Trash.PurgeInterval)
+	a.purger.Start(ctx)
+
+	return nil
+}
+
+// Credentials of the admin are checked the same way as on registration.
+func (a *App) ensureAdmin(users *service.AuthService) error {
+	credentials := &domain.Credentials{
+		Name:     a.cfg.Auth.AdminName,
+		Password: a.cfg.Auth.AdminPassword,
+	}
+
+	err := validator.New(validator.WithRequiredStructEnabled()).Struct(credentials)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errAdmin, err)
+	}
+
+	ctx := context.WithValue(a.ctx, logmsg.OperationID, uuid.New())
+
+	_, err = users.EnsureAdmin(ctx, credentials)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errAdmin, err)
+	}
+
+	return nil
 }
 
 func (a *App) Wait() []error {
 	errs := a.server.Wait()
 
+	// purger uses the storage, so it must be stopped before connections are closed
+	if a.purger != nil {
+		a.stopPurger()
+		a.purger.Wait()
+	}
+
 	for _, closer := range a.toClose {
 		err := closer.Close()
 		if err != nil {
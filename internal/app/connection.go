@@ -9,7 +9,8 @@ import (
 	_ "github.com/lib/pq"
 )
 
-func getPostgresConn(cfg config.PostgresConfig) (*sql.DB, error) {
+// Opens connection pool, it is also used by command line tools.
+func NewPostgresConn(cfg config.PostgresConfig) (*sql.DB, error) {
 	sslMode := "disable"
 	if cfg.SSL {
 		sslMode = "enable"
@@ -0,0 +1,9 @@
+package app
+
+import "errors"
+
+var (
+	errUnknownStorage = errors.New("unknown storage type")
+	errUnknownRole    = errors.New("unknown default role")
+	errAdmin          = errors.New("admin from config is not set")
+)
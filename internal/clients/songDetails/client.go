@@ -0,0 +1,153 @@
+package songdetails
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/qreaqtor/music-library/internal/config"
+	"github.com/qreaqtor/music-library/internal/domain"
+	circuitbreaker "github.com/qreaqtor/music-library/pkg/circuitBreaker"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+)
+
+const infoPath = "/info"
+
+// Client is an HTTP client for the external song details provider.
+// Every request has its own timeout, failed requests are retried with
+// exponential backoff and jitter, repeated failures open the circuit breaker.
+type Client struct {
+	baseURL string
+
+	client  *http.Client
+	breaker *circuitbreaker.CircuitBreaker
+
+	retries    int
+	backoffMin time.Duration
+	backoffMax time.Duration
+}
+
+func NewClient(cfg config.SongDetailsConfig) *Client {
+	return &Client{
+		baseURL: cfg.URL,
+		client: &http.Client{
+			Timeout: cfg.Timeout,
+		},
+		breaker:    circuitbreaker.NewCircuitBreaker(cfg.BreakerThreshold, cfg.BreakerTimeout),
+		retries:    cfg.Retries,
+		backoffMin: cfg.BackoffMin,
+		backoffMax: cfg.BackoffMax,
+	}
+}
+
+// Returns ErrNotFound if provider does not know the song.
+func (c *Client) Details(ctx context.Context, song *domain.Song) (*domain.SongDetails, error) {
+	opID := logmsg.ExtractOperationID(ctx)
+
+	var (
+		details *domain.SongDetails
+		err     error
+	)
+
+	for attempt := 0; attempt <= c.retries; attempt++ {
+		if attempt > 0 {
+			err = c.wait(ctx, attempt)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		var getErr error
+
+		// canceled request tells nothing about the provider
+		err = c.breaker.ExecuteContext(ctx, func() error {
+			details, getErr = c.get(ctx, song)
+			if errors.Is(getErr, ErrNotFound) {
+				// provider works fine, it just does not know the song
+				return nil
+			}
+			return getErr
+		})
+		if err == nil {
+			return details, getErr
+		}
+		if !isRetryable(err) || ctx.Err() != nil {
+			return nil, err
+		}
+
+		slog.Debug(err.Error(), "operation", opID, "attempt", attempt+1)
+	}
+
+	return nil, err
+}
+
+func (c *Client) get(ctx context.Context, song *domain.Song) (*domain.SongDetails, error) {
+	query := url.Values{}
+	query.Set("group", song.Group)
+	query.Set("song", song.SongName)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+infoPath+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, ErrNotFound
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError:
+		return nil, fmt.Errorf("%w: %s", errUnavailable, resp.Status)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("%w: %s", errBadResponse, resp.Status)
+	}
+
+	detail := &songDetail{}
+	err = json.NewDecoder(resp.Body).Decode(detail)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errBadResponse, err)
+	}
+
+	return detail.toDomain()
+}
+
+// Sleeps before the next attempt, the delay grows exponentially up to backoffMax.
+// Full jitter is used, so clients don't retry simultaneously.
+func (c *Client) wait(ctx context.Context, attempt int) error {
+	delay := c.backoffMin << (attempt - 1)
+	if delay <= 0 || delay > c.backoffMax {
+		delay = c.backoffMax
+	}
+
+	if delay > 0 {
+		delay = time.Duration(rand.Int64N(int64(delay))) + 1
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Bad responses and open circuit breaker are not retried,
+// network errors, timeouts and 5xx are.
+func isRetryable(err error) bool {
+	return !errors.Is(err, errBadResponse) &&
+		!errors.Is(err, circuitbreaker.ErrOpen) &&
+		!errors.Is(err, context.Canceled)
+}
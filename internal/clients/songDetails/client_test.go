@@ -0,0 +1,276 @@
+package songdetails
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/clients/songDetails/fake"
+	"github.com/qreaqtor/music-library/internal/config"
+	"github.com/qreaqtor/music-library/internal/domain"
+	circuitbreaker "github.com/qreaqtor/music-library/pkg/circuitBreaker"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+)
+
+var muse = &domain.Song{Group: "Muse", SongName: "Supermassive Black Hole"}
+
+// Counts requests which reached the fake provider.
+type counter struct {
+	handler  http.Handler
+	requests atomic.Int32
+}
+
+func (c *counter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.requests.Add(1)
+	c.handler.ServeHTTP(w, r)
+}
+
+func newTestClient(t *testing.T, cfg config.SongDetailsConfig) (*Client, *fake.Server, *counter) {
+	t.Helper()
+
+	provider := fake.NewServer()
+	requests := &counter{handler: provider}
+
+	server := httptest.NewServer(requests)
+	t.Cleanup(server.Close)
+
+	cfg.URL = server.URL
+	if cfg.Timeout == 0 {
+		cfg.Timeout = time.Second
+	}
+	if cfg.BackoffMin == 0 {
+		cfg.BackoffMin, cfg.BackoffMax = time.Millisecond, 5*time.Millisecond
+	}
+	if cfg.BreakerThreshold == 0 {
+		cfg.BreakerThreshold = 100
+	}
+	if cfg.BreakerTimeout == 0 {
+		cfg.BreakerTimeout = time.Minute
+	}
+
+	return NewClient(cfg), provider, requests
+}
+
+func TestDetails(t *testing.T) {
+	client, _, requests := newTestClient(t, config.SongDetailsConfig{Retries: 3})
+
+	details, err := client.Details(newContext(), muse)
+	if err != nil {
+		t.Fatalf("Details: %v", err)
+	}
+
+	if details.Link != "https://www.youtube.com/watch?v=Xsp3_a-PMTw" {
+		t.Errorf("Details returned link %q", details.Link)
+	}
+	if want := time.Date(2006, time.July, 16, 0, 0, 0, 0, time.UTC); !details.ReleaseDate.Equal(want) {
+		t.Errorf("Details returned release date %v, want %v", details.ReleaseDate, want)
+	}
+	if n := requests.requests.Load(); n != 1 {
+		t.Errorf("provider got %d requests, want 1", n)
+	}
+}
+
+func TestDetailsNotFound(t *testing.T) {
+	client, _, requests := newTestClient(t, config.SongDetailsConfig{Retries: 3, BreakerThreshold: 1})
+
+	unknown := &domain.Song{Group: "Queen", SongName: "Bohemian Rhapsody"}
+
+	// unknown song is not a failure, so it is neither retried nor opens the breaker
+	for range 3 {
+		_, err := client.Details(newContext(), unknown)
+		if !errors.Is(err, ErrNotFound) {
+			t.Fatalf("Details returned %v, want %v", err, ErrNotFound)
+		}
+	}
+
+	if n := requests.requests.Load(); n != 3 {
+		t.Errorf("provider got %d requests, want 3", n)
+	}
+}
+
+func TestDetailsRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		retries  int
+		failures int
+		wantErr  error
+		wantReqs int32
+	}{
+		{"RecoversAfterFailures", 3, 2, nil, 3},
+		{"RetriesExhausted", 2, 10, errUnavailable, 3},
+		{"NoRetries", 0, 1, errUnavailable, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, provider, requests := newTestClient(t, config.SongDetailsConfig{Retries: tt.retries})
+			provider.FailNext(tt.failures)
+
+			_, err := client.Details(newContext(), muse)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Details returned %v, want %v", err, tt.wantErr)
+			}
+			if n := requests.requests.Load(); n != tt.wantReqs {
+				t.Errorf("provider got %d requests, want %d", n, tt.wantReqs)
+			}
+		})
+	}
+}
+
+func TestDetailsTimeout(t *testing.T) {
+	client, provider, requests := newTestClient(t, config.SongDetailsConfig{
+		Timeout: 20 * time.Millisecond,
+		Retries: 2,
+	})
+	provider.SetDelay(time.Second)
+
+	start := time.Now()
+
+	_, err := client.Details(newContext(), muse)
+	if err == nil {
+		t.Fatal("Details succeeded, want timeout")
+	}
+
+	// every attempt has its own timeout, so all of them are made long before the delay
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Details took %v, want attempts to time out", elapsed)
+	}
+	if n := requests.requests.Load(); n != 3 {
+		t.Errorf("provider got %d requests, want 3", n)
+	}
+}
+
+func TestDetailsContextCanceled(t *testing.T) {
+	client, provider, requests := newTestClient(t, config.SongDetailsConfig{
+		Retries:    5,
+		BackoffMin: time.Minute,
+		BackoffMax: time.Minute,
+	})
+	provider.FailNext(10)
+
+	ctx, cancel := context.WithTimeout(newContext(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.Details(ctx, muse)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Details returned %v, want %v", err, context.DeadlineExceeded)
+	}
+	if n := requests.requests.Load(); n != 1 {
+		t.Errorf("provider got %d requests, want 1", n)
+	}
+}
+
+func TestDetailsBreaker(t *testing.T) {
+	client, provider, requests := newTestClient(t, config.SongDetailsConfig{
+		Retries:          0,
+		BreakerThreshold: 2,
+		BreakerTimeout:   50 * time.Millisecond,
+	})
+	provider.FailNext(2)
+
+	for range 2 {
+		_, err := client.Details(newContext(), muse)
+		if !errors.Is(err, errUnavailable) {
+			t.Fatalf("Details returned %v, want %v", err, errUnavailable)
+		}
+	}
+
+	// open breaker rejects calls without requests to the provider
+	_, err := client.Details(newContext(), muse)
+	if !errors.Is(err, circuitbreaker.ErrOpen) {
+		t.Errorf("Details returned %v, want %v", err, circuitbreaker.ErrOpen)
+	}
+	if n := requests.requests.Load(); n != 2 {
+		t.Errorf("provider got %d requests, want 2", n)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+
+	// trial call succeeds and closes the breaker
+	for range 2 {
+		_, err = client.Details(newContext(), muse)
+		if err != nil {
+			t.Fatalf("Details after breaker timeout: %v", err)
+		}
+	}
+	if n := requests.requests.Load(); n != 4 {
+		t.Errorf("provider got %d requests, want 4", n)
+	}
+}
+
+func TestDetailsCanceledNotBreakerFailure(t *testing.T) {
+	client, provider, _ := newTestClient(t, config.SongDetailsConfig{
+		Retries:          0,
+		BreakerThreshold: 1,
+	})
+	provider.SetDelay(time.Second)
+
+	ctx, cancel := context.WithTimeout(newContext(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := client.Details(ctx, muse)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Details returned %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	// the caller gave up, so the breaker stays closed
+	provider.SetDelay(0)
+
+	_, err = client.Details(newContext(), muse)
+	if err != nil {
+		t.Errorf("Details after canceled call: %v", err)
+	}
+}
+
+func TestDetailsOpenBreakerNotRetried(t *testing.T) {
+	client, provider, requests := newTestClient(t, config.SongDetailsConfig{
+		Retries:          5,
+		BreakerThreshold: 2,
+	})
+	provider.FailNext(10)
+
+	_, err := client.Details(newContext(), muse)
+	if !errors.Is(err, circuitbreaker.ErrOpen) {
+		t.Errorf("Details returned %v, want %v", err, circuitbreaker.ErrOpen)
+	}
+	if n := requests.requests.Load(); n != 2 {
+		t.Errorf("provider got %d requests, want 2", n)
+	}
+}
+
+func TestWaitFullJitter(t *testing.T) {
+	client := &Client{backoffMin: 10 * time.Millisecond, backoffMax: 40 * time.Millisecond}
+
+	// delay of later attempts is capped by backoffMax
+	for attempt := 1; attempt <= 10; attempt++ {
+		start := time.Now()
+
+		err := client.wait(newContext(), attempt)
+		if err != nil {
+			t.Fatalf("wait: %v", err)
+		}
+
+		if elapsed := time.Since(start); elapsed > client.backoffMax+20*time.Millisecond {
+			t.Errorf("wait of attempt %d took %v, want at most %v", attempt, elapsed, client.backoffMax)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(newContext())
+	cancel()
+
+	client.backoffMin, client.backoffMax = time.Minute, time.Minute
+
+	err := client.wait(ctx, 1)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("wait returned %v, want %v", err, context.Canceled)
+	}
+}
+
+func newContext() context.Context {
+	return context.WithValue(context.Background(), logmsg.OperationID, uuid.New())
+}
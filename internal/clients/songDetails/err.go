@@ -0,0 +1,10 @@
+package songdetails
+
+import "errors"
+
+var (
+	ErrNotFound = errors.New("song details not found")
+
+	errUnavailable = errors.New("song details provider is unavailable")
+	errBadResponse = errors.New("bad response from song details provider")
+)
@@ -0,0 +1,121 @@
+package fake
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type songDetail struct {
+	ReleaseDate string `json:"releaseDate"`
+	Text        string `json:"text"`
+	Link        string `json:"link"`
+}
+
+type key struct {
+	group string
+	song  string
+}
+
+// Server imitates the external song details provider, so the service
+// can be run and tested offline. Use it with httptest.NewServer or http.ListenAndServe.
+type Server struct {
+	mu sync.Mutex
+
+	songs map[key]songDetail
+
+	failures int
+	delay    time.Duration
+}
+
+// Returns server with one known song: "Muse" - "Supermassive Black Hole".
+func NewServer() *Server {
+	s := &Server{
+		songs: make(map[key]songDetail),
+	}
+
+	s.Add(
+		"Muse",
+		"Supermassive Black Hole",
+		"16.07.2006",
+		"Ooh baby, don't you know I suffer?\nOoh baby, can you hear me moan?\nYou caught me under false pretenses\nHow long before you let me go?\n\nOoh\nYou set my soul alight\nOoh\nYou set my soul alight",
+		"https://www.youtube.com/watch?v=Xsp3_a-PMTw",
+	)
+
+	return s
+}
+
+// releaseDate must match the form "DD.MM.YYYY", verses in text are separated by an empty line.
+func (s *Server) Add(group, song, releaseDate, text, link string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.songs[key{group, song}] = songDetail{
+		ReleaseDate: releaseDate,
+		Text:        text,
+		Link:        link,
+	}
+}
+
+// Next n requests will be answered with 503 Service Unavailable.
+func (s *Server) FailNext(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures = n
+}
+
+// Every request will be answered after delay, useful for timeouts checking.
+func (s *Server) SetDelay(delay time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.delay = delay
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/info" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	group, song := r.URL.Query().Get("group"), r.URL.Query().Get("song")
+	if group == "" || song == "" {
+		http.Error(w, "group and song are required", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	delay := s.delay
+	fail := s.failures > 0
+	if fail {
+		s.failures--
+	}
+	detail, ok := s.songs[key{group, song}]
+	s.mu.Unlock()
+
+	if delay > 0 {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+
+	if fail {
+		http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(detail)
+}
@@ -0,0 +1,34 @@
+package songdetails
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/qreaqtor/music-library/internal/domain"
+)
+
+// Release date format used by the provider.
+const releaseDateLayout = "02.01.2006"
+
+type songDetail struct {
+	ReleaseDate string `json:"releaseDate"`
+	Text        string `json:"text"`
+	Link        string `json:"link"`
+}
+
+func (s *songDetail) toDomain() (*domain.SongDetails, error) {
+	details := &domain.SongDetails{
+		Text: s.Text,
+		Link: s.Link,
+	}
+
+	if s.ReleaseDate != "" {
+		date, err := time.Parse(releaseDateLayout, s.ReleaseDate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid releaseDate %q", errBadResponse, s.ReleaseDate)
+		}
+		details.ReleaseDate = date
+	}
+
+	return details, nil
+}
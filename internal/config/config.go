@@ -1,25 +1,128 @@
 package config
 
+import (
+	"fmt"
+	"time"
+)
+
 type Config struct {
-	Api      ApiConfig
-	Postgres PostgresConfig
+	Storage     StorageConfig
+	Postgres    PostgresConfig
+	Search      SearchConfig
+	Cursor      CursorConfig
+	SongDetails SongDetailsConfig
+	Trash       TrashConfig
+	Auth        AuthConfig
+	RBAC        RBACConfig
 
 	Host string `env:"APP_HOST" env-required:"true"`
 	Port int    `env:"APP_PORT" env-required:"true"`
 	Env  string `env:"APP_ENV" env-required:"true"`
 }
 
-type ApiConfig struct {
-	Version int `env:"API_VERSION" env-required:"true"`
+// Storage types.
+const (
+	StoragePostgres = "postgres"
+	StorageMemory   = "memory"
+)
+
+// Posible storage types: postgres, memory.
+type StorageConfig struct {
+	Type string `env:"STORAGE_TYPE" env-default:"postgres"`
 }
 
+// Required only for postgres storage, so memory storage runs without them.
 type PostgresConfig struct {
-	User     string `env:"POSTGRES_USER" env-required:"true"`
-	Password string `env:"POSTGRES_PASSWORD" env-required:"true"`
-	DB       string `env:"POSTGRES_DB" env-required:"true"`
+	User     string `env:"POSTGRES_USER"`
+	Password string `env:"POSTGRES_PASSWORD"`
+	DB       string `env:"POSTGRES_DB"`
+
+	Host string `env:"POSTGRES_HOST"`
+	Port int    `env:"POSTGRES_PORT"`
+
+	SSL bool `env:"POSTGRES_SSL" env-default:"false"`
+}
+
+// Returns error with the first variable which is not set.
+// Command line tools which always use PostgreSQL check it themselves.
+func (c PostgresConfig) Validate() error {
+	required := []struct {
+		name  string
+		isSet bool
+	}{
+		{"POSTGRES_USER", c.User != ""},
+		{"POSTGRES_PASSWORD", c.Password != ""},
+		{"POSTGRES_DB", c.DB != ""},
+		{"POSTGRES_HOST", c.Host != ""},
+		{"POSTGRES_PORT", c.Port != 0},
+	}
+
+	for _, variable := range required {
+		if !variable.isSet {
+			return fmt.Errorf("%w: %s", errRequired, variable.name)
+		}
+	}
+
+	return nil
+}
+
+// Language is a PostgreSQL text search configuration used for lyrics search,
+// e.g. simple, english or russian. Memory storage always behaves like simple.
+// FuzzyThreshold is a minimal similarity of fuzzy search results if it is not set in request.
+type SearchConfig struct {
+	Language string `env:"SEARCH_LANGUAGE" env-default:"simple"`
+
+	FuzzyThreshold float64 `env:"SEARCH_FUZZY_THRESHOLD" env-default:"0.3"`
+}
+
+// Secret signs pagination cursors, cursors issued with another secret are rejected.
+type CursorConfig struct {
+	Secret string `env:"CURSOR_SECRET" env-required:"true"`
+}
+
+// Song details provider is disabled if URL is empty.
+type SongDetailsConfig struct {
+	URL string `env:"SONG_DETAILS_URL"`
+
+	Timeout time.Duration `env:"SONG_DETAILS_TIMEOUT" env-default:"2s"`
+
+	Retries    int           `env:"SONG_DETAILS_RETRIES" env-default:"3"`
+	BackoffMin time.Duration `env:"SONG_DETAILS_BACKOFF_MIN" env-default:"100ms"`
+	BackoffMax time.Duration `env:"SONG_DETAILS_BACKOFF_MAX" env-default:"2s"`
+
+	BreakerThreshold int           `env:"SONG_DETAILS_BREAKER_THRESHOLD" env-default:"5"`
+	BreakerTimeout   time.Duration `env:"SONG_DETAILS_BREAKER_TIMEOUT" env-default:"30s"`
+}
+
+// Songs are deleted permanently when they are in trash longer than Retention,
+// trash is checked every PurgeInterval.
+type TrashConfig struct {
+	Retention     time.Duration `env:"TRASH_RETENTION" env-default:"720h"`
+	PurgeInterval time.Duration `env:"TRASH_PURGE_INTERVAL" env-default:"1h"`
+}
+
+// Secret signs access and refresh tokens, changing it logs out all users.
+// Access tokens live AccessTTL, a session lasts RefreshTTL after the last refresh.
+// If AdminName is set, the user with AdminPassword is made admin on start, registered users never are.
+type AuthConfig struct {
+	Secret string `env:"AUTH_SECRET" env-required:"true"`
+
+	AccessTTL  time.Duration `env:"AUTH_ACCESS_TTL" env-default:"15m"`
+	RefreshTTL time.Duration `env:"AUTH_REFRESH_TTL" env-default:"720h"`
+
+	AdminName     string `env:"AUTH_ADMIN_NAME"`
+	AdminPassword string `env:"AUTH_ADMIN_PASSWORD"`
+}
 
-	Host string `env:"POSTGRES_HOST" env-required:"true"`
-	Port int    `env:"POSTGRES_PORT" env-required:"true"`
+// Permissions of anonymous requests and of each role, "*" grants all permissions.
+// Possible permissions: songs:read, songs:create, songs:update, songs:delete, songs:feedback, genres:manage,
+// artists:manage, albums:manage, playlists:manage, users:manage, keys:manage.
+// Registered users get DefaultRole.
+type RBACConfig struct {
+	Anonymous []string `env:"RBAC_ANONYMOUS" env-default:"songs:read"`
+	Viewer    []string `env:"RBAC_VIEWER" env-default:"songs:read,songs:feedback"`
+	Editor    []string `env:"RBAC_EDITOR" env-default:"songs:read,songs:create,songs:update,songs:feedback,artists:manage,albums:manage,playlists:manage"`
+	Admin     []string `env:"RBAC_ADMIN" env-default:"*"`
 
-	SSL bool `env:"POSTGRES_SSL" env-required:"true"`
+	DefaultRole string `env:"RBAC_DEFAULT_ROLE" env-default:"viewer"`
 }
@@ -4,4 +4,5 @@ import "fmt"
 
 var (
 	errNotExists = fmt.Errorf("config file does not exists")
+	errRequired  = fmt.Errorf("required variable is not set")
 )
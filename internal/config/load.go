@@ -36,5 +36,12 @@ func LoadFromPath(path string) (*Config, error) {
 		return nil, err
 	}
 
+	if cfg.Storage.Type == StoragePostgres {
+		err = cfg.Postgres.Validate()
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return cfg, nil
 }
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Album struct {
+	ID          uuid.UUID  `json:"id"`
+	Title       string     `json:"title" validate:"required,min=1,max=200"`
+	ArtistID    uuid.UUID  `json:"artistId" validate:"required"`
+	Artist      string     `json:"artist"`
+	ReleaseDate *time.Time `json:"releaseDate,omitempty"`
+	CoverURL    string     `json:"coverUrl,omitempty" validate:"omitempty,http_url"`
+	Label       string     `json:"label,omitempty" validate:"omitempty,max=200"`
+}
+
+type AlbumUpdate struct {
+	Title       string    `json:"title" validate:"omitempty,min=1,max=200"`
+	ArtistID    uuid.UUID `json:"artistId" validate:"omitempty"`
+	ReleaseDate time.Time `json:"releaseDate" validate:"omitempty"`
+	CoverURL    string    `json:"coverUrl" validate:"omitempty,http_url"`
+	Label       string    `json:"label" validate:"omitempty,max=200"`
+}
+
+type AlbumSearch struct {
+	Batch
+
+	ByTitle  string    `json:"by_title" validate:"omitempty,min=1"`
+	ArtistID uuid.UUID `json:"artist_id"`
+}
+
+type AlbumSchema struct {
+	Title       string    `db:"title"`
+	ArtistID    uuid.UUID `db:"artist_id"`
+	ReleaseDate time.Time `db:"release_date"`
+	CoverURL    string    `db:"cover_url"`
+	Label       string    `db:"label"`
+}
+
+// Track links existing song to the album, song is found by group and name.
+// Disc number is 1 if not provided, duration is in seconds.
+type Track struct {
+	Song
+
+	DiscNumber  int `json:"disc" validate:"omitempty,gte=1"`
+	TrackNumber int `json:"track" validate:"required,gte=1"`
+	Duration    int `json:"duration,omitempty" validate:"omitempty,gte=0"`
+}
+
+type Tracklist struct {
+	Tracks []*Track `json:"tracks" validate:"dive"`
+}
+
+// AlbumRef is an album which the song appears on.
+type AlbumRef struct {
+	ID          uuid.UUID  `json:"id"`
+	Title       string     `json:"title"`
+	ReleaseDate *time.Time `json:"releaseDate,omitempty"`
+	DiscNumber  int        `json:"disc"`
+	TrackNumber int        `json:"track"`
+}
+
+func (a *AlbumUpdate) ToAlbumSchema() AlbumSchema {
+	return AlbumSchema{
+		Title:       a.Title,
+		ArtistID:    a.ArtistID,
+		ReleaseDate: a.ReleaseDate,
+		CoverURL:    a.CoverURL,
+		Label:       a.Label,
+	}
+}
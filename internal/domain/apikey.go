@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Scheme of Authorization header with API keys.
+const TokenTypeAPIKey = "ApiKey"
+
+// Kind of API keys, keys look like mlk_<prefix>_<secret>.
+const APIKeyKind = "mlk"
+
+// Role of requests authenticated by API keys, their permissions are the permissions of the key.
+const RoleAPIKey = "apikey"
+
+const MaxAPIKeyName = 100
+
+// Permissions which can be granted to API keys, keys can't manage users and other keys.
+var APIKeyPermissions = []string{
+	PermissionSongsRead,
+	PermissionSongsCreate,
+	PermissionSongsUpdate,
+	PermissionSongsDelete,
+}
+
+// APIKey authenticates a service client. Prefix identifies the key, the key itself is shown only once.
+// Key never expires if ExpiresAt is nil, LastUsedAt is updated at most once a minute.
+type APIKey struct {
+	ID          uuid.UUID  `json:"id"`
+	Name        string     `json:"name"`
+	Prefix      string     `json:"prefix"`
+	Permissions []string   `json:"permissions"`
+	CreatedBy   uuid.UUID  `json:"createdBy"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt  *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt   *time.Time `json:"revokedAt,omitempty"`
+}
+
+// APIKeyAccount is the API key with SHA-256 hash of the key, it is never returned by API.
+type APIKeyAccount struct {
+	APIKey
+
+	Hash []byte
+}
+
+// Expired and revoked keys are rejected.
+func (k *APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || k.ExpiresAt.After(now))
+}
+
+type APIKeyCreate struct {
+	Name        string     `json:"name" validate:"required,max=100"`
+	Permissions []string   `json:"permissions" validate:"required,min=1,dive,oneof=songs:read songs:create songs:update songs:delete"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+}
+
+// IssuedAPIKey is the created or rotated API key with the key itself.
+type IssuedAPIKey struct {
+	APIKey
+
+	Key string `json:"key"`
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Artist struct {
+	ID        uuid.UUID  `json:"id"`
+	Name      string     `json:"name" validate:"required,min=1,max=100"`
+	Aliases   []string   `json:"aliases" validate:"dive,min=1,max=100"`
+	Country   string     `json:"country,omitempty" validate:"omitempty,iso3166_1_alpha2"`
+	Formed    *time.Time `json:"formed,omitempty"`
+	Disbanded *time.Time `json:"disbanded,omitempty"`
+	Bio       string     `json:"bio,omitempty"`
+}
+
+type ArtistUpdate struct {
+	Name      string    `json:"name" validate:"omitempty,min=1,max=100"`
+	Aliases   []string  `json:"aliases" validate:"omitempty,dive,min=1,max=100"`
+	Country   string    `json:"country" validate:"omitempty,iso3166_1_alpha2"`
+	Formed    time.Time `json:"formed" validate:"omitempty"`
+	Disbanded time.Time `json:"disbanded" validate:"omitempty"`
+	Bio       string    `json:"bio" validate:"omitempty"`
+}
+
+type ArtistSearch struct {
+	Batch
+
+	ByName string `json:"by_name" validate:"omitempty,min=1"`
+}
+
+type ArtistSchema struct {
+	Name      string    `db:"name"`
+	Aliases   []string  `db:"aliases"`
+	Country   string    `db:"country"`
+	Formed    time.Time `db:"formed"`
+	Disbanded time.Time `db:"disbanded"`
+	Bio       string    `db:"bio"`
+}
+
+func (a *ArtistUpdate) ToArtistSchema() ArtistSchema {
+	return ArtistSchema{
+		Name:      a.Name,
+		Aliases:   a.Aliases,
+		Country:   a.Country,
+		Formed:    a.Formed,
+		Disbanded: a.Disbanded,
+		Bio:       a.Bio,
+	}
+}
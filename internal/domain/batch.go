@@ -1,6 +1,7 @@
 package domain
 
+// Limit is bounded, so a single request can't make the storage read the whole catalog.
 type Batch struct {
 	Offset int `json:"offset" validate:"gte=0"`
-	Limit  int `json:"limit" validate:"gt=0"`
+	Limit  int `json:"limit" validate:"gt=0,lte=100"`
 }
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Modes of bulk import: insert skips existing songs, upsert updates them.
+const (
+	BulkInsert = "insert"
+	BulkUpsert = "upsert"
+)
+
+// Statuses of bulk import items.
+const (
+	BulkCreated = "created"
+	BulkUpdated = "updated"
+	BulkSkipped = "skipped"
+	BulkError   = "error"
+)
+
+// Full song of bulk import. Existing song is found by group and name up to case and spaces.
+// On update empty fields are kept, lyrics are replaced only if they are provided.
+type BulkSong struct {
+	Group    string `json:"group" validate:"required"`
+	SongName string `json:"song" validate:"required"`
+	// verses as objects with section or as plain strings
+	Lyrics      []*Verse  `json:"lyrics" validate:"omitempty,dive,required"`
+	Link        string    `json:"link" validate:"omitempty,http_url"`
+	ReleaseDate time.Time `json:"releaseDate"`
+}
+
+// If DryRun is set, results are computed, but nothing is saved.
+type BulkOptions struct {
+	Mode   string `json:"mode" validate:"oneof=insert upsert"`
+	DryRun bool   `json:"dryRun"`
+}
+
+// Index is position of the item in the request. ID is id of the created, updated or skipped song,
+// it is nil uuid for errors and for songs which would be created in dry run.
+type BulkResult struct {
+	Index  int       `json:"index"`
+	Status string    `json:"status"`
+	ID     uuid.UUID `json:"id"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// Items with the same key refer to the same song.
+func (s *BulkSong) Key() string {
+	return NameKey(s.Group) + "\x00" + NameKey(s.SongName)
+}
+
+func (s *BulkSong) ToSongSchema() SongSchema {
+	return SongSchema{
+		Group:       s.Group,
+		SongName:    s.SongName,
+		Link:        s.Link,
+		ReleaseDate: s.ReleaseDate,
+	}
+}
+
+func (s *BulkSong) ToLyricsSchema() LyricsSchema {
+	return LyricsSchema{
+		Lyrics: numberVerses(s.Lyrics),
+	}
+}
@@ -0,0 +1,21 @@
+package domain
+
+// Roles of credited artists.
+const (
+	CreditPrimary   = "primary"
+	CreditFeaturing = "featuring"
+	CreditComposer  = "composer"
+	CreditLyricist  = "lyricist"
+	CreditProducer  = "producer"
+)
+
+// Credit links an artist to the song with a role, an artist may have several roles in one song.
+type Credit struct {
+	Artist string `json:"artist" validate:"required,min=1,max=100"`
+	Role   string `json:"role" validate:"required,oneof=primary featuring composer lyricist producer"`
+}
+
+// Credits of the song in display order, they replace all previous credits.
+type SongCredits struct {
+	Credits []Credit `json:"credits" validate:"dive"`
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUnknownResourse = errors.New("unknown resource")
+	ErrEmptyUpdate     = errors.New("nothing to update")
+
+	ErrArtistExists   = errors.New("artist with the same name already exists")
+	ErrArtistHasSongs = errors.New("artist has songs, albums or credits, delete them first")
+	ErrArtistDates    = errors.New("artist can't be disbanded before it was formed")
+
+	ErrUnknownArtist = errors.New("unknown artist")
+	ErrTrackPosition = errors.New("several tracks have the same disc and track number")
+
+	ErrTimestamps      = errors.New("timestamps of lyrics lines must not go back")
+	ErrLyricsNotSynced = errors.New("lyrics have verses without timestamps")
+
+	ErrTranslationVerses = errors.New("translation must have as many verses as the lyrics")
+
+	ErrSongExists = errors.New("song with the same group and name already exists")
+
+	ErrNoSongTags = errors.New("file has no artist or title tag")
+
+	ErrUserExists         = errors.New("user with the same name already exists")
+	ErrInvalidCredentials = errors.New("invalid name or password")
+	ErrInvalidToken       = errors.New("token is invalid, expired or revoked")
+	ErrUnauthenticated    = errors.New("authentication required")
+	ErrForbidden          = errors.New("forbidden")
+	ErrOwnRole            = errors.New("users can't change their own role")
+
+	ErrUserRequired = errors.New("only users have favorites, ratings and listening history")
+
+	ErrGenreExists      = errors.New("genre with the same name already exists")
+	ErrGenreHasChildren = errors.New("genre has child genres, delete or move them first")
+	ErrGenreCycle       = errors.New("genre can't be a descendant of itself")
+	ErrUnknownGenre     = errors.New("unknown genre")
+
+	ErrKeyExpiry  = errors.New("expiration time of api key must be in the future")
+	ErrKeyRevoked = errors.New("api key is revoked")
+)
+
+// SongExistsError is ErrSongExists with id of the existing song.
+type SongExistsError struct {
+	ID uuid.UUID
+}
+
+func (e *SongExistsError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrSongExists, e.ID)
+}
+
+func (e *SongExistsError) Unwrap() error {
+	return ErrSongExists
+}
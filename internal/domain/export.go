@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Formats of catalogue export.
+const (
+	ExportNDJSON = "ndjson"
+	ExportCSV    = "csv"
+)
+
+// Song with all its verses. Json fields match BulkSong, so NDJSON export can be imported back.
+type ExportedSong struct {
+	ID          uuid.UUID `json:"id"`
+	Group       string    `json:"group"`
+	SongName    string    `json:"song"`
+	Lyrics      []*Verse  `json:"lyrics"`
+	Link        string    `json:"link"`
+	ReleaseDate time.Time `json:"releaseDate"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,78 @@
+package domain
+
+import "time"
+
+// Periods of top songs, all counts plays of all time.
+const (
+	PeriodDay   = "day"
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+	PeriodYear  = "year"
+	PeriodAll   = "all"
+)
+
+const (
+	MinRating = 1
+	MaxRating = 5
+)
+
+// SongStats are aggregates of feedback of all users, AverageRating is 0 if the song is not rated.
+type SongStats struct {
+	Plays         int     `json:"plays"`
+	Favorites     int     `json:"favorites"`
+	Ratings       int     `json:"ratings"`
+	AverageRating float32 `json:"averageRating"`
+}
+
+// SongFeedback is feedback of one user, Rating is 0 if the user didn't rate the song.
+type SongFeedback struct {
+	Favorite bool `json:"favorite"`
+	Rating   int  `json:"rating,omitempty"`
+}
+
+type RatingUpdate struct {
+	Rating int `json:"rating" validate:"required,min=1,max=5"`
+}
+
+// FavoriteSong is a song in favorites of the user.
+type FavoriteSong struct {
+	Song
+
+	AddedAt time.Time `json:"addedAt"`
+}
+
+// Play is a song played by the user.
+type Play struct {
+	Song
+
+	PlayedAt time.Time `json:"playedAt"`
+}
+
+// TopSong is a song with number of its plays in the period.
+type TopSong struct {
+	Song
+
+	Plays int `json:"plays"`
+}
+
+type TopSearch struct {
+	Batch
+
+	Period string `json:"period" validate:"required,oneof=day week month year all"`
+}
+
+// Returns start of the period which ends at now, zero time for PeriodAll.
+func (t *TopSearch) Since(now time.Time) time.Time {
+	switch t.Period {
+	case PeriodDay:
+		return now.AddDate(0, 0, -1)
+	case PeriodWeek:
+		return now.AddDate(0, 0, -7)
+	case PeriodMonth:
+		return now.AddDate(0, -1, 0)
+	case PeriodYear:
+		return now.AddDate(-1, 0, 0)
+	default:
+		return time.Time{}
+	}
+}
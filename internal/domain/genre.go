@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Genre is a node of the genre taxonomy, ParentID is nil for top level genres.
+// Names are unique case insensitively.
+type Genre struct {
+	ID       uuid.UUID  `json:"id"`
+	Name     string     `json:"name" validate:"required,min=1,max=50"`
+	ParentID *uuid.UUID `json:"parentId,omitempty"`
+}
+
+// Genres of the song by name, they replace all previous genres.
+type SongGenres struct {
+	Genres []string `json:"genres" validate:"dive,min=1,max=50"`
+}
+
+// Free-form tags of the song, they replace all previous tags.
+type SongTags struct {
+	Tags []string `json:"tags" validate:"dive,min=1,max=50"`
+}
+
+// How several tags of search are matched.
+const (
+	TagMatchAny = "any"
+	TagMatchAll = "all"
+)
+
+// Facet is number of found songs with the value.
+type Facet struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// Facets of search results, pagination is ignored. Songs of a genre are counted
+// with songs of its descendant genres, decades are like "1990s".
+// Genres and tags are ordered by count and then by value, decades chronologically.
+type Facets struct {
+	Genres  []Facet `json:"genres"`
+	Tags    []Facet `json:"tags"`
+	Decades []Facet `json:"decades"`
+}
+
+// Tags are lower case without spaces around, duplicates are removed.
+// Returns sorted tags.
+func NormalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" {
+			normalized = append(normalized, tag)
+		}
+	}
+
+	slices.Sort(normalized)
+	return slices.Compact(normalized)
+}
@@ -0,0 +1,161 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Section types of verses, verses without section are treated as SectionVerse.
+const (
+	SectionIntro     = "intro"
+	SectionVerse     = "verse"
+	SectionPreChorus = "pre-chorus"
+	SectionChorus    = "chorus"
+	SectionBridge    = "bridge"
+	SectionOutro     = "outro"
+)
+
+// Verses are ordered by Position, it starts from 1 and is unique within a song.
+// Position is assigned by order of verses in the update, so it is ignored in requests.
+// Lines are set only for synced lyrics, then Text is made of texts of the lines.
+// Language is BCP 47 code of the verse text, Translation is set only if a translation was requested.
+type Verse struct {
+	Position    int     `json:"position"`
+	Section     string  `json:"section" validate:"omitempty,oneof=intro verse pre-chorus chorus bridge outro"`
+	Language    string  `json:"language,omitempty" validate:"omitempty,bcp47_language_tag"`
+	Text        string  `json:"text" validate:"required_without=Lines"`
+	Translation string  `json:"translation,omitempty"`
+	Lines       []*Line `json:"lines,omitempty" validate:"omitempty,dive,required"`
+}
+
+// Start and End are milliseconds from the beginning of the song, End is zero if it is unknown.
+// Words are set only if lyrics are synced word by word.
+type Line struct {
+	Start int64   `json:"start" validate:"gte=0"`
+	End   int64   `json:"end,omitempty" validate:"gte=0"`
+	Text  string  `json:"text" validate:"required"`
+	Words []*Word `json:"words,omitempty" validate:"omitempty,dive,required"`
+}
+
+// Start is milliseconds from the beginning of the song.
+type Word struct {
+	Start int64  `json:"start" validate:"gte=0"`
+	Text  string `json:"text" validate:"required"`
+}
+
+// Verse is accepted either as an object or as a plain string, which is a verse without section.
+func (v *Verse) UnmarshalJSON(data []byte) error {
+	var text string
+	if json.Unmarshal(data, &text) == nil {
+		*v = Verse{Text: text}
+		return nil
+	}
+
+	// alias has no UnmarshalJSON method, so it is decoded as a plain struct
+	type verse Verse
+	return json.Unmarshal(data, (*verse)(v))
+}
+
+// After and Before are verse positions, they are used instead of Offset:
+// page starts right after the After verse or ends right before the Before verse.
+// If Section is set, only verses of this section are returned.
+// Languages are preferred languages of translation, the best one is chosen among available translations.
+type LyricsBatch struct {
+	Batch
+
+	After  int `json:"-"`
+	Before int `json:"-"`
+
+	Languages []string `json:"-"`
+
+	Section string `json:"section" validate:"omitempty,oneof=intro verse pre-chorus chorus bridge outro"`
+
+	// count all verses of the song, which match Section
+	Total bool `json:"total"`
+}
+
+// Next and Prev are verse positions to continue from, they are zero if there are no verses after or before the page.
+// Total is set only if it was requested. Language is language of the translation in verses, empty if there is no translation.
+type LyricsResult struct {
+	Verses   []*Verse
+	Next     int
+	Prev     int
+	Total    *int
+	Language string
+}
+
+// Numbers verses by their order, sets default section and canonical form of language codes.
+func numberVerses(verses []*Verse) []*Verse {
+	result := make([]*Verse, 0, len(verses))
+
+	for i, verse := range verses {
+		section := verse.Section
+		if section == "" {
+			section = SectionVerse
+		}
+
+		text := verse.Text
+		if len(verse.Lines) != 0 {
+			texts := make([]string, 0, len(verse.Lines))
+			for _, line := range verse.Lines {
+				texts = append(texts, line.Text)
+			}
+			text = strings.Join(texts, "\n")
+		}
+
+		var lang string
+		if verse.Language != "" {
+			lang = language.Make(verse.Language).String()
+		}
+
+		result = append(result, &Verse{
+			Position: i + 1,
+			Section:  section,
+			Language: lang,
+			Text:     text,
+			Lines:    verse.Lines,
+		})
+	}
+
+	return result
+}
+
+// Checks that timestamps of synced lines never go back: every line starts not before the previous one
+// and ends after its start but not after the next line starts, words are within their line.
+// Verses without lines are skipped.
+func CheckTimings(verses []*Verse) error {
+	var prev *Line
+
+	for i, verse := range verses {
+		for j, line := range verse.Lines {
+			at := func(err error) error {
+				return fmt.Errorf("%w: verse %d, line %d", err, i+1, j+1)
+			}
+
+			if prev != nil && line.Start < prev.Start {
+				return at(ErrTimestamps)
+			}
+			if prev != nil && prev.End != 0 && prev.End > line.Start {
+				return at(ErrTimestamps)
+			}
+			if line.End != 0 && line.End <= line.Start {
+				return at(ErrTimestamps)
+			}
+
+			wordStart := line.Start
+			for _, word := range line.Words {
+				if word.Start < wordStart || line.End != 0 && word.Start >= line.End {
+					return at(ErrTimestamps)
+				}
+				wordStart = word.Start
+			}
+
+			prev = line
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,21 @@
+package domain
+
+import "strings"
+
+// Returns name without leading, trailing and repeated spaces.
+func CleanName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// Names with the same key are considered the same, they differ only in case and spaces.
+// Must match name_key function of the database.
+func NameKey(name string) string {
+	return strings.ToLower(CleanName(name))
+}
+
+// Songs of the same artist with the same name key. Keep is the oldest one,
+// songs of Merge are merged into it.
+type Duplicates struct {
+	Keep  Song   `json:"keep"`
+	Merge []Song `json:"merge"`
+}
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Name of imported playlist if neither request nor file has it.
+	DefaultPlaylistName = "Imported playlist"
+
+	// Must match validation of Playlist.Name.
+	MaxPlaylistName = 200
+)
+
+// Songs is the number of items in the playlist.
+type Playlist struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name" validate:"required,min=1,max=200"`
+	Description string    `json:"description,omitempty" validate:"omitempty,max=2000"`
+	Songs       int       `json:"songs"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+type PlaylistUpdate struct {
+	Name        string `json:"name" validate:"omitempty,min=1,max=200"`
+	Description string `json:"description" validate:"omitempty,max=2000"`
+}
+
+type PlaylistSearch struct {
+	Batch
+
+	ByName string `json:"by_name" validate:"omitempty,min=1"`
+}
+
+type PlaylistSchema struct {
+	Name        string `db:"name"`
+	Description string `db:"description"`
+}
+
+// PlaylistItem is a song at the position of the playlist, positions start from 1.
+// Songs in trash are hidden, so positions can have gaps until they are restored.
+type PlaylistItem struct {
+	Song
+
+	Position int    `json:"position"`
+	Link     string `json:"link,omitempty"`
+}
+
+// PlaylistItemInsert adds existing song found by id or by group and name.
+// Position 0 or a position after the last item appends the song.
+type PlaylistItemInsert struct {
+	SongID   uuid.UUID `json:"songId"`
+	Group    string    `json:"group" validate:"required_without=SongID"`
+	SongName string    `json:"song" validate:"required_without=SongID"`
+	Position int       `json:"position" validate:"gte=0"`
+}
+
+// PlaylistItemMove moves the item to the position, the last position is used if it is too big.
+type PlaylistItemMove struct {
+	Position int `json:"position" validate:"required,gte=1"`
+}
+
+// PlaylistEntry is a track of imported playlist file. Song is found by Identifier (urn:uuid:<song id>),
+// then by Link and then by Group and SongName.
+type PlaylistEntry struct {
+	Identifier string `json:"identifier,omitempty"`
+	Link       string `json:"link,omitempty"`
+	Group      string `json:"group,omitempty"`
+	SongName   string `json:"song,omitempty"`
+}
+
+// Unmatched are entries of the file without songs in the library, they are not added to the playlist.
+type PlaylistImport struct {
+	Playlist  *Playlist        `json:"playlist"`
+	Unmatched []*PlaylistEntry `json:"unmatched"`
+}
+
+func (p *PlaylistUpdate) ToPlaylistSchema() PlaylistSchema {
+	return PlaylistSchema{
+		Name:        p.Name,
+		Description: p.Description,
+	}
+}
+
+// Song is found by id if it is set, otherwise by group and name.
+func (i *PlaylistItemInsert) ToSong() *Song {
+	return &Song{
+		ID:       i.SongID,
+		Group:    i.Group,
+		SongName: i.SongName,
+	}
+}
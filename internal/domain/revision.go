@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Names of metadata fields in revision changes.
+const (
+	FieldGroup       = "group"
+	FieldSongName    = "song"
+	FieldLink        = "link"
+	FieldReleaseDate = "releaseDate"
+)
+
+// Revision is written on every change of a song, Number starts from 1 for the created song.
+// Song is the state after the revision, it is set only when a single revision is requested.
+type Revision struct {
+	Number        int           `json:"number"`
+	Author        string        `json:"author,omitempty"`
+	CreatedAt     time.Time     `json:"createdAt"`
+	Changes       []*Change     `json:"changes"`
+	LyricsChanged bool          `json:"lyricsChanged"`
+	Song          *SongSnapshot `json:"song,omitempty"`
+}
+
+// Old and new value of a metadata field, release date is formatted as YYYY-MM-DD.
+type Change struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// Full state of a song metadata, lyrics and their translations ordered by language.
+// Translations are nil in snapshots written before they were saved.
+type SongSnapshot struct {
+	Group        string         `json:"group"`
+	SongName     string         `json:"song"`
+	Link         string         `json:"link"`
+	ReleaseDate  time.Time      `json:"releaseDate"`
+	Lyrics       []*Verse       `json:"lyrics"`
+	Translations []*Translation `json:"translations"`
+}
+
+// Line-level difference of lyrics and changes of metadata between two revisions.
+type RevisionDiff struct {
+	From    int         `json:"from"`
+	To      int         `json:"to"`
+	Changes []*Change   `json:"changes"`
+	Lines   []*DiffLine `json:"lines"`
+}
+
+// Op is "=" for unchanged line, "-" for removed and "+" for added one.
+type DiffLine struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// Returns revision from before to after state, before is nil for the created song.
+func NewRevision(author string, before, after *SongSnapshot) *Revision {
+	if before == nil {
+		before = &SongSnapshot{}
+	}
+
+	// empty lyrics may be nil or empty slice
+	lyricsChanged := (len(before.Lyrics) != 0 || len(after.Lyrics) != 0) && !reflect.DeepEqual(before.Lyrics, after.Lyrics)
+
+	return &Revision{
+		Author:        author,
+		Changes:       before.Changes(after),
+		LyricsChanged: lyricsChanged,
+		Song:          after,
+	}
+}
+
+// Returns changed metadata fields.
+func (s *SongSnapshot) Changes(other *SongSnapshot) []*Change {
+	changes := make([]*Change, 0)
+
+	fields := []struct {
+		name     string
+		old, new string
+	}{
+		{FieldGroup, s.Group, other.Group},
+		{FieldSongName, s.SongName, other.SongName},
+		{FieldLink, s.Link, other.Link},
+		{FieldReleaseDate, formatDate(s.ReleaseDate), formatDate(other.ReleaseDate)},
+	}
+
+	for _, field := range fields {
+		if field.old != field.new {
+			changes = append(changes, &Change{Field: field.name, Old: field.old, New: field.new})
+		}
+	}
+
+	return changes
+}
+
+// Returns lines of all verses in order.
+func (s *SongSnapshot) Lines() []string {
+	lines := make([]string, 0)
+	for _, verse := range s.Lyrics {
+		lines = append(lines, strings.Split(verse.Text, "\n")...)
+	}
+	return lines
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.DateOnly)
+}
+
+type authorKey struct{}
+
+// Returns context with author of changes, it is saved in revisions.
+func WithAuthor(ctx context.Context, author string) context.Context {
+	return context.WithValue(ctx, authorKey{}, author)
+}
+
+// Returns author of changes or empty string if it is unknown.
+func Author(ctx context.Context) string {
+	author, _ := ctx.Value(authorKey{}).(string)
+	return author
+}
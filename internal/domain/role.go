@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+)
+
+// Roles of users, permissions of each role are set in config.
+const (
+	RoleViewer = "viewer"
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
+// Permissions are named <resource>:<action>.
+const (
+	PermissionSongsRead   = "songs:read"
+	PermissionSongsCreate = "songs:create"
+	PermissionSongsUpdate = "songs:update"
+	PermissionSongsDelete = "songs:delete"
+
+	// favorites, ratings and listening history of the user
+	PermissionSongsFeedback = "songs:feedback"
+
+	// genre taxonomy, genres of songs are set with the update permission
+	PermissionGenresManage = "genres:manage"
+
+	// artists, albums and playlists are read with the songs read permission
+	PermissionArtistsManage   = "artists:manage"
+	PermissionAlbumsManage    = "albums:manage"
+	PermissionPlaylistsManage = "playlists:manage"
+
+	PermissionUsersManage = "users:manage"
+	PermissionKeysManage  = "keys:manage"
+)
+
+// All permissions, config may grant only these.
+var Permissions = []string{
+	PermissionSongsRead,
+	PermissionSongsCreate,
+	PermissionSongsUpdate,
+	PermissionSongsDelete,
+	PermissionSongsFeedback,
+	PermissionGenresManage,
+	PermissionArtistsManage,
+	PermissionAlbumsManage,
+	PermissionPlaylistsManage,
+	PermissionUsersManage,
+	PermissionKeysManage,
+}
+
+var Roles = []string{RoleViewer, RoleEditor, RoleAdmin}
+
+type RoleUpdate struct {
+	Role string `json:"role" validate:"required,oneof=viewer editor admin"`
+}
+
+// PermissionError is ErrForbidden with the missing permission and the role of the user,
+// role is empty for anonymous requests.
+type PermissionError struct {
+	Permission string
+	Role       string
+}
+
+func (e *PermissionError) Error() string {
+	if e.Role == "" {
+		return fmt.Sprintf("%s: anonymous requests have no %s permission", ErrForbidden, e.Permission)
+	}
+	return fmt.Sprintf("%s: role %s has no %s permission", ErrForbidden, e.Role, e.Permission)
+}
+
+// Anonymous requests without permission are not forbidden but unauthenticated.
+func (e *PermissionError) Unwrap() error {
+	if e.Role == "" {
+		return ErrUnauthenticated
+	}
+	return ErrForbidden
+}
+
+type principalKey struct{}
+
+// Returns context with the authenticated user, permissions are checked by the role of the user.
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// Returns the authenticated user, ok is false for anonymous requests.
+func PrincipalFrom(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok
+}
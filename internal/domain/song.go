@@ -1,28 +1,184 @@
 package domain
 
-import "time"
+import (
+	"time"
 
+	"github.com/google/uuid"
+)
+
+// Song is found by ID if it is set, otherwise by group and song name, which matches by NameKey.
 type Song struct {
-	Group    string `json:"group" validate:"required,min=1"`
-	SongName string `json:"song" validate:"required,min=1"`
+	ID       uuid.UUID `json:"id"`
+	Group    string    `json:"group" validate:"required,min=1"`
+	SongName string    `json:"song" validate:"required,min=1"`
 }
 
 type SongInfo struct {
-	Group       string    `json:"group"`
-	SongName    string    `json:"song"`
-	Lyrics      string    `json:"lyrics"`
+	ID          uuid.UUID  `json:"id"`
+	Group       string     `json:"group"`
+	SongName    string     `json:"song"`
+	Lyrics      string     `json:"lyrics"`
+	ReleaseDate time.Time  `json:"releaseDate"`
+	Link        string     `json:"link"`
+	Albums      []AlbumRef `json:"albums"`
+	Genres      []string   `json:"genres"`
+	Tags        []string   `json:"tags"`
+	Credits     []Credit   `json:"credits"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+	Stats       SongStats  `json:"stats"`
+
+	// set only if a translation was requested and found
+	Translation *Translation `json:"translation,omitempty"`
+
+	// feedback of the user of the request, not set for anonymous requests and API keys
+	Mine *SongFeedback `json:"mine,omitempty"`
+}
+
+// Rank and Headline are set only when searching by lyrics,
+// Headline contains matched verses with found words wrapped in <b></b>.
+// Similarity is set only for fuzzy search, it is an average similarity of group and song name.
+// Plays is number of all plays and Rating is average rating of the song.
+type FoundSong struct {
+	Song
+
 	ReleaseDate time.Time `json:"releaseDate"`
-	Link        string    `json:"link"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+	Plays       int       `json:"plays"`
+	Rating      float32   `json:"rating"`
+
+	Rank       float32 `json:"rank,omitempty"`
+	Headline   string  `json:"headline,omitempty"`
+	Similarity float32 `json:"similarity,omitempty"`
 }
 
+// Position of the song in sorted search results.
+func (s *FoundSong) Key() *SearchKey {
+	return &SearchKey{
+		ID:          s.ID,
+		Group:       s.Group,
+		SongName:    s.SongName,
+		ReleaseDate: s.ReleaseDate,
+		CreatedAt:   s.CreatedAt,
+		UpdatedAt:   s.UpdatedAt,
+		Plays:       s.Plays,
+		Rating:      s.Rating,
+		Rank:        s.Rank,
+		Similarity:  s.Similarity,
+	}
+}
+
+// SearchKey is a position in sorted search results for keyset pagination:
+// values of all sort fields and the ID tiebreaker of a found song.
+type SearchKey struct {
+	ID          uuid.UUID `json:"id"`
+	Group       string    `json:"group,omitempty"`
+	SongName    string    `json:"song,omitempty"`
+	ReleaseDate time.Time `json:"releaseDate"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+	Plays       int       `json:"plays,omitempty"`
+	Rating      float32   `json:"rating,omitempty"`
+	Rank        float32   `json:"rank,omitempty"`
+	Similarity  float32   `json:"similarity,omitempty"`
+}
+
+// Next and Prev are nil if there are no songs after or before the page.
+// Total and Facets are set only if they were requested.
+type SearchResult struct {
+	Songs       []*FoundSong
+	Suggestions *Suggestions
+	Next        *SearchKey
+	Prev        *SearchKey
+	Total       *int
+	Facets      *Facets
+}
+
+// Group and song names similar to the searched ones, ordered by similarity.
+type Suggestions struct {
+	Groups []string `json:"groups,omitempty"`
+	Songs  []string `json:"songs,omitempty"`
+}
+
+// Search results sort fields, relevance is similarity of fuzzy search and then rank of lyrics search.
+// Plays is number of all plays and rating is average rating of songs.
+const (
+	SortByGroup       = "group"
+	SortBySongName    = "song"
+	SortByReleaseDate = "release_date"
+	SortByCreatedAt   = "created_at"
+	SortByUpdatedAt   = "updated_at"
+	SortByRelevance   = "relevance"
+	SortByPlays       = "plays"
+	SortByRating      = "rating"
+)
+
+const (
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
+// ByLyrics supports web search syntax: "quoted phrase", or, -excluded.
+// With Fuzzy ByGroup and BySongName are matched by trigram similarity not lower than Threshold.
+// Songs with equal sort field are ordered by ID in the same direction.
+// After and Before are used instead of Offset: page starts right after the After key
+// or ends right before the Before key.
+// Genre matches songs of the genre and of its descendants. Tags match songs with any of them,
+// or with all of them if TagMatch is TagMatchAll. Credit matches songs crediting the artist
+// case insensitively, in CreditRole if it is set. CreditRole alone matches songs with any artist in this role.
 type SongSearch struct {
 	Batch
 
-	ByGroup    string `json:"by_group" valid:"omitempty,min=1"`
-	BySongName string `json:"by_song_name" valid:"omitempty,min=1"`
-	ByLyrics   string `json:"by_lyrics" valid:"omitempty,min=1"`
-	ByLink     string `json:"by_link" valid:"omitempty,min=1"`
+	ByGroup    string `json:"by_group" validate:"omitempty,min=1"`
+	BySongName string `json:"by_song_name" validate:"omitempty,min=1"`
+	ByLyrics   string `json:"by_lyrics" validate:"omitempty,min=1"`
+	ByLink     string `json:"by_link" validate:"omitempty,min=1"`
+
+	DateFrom time.Time `json:"from" validate:"omitempty"`
+	DateTo   time.Time `json:"to" validate:"omitempty,gtefield=DateFrom"`
+
+	Genre    string   `json:"genre" validate:"omitempty,min=1"`
+	Tags     []string `json:"tag" validate:"dive,min=1"`
+	TagMatch string   `json:"tag_match" validate:"omitempty,oneof=any all"`
+
+	Credit     string `json:"credit" validate:"omitempty,min=1"`
+	CreditRole string `json:"credit_role" validate:"omitempty,oneof=primary featuring composer lyricist producer"`
+
+	Fuzzy     bool    `json:"fuzzy"`
+	Threshold float64 `json:"threshold" validate:"gte=0,lte=1"`
+
+	Sort  string `json:"sort" validate:"omitempty,oneof=group song release_date created_at updated_at relevance plays rating"`
+	Order string `json:"order" validate:"omitempty,oneof=asc desc"`
+
+	After  *SearchKey `json:"-"`
+	Before *SearchKey `json:"-"`
+
+	// count all found songs
+	Total bool `json:"total"`
+
+	// count found songs per genre, tag and decade
+	Facets bool `json:"facets"`
+}
+
+// Returns sort field and order. By default results of lyrics and fuzzy search are sorted by relevance,
+// other results by creation time. Relevance, plays and rating are sorted descending by default, other fields ascending.
+func (s *SongSearch) Sorting() (string, string) {
+	field := s.Sort
+	if field == "" {
+		field = SortByCreatedAt
+		if s.ByLyrics != "" || s.Fuzzy {
+			field = SortByRelevance
+		}
+	}
+
+	order := s.Order
+	if order == "" {
+		order = OrderAsc
+		if field == SortByRelevance || field == SortByPlays || field == SortByRating {
+			order = OrderDesc
+		}
+	}
 
-	DateFrom time.Time `json:"from" valid:"omitempty"`
-	DateTo   time.Time `json:"to" valid:"omitempty"`
+	return field, order
 }
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Verses in the text from the song details provider are separated by an empty line.
+const versesSeparator = "\n\n"
+
+type SongDetails struct {
+	ReleaseDate time.Time
+	Text        string
+	Link        string
+}
+
+func (d *SongDetails) ToSongSchema(song *Song) SongSchema {
+	return SongSchema{
+		Group:       song.Group,
+		SongName:    song.SongName,
+		Link:        d.Link,
+		ReleaseDate: d.ReleaseDate,
+	}
+}
+
+func (d *SongDetails) ToLyricsSchema() LyricsSchema {
+	lyrics := make([]*Verse, 0)
+
+	for _, verse := range strings.Split(d.Text, versesSeparator) {
+		verse = strings.TrimSpace(verse)
+		if verse != "" {
+			lyrics = append(lyrics, &Verse{Text: verse})
+		}
+	}
+
+	return LyricsSchema{
+		Lyrics: numberVerses(lyrics),
+	}
+}
@@ -1,24 +1,32 @@
 package domain
 
-import "time"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type SongUpdate struct {
-	Group       string    `json:"group" validate:"omitempty,min=1"`
-	SongName    string    `json:"song" validate:"omitempty,min=1"`
-	Lyrics      []string  `json:"lyrics" validate:"omitempty,min=1"`
+	Group    string `json:"group" validate:"omitempty,min=1"`
+	SongName string `json:"song" validate:"omitempty,min=1"`
+	// verses as objects with section or as plain strings
+	Lyrics      []*Verse  `json:"lyrics" validate:"omitempty,min=1,dive,required"`
 	Link        string    `json:"link" validate:"omitempty,http_url"`
 	ReleaseDate time.Time `json:"releaseDate" validate:"omitempty"`
 }
 
+// Group is stored in artists table, storage resolves it to ArtistID.
 type SongSchema struct {
-	Group       string    `db:"group_name"`
+	Group       string    `db:"-"`
+	ArtistID    uuid.UUID `db:"artist_id"`
 	SongName    string    `db:"song"`
 	Link        string    `db:"link"`
 	ReleaseDate time.Time `db:"releaseDate"`
 }
 
+// Verses are numbered and have section.
 type LyricsSchema struct {
-	Lyrics []string
+	Lyrics []*Verse
 }
 
 func (s *SongUpdate) ToSongSchema() SongSchema {
@@ -32,6 +40,6 @@ func (s *SongUpdate) ToSongSchema() SongSchema {
 
 func (s *SongUpdate) ToLyricsSchema() LyricsSchema {
 	return LyricsSchema{
-		Lyrics: s.Lyrics,
+		Lyrics: numberVerses(s.Lyrics),
 	}
 }
@@ -0,0 +1,32 @@
+package domain
+
+import "github.com/google/uuid"
+
+// Statuses of files of tag import. Matched file has the song which already exists, it is left as is
+// in insert mode and updated in upsert mode. Skipped file can't be read or has no artist or title.
+const (
+	TagsCreated = BulkCreated
+	TagsMatched = "matched"
+	TagsUpdated = BulkUpdated
+	TagsSkipped = "skipped"
+)
+
+// ID is id of the created or matched song, it is nil uuid for skipped files
+// and for songs which would be created in dry run. Reason explains why the file was skipped.
+type TagsResult struct {
+	File     string    `json:"file"`
+	Status   string    `json:"status"`
+	ID       uuid.UUID `json:"id"`
+	Group    string    `json:"group,omitempty"`
+	SongName string    `json:"song,omitempty"`
+	Reason   string    `json:"reason,omitempty"`
+}
+
+// Returns result for the file which wasn't imported.
+func SkippedFile(file string, err error) *TagsResult {
+	return &TagsResult{
+		File:   file,
+		Status: TagsSkipped,
+		Reason: err.Error(),
+	}
+}
@@ -0,0 +1,27 @@
+package domain
+
+// Verses of the translation are aligned with verses of the song:
+// i-th verse of the translation translates the verse at position i+1.
+type Translation struct {
+	Language string   `json:"language" validate:"required,bcp47_language_tag"`
+	Verses   []string `json:"verses" validate:"required,min=1,dive,required"`
+}
+
+// Original are languages of the song verses, Translations are languages the lyrics are translated to.
+// Languages are BCP 47 codes.
+type Languages struct {
+	Original     []string `json:"original"`
+	Translations []string `json:"translations"`
+}
+
+// Returns translations which are kept when lyrics are replaced by the given number of verses:
+// verses at positions which still exist keep their translations, translations without verses are dropped.
+func KeepTranslations(translations []*Translation, verses int) []*Translation {
+	kept := make([]*Translation, 0, len(translations))
+	for _, translation := range translations {
+		if n := min(len(translation.Verses), verses); n != 0 {
+			kept = append(kept, &Translation{Language: translation.Language, Verses: translation.Verses[:n:n]})
+		}
+	}
+	return kept
+}
@@ -0,0 +1,10 @@
+package domain
+
+import "time"
+
+// Deleted song, it is kept in trash until the retention period passes.
+type TrashedSong struct {
+	Song
+
+	DeletedAt time.Time `json:"deletedAt"`
+}
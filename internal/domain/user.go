@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Type of issued access tokens, clients send them in Authorization header.
+const TokenTypeBearer = "Bearer"
+
+type User struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Name is the login of the user, names are unique case insensitively.
+// Password length is limited by bcrypt, which uses only first 72 bytes.
+type Credentials struct {
+	Name     string `json:"name" validate:"required,min=3,max=50"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+}
+
+// UserAccount is the user with hash of the password, it is never returned by API.
+type UserAccount struct {
+	User
+
+	PasswordHash []byte
+}
+
+// Session is started on login and lasts until its refresh token expires or it is revoked.
+// RefreshID is id of the last issued refresh token, older refresh tokens of the session are rejected.
+type Session struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	RefreshID uuid.UUID
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	Revoked   bool
+}
+
+// ExpiresIn is lifetime of the access token in seconds.
+type Tokens struct {
+	AccessToken  string `json:"accessToken"`
+	RefreshToken string `json:"refreshToken"`
+	TokenType    string `json:"tokenType"`
+	ExpiresIn    int    `json:"expiresIn"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
+}
+
+// Principal is the authenticated user with id of the session of the access token,
+// or the API key with its own id as SessionID.
+// Permissions are set only for API keys, permissions of users come from their role.
+type Principal struct {
+	ID          uuid.UUID
+	Name        string
+	Role        string
+	SessionID   uuid.UUID
+	Permissions []string
+}
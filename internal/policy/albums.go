@@ -0,0 +1,89 @@
+package policy
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+)
+
+type albumsService interface {
+	Create(context.Context, *domain.Album) (*domain.Album, error)
+	Get(context.Context, uuid.UUID) (*domain.Album, error)
+	List(context.Context, *domain.AlbumSearch) ([]*domain.Album, error)
+	Update(context.Context, uuid.UUID, *domain.AlbumUpdate) (*domain.Album, error)
+	Delete(context.Context, uuid.UUID) error
+	GetTracks(context.Context, uuid.UUID) ([]*domain.Track, error)
+	SetTracks(context.Context, uuid.UUID, *domain.Tracklist) ([]*domain.Track, error)
+}
+
+// AlbumsPolicy checks permissions of the user and passes allowed calls to the albums service.
+// Albums and tracklists are read by everyone who can read songs and changed with the albums:manage permission.
+type AlbumsPolicy struct {
+	srv albumsService
+
+	policy *Policy
+}
+
+func NewAlbumsPolicy(srv albumsService, policy *Policy) *AlbumsPolicy {
+	return &AlbumsPolicy{
+		srv:    srv,
+		policy: policy,
+	}
+}
+
+func (a *AlbumsPolicy) Create(ctx context.Context, album *domain.Album) (*domain.Album, error) {
+	err := a.policy.Check(ctx, domain.PermissionAlbumsManage)
+	if err != nil {
+		return nil, err
+	}
+	return a.srv.Create(ctx, album)
+}
+
+func (a *AlbumsPolicy) Get(ctx context.Context, id uuid.UUID) (*domain.Album, error) {
+	err := a.policy.Check(ctx, domain.PermissionSongsRead)
+	if err != nil {
+		return nil, err
+	}
+	return a.srv.Get(ctx, id)
+}
+
+func (a *AlbumsPolicy) List(ctx context.Context, search *domain.AlbumSearch) ([]*domain.Album, error) {
+	err := a.policy.Check(ctx, domain.PermissionSongsRead)
+	if err != nil {
+		return nil, err
+	}
+	return a.srv.List(ctx, search)
+}
+
+func (a *AlbumsPolicy) Update(ctx context.Context, id uuid.UUID, update *domain.AlbumUpdate) (*domain.Album, error) {
+	err := a.policy.Check(ctx, domain.PermissionAlbumsManage)
+	if err != nil {
+		return nil, err
+	}
+	return a.srv.Update(ctx, id, update)
+}
+
+func (a *AlbumsPolicy) Delete(ctx context.Context, id uuid.UUID) error {
+	err := a.policy.Check(ctx, domain.PermissionAlbumsManage)
+	if err != nil {
+		return err
+	}
+	return a.srv.Delete(ctx, id)
+}
+
+func (a *AlbumsPolicy) GetTracks(ctx context.Context, albumID uuid.UUID) ([]*domain.Track, error) {
+	err := a.policy.Check(ctx, domain.PermissionSongsRead)
+	if err != nil {
+		return nil, err
+	}
+	return a.srv.GetTracks(ctx, albumID)
+}
+
+func (a *AlbumsPolicy) SetTracks(ctx context.Context, albumID uuid.UUID, tracklist *domain.Tracklist) ([]*domain.Track, error) {
+	err := a.policy.Check(ctx, domain.PermissionAlbumsManage)
+	if err != nil {
+		return nil, err
+	}
+	return a.srv.SetTracks(ctx, albumID, tracklist)
+}
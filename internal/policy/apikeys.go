@@ -0,0 +1,61 @@
+package policy
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+)
+
+type apiKeysService interface {
+	Create(context.Context, *domain.APIKeyCreate) (*domain.IssuedAPIKey, error)
+	Keys(context.Context, *domain.Batch) ([]*domain.APIKey, error)
+	Rotate(context.Context, uuid.UUID) (*domain.IssuedAPIKey, error)
+	Revoke(context.Context, uuid.UUID) (*domain.APIKey, error)
+}
+
+// APIKeysPolicy allows management of API keys only with the keys:manage permission.
+type APIKeysPolicy struct {
+	srv apiKeysService
+
+	policy *Policy
+}
+
+func NewAPIKeysPolicy(srv apiKeysService, policy *Policy) *APIKeysPolicy {
+	return &APIKeysPolicy{
+		srv:    srv,
+		policy: policy,
+	}
+}
+
+func (k *APIKeysPolicy) Create(ctx context.Context, create *domain.APIKeyCreate) (*domain.IssuedAPIKey, error) {
+	err := k.policy.Check(ctx, domain.PermissionKeysManage)
+	if err != nil {
+		return nil, err
+	}
+	return k.srv.Create(ctx, create)
+}
+
+func (k *APIKeysPolicy) Keys(ctx context.Context, batch *domain.Batch) ([]*domain.APIKey, error) {
+	err := k.policy.Check(ctx, domain.PermissionKeysManage)
+	if err != nil {
+		return nil, err
+	}
+	return k.srv.Keys(ctx, batch)
+}
+
+func (k *APIKeysPolicy) Rotate(ctx context.Context, id uuid.UUID) (*domain.IssuedAPIKey, error) {
+	err := k.policy.Check(ctx, domain.PermissionKeysManage)
+	if err != nil {
+		return nil, err
+	}
+	return k.srv.Rotate(ctx, id)
+}
+
+func (k *APIKeysPolicy) Revoke(ctx context.Context, id uuid.UUID) (*domain.APIKey, error) {
+	err := k.policy.Check(ctx, domain.PermissionKeysManage)
+	if err != nil {
+		return nil, err
+	}
+	return k.srv.Revoke(ctx, id)
+}
@@ -0,0 +1,71 @@
+package policy
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+)
+
+type artistsService interface {
+	Create(context.Context, *domain.Artist) (*domain.Artist, error)
+	Get(context.Context, uuid.UUID) (*domain.Artist, error)
+	List(context.Context, *domain.ArtistSearch) ([]*domain.Artist, error)
+	Update(context.Context, uuid.UUID, *domain.ArtistUpdate) (*domain.Artist, error)
+	Delete(context.Context, uuid.UUID) error
+}
+
+// ArtistsPolicy checks permissions of the user and passes allowed calls to the artists service.
+// Artists are read by everyone who can read songs and changed with the artists:manage permission.
+type ArtistsPolicy struct {
+	srv artistsService
+
+	policy *Policy
+}
+
+func NewArtistsPolicy(srv artistsService, policy *Policy) *ArtistsPolicy {
+	return &ArtistsPolicy{
+		srv:    srv,
+		policy: policy,
+	}
+}
+
+func (a *ArtistsPolicy) Create(ctx context.Context, artist *domain.Artist) (*domain.Artist, error) {
+	err := a.policy.Check(ctx, domain.PermissionArtistsManage)
+	if err != nil {
+		return nil, err
+	}
+	return a.srv.Create(ctx, artist)
+}
+
+func (a *ArtistsPolicy) Get(ctx context.Context, id uuid.UUID) (*domain.Artist, error) {
+	err := a.policy.Check(ctx, domain.PermissionSongsRead)
+	if err != nil {
+		return nil, err
+	}
+	return a.srv.Get(ctx, id)
+}
+
+func (a *ArtistsPolicy) List(ctx context.Context, search *domain.ArtistSearch) ([]*domain.Artist, error) {
+	err := a.policy.Check(ctx, domain.PermissionSongsRead)
+	if err != nil {
+		return nil, err
+	}
+	return a.srv.List(ctx, search)
+}
+
+func (a *ArtistsPolicy) Update(ctx context.Context, id uuid.UUID, update *domain.ArtistUpdate) (*domain.Artist, error) {
+	err := a.policy.Check(ctx, domain.PermissionArtistsManage)
+	if err != nil {
+		return nil, err
+	}
+	return a.srv.Update(ctx, id, update)
+}
+
+func (a *ArtistsPolicy) Delete(ctx context.Context, id uuid.UUID) error {
+	err := a.policy.Check(ctx, domain.PermissionArtistsManage)
+	if err != nil {
+		return err
+	}
+	return a.srv.Delete(ctx, id)
+}
@@ -0,0 +1,8 @@
+package policy
+
+import "errors"
+
+var (
+	errUnknownRole       = errors.New("unknown role")
+	errUnknownPermission = errors.New("unknown permission")
+)
@@ -0,0 +1,135 @@
+package policy
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+	"github.com/qreaqtor/music-library/pkg/playlist"
+)
+
+type playlistsService interface {
+	Create(context.Context, *domain.Playlist) (*domain.Playlist, error)
+	Get(context.Context, uuid.UUID) (*domain.Playlist, error)
+	List(context.Context, *domain.PlaylistSearch) ([]*domain.Playlist, error)
+	Update(context.Context, uuid.UUID, *domain.PlaylistUpdate) (*domain.Playlist, error)
+	Delete(context.Context, uuid.UUID) error
+	GetItems(context.Context, uuid.UUID) ([]*domain.PlaylistItem, error)
+	InsertItem(context.Context, uuid.UUID, *domain.PlaylistItemInsert) ([]*domain.PlaylistItem, error)
+	MoveItem(ctx context.Context, id uuid.UUID, from, to int) ([]*domain.PlaylistItem, error)
+	RemoveItem(ctx context.Context, id uuid.UUID, position int) ([]*domain.PlaylistItem, error)
+	Duplicate(ctx context.Context, id uuid.UUID, name string) (*domain.Playlist, error)
+	Export(context.Context, uuid.UUID) (*playlist.Playlist, error)
+	Import(ctx context.Context, name string, file *playlist.Playlist) (*domain.PlaylistImport, error)
+}
+
+// PlaylistsPolicy checks permissions of the user and passes allowed calls to the playlists service.
+// Playlists are read and exported by everyone who can read songs, changed and imported with the playlists:manage permission.
+type PlaylistsPolicy struct {
+	srv playlistsService
+
+	policy *Policy
+}
+
+func NewPlaylistsPolicy(srv playlistsService, policy *Policy) *PlaylistsPolicy {
+	return &PlaylistsPolicy{
+		srv:    srv,
+		policy: policy,
+	}
+}
+
+func (p *PlaylistsPolicy) Create(ctx context.Context, playlist *domain.Playlist) (*domain.Playlist, error) {
+	err := p.policy.Check(ctx, domain.PermissionPlaylistsManage)
+	if err != nil {
+		return nil, err
+	}
+	return p.srv.Create(ctx, playlist)
+}
+
+func (p *PlaylistsPolicy) Get(ctx context.Context, id uuid.UUID) (*domain.Playlist, error) {
+	err := p.policy.Check(ctx, domain.PermissionSongsRead)
+	if err != nil {
+		return nil, err
+	}
+	return p.srv.Get(ctx, id)
+}
+
+func (p *PlaylistsPolicy) List(ctx context.Context, search *domain.PlaylistSearch) ([]*domain.Playlist, error) {
+	err := p.policy.Check(ctx, domain.PermissionSongsRead)
+	if err != nil {
+		return nil, err
+	}
+	return p.srv.List(ctx, search)
+}
+
+func (p *PlaylistsPolicy) Update(ctx context.Context, id uuid.UUID, update *domain.PlaylistUpdate) (*domain.Playlist, error) {
+	err := p.policy.Check(ctx, domain.PermissionPlaylistsManage)
+	if err != nil {
+		return nil, err
+	}
+	return p.srv.Update(ctx, id, update)
+}
+
+func (p *PlaylistsPolicy) Delete(ctx context.Context, id uuid.UUID) error {
+	err := p.policy.Check(ctx, domain.PermissionPlaylistsManage)
+	if err != nil {
+		return err
+	}
+	return p.srv.Delete(ctx, id)
+}
+
+func (p *PlaylistsPolicy) GetItems(ctx context.Context, id uuid.UUID) ([]*domain.PlaylistItem, error) {
+	err := p.policy.Check(ctx, domain.PermissionSongsRead)
+	if err != nil {
+		return nil, err
+	}
+	return p.srv.GetItems(ctx, id)
+}
+
+func (p *PlaylistsPolicy) InsertItem(ctx context.Context, id uuid.UUID, item *domain.PlaylistItemInsert) ([]*domain.PlaylistItem, error) {
+	err := p.policy.Check(ctx, domain.PermissionPlaylistsManage)
+	if err != nil {
+		return nil, err
+	}
+	return p.srv.InsertItem(ctx, id, item)
+}
+
+func (p *PlaylistsPolicy) MoveItem(ctx context.Context, id uuid.UUID, from, to int) ([]*domain.PlaylistItem, error) {
+	err := p.policy.Check(ctx, domain.PermissionPlaylistsManage)
+	if err != nil {
+		return nil, err
+	}
+	return p.srv.MoveItem(ctx, id, from, to)
+}
+
+func (p *PlaylistsPolicy) RemoveItem(ctx context.Context, id uuid.UUID, position int) ([]*domain.PlaylistItem, error) {
+	err := p.policy.Check(ctx, domain.PermissionPlaylistsManage)
+	if err != nil {
+		return nil, err
+	}
+	return p.srv.RemoveItem(ctx, id, position)
+}
+
+func (p *PlaylistsPolicy) Duplicate(ctx context.Context, id uuid.UUID, name string) (*domain.Playlist, error) {
+	err := p.policy.Check(ctx, domain.PermissionPlaylistsManage)
+	if err != nil {
+		return nil, err
+	}
+	return p.srv.Duplicate(ctx, id, name)
+}
+
+func (p *PlaylistsPolicy) Export(ctx context.Context, id uuid.UUID) (*playlist.Playlist, error) {
+	err := p.policy.Check(ctx, domain.PermissionSongsRead)
+	if err != nil {
+		return nil, err
+	}
+	return p.srv.Export(ctx, id)
+}
+
+func (p *PlaylistsPolicy) Import(ctx context.Context, name string, file *playlist.Playlist) (*domain.PlaylistImport, error) {
+	err := p.policy.Check(ctx, domain.PermissionPlaylistsManage)
+	if err != nil {
+		return nil, err
+	}
+	return p.srv.Import(ctx, name, file)
+}
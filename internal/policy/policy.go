@@ -0,0 +1,93 @@
+// Package policy checks permissions of the request user before calls to services.
+// Permissions of roles come from config, so access can be changed without code changes.
+package policy
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/qreaqtor/music-library/internal/domain"
+)
+
+// Anonymous is the key of permissions of requests without a user.
+const Anonymous = "anonymous"
+
+// Grants all permissions.
+const allPermissions = "*"
+
+type Policy struct {
+	roles map[string]map[string]bool
+}
+
+// roles maps roles and Anonymous to their permissions, roles without permissions are allowed nothing.
+// Returns error for unknown roles and permissions, so typos in config are found on start.
+func New(roles map[string][]string) (*Policy, error) {
+	p := &Policy{
+		roles: make(map[string]map[string]bool, len(roles)),
+	}
+
+	for role, permissions := range roles {
+		if role != Anonymous && !slices.Contains(domain.Roles, role) {
+			return nil, fmt.Errorf("%w: %q", errUnknownRole, role)
+		}
+
+		granted := make(map[string]bool, len(permissions))
+
+		for _, permission := range permissions {
+			if permission == allPermissions {
+				for _, permission := range domain.Permissions {
+					granted[permission] = true
+				}
+				continue
+			}
+
+			if !slices.Contains(domain.Permissions, permission) {
+				return nil, fmt.Errorf("%w %q of role %s", errUnknownPermission, permission, role)
+			}
+			granted[permission] = true
+		}
+
+		p.roles[role] = granted
+	}
+
+	return p, nil
+}
+
+// Returns domain.PermissionError if the user of the context has no permission.
+func (p *Policy) Check(ctx context.Context, permission string) error {
+	principal, ok := domain.PrincipalFrom(ctx)
+
+	var allowed bool
+	switch {
+	case !ok:
+		allowed = p.roles[Anonymous][permission]
+	case principal.Permissions != nil:
+		// API keys are allowed only their own permissions
+		allowed = slices.Contains(principal.Permissions, permission)
+	default:
+		allowed = p.roles[principal.Role][permission]
+	}
+
+	if allowed {
+		return nil
+	}
+
+	err := &domain.PermissionError{Permission: permission}
+	if ok {
+		err.Role = principal.Role
+	}
+
+	return err
+}
+
+// Checks all permissions, the first missing one is returned.
+func (p *Policy) CheckAll(ctx context.Context, permissions ...string) error {
+	for _, permission := range permissions {
+		err := p.Check(ctx, permission)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
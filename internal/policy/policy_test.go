@@ -0,0 +1,101 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+)
+
+func TestCheck(t *testing.T) {
+	p, err := New(map[string][]string{
+		Anonymous:         {domain.PermissionSongsRead},
+		domain.RoleViewer: {domain.PermissionSongsRead, domain.PermissionSongsFeedback},
+		domain.RoleEditor: {domain.PermissionSongsRead, domain.PermissionSongsCreate, domain.PermissionSongsUpdate},
+		domain.RoleAdmin:  {"*"},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	user := func(role string) context.Context {
+		return domain.WithPrincipal(context.Background(), &domain.Principal{ID: uuid.New(), Role: role})
+	}
+	apiKey := domain.WithPrincipal(context.Background(), &domain.Principal{
+		ID:          uuid.New(),
+		Role:        domain.RoleAPIKey,
+		Permissions: []string{domain.PermissionSongsCreate},
+	})
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		permission string
+		wantErr    error
+	}{
+		{"AnonymousAllowed", context.Background(), domain.PermissionSongsRead, nil},
+		{"AnonymousDenied", context.Background(), domain.PermissionSongsCreate, domain.ErrUnauthenticated},
+		{"ViewerAllowed", user(domain.RoleViewer), domain.PermissionSongsFeedback, nil},
+		{"ViewerDenied", user(domain.RoleViewer), domain.PermissionSongsUpdate, domain.ErrForbidden},
+		{"EditorAllowed", user(domain.RoleEditor), domain.PermissionSongsUpdate, nil},
+		{"EditorDenied", user(domain.RoleEditor), domain.PermissionSongsDelete, domain.ErrForbidden},
+		{"AdminAllowedAll", user(domain.RoleAdmin), domain.PermissionKeysManage, nil},
+		{"UnknownRoleDenied", user("owner"), domain.PermissionSongsRead, domain.ErrForbidden},
+		{"APIKeyAllowed", apiKey, domain.PermissionSongsCreate, nil},
+		{"APIKeyDeniedRead", apiKey, domain.PermissionSongsRead, domain.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := p.Check(tt.ctx, tt.permission)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Check(%s) returned %v, want %v", tt.permission, err, tt.wantErr)
+			}
+
+			var permissionErr *domain.PermissionError
+			if err != nil && (!errors.As(err, &permissionErr) || permissionErr.Permission != tt.permission) {
+				t.Errorf("Check(%s) returned %v, want error of the permission", tt.permission, err)
+			}
+		})
+	}
+}
+
+func TestCheckAll(t *testing.T) {
+	p, err := New(map[string][]string{
+		domain.RoleEditor: {domain.PermissionSongsCreate},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	ctx := domain.WithPrincipal(context.Background(), &domain.Principal{ID: uuid.New(), Role: domain.RoleEditor})
+
+	err = p.CheckAll(ctx, domain.PermissionSongsCreate, domain.PermissionSongsUpdate)
+
+	var permissionErr *domain.PermissionError
+	if !errors.As(err, &permissionErr) || permissionErr.Permission != domain.PermissionSongsUpdate {
+		t.Errorf("CheckAll returned %v, want error of %s", err, domain.PermissionSongsUpdate)
+	}
+}
+
+func TestNewInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		roles   map[string][]string
+		wantErr error
+	}{
+		{"UnknownRole", map[string][]string{"owner": {domain.PermissionSongsRead}}, errUnknownRole},
+		{"UnknownPermission", map[string][]string{domain.RoleViewer: {"songs:write"}}, errUnknownPermission},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.roles)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("New returned %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,362 @@
+package policy
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+	audiotag "github.com/qreaqtor/music-library/pkg/audioTag"
+	"github.com/qreaqtor/music-library/pkg/lrc"
+)
+
+type songsService interface {
+	Info(context.Context, *domain.Song, ...string) (*domain.SongInfo, error)
+	Create(context.Context, *domain.Song) (uuid.UUID, error)
+	Delete(context.Context, *domain.Song) error
+	Update(context.Context, *domain.Song, *domain.SongUpdate) error
+	GetLyrics(context.Context, *domain.Song, *domain.LyricsBatch) (*domain.LyricsResult, error)
+	Search(context.Context, *domain.SongSearch) (*domain.SearchResult, error)
+	ImportLRC(context.Context, *domain.Song, *lrc.File) error
+	ExportLRC(context.Context, *domain.Song) (*lrc.File, error)
+	Languages(context.Context, *domain.Song) (*domain.Languages, error)
+	Translation(context.Context, *domain.Song, string) (*domain.Translation, error)
+	SetTranslation(context.Context, *domain.Song, *domain.Translation) error
+	DeleteTranslation(context.Context, *domain.Song, string) error
+	Revisions(context.Context, *domain.Song, *domain.Batch) ([]*domain.Revision, error)
+	Revision(context.Context, *domain.Song, int) (*domain.Revision, error)
+	Diff(context.Context, *domain.Song, int, int) (*domain.RevisionDiff, error)
+	Restore(context.Context, *domain.Song, int) (*domain.SongInfo, error)
+	Trash(context.Context, *domain.Batch) ([]*domain.TrashedSong, error)
+	Untrash(context.Context, *domain.Song) error
+	Import(context.Context, []*domain.BulkSong, *domain.BulkOptions) ([]*domain.BulkResult, error)
+	Export(context.Context, *domain.SongSearch, func(*domain.ExportedSong) error) error
+	ImportTags(context.Context, []*audiotag.Tags, *domain.BulkOptions) ([]*domain.TagsResult, error)
+	Favorite(context.Context, *domain.Song) error
+	Unfavorite(context.Context, *domain.Song) error
+	Favorites(context.Context, *domain.Batch) ([]*domain.FavoriteSong, error)
+	Rate(context.Context, *domain.Song, *domain.RatingUpdate) error
+	Unrate(context.Context, *domain.Song) error
+	Play(context.Context, *domain.Song) error
+	History(context.Context, *domain.Batch) ([]*domain.Play, error)
+	Top(context.Context, *domain.TopSearch) ([]*domain.TopSong, error)
+	CreateGenre(context.Context, *domain.Genre) (*domain.Genre, error)
+	Genres(context.Context) ([]*domain.Genre, error)
+	UpdateGenre(context.Context, *domain.Genre) (*domain.Genre, error)
+	DeleteGenre(context.Context, uuid.UUID) error
+	SetGenres(context.Context, *domain.Song, *domain.SongGenres) error
+	SetTags(context.Context, *domain.Song, *domain.SongTags) error
+	SetCredits(context.Context, *domain.Song, *domain.SongCredits) error
+}
+
+// SongsPolicy checks permissions of the user and passes allowed calls to the songs service.
+// Trash is managed with the delete permission, since songs are deleted from it.
+// Favorites, ratings and plays of the user require the feedback permission, top songs are read by everyone who can read songs.
+// Genre taxonomy is managed with its own permission, genres, tags and credits of a song are set with the update permission.
+type SongsPolicy struct {
+	srv songsService
+
+	policy *Policy
+}
+
+func NewSongsPolicy(srv songsService, policy *Policy) *SongsPolicy {
+	return &SongsPolicy{
+		srv:    srv,
+		policy: policy,
+	}
+}
+
+func (s *SongsPolicy) Info(ctx context.Context, song *domain.Song, languages ...string) (*domain.SongInfo, error) {
+	err := s.policy.Check(ctx, domain.PermissionSongsRead)
+	if err != nil {
+		return nil, err
+	}
+	return s.srv.Info(ctx, song, languages...)
+}
+
+func (s *SongsPolicy) Create(ctx context.Context, song *domain.Song) (uuid.UUID, error) {
+	err := s.policy.Check(ctx, domain.PermissionSongsCreate)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return s.srv.Create(ctx, song)
+}
+
+func (s *SongsPolicy) Delete(ctx context.Context, song *domain.Song) error {
+	err := s.policy.Check(ctx, domain.PermissionSongsDelete)
+	if err != nil {
+		return err
+	}
+	return s.srv.Delete(ctx, song)
+}
+
+func (s *SongsPolicy) Update(ctx context.Context, song *domain.Song, update *domain.SongUpdate) error {
+	err := s.policy.Check(ctx, domain.PermissionSongsUpdate)
+	if err != nil {
+		return err
+	}
+	return s.srv.Update(ctx, song, update)
+}
+
+func (s *SongsPolicy) GetLyrics(ctx context.Context, song *domain.Song, batch *domain.LyricsBatch) (*domain.LyricsResult, error) {
+	err := s.policy.Check(ctx, domain.PermissionSongsRead)
+	if err != nil {
+		return nil, err
+	}
+	return s.srv.GetLyrics(ctx, song, batch)
+}
+
+func (s *SongsPolicy) Search(ctx context.Context, search *domain.SongSearch) (*domain.SearchResult, error) {
+	err := s.policy.Check(ctx, domain.PermissionSongsRead)
+	if err != nil {
+		return nil, err
+	}
+	return s.srv.Search(ctx, search)
+}
+
+func (s *SongsPolicy) ImportLRC(ctx context.Context, song *domain.Song, file *lrc.File) error {
+	err := s.policy.Check(ctx, domain.PermissionSongsUpdate)
+	if err != nil {
+		return err
+	}
+	return s.srv.ImportLRC(ctx, song, file)
+}
+
+func (s *SongsPolicy) ExportLRC(ctx context.Context, song *domain.Song) (*lrc.File, error) {
+	err := s.policy.Check(ctx, domain.PermissionSongsRead)
+	if err != nil {
+		return nil, err
+	}
+	return s.srv.ExportLRC(ctx, song)
+}
+
+func (s *SongsPolicy) Languages(ctx context.Context, song *domain.Song) (*domain.Languages, error) {
+	err := s.policy.Check(ctx, domain.PermissionSongsRead)
+	if err != nil {
+		return nil, err
+	}
+	return s.srv.Languages(ctx, song)
+}
+
+func (s *SongsPolicy) Translation(ctx context.Context, song *domain.Song, language string) (*domain.Translation, error) {
+	err := s.policy.Check(ctx, domain.PermissionSongsRead)
+	if err != nil {
+		return nil, err
+	}
+	return s.srv.Translation(ctx, song, language)
+}
+
+func (s *SongsPolicy) SetTranslation(ctx context.Context, song *domain.Song, translation *domain.Translation) error {
+	err := s.policy.Check(ctx, domain.PermissionSongsUpdate)
+	if err != nil {
+		return err
+	}
+	return s.srv.SetTranslation(ctx, song, translation)
+}
+
+func (s *SongsPolicy) DeleteTranslation(ctx context.Context, song *domain.Song, language string) error {
+	err := s.policy.Check(ctx, domain.PermissionSongsUpdate)
+	if err != nil {
+		return err
+	}
+	return s.srv.DeleteTranslation(ctx, song, language)
+}
+
+func (s *SongsPolicy) Revisions(ctx context.Context, song *domain.Song, batch *domain.Batch) ([]*domain.Revision, error) {
+	err := s.policy.Check(ctx, domain.PermissionSongsRead)
+	if err != nil {
+		return nil, err
+	}
+	return s.srv.Revisions(ctx, song, batch)
+}
+
+func (s *SongsPolicy) Revision(ctx context.Context, song *domain.Song, number int) (*domain.Revision, error) {
+	err := s.policy.Check(ctx, domain.PermissionSongsRead)
+	if err != nil {
+		return nil, err
+	}
+	return s.srv.Revision(ctx, song, number)
+}
+
+func (s *SongsPolicy) Diff(ctx context.Context, song *domain.Song, from, to int) (*domain.RevisionDiff, error) {
+	err := s.policy.Check(ctx, domain.PermissionSongsRead)
+	if err != nil {
+		return nil, err
+	}
+	return s.srv.Diff(ctx, song, from, to)
+}
+
+func (s *SongsPolicy) Restore(ctx context.Context, song *domain.Song, number int) (*domain.SongInfo, error) {
+	err := s.policy.Check(ctx, domain.PermissionSongsUpdate)
+	if err != nil {
+		return nil, err
+	}
+	return s.srv.Restore(ctx, song, number)
+}
+
+func (s *SongsPolicy) Trash(ctx context.Context, batch *domain.Batch) ([]*domain.TrashedSong, error) {
+	err := s.policy.Check(ctx, domain.PermissionSongsDelete)
+	if err != nil {
+		return nil, err
+	}
+	return s.srv.Trash(ctx, batch)
+}
+
+func (s *SongsPolicy) Untrash(ctx context.Context, song *domain.Song) error {
+	err := s.policy.Check(ctx, domain.PermissionSongsDelete)
+	if err != nil {
+		return err
+	}
+	return s.srv.Untrash(ctx, song)
+}
+
+// Upsert updates existing songs, so it also requires the update permission.
+func (s *SongsPolicy) Import(ctx context.Context, songs []*domain.BulkSong, options *domain.BulkOptions) ([]*domain.BulkResult, error) {
+	err := s.policy.CheckAll(ctx, importPermissions(options)...)
+	if err != nil {
+		return nil, err
+	}
+	return s.srv.Import(ctx, songs, options)
+}
+
+func (s *SongsPolicy) Export(ctx context.Context, search *domain.SongSearch, fn func(*domain.ExportedSong) error) error {
+	err := s.policy.Check(ctx, domain.PermissionSongsRead)
+	if err != nil {
+		return err
+	}
+	return s.srv.Export(ctx, search, fn)
+}
+
+func (s *SongsPolicy) ImportTags(ctx context.Context, files []*audiotag.Tags, options *domain.BulkOptions) ([]*domain.TagsResult, error) {
+	err := s.policy.CheckAll(ctx, importPermissions(options)...)
+	if err != nil {
+		return nil, err
+	}
+	return s.srv.ImportTags(ctx, files, options)
+}
+
+func (s *SongsPolicy) Favorite(ctx context.Context, song *domain.Song) error {
+	err := s.policy.Check(ctx, domain.PermissionSongsFeedback)
+	if err != nil {
+		return err
+	}
+	return s.srv.Favorite(ctx, song)
+}
+
+func (s *SongsPolicy) Unfavorite(ctx context.Context, song *domain.Song) error {
+	err := s.policy.Check(ctx, domain.PermissionSongsFeedback)
+	if err != nil {
+		return err
+	}
+	return s.srv.Unfavorite(ctx, song)
+}
+
+func (s *SongsPolicy) Favorites(ctx context.Context, batch *domain.Batch) ([]*domain.FavoriteSong, error) {
+	err := s.policy.Check(ctx, domain.PermissionSongsFeedback)
+	if err != nil {
+		return nil, err
+	}
+	return s.srv.Favorites(ctx, batch)
+}
+
+func (s *SongsPolicy) Rate(ctx context.Context, song *domain.Song, update *domain.RatingUpdate) error {
+	err := s.policy.Check(ctx, domain.PermissionSongsFeedback)
+	if err != nil {
+		return err
+	}
+	return s.srv.Rate(ctx, song, update)
+}
+
+func (s *SongsPolicy) Unrate(ctx context.Context, song *domain.Song) error {
+	err := s.policy.Check(ctx, domain.PermissionSongsFeedback)
+	if err != nil {
+		return err
+	}
+	return s.srv.Unrate(ctx, song)
+}
+
+func (s *SongsPolicy) Play(ctx context.Context, song *domain.Song) error {
+	err := s.policy.Check(ctx, domain.PermissionSongsFeedback)
+	if err != nil {
+		return err
+	}
+	return s.srv.Play(ctx, song)
+}
+
+func (s *SongsPolicy) History(ctx context.Context, batch *domain.Batch) ([]*domain.Play, error) {
+	err := s.policy.Check(ctx, domain.PermissionSongsFeedback)
+	if err != nil {
+		return nil, err
+	}
+	return s.srv.History(ctx, batch)
+}
+
+func (s *SongsPolicy) Top(ctx context.Context, search *domain.TopSearch) ([]*domain.TopSong, error) {
+	err := s.policy.Check(ctx, domain.PermissionSongsRead)
+	if err != nil {
+		return nil, err
+	}
+	return s.srv.Top(ctx, search)
+}
+
+func (s *SongsPolicy) CreateGenre(ctx context.Context, genre *domain.Genre) (*domain.Genre, error) {
+	err := s.policy.Check(ctx, domain.PermissionGenresManage)
+	if err != nil {
+		return nil, err
+	}
+	return s.srv.CreateGenre(ctx, genre)
+}
+
+func (s *SongsPolicy) Genres(ctx context.Context) ([]*domain.Genre, error) {
+	err := s.policy.Check(ctx, domain.PermissionSongsRead)
+	if err != nil {
+		return nil, err
+	}
+	return s.srv.Genres(ctx)
+}
+
+func (s *SongsPolicy) UpdateGenre(ctx context.Context, genre *domain.Genre) (*domain.Genre, error) {
+	err := s.policy.Check(ctx, domain.PermissionGenresManage)
+	if err != nil {
+		return nil, err
+	}
+	return s.srv.UpdateGenre(ctx, genre)
+}
+
+func (s *SongsPolicy) DeleteGenre(ctx context.Context, id uuid.UUID) error {
+	err := s.policy.Check(ctx, domain.PermissionGenresManage)
+	if err != nil {
+		return err
+	}
+	return s.srv.DeleteGenre(ctx, id)
+}
+
+func (s *SongsPolicy) SetGenres(ctx context.Context, song *domain.Song, genres *domain.SongGenres) error {
+	err := s.policy.Check(ctx, domain.PermissionSongsUpdate)
+	if err != nil {
+		return err
+	}
+	return s.srv.SetGenres(ctx, song, genres)
+}
+
+func (s *SongsPolicy) SetTags(ctx context.Context, song *domain.Song, tags *domain.SongTags) error {
+	err := s.policy.Check(ctx, domain.PermissionSongsUpdate)
+	if err != nil {
+		return err
+	}
+	return s.srv.SetTags(ctx, song, tags)
+}
+
+func (s *SongsPolicy) SetCredits(ctx context.Context, song *domain.Song, credits *domain.SongCredits) error {
+	err := s.policy.Check(ctx, domain.PermissionSongsUpdate)
+	if err != nil {
+		return err
+	}
+	return s.srv.SetCredits(ctx, song, credits)
+}
+
+func importPermissions(options *domain.BulkOptions) []string {
+	if options.Mode == domain.BulkUpsert {
+		return []string{domain.PermissionSongsCreate, domain.PermissionSongsUpdate}
+	}
+	return []string{domain.PermissionSongsCreate}
+}
@@ -0,0 +1,43 @@
+package policy
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+)
+
+type usersService interface {
+	Users(context.Context, *domain.Batch) ([]*domain.User, error)
+	SetRole(context.Context, uuid.UUID, *domain.RoleUpdate) (*domain.User, error)
+}
+
+// UsersPolicy allows management of users only with the users:manage permission.
+type UsersPolicy struct {
+	srv usersService
+
+	policy *Policy
+}
+
+func NewUsersPolicy(srv usersService, policy *Policy) *UsersPolicy {
+	return &UsersPolicy{
+		srv:    srv,
+		policy: policy,
+	}
+}
+
+func (u *UsersPolicy) Users(ctx context.Context, batch *domain.Batch) ([]*domain.User, error) {
+	err := u.policy.Check(ctx, domain.PermissionUsersManage)
+	if err != nil {
+		return nil, err
+	}
+	return u.srv.Users(ctx, batch)
+}
+
+func (u *UsersPolicy) SetRole(ctx context.Context, id uuid.UUID, update *domain.RoleUpdate) (*domain.User, error) {
+	err := u.policy.Check(ctx, domain.PermissionUsersManage)
+	if err != nil {
+		return nil, err
+	}
+	return u.srv.SetRole(ctx, id, update)
+}
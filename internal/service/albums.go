@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+)
+
+type albumsStorage interface {
+	Create(context.Context, *domain.Album) (*domain.Album, error)
+	Get(context.Context, uuid.UUID) (*domain.Album, error)
+	List(context.Context, *domain.AlbumSearch) ([]*domain.Album, error)
+	Update(context.Context, uuid.UUID, *domain.AlbumUpdate) (*domain.Album, error)
+	Delete(context.Context, uuid.UUID) error
+	GetTracks(context.Context, uuid.UUID) ([]*domain.Track, error)
+	SetTracks(context.Context, uuid.UUID, *domain.Tracklist) ([]*domain.Track, error)
+}
+
+type AlbumsService struct {
+	st albumsStorage
+}
+
+func NewAlbumsService(storage albumsStorage) *AlbumsService {
+	return &AlbumsService{
+		st: storage,
+	}
+}
+
+func (s *AlbumsService) Create(ctx context.Context, album *domain.Album) (*domain.Album, error) {
+	return s.st.Create(ctx, album)
+}
+
+func (s *AlbumsService) Get(ctx context.Context, id uuid.UUID) (*domain.Album, error) {
+	return s.st.Get(ctx, id)
+}
+
+func (s *AlbumsService) List(ctx context.Context, search *domain.AlbumSearch) ([]*domain.Album, error) {
+	return s.st.List(ctx, search)
+}
+
+func (s *AlbumsService) Update(ctx context.Context, id uuid.UUID, update *domain.AlbumUpdate) (*domain.Album, error) {
+	return s.st.Update(ctx, id, update)
+}
+
+func (s *AlbumsService) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.st.Delete(ctx, id)
+}
+
+func (s *AlbumsService) GetTracks(ctx context.Context, albumID uuid.UUID) ([]*domain.Track, error) {
+	return s.st.GetTracks(ctx, albumID)
+}
+
+func (s *AlbumsService) SetTracks(ctx context.Context, albumID uuid.UUID, tracklist *domain.Tracklist) ([]*domain.Track, error) {
+	return s.st.SetTracks(ctx, albumID, tracklist)
+}
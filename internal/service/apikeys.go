@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+	apikey "github.com/qreaqtor/music-library/pkg/apiKey"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+)
+
+// Last use of API key is saved at most once per this interval, so requests don't write on every call.
+const lastUsedPrecision = time.Minute
+
+type apiKeysStorage interface {
+	CreateKey(context.Context, *domain.APIKeyAccount) (*domain.APIKey, error)
+	Keys(context.Context, *domain.Batch) ([]*domain.APIKey, error)
+	KeyByPrefix(context.Context, string) (*domain.APIKeyAccount, error)
+	RotateKey(ctx context.Context, id uuid.UUID, prefix string, hash []byte) (*domain.APIKey, error)
+	RevokeKey(context.Context, uuid.UUID) (*domain.APIKey, error)
+	TouchKey(ctx context.Context, id uuid.UUID, usedAt time.Time) error
+}
+
+// APIKeysService mints API keys of service clients, which can't log in interactively.
+// Keys are stored hashed, so a lost key can only be rotated.
+type APIKeysService struct {
+	st apiKeysStorage
+
+	keys *apikey.Generator
+}
+
+func NewAPIKeysService(storage apiKeysStorage, keys *apikey.Generator) *APIKeysService {
+	return &APIKeysService{
+		st:   storage,
+		keys: keys,
+	}
+}
+
+// The user of the context is saved as creator of the key.
+func (s *APIKeysService) Create(ctx context.Context, create *domain.APIKeyCreate) (*domain.IssuedAPIKey, error) {
+	if create.ExpiresAt != nil && !create.ExpiresAt.After(time.Now()) {
+		return nil, domain.ErrKeyExpiry
+	}
+
+	generated, err := s.keys.Generate()
+	if err != nil {
+		return nil, err
+	}
+
+	account := &domain.APIKeyAccount{
+		APIKey: domain.APIKey{
+			Name:        create.Name,
+			Prefix:      generated.Prefix,
+			Permissions: create.Permissions,
+			ExpiresAt:   create.ExpiresAt,
+		},
+		Hash: generated.Hash,
+	}
+	if principal, ok := domain.PrincipalFrom(ctx); ok {
+		account.CreatedBy = principal.ID
+	}
+
+	key, err := s.st.CreateKey(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.IssuedAPIKey{
+		APIKey: *key,
+		Key:    generated.Value,
+	}, nil
+}
+
+func (s *APIKeysService) Keys(ctx context.Context, batch *domain.Batch) ([]*domain.APIKey, error) {
+	return s.st.Keys(ctx, batch)
+}
+
+// Issues a new key with the same name, permissions and expiration, the old key is rejected right after it.
+func (s *APIKeysService) Rotate(ctx context.Context, id uuid.UUID) (*domain.IssuedAPIKey, error) {
+	generated, err := s.keys.Generate()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.st.RotateKey(ctx, id, generated.Prefix, generated.Hash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.IssuedAPIKey{
+		APIKey: *key,
+		Key:    generated.Value,
+	}, nil
+}
+
+func (s *APIKeysService) Revoke(ctx context.Context, id uuid.UUID) (*domain.APIKey, error) {
+	return s.st.RevokeKey(ctx, id)
+}
+
+// Checks the key, it must be neither revoked nor expired. Returns principal with permissions of the key.
+// Unknown, revoked and expired keys are not told apart, all of them are domain.ErrInvalidToken.
+func (s *APIKeysService) Authenticate(ctx context.Context, value string) (*domain.Principal, error) {
+	prefix, err := s.keys.Prefix(value)
+	if err != nil {
+		return nil, domain.ErrInvalidToken
+	}
+
+	account, err := s.st.KeyByPrefix(ctx, prefix)
+	if errors.Is(err, domain.ErrUnknownResourse) {
+		return nil, domain.ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	if !apikey.Verify(value, account.Hash) || !account.Active(now) {
+		return nil, domain.ErrInvalidToken
+	}
+
+	if account.LastUsedAt == nil || now.Sub(*account.LastUsedAt) >= lastUsedPrecision {
+		// the request is served anyway, last use is only informational
+		err = s.st.TouchKey(ctx, account.ID, now)
+		if err != nil {
+			slog.Warn(
+				"failed to save last use of api key",
+				"key", account.ID,
+				"err", err,
+				"operation", logmsg.ExtractOperationID(ctx),
+			)
+		}
+	}
+
+	return &domain.Principal{
+		ID:          account.ID,
+		Name:        account.Name,
+		Role:        domain.RoleAPIKey,
+		SessionID:   account.ID,
+		Permissions: account.Permissions,
+	}, nil
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+)
+
+type artistsStorage interface {
+	Create(context.Context, *domain.Artist) (*domain.Artist, error)
+	Get(context.Context, uuid.UUID) (*domain.Artist, error)
+	List(context.Context, *domain.ArtistSearch) ([]*domain.Artist, error)
+	Update(context.Context, uuid.UUID, *domain.ArtistUpdate) (*domain.Artist, error)
+	Delete(context.Context, uuid.UUID) error
+}
+
+type ArtistsService struct {
+	st artistsStorage
+}
+
+func NewArtistsService(storage artistsStorage) *ArtistsService {
+	return &ArtistsService{
+		st: storage,
+	}
+}
+
+func (s *ArtistsService) Create(ctx context.Context, artist *domain.Artist) (*domain.Artist, error) {
+	return s.st.Create(ctx, artist)
+}
+
+func (s *ArtistsService) Get(ctx context.Context, id uuid.UUID) (*domain.Artist, error) {
+	return s.st.Get(ctx, id)
+}
+
+func (s *ArtistsService) List(ctx context.Context, search *domain.ArtistSearch) ([]*domain.Artist, error) {
+	return s.st.List(ctx, search)
+}
+
+func (s *ArtistsService) Update(ctx context.Context, id uuid.UUID, update *domain.ArtistUpdate) (*domain.Artist, error) {
+	return s.st.Update(ctx, id, update)
+}
+
+func (s *ArtistsService) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.st.Delete(ctx, id)
+}
@@ -0,0 +1,279 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+	authtoken "github.com/qreaqtor/music-library/pkg/authToken"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Hash which is compared with the password of unknown user, so login takes the same time for unknown users.
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+
+type usersStorage interface {
+	CreateUser(context.Context, *domain.UserAccount) (*domain.User, error)
+	User(context.Context, uuid.UUID) (*domain.User, error)
+	UserByName(context.Context, string) (*domain.UserAccount, error)
+	CreateSession(context.Context, *domain.Session) error
+	Session(context.Context, uuid.UUID) (*domain.Session, error)
+	RotateSession(ctx context.Context, id, refreshID, newRefreshID uuid.UUID, expiresAt time.Time) error
+	RevokeSession(context.Context, uuid.UUID) error
+	RevokeSessions(context.Context, uuid.UUID) (int, error)
+	Users(context.Context, *domain.Batch) ([]*domain.User, error)
+	SetRole(ctx context.Context, id uuid.UUID, role string) (*domain.User, error)
+}
+
+// AuthService registers users and issues tokens for them. Access tokens are short lived,
+// refresh tokens are rotated on every use and a reused refresh token revokes its session.
+type AuthService struct {
+	st usersStorage
+
+	tokens *authtoken.Issuer
+
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+
+	// role of registered users
+	defaultRole string
+}
+
+func NewAuthService(storage usersStorage, tokens *authtoken.Issuer, accessTTL, refreshTTL time.Duration, defaultRole string) *AuthService {
+	return &AuthService{
+		st:          storage,
+		tokens:      tokens,
+		accessTTL:   accessTTL,
+		refreshTTL:  refreshTTL,
+		defaultRole: defaultRole,
+	}
+}
+
+func (s *AuthService) Register(ctx context.Context, credentials *domain.Credentials) (*domain.User, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(credentials.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.st.CreateUser(ctx, &domain.UserAccount{
+		User:         domain.User{Name: domain.CleanName(credentials.Name), Role: s.defaultRole},
+		PasswordHash: hash,
+	})
+}
+
+// Makes the user admin, the user is created if there is no user with the name.
+// Returns domain.ErrUserExists if the user exists with another password, so a user
+// who registered with the name of the admin doesn't become admin.
+func (s *AuthService) EnsureAdmin(ctx context.Context, credentials *domain.Credentials) (*domain.User, error) {
+	name := domain.CleanName(credentials.Name)
+
+	account, err := s.st.UserByName(ctx, name)
+	if errors.Is(err, domain.ErrUnknownResourse) {
+		hash, err := bcrypt.GenerateFromPassword([]byte(credentials.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, err
+		}
+
+		return s.st.CreateUser(ctx, &domain.UserAccount{
+			User:         domain.User{Name: name, Role: domain.RoleAdmin},
+			PasswordHash: hash,
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = bcrypt.CompareHashAndPassword(account.PasswordHash, []byte(credentials.Password))
+	if err != nil {
+		return nil, domain.ErrUserExists
+	}
+
+	if account.Role == domain.RoleAdmin {
+		return &account.User, nil
+	}
+
+	return s.st.SetRole(ctx, account.ID, domain.RoleAdmin)
+}
+
+// Starts a new session of the user. Returns domain.ErrInvalidCredentials for unknown name or wrong password.
+func (s *AuthService) Login(ctx context.Context, credentials *domain.Credentials) (*domain.Tokens, error) {
+	account, err := s.st.UserByName(ctx, domain.CleanName(credentials.Name))
+	if errors.Is(err, domain.ErrUnknownResourse) {
+		bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(credentials.Password))
+		return nil, domain.ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = bcrypt.CompareHashAndPassword(account.PasswordHash, []byte(credentials.Password))
+	if err != nil {
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	session := &domain.Session{
+		ID:     uuid.New(),
+		UserID: account.ID,
+	}
+
+	access, refresh, err := s.issue(&account.User, session)
+	if err != nil {
+		return nil, err
+	}
+
+	session.RefreshID, session.ExpiresAt = refresh.ID, refresh.ExpiresAt
+
+	err = s.st.CreateSession(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	return access, nil
+}
+
+// Issues new tokens of the session, the refresh token can't be used again.
+// Reuse of a replaced refresh token means it was stolen, so the whole session is revoked.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*domain.Tokens, error) {
+	claims, err := s.tokens.Parse(refreshToken, authtoken.TypeRefresh)
+	if err != nil {
+		return nil, domain.ErrInvalidToken
+	}
+
+	session, err := s.activeSession(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.RefreshID != claims.ID {
+		err = s.st.RevokeSession(ctx, session.ID)
+		if err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrInvalidToken
+	}
+
+	user, err := s.st.User(ctx, session.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, refresh, err := s.issue(user, session)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.st.RotateSession(ctx, session.ID, claims.ID, refresh.ID, refresh.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// Checks the access token, its session must not be revoked.
+// The user is read on every request, so changes of the role apply immediately.
+func (s *AuthService) Authenticate(ctx context.Context, accessToken string) (*domain.Principal, error) {
+	claims, err := s.tokens.Parse(accessToken, authtoken.TypeAccess)
+	if err != nil {
+		return nil, domain.ErrInvalidToken
+	}
+
+	_, err = s.activeSession(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.st.User(ctx, claims.UserID)
+	if errors.Is(err, domain.ErrUnknownResourse) {
+		return nil, domain.ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.Principal{
+		ID:        user.ID,
+		Name:      user.Name,
+		Role:      user.Role,
+		SessionID: claims.SessionID,
+	}, nil
+}
+
+// Revokes the session, its access and refresh tokens are rejected after it.
+func (s *AuthService) Logout(ctx context.Context, sessionID uuid.UUID) error {
+	return s.st.RevokeSession(ctx, sessionID)
+}
+
+// Revokes all sessions of the user, returns number of revoked sessions.
+func (s *AuthService) LogoutAll(ctx context.Context, userID uuid.UUID) (int, error) {
+	return s.st.RevokeSessions(ctx, userID)
+}
+
+func (s *AuthService) User(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	return s.st.User(ctx, id)
+}
+
+func (s *AuthService) Users(ctx context.Context, batch *domain.Batch) ([]*domain.User, error) {
+	return s.st.Users(ctx, batch)
+}
+
+// Users can't change their own role, so the last admin can't lose access to users.
+func (s *AuthService) SetRole(ctx context.Context, id uuid.UUID, update *domain.RoleUpdate) (*domain.User, error) {
+	if principal, ok := domain.PrincipalFrom(ctx); ok && principal.ID == id {
+		return nil, domain.ErrOwnRole
+	}
+
+	return s.st.SetRole(ctx, id, update.Role)
+}
+
+// Returns session of the token if it is neither revoked nor expired.
+func (s *AuthService) activeSession(ctx context.Context, claims *authtoken.Claims) (*domain.Session, error) {
+	session, err := s.st.Session(ctx, claims.SessionID)
+	if errors.Is(err, domain.ErrUnknownResourse) {
+		return nil, domain.ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if session.Revoked || session.UserID != claims.UserID || !session.ExpiresAt.After(time.Now()) {
+		return nil, domain.ErrInvalidToken
+	}
+
+	return session, nil
+}
+
+// Returns tokens for the session and claims of the refresh token.
+func (s *AuthService) issue(user *domain.User, session *domain.Session) (*domain.Tokens, *authtoken.Claims, error) {
+	access := &authtoken.Claims{
+		Type:      authtoken.TypeAccess,
+		UserID:    user.ID,
+		Name:      user.Name,
+		SessionID: session.ID,
+	}
+
+	accessToken, err := s.tokens.Issue(access, s.accessTTL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	refresh := &authtoken.Claims{
+		Type:      authtoken.TypeRefresh,
+		UserID:    user.ID,
+		SessionID: session.ID,
+	}
+
+	refreshToken, err := s.tokens.Issue(refresh, s.refreshTTL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &domain.Tokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    domain.TokenTypeBearer,
+		ExpiresIn:    int(s.accessTTL.Seconds()),
+	}, refresh, nil
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+
+	"github.com/qreaqtor/music-library/internal/domain"
+)
+
+// Imports songs as a single batch, results are in the order of songs.
+// Names are cleaned up the same way as by Create, songs with invalid timings
+// get error results and are not passed to the storage.
+func (s *SongsService) Import(ctx context.Context, songs []*domain.BulkSong, options *domain.BulkOptions) ([]*domain.BulkResult, error) {
+	results := make([]*domain.BulkResult, len(songs))
+
+	valid := make([]*domain.BulkSong, 0, len(songs))
+	positions := make([]int, 0, len(songs))
+
+	for i, song := range songs {
+		err := domain.CheckTimings(song.Lyrics)
+		if err != nil {
+			results[i] = &domain.BulkResult{Status: domain.BulkError, Error: err.Error()}
+			continue
+		}
+
+		song.Group = domain.CleanName(song.Group)
+		song.SongName = domain.CleanName(song.SongName)
+
+		valid = append(valid, song)
+		positions = append(positions, i)
+	}
+
+	if len(valid) == 0 {
+		return results, nil
+	}
+
+	imported, err := s.st.Import(ctx, valid, options)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, result := range imported {
+		results[positions[i]] = result
+	}
+
+	return results, nil
+}
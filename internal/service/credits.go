@@ -0,0 +1,18 @@
+package service
+
+import (
+	"context"
+
+	"github.com/qreaqtor/music-library/internal/domain"
+)
+
+// Artist names are cleaned like group names, so credits and groups refer to the same artists.
+func (s *SongsService) SetCredits(ctx context.Context, song *domain.Song, credits *domain.SongCredits) error {
+	cleaned := make([]domain.Credit, 0, len(credits.Credits))
+	for _, credit := range credits.Credits {
+		credit.Artist = domain.CleanName(credit.Artist)
+		cleaned = append(cleaned, credit)
+	}
+
+	return s.st.SetCredits(ctx, song, cleaned)
+}
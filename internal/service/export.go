@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+
+	"github.com/qreaqtor/music-library/internal/domain"
+)
+
+// Passes all found songs to fn in search order, pagination of the search is ignored.
+// Export stops at the first error returned by fn.
+func (s *SongsService) Export(ctx context.Context, search *domain.SongSearch, fn func(*domain.ExportedSong) error) error {
+	if search.Threshold == 0 {
+		search.Threshold = s.fuzzyThreshold
+	}
+
+	search.Tags = domain.NormalizeTags(search.Tags)
+
+	return s.st.Export(ctx, search, fn)
+}
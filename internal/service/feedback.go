@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+)
+
+// Adding favorite song again is not an error.
+func (s *SongsService) Favorite(ctx context.Context, song *domain.Song) error {
+	userID, err := feedbackUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.st.AddFavorite(ctx, song, userID)
+}
+
+func (s *SongsService) Unfavorite(ctx context.Context, song *domain.Song) error {
+	userID, err := feedbackUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.st.RemoveFavorite(ctx, song, userID)
+}
+
+// Favorite songs of the user of the context, recently added first.
+func (s *SongsService) Favorites(ctx context.Context, batch *domain.Batch) ([]*domain.FavoriteSong, error) {
+	userID, err := feedbackUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.st.Favorites(ctx, userID, batch)
+}
+
+// Replaces the previous rating of the user.
+func (s *SongsService) Rate(ctx context.Context, song *domain.Song, update *domain.RatingUpdate) error {
+	userID, err := feedbackUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.st.Rate(ctx, song, userID, update.Rating)
+}
+
+func (s *SongsService) Unrate(ctx context.Context, song *domain.Song) error {
+	userID, err := feedbackUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.st.Unrate(ctx, song, userID)
+}
+
+// Saves a play of the song by the user of the context at the current time.
+func (s *SongsService) Play(ctx context.Context, song *domain.Song) error {
+	userID, err := feedbackUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.st.AddPlay(ctx, song, userID, time.Now())
+}
+
+// Listening history of the user of the context, recent plays first.
+func (s *SongsService) History(ctx context.Context, batch *domain.Batch) ([]*domain.Play, error) {
+	userID, err := feedbackUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.st.Plays(ctx, userID, batch)
+}
+
+// Songs with most plays in the period which ends now.
+func (s *SongsService) Top(ctx context.Context, search *domain.TopSearch) ([]*domain.TopSong, error) {
+	return s.st.TopSongs(ctx, search.Since(time.Now()), &search.Batch)
+}
+
+// Sets feedback of the user of the context to the song info, anonymous requests and API keys have none.
+func (s *SongsService) setFeedback(ctx context.Context, info *domain.SongInfo) error {
+	principal, ok := domain.PrincipalFrom(ctx)
+	if !ok || principal.Role == domain.RoleAPIKey {
+		return nil
+	}
+
+	var err error
+	info.Mine, err = s.st.Feedback(ctx, info.ID, principal.ID)
+	return err
+}
+
+// Returns id of the user of the context. Feedback belongs to users,
+// so API keys get domain.ErrUserRequired.
+func feedbackUser(ctx context.Context) (uuid.UUID, error) {
+	principal, ok := domain.PrincipalFrom(ctx)
+	if !ok {
+		return uuid.Nil, domain.ErrUnauthenticated
+	}
+
+	if principal.Role == domain.RoleAPIKey {
+		return uuid.Nil, domain.ErrUserRequired
+	}
+
+	return principal.ID, nil
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+)
+
+func (s *SongsService) CreateGenre(ctx context.Context, genre *domain.Genre) (*domain.Genre, error) {
+	genre.Name = domain.CleanName(genre.Name)
+	return s.st.CreateGenre(ctx, genre)
+}
+
+func (s *SongsService) Genres(ctx context.Context) ([]*domain.Genre, error) {
+	return s.st.Genres(ctx)
+}
+
+// Genre is renamed and moved to the new parent, nil parent makes it a top level genre.
+func (s *SongsService) UpdateGenre(ctx context.Context, genre *domain.Genre) (*domain.Genre, error) {
+	genre.Name = domain.CleanName(genre.Name)
+	return s.st.UpdateGenre(ctx, genre)
+}
+
+func (s *SongsService) DeleteGenre(ctx context.Context, id uuid.UUID) error {
+	return s.st.DeleteGenre(ctx, id)
+}
+
+func (s *SongsService) SetGenres(ctx context.Context, song *domain.Song, genres *domain.SongGenres) error {
+	return s.st.SetGenres(ctx, song, genres.Genres)
+}
+
+// Tags are normalized, so "Live " and "live" are the same tag.
+func (s *SongsService) SetTags(ctx context.Context, song *domain.Song, tags *domain.SongTags) error {
+	return s.st.SetTags(ctx, song, domain.NormalizeTags(tags.Tags))
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/qreaqtor/music-library/internal/domain"
+	"github.com/qreaqtor/music-library/pkg/lrc"
+)
+
+// Replaces lyrics of the song with lines of the LRC file. Consecutive lines form a verse,
+// verses are separated by lines without text. Line ends where the next line starts,
+// unless its end is set by enhanced LRC. ID tags of the file are ignored.
+func (s *SongsService) ImportLRC(ctx context.Context, song *domain.Song, file *lrc.File) error {
+	verses := versesFromLRC(file)
+	if len(verses) == 0 {
+		return domain.ErrEmptyUpdate
+	}
+
+	err := domain.CheckTimings(verses)
+	if err != nil {
+		return err
+	}
+
+	return s.st.Update(ctx, song, &domain.SongUpdate{Lyrics: verses})
+}
+
+// Renders synced lyrics of the song as LRC file with artist and title tags.
+// Verses are separated by lines without text at the end of their last line.
+// Returns domain.ErrLyricsNotSynced if some verse has no timed lines.
+func (s *SongsService) ExportLRC(ctx context.Context, song *domain.Song) (*lrc.File, error) {
+	info, err := s.st.Info(ctx, song)
+	if err != nil {
+		return nil, err
+	}
+
+	verses, err := s.st.Verses(ctx, song)
+	if err != nil {
+		return nil, err
+	}
+
+	file := &lrc.File{
+		Tags: []lrc.Tag{
+			{Key: lrc.TagArtist, Value: info.Group},
+			{Key: lrc.TagTitle, Value: info.SongName},
+		},
+		Lines: make([]lrc.Line, 0),
+	}
+
+	for i, verse := range verses {
+		if len(verse.Lines) == 0 {
+			return nil, domain.ErrLyricsNotSynced
+		}
+
+		for _, line := range verse.Lines {
+			file.Lines = append(file.Lines, lrcLine(line))
+		}
+
+		end := verse.Lines[len(verse.Lines)-1].End
+		if end == 0 && i+1 < len(verses) && len(verses[i+1].Lines) != 0 {
+			end = verses[i+1].Lines[0].Start
+		}
+		if end != 0 {
+			file.Lines = append(file.Lines, lrc.Line{Time: duration(end)})
+		}
+	}
+
+	return file, nil
+}
+
+func versesFromLRC(file *lrc.File) []*domain.Verse {
+	verses := make([]*domain.Verse, 0)
+
+	var current *domain.Verse
+
+	for i, line := range file.Lines {
+		if line.Text == "" {
+			current = nil
+			continue
+		}
+
+		l := &domain.Line{
+			Start: line.Time.Milliseconds(),
+			End:   line.End.Milliseconds(),
+			Text:  line.Text,
+		}
+
+		if l.End == 0 && i+1 < len(file.Lines) && file.Lines[i+1].Time > line.Time {
+			l.End = file.Lines[i+1].Time.Milliseconds()
+		}
+
+		for _, word := range line.Words {
+			l.Words = append(l.Words, &domain.Word{
+				Start: word.Time.Milliseconds(),
+				Text:  word.Text,
+			})
+		}
+
+		if current == nil {
+			current = &domain.Verse{Section: domain.SectionVerse}
+			verses = append(verses, current)
+		}
+
+		current.Lines = append(current.Lines, l)
+	}
+
+	return verses
+}
+
+func lrcLine(line *domain.Line) lrc.Line {
+	result := lrc.Line{
+		Time: duration(line.Start),
+		End:  duration(line.End),
+		Text: line.Text,
+	}
+
+	for _, word := range line.Words {
+		result.Words = append(result.Words, lrc.Word{
+			Time: duration(word.Start),
+			Text: word.Text,
+		})
+	}
+
+	return result
+}
+
+func duration(ms int64) time.Duration {
+	return time.Duration(ms) * time.Millisecond
+}
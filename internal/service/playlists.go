@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+	"github.com/qreaqtor/music-library/pkg/playlist"
+)
+
+// Identifier of exported track, imported playlists find songs by it first.
+const songURNPrefix = "urn:uuid:"
+
+type playlistsStorage interface {
+	Create(context.Context, *domain.Playlist) (*domain.Playlist, error)
+	Get(context.Context, uuid.UUID) (*domain.Playlist, error)
+	List(context.Context, *domain.PlaylistSearch) ([]*domain.Playlist, error)
+	Update(context.Context, uuid.UUID, *domain.PlaylistUpdate) (*domain.Playlist, error)
+	Delete(context.Context, uuid.UUID) error
+	GetItems(context.Context, uuid.UUID) ([]*domain.PlaylistItem, error)
+	InsertItem(context.Context, uuid.UUID, *domain.PlaylistItemInsert) ([]*domain.PlaylistItem, error)
+	MoveItem(ctx context.Context, id uuid.UUID, from, to int) ([]*domain.PlaylistItem, error)
+	RemoveItem(ctx context.Context, id uuid.UUID, position int) ([]*domain.PlaylistItem, error)
+	Duplicate(ctx context.Context, id uuid.UUID, name string) (*domain.Playlist, error)
+	Import(context.Context, *domain.Playlist, []*domain.PlaylistEntry) (*domain.PlaylistImport, error)
+}
+
+type PlaylistsService struct {
+	st playlistsStorage
+}
+
+func NewPlaylistsService(storage playlistsStorage) *PlaylistsService {
+	return &PlaylistsService{
+		st: storage,
+	}
+}
+
+func (s *PlaylistsService) Create(ctx context.Context, playlist *domain.Playlist) (*domain.Playlist, error) {
+	playlist.Name = domain.CleanName(playlist.Name)
+	return s.st.Create(ctx, playlist)
+}
+
+func (s *PlaylistsService) Get(ctx context.Context, id uuid.UUID) (*domain.Playlist, error) {
+	return s.st.Get(ctx, id)
+}
+
+func (s *PlaylistsService) List(ctx context.Context, search *domain.PlaylistSearch) ([]*domain.Playlist, error) {
+	return s.st.List(ctx, search)
+}
+
+func (s *PlaylistsService) Update(ctx context.Context, id uuid.UUID, update *domain.PlaylistUpdate) (*domain.Playlist, error) {
+	update.Name = domain.CleanName(update.Name)
+	return s.st.Update(ctx, id, update)
+}
+
+func (s *PlaylistsService) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.st.Delete(ctx, id)
+}
+
+func (s *PlaylistsService) GetItems(ctx context.Context, id uuid.UUID) ([]*domain.PlaylistItem, error) {
+	return s.st.GetItems(ctx, id)
+}
+
+func (s *PlaylistsService) InsertItem(ctx context.Context, id uuid.UUID, item *domain.PlaylistItemInsert) ([]*domain.PlaylistItem, error) {
+	item.Group = domain.CleanName(item.Group)
+	item.SongName = domain.CleanName(item.SongName)
+	return s.st.InsertItem(ctx, id, item)
+}
+
+func (s *PlaylistsService) MoveItem(ctx context.Context, id uuid.UUID, from, to int) ([]*domain.PlaylistItem, error) {
+	return s.st.MoveItem(ctx, id, from, to)
+}
+
+func (s *PlaylistsService) RemoveItem(ctx context.Context, id uuid.UUID, position int) ([]*domain.PlaylistItem, error) {
+	return s.st.RemoveItem(ctx, id, position)
+}
+
+func (s *PlaylistsService) Duplicate(ctx context.Context, id uuid.UUID, name string) (*domain.Playlist, error) {
+	return s.st.Duplicate(ctx, id, domain.CleanName(name))
+}
+
+// Returns the playlist with its songs as tracks of playlist file.
+// Tracks are identified by song ids, songs without link have no location.
+func (s *PlaylistsService) Export(ctx context.Context, id uuid.UUID) (*playlist.Playlist, error) {
+	info, err := s.st.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.st.GetItems(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	file := &playlist.Playlist{
+		Title:   info.Name,
+		Entries: make([]playlist.Entry, 0, len(items)),
+	}
+
+	for _, item := range items {
+		file.Entries = append(file.Entries, playlist.Entry{
+			Location:   item.Link,
+			Identifier: songURNPrefix + item.ID.String(),
+			Artist:     item.Group,
+			Title:      item.SongName,
+		})
+	}
+
+	return file, nil
+}
+
+// Creates a playlist from tracks of playlist file, name of the file is used if name is empty.
+func (s *PlaylistsService) Import(ctx context.Context, name string, file *playlist.Playlist) (*domain.PlaylistImport, error) {
+	name = domain.CleanName(name)
+	if name == "" {
+		name = domain.CleanName(file.Title)
+	}
+	if name == "" {
+		name = domain.DefaultPlaylistName
+	}
+
+	// title of the file is not validated
+	if runes := []rune(name); len(runes) > domain.MaxPlaylistName {
+		name = string(runes[:domain.MaxPlaylistName])
+	}
+
+	entries := make([]*domain.PlaylistEntry, 0, len(file.Entries))
+	for _, entry := range file.Entries {
+		entries = append(entries, &domain.PlaylistEntry{
+			Identifier: entry.Identifier,
+			Link:       entry.Location,
+			Group:      domain.CleanName(entry.Artist),
+			SongName:   domain.CleanName(entry.Title),
+		})
+	}
+
+	return s.st.Import(ctx, &domain.Playlist{Name: name}, entries)
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+type trash interface {
+	PurgeTrash(context.Context, time.Duration) (int, error)
+}
+
+// Purger periodically deletes songs which are in trash longer than the retention period.
+type Purger struct {
+	trash trash
+
+	retention time.Duration
+	interval  time.Duration
+
+	done chan struct{}
+}
+
+func NewPurger(trash trash, retention, interval time.Duration) *Purger {
+	return &Purger{
+		trash:     trash,
+		retention: retention,
+		interval:  interval,
+		done:      make(chan struct{}),
+	}
+}
+
+// Purges trash right away and then every interval until ctx is done.
+// Errors are logged, failed purge is retried on the next tick.
+func (p *Purger) Start(ctx context.Context) {
+	go func() {
+		defer close(p.done)
+
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		for {
+			p.purge(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Waits until the purger is stopped.
+func (p *Purger) Wait() {
+	<-p.done
+}
+
+func (p *Purger) purge(ctx context.Context) {
+	n, err := p.trash.PurgeTrash(ctx, p.retention)
+	if err != nil {
+		slog.Error("failed to purge trash", "err", err)
+		return
+	}
+
+	if n != 0 {
+		slog.Info("trash purged", "songs", n)
+	}
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+
+	"github.com/qreaqtor/music-library/internal/domain"
+	"github.com/qreaqtor/music-library/pkg/diff"
+)
+
+func (s *SongsService) Revisions(ctx context.Context, song *domain.Song, batch *domain.Batch) ([]*domain.Revision, error) {
+	return s.st.Revisions(ctx, song, batch)
+}
+
+func (s *SongsService) Revision(ctx context.Context, song *domain.Song, number int) (*domain.Revision, error) {
+	return s.st.Revision(ctx, song, number)
+}
+
+// Compares states of the song after two revisions, from may be greater than to.
+// Lyrics are compared line by line regardless of verses.
+func (s *SongsService) Diff(ctx context.Context, song *domain.Song, from, to int) (*domain.RevisionDiff, error) {
+	fromRevision, err := s.st.Revision(ctx, song, from)
+	if err != nil {
+		return nil, err
+	}
+
+	toRevision, err := s.st.Revision(ctx, song, to)
+	if err != nil {
+		return nil, err
+	}
+
+	edits := diff.Lines(fromRevision.Song.Lines(), toRevision.Song.Lines())
+
+	lines := make([]*domain.DiffLine, 0, len(edits))
+	for _, edit := range edits {
+		lines = append(lines, &domain.DiffLine{Op: edit.Op, Text: edit.Text})
+	}
+
+	return &domain.RevisionDiff{
+		From:    from,
+		To:      to,
+		Changes: fromRevision.Song.Changes(toRevision.Song),
+		Lines:   lines,
+	}, nil
+}
+
+// Returns info of the song after restore.
+func (s *SongsService) Restore(ctx context.Context, song *domain.Song, number int) (*domain.SongInfo, error) {
+	err := s.st.Restore(ctx, song, number)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.st.Info(ctx, song)
+}
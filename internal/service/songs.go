@@ -2,49 +2,280 @@ package service
 
 import (
 	"context"
+	"log/slog"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
 )
 
 type storage interface {
 	Info(context.Context, *domain.Song) (*domain.SongInfo, error)
-	Create(context.Context, *domain.Song) error
+	Create(context.Context, *domain.Song, *domain.SongDetails) (uuid.UUID, error)
 	Delete(context.Context, *domain.Song) error
 	Update(context.Context, *domain.Song, *domain.SongUpdate) error
-	GetLyrics(context.Context, *domain.Song, *domain.Batch) ([]string, error)
-	Search(context.Context, *domain.SongSearch) ([]*domain.Song, error)
+	GetLyrics(context.Context, *domain.Song, *domain.LyricsBatch) ([]*domain.Verse, error)
+	CountLyrics(context.Context, *domain.Song, *domain.LyricsBatch) (int, error)
+	Verses(context.Context, *domain.Song) ([]*domain.Verse, error)
+	Search(context.Context, *domain.SongSearch) ([]*domain.FoundSong, error)
+	Count(context.Context, *domain.SongSearch) (int, error)
+	Suggest(context.Context, *domain.SongSearch) (*domain.Suggestions, error)
+	Languages(context.Context, *domain.Song) (*domain.Languages, error)
+	Translation(context.Context, *domain.Song, string) (*domain.Translation, error)
+	SetTranslation(context.Context, *domain.Song, *domain.Translation) error
+	DeleteTranslation(context.Context, *domain.Song, string) error
+	Revisions(context.Context, *domain.Song, *domain.Batch) ([]*domain.Revision, error)
+	Revision(context.Context, *domain.Song, int) (*domain.Revision, error)
+	Restore(context.Context, *domain.Song, int) error
+	Trash(context.Context, *domain.Batch) ([]*domain.TrashedSong, error)
+	Untrash(context.Context, *domain.Song) error
+	Purge(context.Context, time.Time) (int, error)
+	Import(context.Context, []*domain.BulkSong, *domain.BulkOptions) ([]*domain.BulkResult, error)
+	Export(context.Context, *domain.SongSearch, func(*domain.ExportedSong) error) error
+	AddFavorite(ctx context.Context, song *domain.Song, userID uuid.UUID) error
+	RemoveFavorite(ctx context.Context, song *domain.Song, userID uuid.UUID) error
+	Favorites(ctx context.Context, userID uuid.UUID, batch *domain.Batch) ([]*domain.FavoriteSong, error)
+	Rate(ctx context.Context, song *domain.Song, userID uuid.UUID, rating int) error
+	Unrate(ctx context.Context, song *domain.Song, userID uuid.UUID) error
+	AddPlay(ctx context.Context, song *domain.Song, userID uuid.UUID, playedAt time.Time) error
+	Plays(ctx context.Context, userID uuid.UUID, batch *domain.Batch) ([]*domain.Play, error)
+	TopSongs(ctx context.Context, since time.Time, batch *domain.Batch) ([]*domain.TopSong, error)
+	Feedback(ctx context.Context, songID, userID uuid.UUID) (*domain.SongFeedback, error)
+	CreateGenre(context.Context, *domain.Genre) (*domain.Genre, error)
+	Genres(context.Context) ([]*domain.Genre, error)
+	UpdateGenre(context.Context, *domain.Genre) (*domain.Genre, error)
+	DeleteGenre(context.Context, uuid.UUID) error
+	SetGenres(context.Context, *domain.Song, []string) error
+	SetTags(context.Context, *domain.Song, []string) error
+	SetCredits(context.Context, *domain.Song, []domain.Credit) error
+	Facets(context.Context, *domain.SongSearch) (*domain.Facets, error)
+}
+
+// SongDetailsProvider returns release date, text and link of the song from the external source.
+type SongDetailsProvider interface {
+	Details(context.Context, *domain.Song) (*domain.SongDetails, error)
 }
 
 type SongsService struct {
 	st storage
+
+	details SongDetailsProvider
+
+	// used if threshold is not set in search
+	fuzzyThreshold float64
 }
 
-func NewSongsService(storage storage) *SongsService {
+// details may be nil, then new songs are created without details.
+func NewSongsService(storage storage, details SongDetailsProvider, fuzzyThreshold float64) *SongsService {
 	return &SongsService{
-		st: storage,
+		st:             storage,
+		details:        details,
+		fuzzyThreshold: fuzzyThreshold,
 	}
 }
 
-func (s *SongsService) GetLyrics(ctx context.Context, song *domain.Song, batch *domain.Batch) ([]string, error) {
-	return s.st.GetLyrics(ctx, song, batch)
+// One more verse is requested to find out if there is the next (or previous for batch.Before) page.
+// Verses are translated to the best of batch.Languages if there is such translation.
+func (s *SongsService) GetLyrics(ctx context.Context, song *domain.Song, batch *domain.LyricsBatch) (*domain.LyricsResult, error) {
+	page := *batch
+	page.Limit++
+
+	verses, err := s.st.GetLyrics(ctx, song, &page)
+	if err != nil {
+		return nil, err
+	}
+
+	more := len(verses) > batch.Limit
+	backward := batch.Before != 0
+
+	if more && backward {
+		verses = verses[1:]
+	} else if more {
+		verses = verses[:batch.Limit]
+	}
+
+	result := &domain.LyricsResult{
+		Verses: verses,
+	}
+
+	translation, err := s.preferredTranslation(ctx, song, batch.Languages)
+	if err != nil {
+		return nil, err
+	}
+
+	if translation != nil {
+		result.Language = translation.Language
+		result.Verses = translate(verses, translation)
+	}
+
+	if len(verses) != 0 {
+		first, last := verses[0].Position, verses[len(verses)-1].Position
+
+		if more || backward {
+			result.Next = last
+		}
+		if more && backward || !backward && (batch.After != 0 || batch.Offset != 0) {
+			result.Prev = first
+		}
+	}
+
+	if batch.Total {
+		total, err := s.st.CountLyrics(ctx, song, batch)
+		if err != nil {
+			return nil, err
+		}
+		result.Total = &total
+	}
+
+	return result, nil
 }
 
-func (s *SongsService) Search(ctx context.Context, search *domain.SongSearch) ([]*domain.Song, error) {
-	return s.st.Search(ctx, search)
+// One more song is requested to find out if there is the next (or previous for search.Before) page.
+// Suggestions are returned only if exact search by group or song name found nothing.
+func (s *SongsService) Search(ctx context.Context, search *domain.SongSearch) (*domain.SearchResult, error) {
+	if search.Threshold == 0 {
+		search.Threshold = s.fuzzyThreshold
+	}
+
+	search.Tags = domain.NormalizeTags(search.Tags)
+
+	page := *search
+	page.Limit++
+
+	songs, err := s.st.Search(ctx, &page)
+	if err != nil {
+		return nil, err
+	}
+
+	more := len(songs) > search.Limit
+	backward := search.Before != nil
+
+	if more && backward {
+		songs = songs[1:]
+	} else if more {
+		songs = songs[:search.Limit]
+	}
+
+	result := &domain.SearchResult{
+		Songs: songs,
+	}
+
+	if len(songs) != 0 {
+		first, last := songs[0].Key(), songs[len(songs)-1].Key()
+
+		if more || backward {
+			result.Next = last
+		}
+		if more && backward || !backward && (search.After != nil || search.Offset != 0) {
+			result.Prev = first
+		}
+	}
+
+	if search.Total {
+		total, err := s.st.Count(ctx, search)
+		if err != nil {
+			return nil, err
+		}
+		result.Total = &total
+	}
+
+	if search.Facets {
+		result.Facets, err = s.st.Facets(ctx, search)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	exact := !search.Fuzzy && (search.ByGroup != "" || search.BySongName != "")
+	firstPage := search.Offset == 0 && search.After == nil && search.Before == nil
+
+	if len(songs) != 0 || !exact || !firstPage {
+		return result, nil
+	}
+
+	result.Suggestions, err = s.st.Suggest(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
 }
 
-func (s *SongsService) Info(ctx context.Context, song *domain.Song) (*domain.SongInfo, error) {
-	return s.st.Info(ctx, song)
+// Translation to the best of preferred languages is added if there is such translation.
+func (s *SongsService) Info(ctx context.Context, song *domain.Song, languages ...string) (*domain.SongInfo, error) {
+	info, err := s.st.Info(ctx, song)
+	if err != nil {
+		return nil, err
+	}
+
+	info.Translation, err = s.preferredTranslation(ctx, song, languages)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.setFeedback(ctx, info)
+	if err != nil {
+		return nil, err
+	}
+
+	return info, nil
 }
 
-func (s *SongsService) Create(ctx context.Context, song *domain.Song) error {
-	return s.st.Create(ctx, song)
+// Song is created even if the details provider fails, details can be added later by update.
+// Spaces around and inside group and song name are cleaned up.
+// Returns id of the created song or domain.SongExistsError if there is such song already.
+func (s *SongsService) Create(ctx context.Context, song *domain.Song) (uuid.UUID, error) {
+	song.Group = domain.CleanName(song.Group)
+	song.SongName = domain.CleanName(song.SongName)
+
+	var details *domain.SongDetails
+
+	if s.details != nil {
+		var err error
+
+		details, err = s.details.Details(ctx, song)
+		if err != nil {
+			slog.Warn(
+				"failed to get song details",
+				"err", err,
+				"operation", logmsg.ExtractOperationID(ctx),
+			)
+		}
+	}
+
+	return s.st.Create(ctx, song, details)
 }
 
 func (s *SongsService) Delete(ctx context.Context, song *domain.Song) error {
 	return s.st.Delete(ctx, song)
 }
 
+// Timestamps of synced lyrics are checked by domain.CheckTimings.
+// Returns domain.SongExistsError if the song is renamed to another existing one.
 func (s *SongsService) Update(ctx context.Context, song *domain.Song, update *domain.SongUpdate) error {
+	err := domain.CheckTimings(update.Lyrics)
+	if err != nil {
+		return err
+	}
+
+	update.Group = domain.CleanName(update.Group)
+	update.SongName = domain.CleanName(update.SongName)
+
 	return s.st.Update(ctx, song, update)
 }
+
+// Returns copies of verses with translation of the same position.
+func translate(verses []*domain.Verse, translation *domain.Translation) []*domain.Verse {
+	result := make([]*domain.Verse, 0, len(verses))
+
+	for _, verse := range verses {
+		translated := *verse
+		if verse.Position <= len(translation.Verses) {
+			translated.Translation = translation.Verses[verse.Position-1]
+		}
+		result = append(result, &translated)
+	}
+
+	return result
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/qreaqtor/music-library/internal/domain"
+	audiotag "github.com/qreaqtor/music-library/pkg/audioTag"
+	"github.com/qreaqtor/music-library/pkg/lrc"
+)
+
+// Layouts of dates in tags, time of the day is cut off before parsing.
+// Date with only year or month is the first day of it.
+var tagDateLayouts = []string{time.DateOnly, "2006-01", "2006"}
+
+// Imports songs from tags of audio files as a single batch, results are in the order of files
+// and their File is not set. Files without artist or title are skipped.
+func (s *SongsService) ImportTags(ctx context.Context, files []*audiotag.Tags, options *domain.BulkOptions) ([]*domain.TagsResult, error) {
+	results := make([]*domain.TagsResult, len(files))
+
+	songs := make([]*domain.BulkSong, 0, len(files))
+	positions := make([]int, 0, len(files))
+
+	for i, tags := range files {
+		song, err := SongFromTags(tags)
+		if err != nil {
+			results[i] = domain.SkippedFile("", err)
+			continue
+		}
+
+		songs = append(songs, song)
+		positions = append(positions, i)
+	}
+
+	imported, err := s.Import(ctx, songs, options)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, result := range imported {
+		// names are cleaned up by Import
+		tagsResult := &domain.TagsResult{
+			Status:   result.Status,
+			ID:       result.ID,
+			Group:    songs[i].Group,
+			SongName: songs[i].SongName,
+		}
+
+		switch result.Status {
+		case domain.BulkSkipped:
+			tagsResult.Status = domain.TagsMatched
+		case domain.BulkError:
+			tagsResult.Status = domain.TagsSkipped
+			tagsResult.Reason = result.Error
+		}
+
+		results[positions[i]] = tagsResult
+	}
+
+	return results, nil
+}
+
+// Converts tags of an audio file to a song. Synced lyrics are preferred over unsynchronized ones,
+// unsynchronized lyrics in LRC format are synced too, otherwise they are split into verses by empty lines.
+// Date which can't be parsed is ignored.
+func SongFromTags(tags *audiotag.Tags) (*domain.BulkSong, error) {
+	if strings.TrimSpace(tags.Artist) == "" || strings.TrimSpace(tags.Title) == "" {
+		return nil, domain.ErrNoSongTags
+	}
+
+	return &domain.BulkSong{
+		Group:       tags.Artist,
+		SongName:    tags.Title,
+		Lyrics:      tagsLyrics(tags),
+		ReleaseDate: tagsDate(tags.Date),
+	}, nil
+}
+
+func tagsLyrics(tags *audiotag.Tags) []*domain.Verse {
+	if len(tags.SyncedLyrics) != 0 {
+		file := &lrc.File{
+			Lines: make([]lrc.Line, 0, len(tags.SyncedLyrics)),
+		}
+		for _, line := range tags.SyncedLyrics {
+			file.Lines = append(file.Lines, lrc.Line{Time: line.Time, Text: line.Text})
+		}
+
+		return versesFromLRC(file)
+	}
+
+	// ID3 lyrics often use carriage returns
+	text := strings.ReplaceAll(tags.Lyrics, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+
+	file, err := lrc.Parse(strings.NewReader(text))
+	if err == nil && len(file.Lines) != 0 {
+		return versesFromLRC(file)
+	}
+
+	details := &domain.SongDetails{Text: text}
+	return details.ToLyricsSchema().Lyrics
+}
+
+func tagsDate(value string) time.Time {
+	value, _, _ = strings.Cut(strings.TrimSpace(value), "T")
+	value, _, _ = strings.Cut(value, " ")
+
+	for _, layout := range tagDateLayouts {
+		date, err := time.Parse(layout, value)
+		if err == nil {
+			return date
+		}
+	}
+
+	return time.Time{}
+}
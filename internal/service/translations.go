@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"slices"
+
+	"github.com/qreaqtor/music-library/internal/domain"
+	"golang.org/x/text/language"
+)
+
+func (s *SongsService) Languages(ctx context.Context, song *domain.Song) (*domain.Languages, error) {
+	return s.st.Languages(ctx, song)
+}
+
+func (s *SongsService) Translation(ctx context.Context, song *domain.Song, language string) (*domain.Translation, error) {
+	return s.st.Translation(ctx, song, language)
+}
+
+func (s *SongsService) SetTranslation(ctx context.Context, song *domain.Song, translation *domain.Translation) error {
+	return s.st.SetTranslation(ctx, song, translation)
+}
+
+func (s *SongsService) DeleteTranslation(ctx context.Context, song *domain.Song, language string) error {
+	return s.st.DeleteTranslation(ctx, song, language)
+}
+
+// Returns translation to the best of preferred languages, nil if the original language
+// is preferred over all translations or no translation matches.
+func (s *SongsService) preferredTranslation(ctx context.Context, song *domain.Song, preferred []string) (*domain.Translation, error) {
+	if len(preferred) == 0 {
+		return nil, nil
+	}
+
+	languages, err := s.st.Languages(ctx, song)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(languages.Translations) == 0 {
+		return nil, nil
+	}
+
+	// original languages go first, so they win over translations to the same language
+	supported := make([]language.Tag, 0, len(languages.Original)+len(languages.Translations))
+	for _, lang := range slices.Concat(languages.Original, languages.Translations) {
+		supported = append(supported, language.Make(lang))
+	}
+
+	tags := make([]language.Tag, 0, len(preferred))
+	for _, lang := range preferred {
+		tags = append(tags, language.Make(lang))
+	}
+
+	_, i, confidence := language.NewMatcher(supported).Match(tags...)
+	if confidence == language.No || i < len(languages.Original) {
+		return nil, nil
+	}
+
+	return s.st.Translation(ctx, song, languages.Translations[i-len(languages.Original)])
+}
@@ -0,0 +1,22 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/qreaqtor/music-library/internal/domain"
+)
+
+func (s *SongsService) Trash(ctx context.Context, batch *domain.Batch) ([]*domain.TrashedSong, error) {
+	return s.st.Trash(ctx, batch)
+}
+
+func (s *SongsService) Untrash(ctx context.Context, song *domain.Song) error {
+	return s.st.Untrash(ctx, song)
+}
+
+// Permanently deletes songs which are in trash longer than retention.
+// Returns number of deleted songs.
+func (s *SongsService) PurgeTrash(ctx context.Context, retention time.Duration) (int, error) {
+	return s.st.Purge(ctx, time.Now().Add(-retention))
+}
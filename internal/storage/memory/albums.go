@@ -0,0 +1,324 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+)
+
+type album struct {
+	id     uuid.UUID
+	title  string
+	artist *domain.Artist
+
+	// nil if the release date is unknown
+	releaseDate *time.Time
+
+	coverURL string
+	label    string
+
+	tracks []track
+}
+
+type track struct {
+	song *song
+
+	disc     int
+	number   int
+	duration int
+}
+
+// AlbumsStorage manages albums of artists of the songs storage and their tracklists.
+// It uses the lock of the songs storage.
+type AlbumsStorage struct {
+	songs *SongsStorage
+}
+
+func NewAlbumsStorage(songs *SongsStorage) *AlbumsStorage {
+	return &AlbumsStorage{
+		songs: songs,
+	}
+}
+
+func (s *AlbumsStorage) Create(ctx context.Context, album *domain.Album) (*domain.Album, error) {
+	s.songs.mu.Lock()
+	defer s.songs.mu.Unlock()
+
+	created := newAlbum(album)
+
+	created.artist = s.songs.artist(album.ArtistID)
+	if created.artist == nil {
+		return nil, domain.ErrUnknownArtist
+	}
+
+	s.songs.albums = append(s.songs.albums, created)
+
+	return created.toDomain(), nil
+}
+
+func (s *AlbumsStorage) Get(ctx context.Context, id uuid.UUID) (*domain.Album, error) {
+	s.songs.mu.RLock()
+	defer s.songs.mu.RUnlock()
+
+	album := s.songs.album(id)
+	if album == nil {
+		slog.Debug("album not found", "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+
+	return album.toDomain(), nil
+}
+
+// Albums are filtered by title and artist and ordered by release date.
+func (s *AlbumsStorage) List(ctx context.Context, search *domain.AlbumSearch) ([]*domain.Album, error) {
+	s.songs.mu.RLock()
+	defer s.songs.mu.RUnlock()
+
+	found := make([]*album, 0)
+	for _, album := range s.songs.albums {
+		if (search.ByTitle == "" || contains(album.title, search.ByTitle)) &&
+			(search.ArtistID == uuid.Nil || album.artist.ID == search.ArtistID) {
+			found = append(found, album)
+		}
+	}
+
+	slices.SortStableFunc(found, compareAlbums)
+
+	albums := make([]*domain.Album, 0)
+	for _, album := range page(found, &search.Batch) {
+		albums = append(albums, album.toDomain())
+	}
+
+	return albums, nil
+}
+
+func (s *AlbumsStorage) Update(ctx context.Context, id uuid.UUID, update *domain.AlbumUpdate) (*domain.Album, error) {
+	s.songs.mu.Lock()
+	defer s.songs.mu.Unlock()
+
+	schema := update.ToAlbumSchema()
+	if schema.Title == "" && schema.ArtistID == uuid.Nil && schema.ReleaseDate.IsZero() &&
+		schema.CoverURL == "" && schema.Label == "" {
+		return nil, domain.ErrEmptyUpdate
+	}
+
+	album := s.songs.album(id)
+	if album == nil {
+		slog.Debug("album not found", "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+
+	artist := album.artist
+	if schema.ArtistID != uuid.Nil {
+		artist = s.songs.artist(schema.ArtistID)
+		if artist == nil {
+			return nil, domain.ErrUnknownArtist
+		}
+	}
+
+	album.artist = artist
+	if schema.Title != "" {
+		album.title = schema.Title
+	}
+	if !schema.ReleaseDate.IsZero() {
+		releaseDate := truncateDate(schema.ReleaseDate)
+		album.releaseDate = &releaseDate
+	}
+	if schema.CoverURL != "" {
+		album.coverURL = schema.CoverURL
+	}
+	if schema.Label != "" {
+		album.label = schema.Label
+	}
+
+	return album.toDomain(), nil
+}
+
+// Tracks of the album are deleted with it, songs are kept.
+func (s *AlbumsStorage) Delete(ctx context.Context, id uuid.UUID) error {
+	s.songs.mu.Lock()
+	defer s.songs.mu.Unlock()
+
+	if s.songs.album(id) == nil {
+		slog.Debug("album not found", "operation", logmsg.ExtractOperationID(ctx))
+		return domain.ErrUnknownResourse
+	}
+
+	s.songs.albums = slices.DeleteFunc(s.songs.albums, func(a *album) bool { return a.id == id })
+
+	return nil
+}
+
+// Tracks are ordered by disc and track number.
+func (s *AlbumsStorage) GetTracks(ctx context.Context, albumID uuid.UUID) ([]*domain.Track, error) {
+	s.songs.mu.RLock()
+	defer s.songs.mu.RUnlock()
+
+	album := s.songs.album(albumID)
+	if album == nil {
+		slog.Debug("album not found", "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+
+	return album.trackList(), nil
+}
+
+// Replaces the whole tracklist of the album.
+func (s *AlbumsStorage) SetTracks(ctx context.Context, albumID uuid.UUID, tracklist *domain.Tracklist) ([]*domain.Track, error) {
+	s.songs.mu.Lock()
+	defer s.songs.mu.Unlock()
+
+	album := s.songs.album(albumID)
+	if album == nil {
+		slog.Debug("album not found", "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+
+	tracks := make([]track, 0, len(tracklist.Tracks))
+	for _, t := range tracklist.Tracks {
+		song := s.songs.find(&t.Song)
+		if song == nil {
+			return nil, fmt.Errorf("%w: %s - %s", domain.ErrUnknownResourse, t.Group, t.SongName)
+		}
+
+		disc := t.DiscNumber
+		if disc == 0 {
+			disc = 1
+		}
+
+		// equivalent of the primary key of album tracks
+		if slices.ContainsFunc(tracks, func(tr track) bool { return tr.disc == disc && tr.number == t.TrackNumber }) {
+			return nil, domain.ErrTrackPosition
+		}
+
+		tracks = append(tracks, track{song: song, disc: disc, number: t.TrackNumber, duration: t.Duration})
+	}
+
+	album.tracks = tracks
+
+	return album.trackList(), nil
+}
+
+// Returns the album with the id or nil. Caller must hold the lock.
+func (s *SongsStorage) album(id uuid.UUID) *album {
+	i := slices.IndexFunc(s.albums, func(album *album) bool { return album.id == id })
+	if i == -1 {
+		return nil
+	}
+	return s.albums[i]
+}
+
+// Returns albums which the song appears on, ordered by release date. Caller must hold the lock.
+func (s *SongsStorage) songAlbums(song *song) []domain.AlbumRef {
+	albums := make([]*album, 0)
+	for _, album := range s.albums {
+		if slices.ContainsFunc(album.tracks, func(t track) bool { return t.song == song }) {
+			albums = append(albums, album)
+		}
+	}
+
+	slices.SortStableFunc(albums, compareAlbums)
+
+	refs := make([]domain.AlbumRef, 0)
+	for _, album := range albums {
+		for _, t := range album.tracks {
+			if t.song == song {
+				refs = append(refs, domain.AlbumRef{
+					ID:          album.id,
+					Title:       album.title,
+					ReleaseDate: copyDate(album.releaseDate),
+					DiscNumber:  t.disc,
+					TrackNumber: t.number,
+				})
+			}
+		}
+	}
+
+	return refs
+}
+
+// Deletes tracks of songs which are not in the storage anymore,
+// it is equivalent of cascade deletion of album tracks. Caller must hold the lock.
+func (s *SongsStorage) deleteTracks() {
+	for _, album := range s.albums {
+		album.tracks = slices.DeleteFunc(album.tracks, func(t track) bool { return !slices.Contains(s.songs, t.song) })
+	}
+}
+
+func newAlbum(a *domain.Album) *album {
+	return &album{
+		id:          uuid.New(),
+		title:       a.Title,
+		releaseDate: copyDate(a.ReleaseDate),
+		coverURL:    a.CoverURL,
+		label:       a.Label,
+		tracks:      make([]track, 0),
+	}
+}
+
+func (a *album) toDomain() *domain.Album {
+	return &domain.Album{
+		ID:          a.id,
+		Title:       a.title,
+		ArtistID:    a.artist.ID,
+		Artist:      a.artist.Name,
+		ReleaseDate: copyDate(a.releaseDate),
+		CoverURL:    a.coverURL,
+		Label:       a.label,
+	}
+}
+
+// Tracks of songs in trash are hidden.
+func (a *album) trackList() []*domain.Track {
+	tracks := make([]*domain.Track, 0, len(a.tracks))
+	for _, t := range a.tracks {
+		if t.song.trashed() {
+			continue
+		}
+
+		tracks = append(tracks, &domain.Track{
+			Song:        domain.Song{ID: t.song.id, Group: t.song.artist.Name, SongName: t.song.name},
+			DiscNumber:  t.disc,
+			TrackNumber: t.number,
+			Duration:    t.duration,
+		})
+	}
+
+	slices.SortFunc(tracks, func(a, b *domain.Track) int {
+		return cmp.Or(cmp.Compare(a.DiscNumber, b.DiscNumber), cmp.Compare(a.TrackNumber, b.TrackNumber))
+	})
+
+	return tracks
+}
+
+// Equivalent of `ORDER BY release_date NULLS LAST, title`.
+func compareAlbums(a, b *album) int {
+	switch {
+	case a.releaseDate == nil && b.releaseDate != nil:
+		return 1
+	case a.releaseDate != nil && b.releaseDate == nil:
+		return -1
+	case a.releaseDate != nil && b.releaseDate != nil:
+		if c := a.releaseDate.Compare(*b.releaseDate); c != 0 {
+			return c
+		}
+	}
+	return strings.Compare(a.title, b.title)
+}
+
+// Release dates are stored as DATE.
+func copyDate(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	date := truncateDate(*t)
+	return &date
+}
@@ -0,0 +1,166 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+)
+
+// APIKeysStorage keeps API keys in memory and behaves the same way as the PostgreSQL storage.
+// It is safe for concurrent use, data is lost after restart.
+type APIKeysStorage struct {
+	mu sync.RWMutex
+
+	keys map[uuid.UUID]*domain.APIKeyAccount
+
+	// ids of keys by prefix
+	prefixes map[string]uuid.UUID
+}
+
+func NewAPIKeysStorage() *APIKeysStorage {
+	return &APIKeysStorage{
+		keys:     make(map[uuid.UUID]*domain.APIKeyAccount),
+		prefixes: make(map[string]uuid.UUID),
+	}
+}
+
+func (s *APIKeysStorage) CreateKey(ctx context.Context, account *domain.APIKeyAccount) (*domain.APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.prefixes[account.Prefix]; exists {
+		return nil, errPrefixExists
+	}
+
+	created := &domain.APIKeyAccount{
+		APIKey: domain.APIKey{
+			ID:          uuid.New(),
+			Name:        account.Name,
+			Prefix:      account.Prefix,
+			Permissions: slices.Clone(account.Permissions),
+			CreatedBy:   account.CreatedBy,
+			CreatedAt:   time.Now(),
+			ExpiresAt:   account.ExpiresAt,
+		},
+		Hash: account.Hash,
+	}
+
+	s.keys[created.ID] = created
+	s.prefixes[created.Prefix] = created.ID
+
+	return copyKey(created), nil
+}
+
+// Keys are ordered by creation, revoked and expired keys are listed too.
+func (s *APIKeysStorage) Keys(ctx context.Context, batch *domain.Batch) ([]*domain.APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	accounts := make([]*domain.APIKeyAccount, 0, len(s.keys))
+	for _, account := range s.keys {
+		accounts = append(accounts, account)
+	}
+
+	slices.SortFunc(accounts, func(a, b *domain.APIKeyAccount) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), strings.Compare(a.ID.String(), b.ID.String()))
+	})
+
+	keys := make([]*domain.APIKey, 0)
+	for _, account := range page(accounts, batch) {
+		keys = append(keys, copyKey(account))
+	}
+
+	return keys, nil
+}
+
+func (s *APIKeysStorage) KeyByPrefix(ctx context.Context, prefix string) (*domain.APIKeyAccount, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	id, ok := s.prefixes[prefix]
+	if !ok {
+		slog.Debug("api key not found", "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+
+	account := s.keys[id]
+	return &domain.APIKeyAccount{
+		APIKey: *copyKey(account),
+		Hash:   account.Hash,
+	}, nil
+}
+
+// Replaces the key, the old one is rejected right after it.
+// Returns domain.ErrKeyRevoked if the key is revoked.
+func (s *APIKeysStorage) RotateKey(ctx context.Context, id uuid.UUID, prefix string, hash []byte) (*domain.APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	account, ok := s.keys[id]
+	if !ok {
+		slog.Debug("api key not found", "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+	if account.RevokedAt != nil {
+		return nil, domain.ErrKeyRevoked
+	}
+	if _, exists := s.prefixes[prefix]; exists {
+		return nil, errPrefixExists
+	}
+
+	delete(s.prefixes, account.Prefix)
+	s.prefixes[prefix] = id
+
+	account.Prefix = prefix
+	account.Hash = hash
+	account.LastUsedAt = nil
+
+	return copyKey(account), nil
+}
+
+// Revoking revoked key is not an error.
+func (s *APIKeysStorage) RevokeKey(ctx context.Context, id uuid.UUID) (*domain.APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	account, ok := s.keys[id]
+	if !ok {
+		slog.Debug("api key not found", "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+
+	if account.RevokedAt == nil {
+		revokedAt := time.Now()
+		account.RevokedAt = &revokedAt
+	}
+
+	return copyKey(account), nil
+}
+
+// Sets time of the last use, earlier time doesn't replace later one.
+func (s *APIKeysStorage) TouchKey(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	account, ok := s.keys[id]
+	if ok && (account.LastUsedAt == nil || account.LastUsedAt.Before(usedAt)) {
+		account.LastUsedAt = &usedAt
+	}
+
+	return nil
+}
+
+// Times are never changed in place, so they are shared with the copy.
+func copyKey(account *domain.APIKeyAccount) *domain.APIKey {
+	key := account.APIKey
+	key.Permissions = slices.Clone(key.Permissions)
+	return &key
+}
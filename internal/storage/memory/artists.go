@@ -0,0 +1,217 @@
+package memory
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+)
+
+// ArtistsStorage manages artists of the songs storage, groups and credits of its songs refer to them
+// like songs refer to the artists table in the PostgreSQL storage. It uses the lock of the songs storage.
+type ArtistsStorage struct {
+	songs *SongsStorage
+}
+
+func NewArtistsStorage(songs *SongsStorage) *ArtistsStorage {
+	return &ArtistsStorage{
+		songs: songs,
+	}
+}
+
+func (s *ArtistsStorage) Create(ctx context.Context, artist *domain.Artist) (*domain.Artist, error) {
+	s.songs.mu.Lock()
+	defer s.songs.mu.Unlock()
+
+	created := copyArtist(artist)
+	created.ID = uuid.New()
+
+	err := s.check(created)
+	if err != nil {
+		return nil, err
+	}
+
+	s.songs.artists = append(s.songs.artists, created)
+
+	return copyArtist(created), nil
+}
+
+func (s *ArtistsStorage) Get(ctx context.Context, id uuid.UUID) (*domain.Artist, error) {
+	s.songs.mu.RLock()
+	defer s.songs.mu.RUnlock()
+
+	artist := s.songs.artist(id)
+	if artist == nil {
+		slog.Debug("artist not found", "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+
+	return copyArtist(artist), nil
+}
+
+// Artists are filtered by name or alias and ordered by name.
+func (s *ArtistsStorage) List(ctx context.Context, search *domain.ArtistSearch) ([]*domain.Artist, error) {
+	s.songs.mu.RLock()
+	defer s.songs.mu.RUnlock()
+
+	artists := make([]*domain.Artist, 0)
+	for _, artist := range s.songs.artists {
+		if search.ByName == "" || contains(artist.Name, search.ByName) ||
+			slices.ContainsFunc(artist.Aliases, func(alias string) bool { return contains(alias, search.ByName) }) {
+			artists = append(artists, copyArtist(artist))
+		}
+	}
+
+	slices.SortFunc(artists, func(a, b *domain.Artist) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return page(artists, &search.Batch), nil
+}
+
+// Renaming the artist renames the group of all its songs.
+func (s *ArtistsStorage) Update(ctx context.Context, id uuid.UUID, update *domain.ArtistUpdate) (*domain.Artist, error) {
+	s.songs.mu.Lock()
+	defer s.songs.mu.Unlock()
+
+	schema := update.ToArtistSchema()
+	if schema.Name == "" && schema.Aliases == nil && schema.Country == "" &&
+		schema.Formed.IsZero() && schema.Disbanded.IsZero() && schema.Bio == "" {
+		return nil, domain.ErrEmptyUpdate
+	}
+
+	artist := s.songs.artist(id)
+	if artist == nil {
+		slog.Debug("artist not found", "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+
+	updated := copyArtist(artist)
+	if schema.Name != "" {
+		updated.Name = schema.Name
+	}
+	if schema.Aliases != nil {
+		updated.Aliases = slices.Clone(schema.Aliases)
+	}
+	if schema.Country != "" {
+		updated.Country = schema.Country
+	}
+	if !schema.Formed.IsZero() {
+		formed := truncateDate(schema.Formed)
+		updated.Formed = &formed
+	}
+	if !schema.Disbanded.IsZero() {
+		disbanded := truncateDate(schema.Disbanded)
+		updated.Disbanded = &disbanded
+	}
+	if schema.Bio != "" {
+		updated.Bio = schema.Bio
+	}
+
+	err := s.check(updated)
+	if err != nil {
+		return nil, err
+	}
+
+	// songs keep the pointer, so their group is renamed too
+	*artist = *updated
+
+	return copyArtist(artist), nil
+}
+
+// Artist can't be deleted while it has songs, albums or credits.
+func (s *ArtistsStorage) Delete(ctx context.Context, id uuid.UUID) error {
+	s.songs.mu.Lock()
+	defer s.songs.mu.Unlock()
+
+	artist := s.songs.artist(id)
+	if artist == nil {
+		slog.Debug("artist not found", "operation", logmsg.ExtractOperationID(ctx))
+		return domain.ErrUnknownResourse
+	}
+
+	if s.songs.referenced(artist) {
+		return domain.ErrArtistHasSongs
+	}
+
+	s.songs.artists = slices.DeleteFunc(s.songs.artists, func(a *domain.Artist) bool { return a == artist })
+
+	return nil
+}
+
+// Equivalent of constraints of the artists table: unique name up to case and dates check.
+// Caller must hold the lock.
+func (s *ArtistsStorage) check(artist *domain.Artist) error {
+	if slices.ContainsFunc(s.songs.artists, func(a *domain.Artist) bool {
+		return a.ID != artist.ID && strings.EqualFold(a.Name, artist.Name)
+	}) {
+		return domain.ErrArtistExists
+	}
+
+	if artist.Formed != nil && artist.Disbanded != nil && artist.Disbanded.Before(*artist.Formed) {
+		return domain.ErrArtistDates
+	}
+
+	return nil
+}
+
+// Returns the artist with the id or nil. Caller must hold the lock.
+func (s *SongsStorage) artist(id uuid.UUID) *domain.Artist {
+	i := slices.IndexFunc(s.artists, func(artist *domain.Artist) bool { return artist.ID == id })
+	if i == -1 {
+		return nil
+	}
+	return s.artists[i]
+}
+
+// Returns the first artist with the name or alias or nil. Caller must hold the lock.
+func (s *SongsStorage) findArtist(name string) *domain.Artist {
+	i := slices.IndexFunc(s.artists, func(artist *domain.Artist) bool { return isArtist(artist, name) })
+	if i == -1 {
+		return nil
+	}
+	return s.artists[i]
+}
+
+// Returns the artist with the name or alias, new artist is created if there is no such.
+// Names are unique case insensitively, so "muse" resolves to existing "Muse". Caller must hold the lock.
+func (s *SongsStorage) getOrCreateArtist(name string) *domain.Artist {
+	artist := s.findArtist(name)
+	if artist == nil {
+		artist = &domain.Artist{ID: uuid.New(), Name: name, Aliases: make([]string, 0)}
+		s.artists = append(s.artists, artist)
+	}
+	return artist
+}
+
+// Reports if songs in or out of trash, their credits or albums refer to the artist,
+// it is equivalent of foreign keys to the artists table. Caller must hold the lock.
+func (s *SongsStorage) referenced(artist *domain.Artist) bool {
+	return slices.ContainsFunc(s.songs, func(song *song) bool {
+		return song.artist == artist || slices.ContainsFunc(song.credits, func(c credit) bool { return c.artist == artist })
+	}) || slices.ContainsFunc(s.albums, func(album *album) bool { return album.artist == artist })
+}
+
+// Artist matches its name or alias case insensitively, like artistCondition of the PostgreSQL storage.
+func isArtist(artist *domain.Artist, name string) bool {
+	return strings.EqualFold(artist.Name, name) ||
+		slices.ContainsFunc(artist.Aliases, func(alias string) bool { return strings.EqualFold(alias, name) })
+}
+
+func copyArtist(artist *domain.Artist) *domain.Artist {
+	copied := *artist
+	copied.Aliases = append(make([]string, 0, len(artist.Aliases)), artist.Aliases...)
+	if artist.Formed != nil {
+		formed := truncateDate(*artist.Formed)
+		copied.Formed = &formed
+	}
+	if artist.Disbanded != nil {
+		disbanded := truncateDate(*artist.Disbanded)
+		copied.Disbanded = &disbanded
+	}
+	return &copied
+}
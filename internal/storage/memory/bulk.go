@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+)
+
+// Imports songs atomically, results are in the order of songs.
+// In dry run songs are not saved, but later songs of the batch see earlier ones.
+func (s *SongsStorage) Import(ctx context.Context, songs []*domain.BulkSong, options *domain.BulkOptions) ([]*domain.BulkResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// songs which would be created in dry run by name key
+	created := make(map[string]*song)
+
+	results := make([]*domain.BulkResult, 0, len(songs))
+
+	for _, item := range songs {
+		schema := item.ToSongSchema()
+		key := item.Key()
+
+		existing := s.findDuplicate(nil, s.findArtist(schema.Group), schema.SongName)
+		if existing == nil {
+			existing = created[key]
+		}
+
+		switch {
+		case existing == nil:
+			song := &song{
+				id:           uuid.New(),
+				name:         schema.SongName,
+				releaseDate:  today(),
+				link:         schema.Link,
+				verses:       item.ToLyricsSchema().Lyrics,
+				translations: make(map[string][]string),
+				createdAt:    now(),
+			}
+			song.updatedAt = song.createdAt
+			if !schema.ReleaseDate.IsZero() {
+				song.releaseDate = truncateDate(schema.ReleaseDate)
+			}
+
+			result := &domain.BulkResult{Status: domain.BulkCreated}
+
+			if options.DryRun {
+				created[key] = song
+			} else {
+				song.artist = s.getOrCreateArtist(schema.Group)
+				song.writeRevision(ctx, nil)
+				s.songs = append(s.songs, song)
+				result.ID = song.id
+			}
+
+			results = append(results, result)
+		default:
+			result := &domain.BulkResult{Status: domain.BulkSkipped, ID: existing.id}
+
+			if options.Mode == domain.BulkUpsert {
+				result.Status = domain.BulkUpdated
+				if !options.DryRun {
+					existing.importUpdate(ctx, item)
+				}
+			}
+
+			// songs created in dry run have no real id
+			if created[key] == existing {
+				result.ID = uuid.Nil
+			}
+
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+// Empty fields of the item are kept, lyrics are replaced only if they are provided.
+func (s *song) importUpdate(ctx context.Context, item *domain.BulkSong) {
+	before := s.snapshot()
+
+	if item.Link != "" {
+		s.link = item.Link
+	}
+	if !item.ReleaseDate.IsZero() {
+		s.releaseDate = truncateDate(item.ReleaseDate)
+	}
+
+	lyrics := item.ToLyricsSchema().Lyrics
+	if len(lyrics) != 0 {
+		s.replaceLyrics(lyrics, s.translationList())
+	}
+
+	s.updatedAt = now()
+
+	s.writeRevision(ctx, before)
+}
@@ -0,0 +1,48 @@
+package memory
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+
+	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+)
+
+type credit struct {
+	artist *domain.Artist
+	role   string
+}
+
+// Credited artists are found by name or alias, new artists are created like in the PostgreSQL storage.
+// Duplicate credits are ignored.
+func (s *SongsStorage) SetCredits(ctx context.Context, target *domain.Song, credits []domain.Credit) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	song := s.find(target)
+	if song == nil {
+		slog.Debug("song not found", "operation", logmsg.ExtractOperationID(ctx))
+		return domain.ErrUnknownResourse
+	}
+
+	set := make([]credit, 0, len(credits))
+	for _, c := range credits {
+		credit := credit{artist: s.getOrCreateArtist(c.Artist), role: c.Role}
+		if !slices.Contains(set, credit) {
+			set = append(set, credit)
+		}
+	}
+
+	song.credits = set
+
+	return nil
+}
+
+func (s *song) creditList() []domain.Credit {
+	credits := make([]domain.Credit, 0, len(s.credits))
+	for _, credit := range s.credits {
+		credits = append(credits, domain.Credit{Artist: credit.artist.Name, Role: credit.role})
+	}
+	return credits
+}
@@ -0,0 +1,8 @@
+package memory
+
+import "errors"
+
+var (
+	// equivalent of unique violation of api key prefix, prefixes are random so it is unlikely
+	errPrefixExists = errors.New("api key with the same prefix exists")
+)
@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+)
+
+// Passes found songs to fn in search order, pagination is ignored.
+// Songs are copied under the lock, so fn can be slow without blocking writers.
+func (s *SongsStorage) Export(ctx context.Context, search *domain.SongSearch, fn func(*domain.ExportedSong) error) error {
+	songs := s.export(search)
+
+	for _, song := range songs {
+		err := ctx.Err()
+		if err != nil {
+			return err
+		}
+
+		err = fn(song)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *SongsStorage) export(search *domain.SongSearch) []*domain.ExportedSong {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	byID := make(map[uuid.UUID]*song, len(s.songs))
+	for _, song := range s.songs {
+		byID[song.id] = song
+	}
+
+	found := s.search(search)
+	songs := make([]*domain.ExportedSong, 0, len(found))
+
+	for _, result := range found {
+		song := byID[result.ID]
+		songs = append(songs, &domain.ExportedSong{
+			ID:          song.id,
+			Group:       song.artist.Name,
+			SongName:    song.name,
+			Lyrics:      append(make([]*domain.Verse, 0, len(song.verses)), song.verses...),
+			Link:        song.link,
+			ReleaseDate: song.releaseDate,
+			CreatedAt:   song.createdAt,
+			UpdatedAt:   song.updatedAt,
+		})
+	}
+
+	return songs
+}
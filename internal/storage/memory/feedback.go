@@ -0,0 +1,242 @@
+package memory
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+)
+
+type play struct {
+	userID   uuid.UUID
+	playedAt time.Time
+}
+
+// Adding favorite song again is not an error, the time it was added is kept.
+func (s *SongsStorage) AddFavorite(ctx context.Context, target *domain.Song, userID uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	song := s.find(target)
+	if song == nil {
+		slog.Debug("song not found", "operation", logmsg.ExtractOperationID(ctx))
+		return domain.ErrUnknownResourse
+	}
+
+	if song.favorites == nil {
+		song.favorites = make(map[uuid.UUID]time.Time)
+	}
+	if _, ok := song.favorites[userID]; !ok {
+		song.favorites[userID] = now()
+	}
+
+	return nil
+}
+
+// Removing song which is not favorite is not an error.
+func (s *SongsStorage) RemoveFavorite(ctx context.Context, target *domain.Song, userID uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	song := s.find(target)
+	if song == nil {
+		slog.Debug("song not found", "operation", logmsg.ExtractOperationID(ctx))
+		return domain.ErrUnknownResourse
+	}
+
+	delete(song.favorites, userID)
+
+	return nil
+}
+
+// Favorite songs of the user, recently added first. Songs in trash are skipped.
+func (s *SongsStorage) Favorites(ctx context.Context, userID uuid.UUID, batch *domain.Batch) ([]*domain.FavoriteSong, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	favorites := make([]*domain.FavoriteSong, 0)
+	for _, song := range s.songs {
+		addedAt, ok := song.favorites[userID]
+		if !ok || song.trashed() {
+			continue
+		}
+
+		favorites = append(favorites, &domain.FavoriteSong{
+			Song:    song.ref(),
+			AddedAt: addedAt,
+		})
+	}
+
+	slices.SortFunc(favorites, func(a, b *domain.FavoriteSong) int {
+		return cmp.Or(b.AddedAt.Compare(a.AddedAt), bytes.Compare(a.ID[:], b.ID[:]))
+	})
+
+	return page(favorites, batch), nil
+}
+
+// Replaces the previous rating of the user.
+func (s *SongsStorage) Rate(ctx context.Context, target *domain.Song, userID uuid.UUID, rating int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	song := s.find(target)
+	if song == nil {
+		slog.Debug("song not found", "operation", logmsg.ExtractOperationID(ctx))
+		return domain.ErrUnknownResourse
+	}
+
+	if song.ratings == nil {
+		song.ratings = make(map[uuid.UUID]int)
+	}
+	song.ratings[userID] = rating
+
+	return nil
+}
+
+// Removing rating of song which is not rated is not an error.
+func (s *SongsStorage) Unrate(ctx context.Context, target *domain.Song, userID uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	song := s.find(target)
+	if song == nil {
+		slog.Debug("song not found", "operation", logmsg.ExtractOperationID(ctx))
+		return domain.ErrUnknownResourse
+	}
+
+	delete(song.ratings, userID)
+
+	return nil
+}
+
+func (s *SongsStorage) AddPlay(ctx context.Context, target *domain.Song, userID uuid.UUID, playedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	song := s.find(target)
+	if song == nil {
+		slog.Debug("song not found", "operation", logmsg.ExtractOperationID(ctx))
+		return domain.ErrUnknownResourse
+	}
+
+	song.plays = append(song.plays, play{
+		userID:   userID,
+		playedAt: playedAt.UTC().Truncate(time.Microsecond),
+	})
+
+	return nil
+}
+
+// Listening history of the user, recent plays first. Plays of songs in trash are skipped.
+func (s *SongsStorage) Plays(ctx context.Context, userID uuid.UUID, batch *domain.Batch) ([]*domain.Play, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	plays := make([]*domain.Play, 0)
+	for _, song := range s.songs {
+		if song.trashed() {
+			continue
+		}
+
+		for _, play := range song.plays {
+			if play.userID == userID {
+				plays = append(plays, &domain.Play{
+					Song:     song.ref(),
+					PlayedAt: play.playedAt,
+				})
+			}
+		}
+	}
+
+	slices.SortStableFunc(plays, func(a, b *domain.Play) int {
+		return cmp.Or(b.PlayedAt.Compare(a.PlayedAt), bytes.Compare(a.ID[:], b.ID[:]))
+	})
+
+	return page(plays, batch), nil
+}
+
+// Songs with most plays since the time, zero time counts all plays. Songs without plays are not listed,
+// songs with the same number of plays are ordered by id. Songs in trash are skipped.
+func (s *SongsStorage) TopSongs(ctx context.Context, since time.Time, batch *domain.Batch) ([]*domain.TopSong, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	top := make([]*domain.TopSong, 0)
+	for _, song := range s.songs {
+		if song.trashed() {
+			continue
+		}
+
+		plays := 0
+		for _, play := range song.plays {
+			if !play.playedAt.Before(since) {
+				plays++
+			}
+		}
+
+		if plays != 0 {
+			top = append(top, &domain.TopSong{
+				Song:  song.ref(),
+				Plays: plays,
+			})
+		}
+	}
+
+	slices.SortFunc(top, func(a, b *domain.TopSong) int {
+		return cmp.Or(cmp.Compare(b.Plays, a.Plays), bytes.Compare(a.ID[:], b.ID[:]))
+	})
+
+	return page(top, batch), nil
+}
+
+// Feedback of the user on the song with the id, the song may be in trash.
+func (s *SongsStorage) Feedback(ctx context.Context, songID, userID uuid.UUID) (*domain.SongFeedback, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, song := range s.songs {
+		if song.id == songID {
+			_, favorite := song.favorites[userID]
+			return &domain.SongFeedback{
+				Favorite: favorite,
+				Rating:   song.ratings[userID],
+			}, nil
+		}
+	}
+
+	slog.Debug("song not found", "operation", logmsg.ExtractOperationID(ctx))
+	return nil, domain.ErrUnknownResourse
+}
+
+// Equivalent of counters of the songs table.
+func (s *song) stats() domain.SongStats {
+	stats := domain.SongStats{
+		Plays:     len(s.plays),
+		Favorites: len(s.favorites),
+		Ratings:   len(s.ratings),
+	}
+
+	if stats.Ratings != 0 {
+		sum := 0
+		for _, rating := range s.ratings {
+			sum += rating
+		}
+		stats.AverageRating = float32(sum) / float32(stats.Ratings)
+	}
+
+	return stats
+}
+
+func (s *song) ref() domain.Song {
+	return domain.Song{
+		ID:       s.id,
+		Group:    s.artist.Name,
+		SongName: s.name,
+	}
+}
@@ -0,0 +1,186 @@
+package memory
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Max number of verses in a headline, same as MaxFragments of the PostgreSQL search query.
+const maxFragments = 2
+
+// tsQuery is a parsed web search query, an equivalent of websearch_to_tsquery
+// with the simple text search configuration: words are neither stemmed nor dropped as stop words.
+// Alternatives are separated by "or", all terms of an alternative must match.
+type tsQuery [][]tsTerm
+
+// Words of a term must follow each other, so quoted phrases and
+// words like "don't" are matched as a phrase.
+type tsTerm struct {
+	words   []string
+	exclude bool
+}
+
+func parseTsQuery(text string) tsQuery {
+	query := make(tsQuery, 0)
+	current := make([]tsTerm, 0)
+
+	runes := []rune(text)
+
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+
+		exclude := runes[i] == '-'
+		if exclude {
+			i++
+		}
+
+		var chunk string
+		quoted := i < len(runes) && runes[i] == '"'
+
+		if quoted {
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			chunk = string(runes[i+1 : end])
+			i = end + 1
+		} else {
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != '"' {
+				i++
+			}
+			chunk = string(runes[start:i])
+		}
+
+		if !quoted && !exclude && strings.EqualFold(chunk, "or") {
+			if len(current) != 0 {
+				query = append(query, current)
+				current = make([]tsTerm, 0)
+			}
+			continue
+		}
+
+		words := lexemes(chunk)
+		if len(words) != 0 {
+			current = append(current, tsTerm{words: words, exclude: exclude})
+		}
+	}
+
+	if len(current) != 0 {
+		query = append(query, current)
+	}
+
+	return query
+}
+
+// Returns number of found words in the verse, zero if the verse doesn't match.
+// It is comparable only with ranks of other verses, not with ts_rank.
+func (q tsQuery) rank(verse string) float32 {
+	words := lexemes(verse)
+
+	var rank float32
+	matched := false
+
+	for _, alternative := range q {
+		hits, ok := matchAlternative(alternative, words)
+		if ok {
+			matched = true
+			rank += float32(hits)
+		}
+	}
+
+	if !matched {
+		return 0
+	}
+
+	// verse without excluded words matches a query without positive terms
+	return max(rank, 1)
+}
+
+// Wraps found words of the verse in <b></b>.
+func (q tsQuery) highlight(verse string) string {
+	found := make(map[string]struct{})
+	for _, alternative := range q {
+		for _, term := range alternative {
+			if term.exclude {
+				continue
+			}
+			for _, word := range term.words {
+				found[word] = struct{}{}
+			}
+		}
+	}
+
+	var sb strings.Builder
+	runes := []rune(verse)
+
+	for i := 0; i < len(runes); {
+		if !isWordRune(runes[i]) {
+			sb.WriteRune(runes[i])
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(runes) && isWordRune(runes[i]) {
+			i++
+		}
+		word := string(runes[start:i])
+
+		if _, ok := found[strings.ToLower(word)]; ok {
+			sb.WriteString("<b>" + word + "</b>")
+		} else {
+			sb.WriteString(word)
+		}
+	}
+
+	return sb.String()
+}
+
+// Returns number of occurrences of positive terms.
+func matchAlternative(alternative []tsTerm, words []string) (int, bool) {
+	hits := 0
+
+	for _, term := range alternative {
+		n := countPhrase(words, term.words)
+		if term.exclude && n != 0 || !term.exclude && n == 0 {
+			return 0, false
+		}
+		hits += n
+	}
+
+	return hits, true
+}
+
+func countPhrase(words, phrase []string) int {
+	n := 0
+
+	for i := 0; i+len(phrase) <= len(words); i++ {
+		match := true
+		for j, word := range phrase {
+			if words[i+j] != word {
+				match = false
+				break
+			}
+		}
+		if match {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Splits text to lower case words, punctuation is dropped.
+func lexemes(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !isWordRune(r)
+	})
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,314 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+)
+
+// Max number of genres and tags in facets, same as in the PostgreSQL storage.
+const maxFacets = 20
+
+// Returns domain.ErrUnknownGenre if the parent doesn't exist.
+func (s *SongsStorage) CreateGenre(ctx context.Context, genre *domain.Genre) (*domain.Genre, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.checkGenre(genre)
+	if err != nil {
+		return nil, err
+	}
+
+	created := copyGenre(genre)
+	created.ID = uuid.New()
+
+	s.genres = append(s.genres, created)
+
+	return copyGenre(created), nil
+}
+
+// Genres are ordered by name.
+func (s *SongsStorage) Genres(ctx context.Context) ([]*domain.Genre, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	genres := make([]*domain.Genre, 0, len(s.genres))
+	for _, genre := range s.genres {
+		genres = append(genres, copyGenre(genre))
+	}
+
+	slices.SortFunc(genres, func(a, b *domain.Genre) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return genres, nil
+}
+
+// Replaces name and parent of the genre with the id.
+// Returns domain.ErrGenreCycle if the new parent is the genre itself or its descendant.
+func (s *SongsStorage) UpdateGenre(ctx context.Context, genre *domain.Genre) (*domain.Genre, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing := s.genre(genre.ID)
+	if existing == nil {
+		slog.Debug("genre not found", "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+
+	err := s.checkGenre(genre)
+	if err != nil {
+		return nil, err
+	}
+
+	if genre.ParentID != nil && slices.Contains(s.descendants(genre.ID), *genre.ParentID) {
+		return nil, domain.ErrGenreCycle
+	}
+
+	existing.Name = genre.Name
+	existing.ParentID = copyGenre(genre).ParentID
+
+	return copyGenre(existing), nil
+}
+
+// Songs lose the deleted genre. Returns domain.ErrGenreHasChildren if the genre has child genres.
+func (s *SongsStorage) DeleteGenre(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := slices.IndexFunc(s.genres, func(genre *domain.Genre) bool { return genre.ID == id })
+	if i == -1 {
+		slog.Debug("genre not found", "operation", logmsg.ExtractOperationID(ctx))
+		return domain.ErrUnknownResourse
+	}
+
+	if slices.ContainsFunc(s.genres, func(genre *domain.Genre) bool {
+		return genre.ParentID != nil && *genre.ParentID == id
+	}) {
+		return domain.ErrGenreHasChildren
+	}
+
+	s.genres = slices.Delete(s.genres, i, i+1)
+
+	for _, song := range s.songs {
+		song.genres = slices.DeleteFunc(song.genres, func(genreID uuid.UUID) bool { return genreID == id })
+	}
+
+	return nil
+}
+
+// Genres are found by name case insensitively, returns domain.ErrUnknownGenre if some of them don't exist.
+func (s *SongsStorage) SetGenres(ctx context.Context, target *domain.Song, names []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	song := s.find(target)
+	if song == nil {
+		slog.Debug("song not found", "operation", logmsg.ExtractOperationID(ctx))
+		return domain.ErrUnknownResourse
+	}
+
+	genres := make([]uuid.UUID, 0, len(names))
+	for _, name := range names {
+		genre := s.genreByName(name)
+		if genre == nil {
+			return fmt.Errorf("%w: %s", domain.ErrUnknownGenre, name)
+		}
+		if !slices.Contains(genres, genre.ID) {
+			genres = append(genres, genre.ID)
+		}
+	}
+
+	song.genres = genres
+
+	return nil
+}
+
+// Tags must be normalized by domain.NormalizeTags.
+func (s *SongsStorage) SetTags(ctx context.Context, target *domain.Song, tags []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	song := s.find(target)
+	if song == nil {
+		slog.Debug("song not found", "operation", logmsg.ExtractOperationID(ctx))
+		return domain.ErrUnknownResourse
+	}
+
+	song.tags = slices.Clone(tags)
+
+	return nil
+}
+
+// Counts all found songs per genre, tag and decade, pagination is ignored.
+func (s *SongsStorage) Facets(ctx context.Context, search *domain.SongSearch) (*domain.Facets, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	byID := make(map[uuid.UUID]*song, len(s.songs))
+	for _, song := range s.songs {
+		byID[song.id] = song
+	}
+
+	genres := make(map[string]int)
+	tags := make(map[string]int)
+	decades := make(map[int]int)
+
+	for _, found := range s.search(search) {
+		song := byID[found.ID]
+
+		// a song is counted once for a genre, even if it has several of its descendants
+		counted := make(map[uuid.UUID]struct{})
+		for _, genreID := range song.genres {
+			for _, ancestor := range s.ancestors(genreID) {
+				if _, ok := counted[ancestor.ID]; !ok {
+					counted[ancestor.ID] = struct{}{}
+					genres[ancestor.Name]++
+				}
+			}
+		}
+
+		for _, tag := range song.tags {
+			tags[tag]++
+		}
+
+		decades[song.releaseDate.Year()/10*10]++
+	}
+
+	facets := &domain.Facets{
+		Genres:  topFacets(genres),
+		Tags:    topFacets(tags),
+		Decades: make([]domain.Facet, 0, len(decades)),
+	}
+
+	years := make([]int, 0, len(decades))
+	for year := range decades {
+		years = append(years, year)
+	}
+	slices.Sort(years)
+
+	for _, year := range years {
+		facets.Decades = append(facets.Decades, domain.Facet{Value: decade(year), Count: decades[year]})
+	}
+
+	return facets, nil
+}
+
+// Returns names of genres of the song ordered by name. Caller must hold the lock.
+func (s *SongsStorage) genreNames(song *song) []string {
+	names := make([]string, 0, len(song.genres))
+	for _, id := range song.genres {
+		if genre := s.genre(id); genre != nil {
+			names = append(names, genre.Name)
+		}
+	}
+
+	slices.Sort(names)
+	return names
+}
+
+// Returns ids of the genre with the name and of all its descendants,
+// nil if there is no such genre. Caller must hold the lock.
+func (s *SongsStorage) genreFilter(name string) []uuid.UUID {
+	genre := s.genreByName(name)
+	if genre == nil {
+		return nil
+	}
+	return s.descendants(genre.ID)
+}
+
+// Equivalent of the foreign key and unique index of genres. Caller must hold the lock.
+func (s *SongsStorage) checkGenre(genre *domain.Genre) error {
+	if existing := s.genreByName(genre.Name); existing != nil && existing.ID != genre.ID {
+		return domain.ErrGenreExists
+	}
+
+	if genre.ParentID != nil && s.genre(*genre.ParentID) == nil {
+		return domain.ErrUnknownGenre
+	}
+
+	return nil
+}
+
+// Returns the genre and its descendants. Caller must hold the lock.
+func (s *SongsStorage) descendants(id uuid.UUID) []uuid.UUID {
+	ids := []uuid.UUID{id}
+
+	for i := 0; i < len(ids); i++ {
+		for _, genre := range s.genres {
+			if genre.ParentID != nil && *genre.ParentID == ids[i] {
+				ids = append(ids, genre.ID)
+			}
+		}
+	}
+
+	return ids
+}
+
+// Returns the genre and its ancestors up to the top level genre. Caller must hold the lock.
+func (s *SongsStorage) ancestors(id uuid.UUID) []*domain.Genre {
+	ancestors := make([]*domain.Genre, 0)
+
+	for genre := s.genre(id); genre != nil; {
+		ancestors = append(ancestors, genre)
+		if genre.ParentID == nil {
+			break
+		}
+		genre = s.genre(*genre.ParentID)
+	}
+
+	return ancestors
+}
+
+func (s *SongsStorage) genre(id uuid.UUID) *domain.Genre {
+	for _, genre := range s.genres {
+		if genre.ID == id {
+			return genre
+		}
+	}
+	return nil
+}
+
+// Equivalent of the unique index on lower(name).
+func (s *SongsStorage) genreByName(name string) *domain.Genre {
+	for _, genre := range s.genres {
+		if strings.EqualFold(genre.Name, name) {
+			return genre
+		}
+	}
+	return nil
+}
+
+// Returns at most maxFacets values with the largest counts, ordered by count and then by value.
+func topFacets(counts map[string]int) []domain.Facet {
+	facets := make([]domain.Facet, 0, len(counts))
+	for value, count := range counts {
+		facets = append(facets, domain.Facet{Value: value, Count: count})
+	}
+
+	slices.SortFunc(facets, func(a, b domain.Facet) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), strings.Compare(a.Value, b.Value))
+	})
+
+	return facets[:min(len(facets), maxFacets)]
+}
+
+func decade(year int) string {
+	return fmt.Sprintf("%ds", year)
+}
+
+func copyGenre(genre *domain.Genre) *domain.Genre {
+	copied := *genre
+	if genre.ParentID != nil {
+		parentID := *genre.ParentID
+		copied.ParentID = &parentID
+	}
+	return &copied
+}
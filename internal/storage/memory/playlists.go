@@ -0,0 +1,359 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+)
+
+// Identifier of imported entry which refers to the song by its id.
+const songURNPrefix = "urn:uuid:"
+
+type playlist struct {
+	id          uuid.UUID
+	name        string
+	description string
+
+	// position of the song is its index plus one, songs in trash keep their positions
+	items []*song
+
+	createdAt time.Time
+	updatedAt time.Time
+}
+
+// PlaylistsStorage manages playlists of songs of the songs storage. It uses the lock of the songs storage.
+type PlaylistsStorage struct {
+	songs *SongsStorage
+}
+
+func NewPlaylistsStorage(songs *SongsStorage) *PlaylistsStorage {
+	return &PlaylistsStorage{
+		songs: songs,
+	}
+}
+
+func (s *PlaylistsStorage) Create(ctx context.Context, playlist *domain.Playlist) (*domain.Playlist, error) {
+	s.songs.mu.Lock()
+	defer s.songs.mu.Unlock()
+
+	return s.songs.insertPlaylist(playlist.Name, playlist.Description, make([]*song, 0)).toDomain(), nil
+}
+
+func (s *PlaylistsStorage) Get(ctx context.Context, id uuid.UUID) (*domain.Playlist, error) {
+	s.songs.mu.RLock()
+	defer s.songs.mu.RUnlock()
+
+	playlist := s.songs.playlist(id)
+	if playlist == nil {
+		slog.Debug("playlist not found", "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+
+	return playlist.toDomain(), nil
+}
+
+// Playlists are filtered by name and ordered by the last change, recently changed go first.
+func (s *PlaylistsStorage) List(ctx context.Context, search *domain.PlaylistSearch) ([]*domain.Playlist, error) {
+	s.songs.mu.RLock()
+	defer s.songs.mu.RUnlock()
+
+	found := make([]*playlist, 0)
+	for _, playlist := range s.songs.playlists {
+		if search.ByName == "" || contains(playlist.name, search.ByName) {
+			found = append(found, playlist)
+		}
+	}
+
+	slices.SortFunc(found, func(a, b *playlist) int {
+		if c := b.updatedAt.Compare(a.updatedAt); c != 0 {
+			return c
+		}
+		return bytes.Compare(a.id[:], b.id[:])
+	})
+
+	playlists := make([]*domain.Playlist, 0)
+	for _, playlist := range page(found, &search.Batch) {
+		playlists = append(playlists, playlist.toDomain())
+	}
+
+	return playlists, nil
+}
+
+func (s *PlaylistsStorage) Update(ctx context.Context, id uuid.UUID, update *domain.PlaylistUpdate) (*domain.Playlist, error) {
+	s.songs.mu.Lock()
+	defer s.songs.mu.Unlock()
+
+	schema := update.ToPlaylistSchema()
+	if schema.Name == "" && schema.Description == "" {
+		return nil, domain.ErrEmptyUpdate
+	}
+
+	playlist := s.songs.playlist(id)
+	if playlist == nil {
+		slog.Debug("playlist not found", "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+
+	if schema.Name != "" {
+		playlist.name = schema.Name
+	}
+	if schema.Description != "" {
+		playlist.description = schema.Description
+	}
+	playlist.updatedAt = now()
+
+	return playlist.toDomain(), nil
+}
+
+// Items of the playlist are deleted with it, songs are kept.
+func (s *PlaylistsStorage) Delete(ctx context.Context, id uuid.UUID) error {
+	s.songs.mu.Lock()
+	defer s.songs.mu.Unlock()
+
+	if s.songs.playlist(id) == nil {
+		slog.Debug("playlist not found", "operation", logmsg.ExtractOperationID(ctx))
+		return domain.ErrUnknownResourse
+	}
+
+	s.songs.playlists = slices.DeleteFunc(s.songs.playlists, func(p *playlist) bool { return p.id == id })
+
+	return nil
+}
+
+// Items are ordered by position.
+func (s *PlaylistsStorage) GetItems(ctx context.Context, id uuid.UUID) ([]*domain.PlaylistItem, error) {
+	s.songs.mu.RLock()
+	defer s.songs.mu.RUnlock()
+
+	playlist := s.songs.playlist(id)
+	if playlist == nil {
+		slog.Debug("playlist not found", "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+
+	return playlist.itemList(), nil
+}
+
+// Inserts the song at the position, items from it are shifted down.
+// The song is appended if the position is 0 or after the last item.
+func (s *PlaylistsStorage) InsertItem(ctx context.Context, id uuid.UUID, item *domain.PlaylistItemInsert) ([]*domain.PlaylistItem, error) {
+	s.songs.mu.Lock()
+	defer s.songs.mu.Unlock()
+
+	playlist := s.songs.playlist(id)
+	if playlist == nil {
+		slog.Debug("playlist not found", "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+
+	target := item.ToSong()
+
+	song := s.songs.find(target)
+	if song == nil && target.ID == uuid.Nil {
+		return nil, fmt.Errorf("%w: %s - %s", domain.ErrUnknownResourse, target.Group, target.SongName)
+	}
+	if song == nil {
+		slog.Debug("song not found", "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+
+	position := item.Position
+	if position == 0 || position > len(playlist.items) {
+		position = len(playlist.items) + 1
+	}
+
+	playlist.items = slices.Insert(playlist.items, position-1, song)
+	playlist.updatedAt = now()
+
+	return playlist.itemList(), nil
+}
+
+// Moves the item to the position, items between them are shifted. The item is moved to the end if to is too big.
+func (s *PlaylistsStorage) MoveItem(ctx context.Context, id uuid.UUID, from, to int) ([]*domain.PlaylistItem, error) {
+	s.songs.mu.Lock()
+	defer s.songs.mu.Unlock()
+
+	playlist := s.songs.playlist(id)
+	if playlist == nil {
+		slog.Debug("playlist not found", "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+
+	if from < 1 || from > len(playlist.items) {
+		slog.Debug("no item at position", "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+
+	to = min(to, len(playlist.items))
+
+	song := playlist.items[from-1]
+	playlist.items = slices.Insert(slices.Delete(playlist.items, from-1, from), to-1, song)
+	playlist.updatedAt = now()
+
+	return playlist.itemList(), nil
+}
+
+// Removes the item at the position, items after it are shifted up.
+func (s *PlaylistsStorage) RemoveItem(ctx context.Context, id uuid.UUID, position int) ([]*domain.PlaylistItem, error) {
+	s.songs.mu.Lock()
+	defer s.songs.mu.Unlock()
+
+	playlist := s.songs.playlist(id)
+	if playlist == nil {
+		slog.Debug("playlist not found", "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+
+	if position < 1 || position > len(playlist.items) {
+		slog.Debug("no item at position", "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+
+	playlist.items = slices.Delete(playlist.items, position-1, position)
+	playlist.updatedAt = now()
+
+	return playlist.itemList(), nil
+}
+
+// Copies the playlist with its items. Copy is named "<name> (copy)" if name is empty.
+func (s *PlaylistsStorage) Duplicate(ctx context.Context, id uuid.UUID, name string) (*domain.Playlist, error) {
+	s.songs.mu.Lock()
+	defer s.songs.mu.Unlock()
+
+	playlist := s.songs.playlist(id)
+	if playlist == nil {
+		slog.Debug("playlist not found", "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+
+	if name == "" {
+		name = playlist.name + " (copy)"
+		if runes := []rune(name); len(runes) > domain.MaxPlaylistName {
+			name = string(runes[:domain.MaxPlaylistName])
+		}
+	}
+
+	return s.songs.insertPlaylist(name, playlist.description, slices.Clone(playlist.items)).toDomain(), nil
+}
+
+// Creates the playlist with songs of the entries in their order.
+// Entries without songs in the library are returned as unmatched.
+func (s *PlaylistsStorage) Import(ctx context.Context, playlist *domain.Playlist, entries []*domain.PlaylistEntry) (*domain.PlaylistImport, error) {
+	s.songs.mu.Lock()
+	defer s.songs.mu.Unlock()
+
+	result := &domain.PlaylistImport{
+		Unmatched: make([]*domain.PlaylistEntry, 0),
+	}
+
+	items := make([]*song, 0, len(entries))
+	for _, entry := range entries {
+		song := s.songs.findEntrySong(entry)
+		if song == nil {
+			result.Unmatched = append(result.Unmatched, entry)
+			continue
+		}
+		items = append(items, song)
+	}
+
+	result.Playlist = s.songs.insertPlaylist(playlist.Name, playlist.Description, items).toDomain()
+
+	return result, nil
+}
+
+// Caller must hold the lock.
+func (s *SongsStorage) insertPlaylist(name, description string, items []*song) *playlist {
+	created := now()
+
+	playlist := &playlist{
+		id:          uuid.New(),
+		name:        name,
+		description: description,
+		items:       items,
+		createdAt:   created,
+		updatedAt:   created,
+	}
+	s.playlists = append(s.playlists, playlist)
+
+	return playlist
+}
+
+// Returns the playlist with the id or nil. Caller must hold the lock.
+func (s *SongsStorage) playlist(id uuid.UUID) *playlist {
+	i := slices.IndexFunc(s.playlists, func(playlist *playlist) bool { return playlist.id == id })
+	if i == -1 {
+		return nil
+	}
+	return s.playlists[i]
+}
+
+// Song of imported entry is found by urn:uuid identifier, then by link and then by group and name.
+// Returns nil if there is no such song. Caller must hold the lock.
+func (s *SongsStorage) findEntrySong(entry *domain.PlaylistEntry) *song {
+	if urn, ok := strings.CutPrefix(entry.Identifier, songURNPrefix); ok {
+		if id, err := uuid.Parse(urn); err == nil {
+			if song := s.find(&domain.Song{ID: id}); song != nil {
+				return song
+			}
+		}
+	}
+
+	if entry.Link != "" {
+		// songs are kept in creation order
+		i := slices.IndexFunc(s.songs, func(song *song) bool { return !song.trashed() && song.link == entry.Link })
+		if i != -1 {
+			return s.songs[i]
+		}
+	}
+
+	if entry.Group == "" || entry.SongName == "" {
+		return nil
+	}
+
+	return s.find(&domain.Song{Group: entry.Group, SongName: entry.SongName})
+}
+
+// Deletes items of songs which are not in the storage anymore and renumbers the rest,
+// it is equivalent of cascade deletion of playlist items. Caller must hold the lock.
+func (s *SongsStorage) deleteItems() {
+	for _, playlist := range s.playlists {
+		playlist.items = slices.DeleteFunc(playlist.items, func(song *song) bool { return !slices.Contains(s.songs, song) })
+	}
+}
+
+// Songs in trash are not counted.
+func (p *playlist) toDomain() *domain.Playlist {
+	return &domain.Playlist{
+		ID:          p.id,
+		Name:        p.name,
+		Description: p.description,
+		Songs:       len(p.itemList()),
+		CreatedAt:   p.createdAt,
+		UpdatedAt:   p.updatedAt,
+	}
+}
+
+// Songs in trash are hidden, so positions can have gaps.
+func (p *playlist) itemList() []*domain.PlaylistItem {
+	items := make([]*domain.PlaylistItem, 0, len(p.items))
+	for i, song := range p.items {
+		if song.trashed() {
+			continue
+		}
+
+		items = append(items, &domain.PlaylistItem{
+			Song:     domain.Song{ID: song.id, Group: song.artist.Name, SongName: song.name},
+			Position: i + 1,
+			Link:     song.link,
+		})
+	}
+	return items
+}
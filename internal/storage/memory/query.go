@@ -0,0 +1,266 @@
+package memory
+
+import (
+	"bytes"
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/qreaqtor/music-library/internal/domain"
+	"github.com/qreaqtor/music-library/pkg/trigram"
+)
+
+// Max number of group and song names returned as suggestions, same as in the PostgreSQL storage.
+const maxSuggestions = 3
+
+// Same conditions as the PostgreSQL search query: every non-empty criterion
+// is a case insensitive substring match, dates are inclusive bounds, tags are matched exactly
+// and credited artists by name or alias case insensitively.
+// Genre is matched by SongsStorage.search, because it needs the genre taxonomy.
+// Lyrics are matched by Search with full text query, group and song name
+// are matched by fuzzyMatch with fuzzy search.
+func matches(song *song, search *domain.SongSearch) bool {
+	if !search.Fuzzy && search.ByGroup != "" && !contains(song.artist.Name, search.ByGroup) {
+		return false
+	}
+
+	if !search.Fuzzy && search.BySongName != "" && !contains(song.name, search.BySongName) {
+		return false
+	}
+
+	if search.ByLink != "" && !contains(song.link, search.ByLink) {
+		return false
+	}
+
+	if !search.DateFrom.IsZero() && song.releaseDate.Before(search.DateFrom) {
+		return false
+	}
+
+	if !search.DateTo.IsZero() && song.releaseDate.After(search.DateTo) {
+		return false
+	}
+
+	if len(search.Tags) != 0 {
+		hasTag := func(tag string) bool { return slices.Contains(song.tags, tag) }
+
+		if search.TagMatch == domain.TagMatchAll && !all(search.Tags, hasTag) ||
+			search.TagMatch != domain.TagMatchAll && !slices.ContainsFunc(search.Tags, hasTag) {
+			return false
+		}
+	}
+
+	if (search.Credit != "" || search.CreditRole != "") && !slices.ContainsFunc(song.credits, func(credit credit) bool {
+		return (search.Credit == "" || isArtist(credit.artist, search.Credit)) &&
+			(search.CreditRole == "" || credit.role == search.CreditRole)
+	}) {
+		return false
+	}
+
+	return true
+}
+
+func all[T any](items []T, fn func(T) bool) bool {
+	return !slices.ContainsFunc(items, func(item T) bool { return !fn(item) })
+}
+
+// Equivalent of the ORDER BY clause of the PostgreSQL search page query.
+func sortFound(found []*domain.FoundSong, search *domain.SongSearch) {
+	field, order := search.Sorting()
+
+	slices.SortFunc(found, func(a, b *domain.FoundSong) int {
+		return compareKeys(field, order, a.Key(), b.Key())
+	})
+}
+
+// Equivalent of the keyset condition, LIMIT and OFFSET of the PostgreSQL search page query,
+// found songs must be sorted.
+func pageFound(found []*domain.FoundSong, search *domain.SongSearch) []*domain.FoundSong {
+	field, order := search.Sorting()
+
+	if search.Before != nil {
+		end := len(found)
+		if i := slices.IndexFunc(found, func(song *domain.FoundSong) bool {
+			return compareKeys(field, order, song.Key(), search.Before) >= 0
+		}); i != -1 {
+			end = i
+		}
+		return slices.Clone(found[max(0, end-search.Limit):end])
+	}
+
+	if search.After != nil {
+		start := len(found)
+		if i := slices.IndexFunc(found, func(song *domain.FoundSong) bool {
+			return compareKeys(field, order, song.Key(), search.After) > 0
+		}); i != -1 {
+			start = i
+		}
+		found = found[start:]
+	}
+
+	return page(found, &search.Batch)
+}
+
+// Compares keys by the sort field and then by id in the same direction.
+// Names are compared byte-wise, while PostgreSQL uses collation of the database.
+func compareKeys(field, order string, a, b *domain.SearchKey) int {
+	var result int
+
+	switch field {
+	case domain.SortByGroup:
+		result = strings.Compare(a.Group, b.Group)
+	case domain.SortBySongName:
+		result = strings.Compare(a.SongName, b.SongName)
+	case domain.SortByReleaseDate:
+		result = a.ReleaseDate.Compare(b.ReleaseDate)
+	case domain.SortByCreatedAt:
+		result = a.CreatedAt.Compare(b.CreatedAt)
+	case domain.SortByUpdatedAt:
+		result = a.UpdatedAt.Compare(b.UpdatedAt)
+	case domain.SortByRelevance:
+		result = cmp.Or(cmp.Compare(a.Similarity, b.Similarity), cmp.Compare(a.Rank, b.Rank))
+	case domain.SortByPlays:
+		result = cmp.Compare(a.Plays, b.Plays)
+	case domain.SortByRating:
+		result = cmp.Compare(a.Rating, b.Rating)
+	}
+
+	result = cmp.Or(result, bytes.Compare(a.ID[:], b.ID[:]))
+
+	if order == domain.OrderDesc {
+		return -result
+	}
+	return result
+}
+
+// Equivalent of `$query <% column` conditions of the PostgreSQL fuzzy search.
+// Returns average word similarity of group and song name.
+func fuzzyMatch(song *song, search *domain.SongSearch) (float32, bool) {
+	similarities := make([]float64, 0, 2)
+
+	if search.ByGroup != "" {
+		similarities = append(similarities, trigram.WordSimilarity(search.ByGroup, song.artist.Name))
+	}
+	if search.BySongName != "" {
+		similarities = append(similarities, trigram.WordSimilarity(search.BySongName, song.name))
+	}
+
+	if len(similarities) == 0 {
+		return 0, true
+	}
+
+	var sum float64
+	for _, similarity := range similarities {
+		if similarity < search.Threshold {
+			return 0, false
+		}
+		sum += similarity
+	}
+
+	return float32(sum / float64(len(similarities))), true
+}
+
+// Returns at most maxSuggestions distinct names similar to the query,
+// ordered by word similarity and then by name.
+func suggest(query string, names []string, threshold float64) []string {
+	type suggestion struct {
+		name       string
+		similarity float64
+	}
+
+	suggestions := make([]suggestion, 0)
+
+	for _, name := range names {
+		similarity := trigram.WordSimilarity(query, name)
+		if similarity < threshold {
+			continue
+		}
+
+		if !slices.ContainsFunc(suggestions, func(s suggestion) bool { return s.name == name }) {
+			suggestions = append(suggestions, suggestion{name: name, similarity: similarity})
+		}
+	}
+
+	slices.SortFunc(suggestions, func(a, b suggestion) int {
+		return cmp.Or(cmp.Compare(b.similarity, a.similarity), strings.Compare(a.name, b.name))
+	})
+
+	result := make([]string, 0, maxSuggestions)
+	for _, s := range suggestions[:min(len(suggestions), maxSuggestions)] {
+		result = append(result, s.name)
+	}
+
+	return result
+}
+
+// Equivalent of `s ILIKE '%' || substr || '%'`.
+func contains(s, substr string) bool {
+	return ilike(s, "%"+substr+"%")
+}
+
+// Matches s against SQL LIKE pattern case insensitively:
+// "%" matches any sequence of characters, "_" matches any single character,
+// "\" escapes the next character.
+func ilike(s, pattern string) bool {
+	return like([]rune(strings.ToLower(s)), []rune(strings.ToLower(pattern)))
+}
+
+func like(s, pattern []rune) bool {
+	// star is a position in pattern after the last "%" and
+	// match is a position in s where this "%" started to match.
+	star, match := -1, 0
+	i, j := 0, 0
+
+	for i < len(s) {
+		switch {
+		case j < len(pattern) && pattern[j] == '%':
+			star, match = j+1, i
+			j++
+		case j < len(pattern) && pattern[j] == '\\' && j+1 < len(pattern) && pattern[j+1] == s[i]:
+			i++
+			j += 2
+		case j < len(pattern) && pattern[j] != '\\' && (pattern[j] == '_' || pattern[j] == s[i]):
+			i++
+			j++
+		case star != -1:
+			match++
+			i, j = match, star
+		default:
+			return false
+		}
+	}
+
+	for j < len(pattern) && pattern[j] == '%' {
+		j++
+	}
+
+	return j == len(pattern)
+}
+
+// Equivalent of `LIMIT batch.Limit OFFSET batch.Offset`.
+func page[T any](items []T, batch *domain.Batch) []T {
+	result := make([]T, 0)
+
+	if batch.Offset >= len(items) {
+		return result
+	}
+
+	end := min(batch.Offset+batch.Limit, len(items))
+
+	return append(result, items[batch.Offset:end]...)
+}
+
+// Equivalent of now(), PostgreSQL keeps timestamps with microseconds.
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+// Equivalent of current_date.
+func today() time.Time {
+	return truncateDate(time.Now())
+}
+
+// Drops time of day, because songs release date is stored as DATE.
+func truncateDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
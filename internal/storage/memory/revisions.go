@@ -0,0 +1,125 @@
+package memory
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+
+	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+)
+
+// Returns revisions of the song without snapshots, newest first.
+func (s *SongsStorage) Revisions(ctx context.Context, target *domain.Song, batch *domain.Batch) ([]*domain.Revision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	song := s.find(target)
+	if song == nil {
+		slog.Debug("song not found", "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+
+	revisions := make([]*domain.Revision, 0, len(song.revisions))
+	for _, revision := range slices.Backward(song.revisions) {
+		revision := *revision
+		revision.Song = nil
+		revisions = append(revisions, &revision)
+	}
+
+	return page(revisions, batch), nil
+}
+
+// Returns the revision with the state of the song after it.
+func (s *SongsStorage) Revision(ctx context.Context, target *domain.Song, number int) (*domain.Revision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	song := s.find(target)
+	if song == nil {
+		slog.Debug("song not found", "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+
+	revision := song.revision(number)
+	if revision == nil {
+		slog.Debug("revision not found", "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+
+	copied := *revision
+	return &copied, nil
+}
+
+// Sets metadata, lyrics and translations of the song to the state after the revision.
+// Restore is written as a new revision, so it can be reverted too.
+func (s *SongsStorage) Restore(ctx context.Context, target *domain.Song, number int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	song := s.find(target)
+	if song == nil {
+		slog.Debug("song not found", "operation", logmsg.ExtractOperationID(ctx))
+		return domain.ErrUnknownResourse
+	}
+
+	revision := song.revision(number)
+	if revision == nil {
+		slog.Debug("revision not found", "operation", logmsg.ExtractOperationID(ctx))
+		return domain.ErrUnknownResourse
+	}
+
+	state := revision.Song
+
+	if existing := s.findDuplicate(song, s.findArtist(state.Group), state.SongName); existing != nil {
+		return &domain.SongExistsError{ID: existing.id}
+	}
+
+	before := song.snapshot()
+
+	song.artist = s.getOrCreateArtist(state.Group)
+	song.name = state.SongName
+	song.link = state.Link
+	song.releaseDate = state.ReleaseDate
+	// snapshots written before translations were saved keep current translations
+	translations := state.Translations
+	if translations == nil {
+		translations = song.translationList()
+	}
+	song.replaceLyrics(slices.Clone(state.Lyrics), translations)
+	song.updatedAt = now()
+
+	song.writeRevision(ctx, before)
+
+	return nil
+}
+
+// Returns current state of the song.
+func (s *song) snapshot() *domain.SongSnapshot {
+	return &domain.SongSnapshot{
+		Group:        s.artist.Name,
+		SongName:     s.name,
+		Link:         s.link,
+		ReleaseDate:  s.releaseDate,
+		Lyrics:       slices.Clone(s.verses),
+		Translations: s.translationList(),
+	}
+}
+
+// Writes the next revision of the song from before to its current state.
+// before is nil for the created song.
+func (s *song) writeRevision(ctx context.Context, before *domain.SongSnapshot) {
+	revision := domain.NewRevision(domain.Author(ctx), before, s.snapshot())
+	revision.Number = len(s.revisions) + 1
+	revision.CreatedAt = s.updatedAt
+
+	s.revisions = append(s.revisions, revision)
+}
+
+// Returns nil if there is no revision with the number.
+func (s *song) revision(number int) *domain.Revision {
+	if number < 1 || number > len(s.revisions) {
+		return nil
+	}
+	return s.revisions[number-1]
+}
@@ -0,0 +1,452 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+)
+
+type song struct {
+	id uuid.UUID
+
+	// group of the song, renaming the artist renames groups of all its songs
+	artist *domain.Artist
+
+	name        string
+	releaseDate time.Time
+	link        string
+
+	verses []*domain.Verse
+
+	// verses of translations by language, they are aligned with verses
+	translations map[string][]string
+
+	// revisions are numbered from 1, the last one is the current state
+	revisions []*domain.Revision
+
+	// feedback of users by user id, maps are created on first use
+	favorites map[uuid.UUID]time.Time
+	ratings   map[uuid.UUID]int
+	plays     []play
+
+	// ids of genres and normalized tags
+	genres []uuid.UUID
+	tags   []string
+
+	// credits in display order
+	credits []credit
+
+	createdAt time.Time
+	updatedAt time.Time
+
+	// zero if the song is not in trash
+	deletedAt time.Time
+}
+
+// SongsStorage keeps songs in memory and behaves the same way as the PostgreSQL storage.
+// It is safe for concurrent use, data is lost after restart.
+type SongsStorage struct {
+	mu sync.RWMutex
+
+	// songs are kept in insertion order
+	songs []*song
+
+	// genre taxonomy in creation order
+	genres []*domain.Genre
+
+	// artists of groups and credits of songs in creation order, they are kept after songs are deleted
+	artists []*domain.Artist
+
+	// albums in creation order, their tracks refer to songs
+	albums []*album
+
+	// playlists in creation order, their items refer to songs
+	playlists []*playlist
+}
+
+func NewSongsStorage() *SongsStorage {
+	return &SongsStorage{
+		songs: make([]*song, 0),
+	}
+}
+
+func (s *SongsStorage) Info(ctx context.Context, target *domain.Song) (*domain.SongInfo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	song := s.find(target)
+	if song == nil {
+		slog.Debug("song not found", "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+
+	return &domain.SongInfo{
+		ID:          song.id,
+		Group:       song.artist.Name,
+		SongName:    song.name,
+		Lyrics:      strings.Join(texts(song.verses), "\n"),
+		ReleaseDate: song.releaseDate,
+		Link:        song.link,
+		Albums:      s.songAlbums(song),
+		Genres:      s.genreNames(song),
+		Tags:        append(make([]string, 0, len(song.tags)), song.tags...),
+		Credits:     song.creditList(),
+		CreatedAt:   song.createdAt,
+		UpdatedAt:   song.updatedAt,
+		Stats:       song.stats(),
+	}, nil
+}
+
+// Songs are sorted and paginated the same way as by the PostgreSQL storage.
+func (s *SongsStorage) Search(ctx context.Context, search *domain.SongSearch) ([]*domain.FoundSong, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return pageFound(s.search(search), search), nil
+}
+
+// Counts all found songs, pagination is ignored.
+func (s *SongsStorage) Count(ctx context.Context, search *domain.SongSearch) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.search(search)), nil
+}
+
+// Returns group and song names similar to the searched ones, at most maxSuggestions of each.
+func (s *SongsStorage) Suggest(ctx context.Context, search *domain.SongSearch) (*domain.Suggestions, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	suggestions := &domain.Suggestions{}
+
+	if search.ByGroup != "" {
+		groups := make([]string, 0, len(s.songs))
+		for _, song := range s.songs {
+			if !song.trashed() {
+				groups = append(groups, song.artist.Name)
+			}
+		}
+		suggestions.Groups = suggest(search.ByGroup, groups, search.Threshold)
+	}
+
+	if search.BySongName != "" {
+		names := make([]string, 0, len(s.songs))
+		for _, song := range s.songs {
+			if !song.trashed() {
+				names = append(names, song.name)
+			}
+		}
+		suggestions.Songs = suggest(search.BySongName, names, search.Threshold)
+	}
+
+	return suggestions, nil
+}
+
+// Verses are ordered by position, empty section matches verses of all sections.
+func (s *SongsStorage) GetLyrics(ctx context.Context, target *domain.Song, batch *domain.LyricsBatch) ([]*domain.Verse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	song := s.find(target)
+	if song == nil {
+		slog.Debug("song not found", "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+
+	verses := inSection(song.verses, batch.Section)
+
+	if batch.Before != 0 {
+		end := len(verses)
+		if i := slices.IndexFunc(verses, func(v *domain.Verse) bool { return v.Position >= batch.Before }); i != -1 {
+			end = i
+		}
+		return slices.Clone(verses[max(0, end-batch.Limit):end]), nil
+	}
+
+	start := len(verses)
+	if i := slices.IndexFunc(verses, func(v *domain.Verse) bool { return v.Position > batch.After }); i != -1 {
+		start = i
+	}
+
+	return page(verses[start:], &batch.Batch), nil
+}
+
+// Returns all verses of the song ordered by position.
+func (s *SongsStorage) Verses(ctx context.Context, target *domain.Song) ([]*domain.Verse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	song := s.find(target)
+	if song == nil {
+		slog.Debug("song not found", "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+
+	return slices.Clone(song.verses), nil
+}
+
+func (s *SongsStorage) CountLyrics(ctx context.Context, target *domain.Song, batch *domain.LyricsBatch) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	song := s.find(target)
+	if song == nil {
+		slog.Debug("song not found", "operation", logmsg.ExtractOperationID(ctx))
+		return 0, domain.ErrUnknownResourse
+	}
+
+	return len(inSection(song.verses, batch.Section)), nil
+}
+
+// details may be nil, then only group and song name are saved.
+// Returns id of the created song.
+func (s *SongsStorage) Create(ctx context.Context, target *domain.Song, details *domain.SongDetails) (uuid.UUID, error) {
+	song := &song{
+		id:           uuid.New(),
+		name:         target.SongName,
+		releaseDate:  today(),
+		verses:       make([]*domain.Verse, 0),
+		translations: make(map[string][]string),
+		createdAt:    now(),
+	}
+	song.updatedAt = song.createdAt
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing := s.findDuplicate(song, s.findArtist(target.Group), song.name); existing != nil {
+		return uuid.Nil, &domain.SongExistsError{ID: existing.id}
+	}
+
+	song.artist = s.getOrCreateArtist(target.Group)
+
+	if details != nil {
+		schema := details.ToSongSchema(target)
+		if !schema.ReleaseDate.IsZero() {
+			song.releaseDate = truncateDate(schema.ReleaseDate)
+		}
+		song.link = schema.Link
+		song.verses = details.ToLyricsSchema().Lyrics
+	}
+
+	song.writeRevision(ctx, nil)
+
+	s.songs = append(s.songs, song)
+
+	return song.id, nil
+}
+
+// Moves the song with the id to trash if it is set,
+// otherwise all songs with the same group and name.
+func (s *SongsStorage) Delete(ctx context.Context, target *domain.Song) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deletedAt := now()
+	deleted := false
+
+	for _, song := range s.songs {
+		if !song.trashed() && song.is(target) {
+			song.deletedAt = deletedAt
+			deleted = true
+		}
+	}
+
+	if !deleted {
+		slog.Debug("no rows affected", "operation", logmsg.ExtractOperationID(ctx))
+		return domain.ErrUnknownResourse
+	}
+
+	return nil
+}
+
+// Song is found the same way as by Info,
+// lyrics are replaced only if new ones are provided.
+func (s *SongsStorage) Update(ctx context.Context, target *domain.Song, update *domain.SongUpdate) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	song := s.find(target)
+	if song == nil {
+		slog.Debug("song not found", "operation", logmsg.ExtractOperationID(ctx))
+		return domain.ErrUnknownResourse
+	}
+
+	schema := update.ToSongSchema()
+
+	artist := song.artist
+	if schema.Group != "" {
+		artist = s.findArtist(schema.Group)
+	}
+
+	existing := s.findDuplicate(song, artist, cmp.Or(schema.SongName, song.name))
+	if existing != nil {
+		return &domain.SongExistsError{ID: existing.id}
+	}
+
+	before := song.snapshot()
+
+	lyrics := update.ToLyricsSchema()
+	if len(lyrics.Lyrics) != 0 {
+		song.replaceLyrics(lyrics.Lyrics, song.translationList())
+	}
+
+	if schema.Group != "" {
+		song.artist = s.getOrCreateArtist(schema.Group)
+	}
+	if schema.SongName != "" {
+		song.name = schema.SongName
+	}
+	if schema.Link != "" {
+		song.link = schema.Link
+	}
+	if !schema.ReleaseDate.IsZero() {
+		song.releaseDate = truncateDate(schema.ReleaseDate)
+	}
+
+	song.updatedAt = now()
+
+	song.writeRevision(ctx, before)
+
+	return nil
+}
+
+// Returns found songs in sort order. Caller must hold the lock.
+func (s *SongsStorage) search(search *domain.SongSearch) []*domain.FoundSong {
+	found := make([]*domain.FoundSong, 0)
+
+	var query tsQuery
+	if search.ByLyrics != "" {
+		query = parseTsQuery(search.ByLyrics)
+	}
+
+	var genres []uuid.UUID
+	if search.Genre != "" {
+		genres = s.genreFilter(search.Genre)
+	}
+
+	for _, song := range s.songs {
+		if song.trashed() || !matches(song, search) {
+			continue
+		}
+
+		if search.Genre != "" && !slices.ContainsFunc(song.genres, func(id uuid.UUID) bool { return slices.Contains(genres, id) }) {
+			continue
+		}
+
+		result := &domain.FoundSong{
+			Song: domain.Song{
+				ID:       song.id,
+				Group:    song.artist.Name,
+				SongName: song.name,
+			},
+			ReleaseDate: song.releaseDate,
+			CreatedAt:   song.createdAt,
+			UpdatedAt:   song.updatedAt,
+		}
+
+		stats := song.stats()
+		result.Plays, result.Rating = stats.Plays, stats.AverageRating
+
+		if search.Fuzzy {
+			similarity, ok := fuzzyMatch(song, search)
+			if !ok {
+				continue
+			}
+			result.Similarity = similarity
+		}
+
+		if query != nil {
+			fragments := make([]string, 0, maxFragments)
+
+			for _, verse := range song.verses {
+				rank := query.rank(verse.Text)
+				if rank == 0 {
+					continue
+				}
+
+				result.Rank = max(result.Rank, rank)
+				if len(fragments) < maxFragments {
+					fragments = append(fragments, query.highlight(verse.Text))
+				}
+			}
+
+			if len(fragments) == 0 {
+				continue
+			}
+			result.Headline = strings.Join(fragments, " ... ")
+		}
+
+		found = append(found, result)
+	}
+
+	sortFound(found, search)
+
+	return found
+}
+
+// Returns song with the id or first song with the same group and name or nil.
+// Songs in trash are not found. Caller must hold the lock.
+func (s *SongsStorage) find(target *domain.Song) *song {
+	for _, song := range s.songs {
+		if !song.trashed() && song.is(target) {
+			return song
+		}
+	}
+	return nil
+}
+
+// Returns another song not in trash of the artist with the same name up to case and spaces or nil,
+// it is equivalent of the unique index. Nil artist has no songs. Caller must hold the lock.
+func (s *SongsStorage) findDuplicate(except *song, artist *domain.Artist, name string) *song {
+	for _, song := range s.songs {
+		if song != except && !song.trashed() &&
+			song.artist == artist && domain.NameKey(song.name) == domain.NameKey(name) {
+			return song
+		}
+	}
+	return nil
+}
+
+// Same lookup rules as domain.Song describes, group is the name or alias of the artist.
+func (s *song) is(target *domain.Song) bool {
+	if target.ID != uuid.Nil {
+		return s.id == target.ID
+	}
+	return isArtist(s.artist, target.Group) && domain.NameKey(s.name) == domain.NameKey(target.SongName)
+}
+
+func (s *song) trashed() bool {
+	return !s.deletedAt.IsZero()
+}
+
+func inSection(verses []*domain.Verse, section string) []*domain.Verse {
+	if section == "" {
+		return verses
+	}
+
+	result := make([]*domain.Verse, 0)
+	for _, verse := range verses {
+		if verse.Section == section {
+			result = append(result, verse)
+		}
+	}
+	return result
+}
+
+func texts(verses []*domain.Verse) []string {
+	result := make([]string, 0, len(verses))
+	for _, verse := range verses {
+		result = append(result, verse.Text)
+	}
+	return result
+}
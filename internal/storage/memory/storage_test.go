@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/qreaqtor/music-library/internal/storage/storagetest"
+)
+
+func TestConformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storagetest.Storage { return NewSongsStorage() })
+}
+
+func TestUsersConformance(t *testing.T) {
+	storagetest.RunUsers(t, func(t *testing.T) storagetest.UsersStorage { return NewUsersStorage() })
+}
+
+func TestAPIKeysConformance(t *testing.T) {
+	storagetest.RunAPIKeys(t, func(t *testing.T) storagetest.APIKeysStorage { return NewAPIKeysStorage() })
+}
+
+func TestArtistsConformance(t *testing.T) {
+	storagetest.RunArtists(t, func(t *testing.T) (storagetest.ArtistsStorage, storagetest.Storage) {
+		songs := NewSongsStorage()
+		return NewArtistsStorage(songs), songs
+	})
+}
+
+func TestAlbumsConformance(t *testing.T) {
+	storagetest.RunAlbums(t, func(t *testing.T) (storagetest.AlbumsStorage, storagetest.ArtistsStorage, storagetest.Storage) {
+		songs := NewSongsStorage()
+		return NewAlbumsStorage(songs), NewArtistsStorage(songs), songs
+	})
+}
+
+func TestPlaylistsConformance(t *testing.T) {
+	storagetest.RunPlaylists(t, func(t *testing.T) (storagetest.PlaylistsStorage, storagetest.Storage) {
+		songs := NewSongsStorage()
+		return NewPlaylistsStorage(songs), songs
+	})
+}
+
+func TestFeedbackConformance(t *testing.T) {
+	storagetest.RunFeedback(t, func(t *testing.T) (storagetest.FeedbackStorage, storagetest.UsersStorage) {
+		return NewSongsStorage(), NewUsersStorage()
+	})
+}
@@ -0,0 +1,123 @@
+package memory
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"slices"
+
+	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+)
+
+// Languages are ordered alphabetically, verses without language are skipped.
+func (s *SongsStorage) Languages(ctx context.Context, target *domain.Song) (*domain.Languages, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	song := s.find(target)
+	if song == nil {
+		slog.Debug("song not found", "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+
+	original := make([]string, 0)
+	for _, verse := range song.verses {
+		if verse.Language != "" && !slices.Contains(original, verse.Language) {
+			original = append(original, verse.Language)
+		}
+	}
+	slices.Sort(original)
+
+	return &domain.Languages{
+		Original:     original,
+		Translations: slices.Sorted(maps.Keys(song.translations)),
+	}, nil
+}
+
+// Returns domain.ErrUnknownResourse if the song has no translation to the language.
+func (s *SongsStorage) Translation(ctx context.Context, target *domain.Song, language string) (*domain.Translation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	song := s.find(target)
+	if song == nil {
+		slog.Debug("song not found", "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+
+	verses, ok := song.translations[language]
+	if !ok {
+		slog.Debug("translation not found", "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+
+	return &domain.Translation{
+		Language: language,
+		Verses:   slices.Clone(verses),
+	}, nil
+}
+
+// Replaces translation to the same language.
+// Returns domain.ErrTranslationVerses if number of verses differs from the lyrics.
+func (s *SongsStorage) SetTranslation(ctx context.Context, target *domain.Song, translation *domain.Translation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	song := s.find(target)
+	if song == nil {
+		slog.Debug("song not found", "operation", logmsg.ExtractOperationID(ctx))
+		return domain.ErrUnknownResourse
+	}
+
+	if len(song.verses) != len(translation.Verses) {
+		return domain.ErrTranslationVerses
+	}
+
+	song.translations[translation.Language] = slices.Clone(translation.Verses)
+
+	return nil
+}
+
+func (s *SongsStorage) DeleteTranslation(ctx context.Context, target *domain.Song, language string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	song := s.find(target)
+	if song == nil {
+		slog.Debug("song not found", "operation", logmsg.ExtractOperationID(ctx))
+		return domain.ErrUnknownResourse
+	}
+
+	if _, ok := song.translations[language]; !ok {
+		slog.Debug("translation not found", "operation", logmsg.ExtractOperationID(ctx))
+		return domain.ErrUnknownResourse
+	}
+
+	delete(song.translations, language)
+
+	return nil
+}
+
+// Returns copies of translations of the song ordered by language.
+func (s *song) translationList() []*domain.Translation {
+	translations := make([]*domain.Translation, 0, len(s.translations))
+	for _, language := range slices.Sorted(maps.Keys(s.translations)) {
+		translations = append(translations, &domain.Translation{
+			Language: language,
+			Verses:   slices.Clone(s.translations[language]),
+		})
+	}
+	return translations
+}
+
+// Replaces verses of the song, translations are replaced by the given ones
+// which are kept for verses at positions which still exist.
+func (s *song) replaceLyrics(verses []*domain.Verse, translations []*domain.Translation) {
+	s.verses = verses
+
+	s.translations = make(map[string][]string)
+	for _, translation := range domain.KeepTranslations(translations, len(verses)) {
+		s.translations[translation.Language] = slices.Clone(translation.Verses)
+	}
+}
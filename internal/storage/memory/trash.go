@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+)
+
+// Returns songs in trash, recently deleted first.
+func (s *SongsStorage) Trash(ctx context.Context, batch *domain.Batch) ([]*domain.TrashedSong, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	trashed := make([]*domain.TrashedSong, 0)
+	for _, song := range s.songs {
+		if !song.trashed() {
+			continue
+		}
+
+		trashed = append(trashed, &domain.TrashedSong{
+			Song: domain.Song{
+				ID:       song.id,
+				Group:    song.artist.Name,
+				SongName: song.name,
+			},
+			DeletedAt: song.deletedAt,
+		})
+	}
+
+	slices.SortFunc(trashed, func(a, b *domain.TrashedSong) int {
+		return cmp.Or(
+			b.DeletedAt.Compare(a.DeletedAt),
+			slices.Compare(a.ID[:], b.ID[:]),
+		)
+	})
+
+	return page(trashed, batch), nil
+}
+
+// Moves the song with the id out of trash if it is set,
+// otherwise the most recently deleted song with the same group and name.
+// Returns domain.SongExistsError if there is another song with the same group and name.
+func (s *SongsStorage) Untrash(ctx context.Context, target *domain.Song) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var restored *song
+	for _, song := range s.songs {
+		if song.trashed() && song.is(target) && (restored == nil || song.deletedAt.After(restored.deletedAt)) {
+			restored = song
+		}
+	}
+
+	if restored == nil {
+		slog.Debug("no rows affected", "operation", logmsg.ExtractOperationID(ctx))
+		return domain.ErrUnknownResourse
+	}
+
+	if existing := s.findDuplicate(restored, restored.artist, restored.name); existing != nil {
+		return &domain.SongExistsError{ID: existing.id}
+	}
+
+	restored.deletedAt = time.Time{}
+
+	return nil
+}
+
+// Permanently deletes songs moved to trash before the time.
+// Returns number of deleted songs.
+func (s *SongsStorage) Purge(ctx context.Context, before time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := len(s.songs)
+	s.songs = slices.DeleteFunc(s.songs, func(song *song) bool {
+		return song.trashed() && song.deletedAt.Before(before)
+	})
+	s.deleteTracks()
+	s.deleteItems()
+
+	return n - len(s.songs), nil
+}
@@ -0,0 +1,221 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+)
+
+// UsersStorage keeps users and their sessions in memory and behaves the same way as the PostgreSQL storage.
+// It is safe for concurrent use, data is lost after restart.
+type UsersStorage struct {
+	mu sync.RWMutex
+
+	users map[uuid.UUID]*domain.UserAccount
+
+	// ids of users by lower case name
+	names map[string]uuid.UUID
+
+	sessions map[uuid.UUID]*domain.Session
+}
+
+func NewUsersStorage() *UsersStorage {
+	return &UsersStorage{
+		users:    make(map[uuid.UUID]*domain.UserAccount),
+		names:    make(map[string]uuid.UUID),
+		sessions: make(map[uuid.UUID]*domain.Session),
+	}
+}
+
+// Names are unique case insensitively.
+func (s *UsersStorage) CreateUser(ctx context.Context, account *domain.UserAccount) (*domain.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := strings.ToLower(account.Name)
+	if _, exists := s.names[key]; exists {
+		return nil, domain.ErrUserExists
+	}
+
+	created := &domain.UserAccount{
+		User: domain.User{
+			ID:        uuid.New(),
+			Name:      account.Name,
+			Role:      account.Role,
+			CreatedAt: time.Now(),
+		},
+		PasswordHash: account.PasswordHash,
+	}
+
+	s.users[created.ID] = created
+	s.names[key] = created.ID
+
+	user := created.User
+	return &user, nil
+}
+
+func (s *UsersStorage) User(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	account, ok := s.users[id]
+	if !ok {
+		slog.Debug("user not found", "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+
+	user := account.User
+	return &user, nil
+}
+
+// Users are ordered by registration.
+func (s *UsersStorage) Users(ctx context.Context, batch *domain.Batch) ([]*domain.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	accounts := make([]*domain.UserAccount, 0, len(s.users))
+	for _, account := range s.users {
+		accounts = append(accounts, account)
+	}
+
+	slices.SortFunc(accounts, func(a, b *domain.UserAccount) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), strings.Compare(a.ID.String(), b.ID.String()))
+	})
+
+	users := make([]*domain.User, 0)
+	for _, account := range page(accounts, batch) {
+		user := account.User
+		users = append(users, &user)
+	}
+
+	return users, nil
+}
+
+func (s *UsersStorage) SetRole(ctx context.Context, id uuid.UUID, role string) (*domain.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	account, ok := s.users[id]
+	if !ok {
+		slog.Debug("user not found", "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+
+	account.Role = role
+
+	user := account.User
+	return &user, nil
+}
+
+// User is found by name case insensitively.
+func (s *UsersStorage) UserByName(ctx context.Context, name string) (*domain.UserAccount, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	id, ok := s.names[strings.ToLower(name)]
+	if !ok {
+		slog.Debug("user not found", "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+
+	account := *s.users[id]
+	return &account, nil
+}
+
+// Expired and revoked sessions of the user are deleted, so they don't pile up.
+func (s *UsersStorage) CreateSession(ctx context.Context, session *domain.Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[session.UserID]; !ok {
+		return domain.ErrUnknownResourse
+	}
+
+	now := time.Now()
+
+	for id, existing := range s.sessions {
+		if existing.UserID == session.UserID && (existing.Revoked || existing.ExpiresAt.Before(now)) {
+			delete(s.sessions, id)
+		}
+	}
+
+	session.CreatedAt = now
+
+	created := *session
+	s.sessions[session.ID] = &created
+
+	return nil
+}
+
+func (s *UsersStorage) Session(ctx context.Context, id uuid.UUID) (*domain.Session, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	session, ok := s.sessions[id]
+	if !ok {
+		slog.Debug("session not found", "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+
+	found := *session
+	return &found, nil
+}
+
+// Replaces refresh token of the session if refreshID is still the last issued one.
+// Returns domain.ErrInvalidToken if the session was revoked or refreshed concurrently.
+func (s *UsersStorage) RotateSession(ctx context.Context, id, refreshID, newRefreshID uuid.UUID, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[id]
+	if !ok || session.RefreshID != refreshID || session.Revoked || !session.ExpiresAt.After(time.Now()) {
+		slog.Debug("session can't be refreshed", "operation", logmsg.ExtractOperationID(ctx))
+		return domain.ErrInvalidToken
+	}
+
+	session.RefreshID = newRefreshID
+	session.ExpiresAt = expiresAt
+
+	return nil
+}
+
+// Revoking revoked session is not an error.
+func (s *UsersStorage) RevokeSession(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[id]
+	if !ok {
+		slog.Debug("session not found", "operation", logmsg.ExtractOperationID(ctx))
+		return domain.ErrUnknownResourse
+	}
+
+	session.Revoked = true
+
+	return nil
+}
+
+// Returns number of revoked sessions, already revoked ones are not counted.
+func (s *UsersStorage) RevokeSessions(ctx context.Context, userID uuid.UUID) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	revoked := 0
+
+	for _, session := range s.sessions {
+		if session.UserID == userID && !session.Revoked {
+			session.Revoked = true
+			revoked++
+		}
+	}
+
+	return revoked, nil
+}
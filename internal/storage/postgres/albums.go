@@ -0,0 +1,355 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+)
+
+const albumColumns = "al.id, al.title, al.artist_id, a.name, al.release_date, COALESCE(al.cover_url, ''), COALESCE(al.label, '')"
+
+type AlbumsStorage struct {
+	db *sql.DB
+}
+
+func NewAlbumsStorage(connection *sql.DB) *AlbumsStorage {
+	return &AlbumsStorage{
+		db: connection,
+	}
+}
+
+func (s *AlbumsStorage) Create(ctx context.Context, album *domain.Album) (*domain.Album, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var albumID uuid.UUID
+
+	query :=
+		`INSERT INTO albums (title, artist_id, release_date, cover_url, label)
+		VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''))
+		RETURNING id;`
+
+	err = tx.QueryRowContext(
+		ctx,
+		query,
+		album.Title,
+		album.ArtistID,
+		album.ReleaseDate,
+		album.CoverURL,
+		album.Label,
+	).Scan(&albumID)
+	if err != nil {
+		return nil, albumError(err)
+	}
+
+	created, err := getAlbum(ctx, tx, albumID)
+	if err != nil {
+		return nil, err
+	}
+
+	return created, tx.Commit()
+}
+
+func (s *AlbumsStorage) Get(ctx context.Context, id uuid.UUID) (*domain.Album, error) {
+	return getAlbum(ctx, s.db, id)
+}
+
+// Albums are filtered by title and artist and ordered by release date.
+func (s *AlbumsStorage) List(ctx context.Context, search *domain.AlbumSearch) ([]*domain.Album, error) {
+	albums := make([]*domain.Album, 0)
+
+	query :=
+		`SELECT ` + albumColumns + `
+		FROM albums al JOIN artists a ON a.id = al.artist_id
+		WHERE ($1 = '' OR al.title ILIKE '%' || $1 || '%')
+			AND ($2::uuid IS NULL OR al.artist_id = $2)
+		ORDER BY al.release_date NULLS LAST, al.title
+		LIMIT $3 OFFSET $4;`
+
+	artistID := uuid.NullUUID{UUID: search.ArtistID, Valid: search.ArtistID != uuid.Nil}
+
+	rows, err := s.db.QueryContext(ctx, query, search.ByTitle, artistID, search.Limit, search.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		album, err := scanAlbum(rows)
+		if err != nil {
+			return nil, err
+		}
+		albums = append(albums, album)
+	}
+
+	return albums, rows.Err()
+}
+
+func (s *AlbumsStorage) Update(ctx context.Context, id uuid.UUID, update *domain.AlbumUpdate) (*domain.Album, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	updateQuery, err := getUpdateQuery("albums", id, update.ToAlbumSchema())
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := tx.ExecContext(ctx, updateQuery.query, updateQuery.args...)
+	if err != nil {
+		return nil, albumError(err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil || n == 0 {
+		slog.Debug("no rows affected", "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+
+	album, err := getAlbum(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return album, tx.Commit()
+}
+
+// Tracks of the album are deleted with it, songs are kept.
+func (s *AlbumsStorage) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := s.db.ExecContext(ctx, "DELETE FROM albums WHERE id = $1;", id)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil || n == 0 {
+		slog.Debug("no rows affected", "operation", logmsg.ExtractOperationID(ctx))
+		return domain.ErrUnknownResourse
+	}
+
+	return nil
+}
+
+// Tracks are ordered by disc and track number.
+func (s *AlbumsStorage) GetTracks(ctx context.Context, albumID uuid.UUID) ([]*domain.Track, error) {
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	_, err = getAlbum(ctx, tx, albumID)
+	if err != nil {
+		return nil, err
+	}
+
+	tracks, err := getTracks(ctx, tx, albumID)
+	if err != nil {
+		return nil, err
+	}
+
+	return tracks, tx.Commit()
+}
+
+// Replaces the whole tracklist of the album.
+func (s *AlbumsStorage) SetTracks(ctx context.Context, albumID uuid.UUID, tracklist *domain.Tracklist) ([]*domain.Track, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	_, err = getAlbum(ctx, tx, albumID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM album_tracks WHERE album_id = $1;", albumID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tracklist.Tracks) != 0 {
+		values := make([]string, 0, len(tracklist.Tracks))
+		args := make([]any, 0, len(tracklist.Tracks)*4+1)
+		args = append(args, albumID)
+
+		for _, track := range tracklist.Tracks {
+			songID, err := findSongID(ctx, tx, &track.Song)
+			if errors.Is(err, domain.ErrUnknownResourse) {
+				return nil, fmt.Errorf("%w: %s - %s", err, track.Group, track.SongName)
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			disc := track.DiscNumber
+			if disc == 0 {
+				disc = 1
+			}
+
+			values = append(values, fmt.Sprintf(
+				"($1, $%d, $%d, $%d, NULLIF($%d, 0))",
+				len(args)+1, len(args)+2, len(args)+3, len(args)+4,
+			))
+			args = append(args, songID, disc, track.TrackNumber, track.Duration)
+		}
+
+		query := fmt.Sprintf(
+			`INSERT INTO album_tracks (album_id, song_id, disc_number, track_number, duration) VALUES %s;`,
+			strings.Join(values, ","),
+		)
+
+		_, err = tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return nil, albumError(err)
+		}
+	}
+
+	tracks, err := getTracks(ctx, tx, albumID)
+	if err != nil {
+		return nil, err
+	}
+
+	return tracks, tx.Commit()
+}
+
+func getAlbum(ctx context.Context, q querier, id uuid.UUID) (*domain.Album, error) {
+	query :=
+		`SELECT ` + albumColumns + `
+		FROM albums al JOIN artists a ON a.id = al.artist_id
+		WHERE al.id = $1;`
+
+	album, err := scanAlbum(q.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		slog.Debug(err.Error(), "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return album, nil
+}
+
+func getTracks(ctx context.Context, q querier, albumID uuid.UUID) ([]*domain.Track, error) {
+	tracks := make([]*domain.Track, 0)
+
+	query :=
+		`SELECT a.name, s.song, t.song_id, t.disc_number, t.track_number, COALESCE(t.duration, 0)
+		FROM album_tracks t
+			JOIN songs s ON s.id = t.song_id
+			JOIN artists a ON a.id = s.artist_id
+		WHERE t.album_id = $1 AND s.deleted_at IS NULL
+		ORDER BY t.disc_number, t.track_number;`
+
+	rows, err := q.QueryContext(ctx, query, albumID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		track := &domain.Track{}
+
+		err = rows.Scan(
+			&track.Group,
+			&track.SongName,
+			&track.ID,
+			&track.DiscNumber,
+			&track.TrackNumber,
+			&track.Duration,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		tracks = append(tracks, track)
+	}
+
+	return tracks, rows.Err()
+}
+
+// Returns albums which the song appears on, ordered by release date.
+func getSongAlbums(ctx context.Context, q querier, songID uuid.UUID) ([]domain.AlbumRef, error) {
+	albums := make([]domain.AlbumRef, 0)
+
+	query :=
+		`SELECT al.id, al.title, al.release_date, t.disc_number, t.track_number
+		FROM album_tracks t JOIN albums al ON al.id = t.album_id
+		WHERE t.song_id = $1
+		ORDER BY al.release_date NULLS LAST, al.title;`
+
+	rows, err := q.QueryContext(ctx, query, songID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		album := domain.AlbumRef{}
+		var releaseDate sql.NullTime
+
+		err = rows.Scan(&album.ID, &album.Title, &releaseDate, &album.DiscNumber, &album.TrackNumber)
+		if err != nil {
+			return nil, err
+		}
+
+		if releaseDate.Valid {
+			album.ReleaseDate = &releaseDate.Time
+		}
+
+		albums = append(albums, album)
+	}
+
+	return albums, rows.Err()
+}
+
+// Columns must be selected in albumColumns order.
+func scanAlbum(row scanner) (*domain.Album, error) {
+	album := &domain.Album{}
+	var releaseDate sql.NullTime
+
+	err := row.Scan(
+		&album.ID,
+		&album.Title,
+		&album.ArtistID,
+		&album.Artist,
+		&releaseDate,
+		&album.CoverURL,
+		&album.Label,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if releaseDate.Valid {
+		album.ReleaseDate = &releaseDate.Time
+	}
+
+	return album, nil
+}
+
+// Converts constraint violations to domain errors.
+func albumError(err error) error {
+	switch {
+	case hasCode(err, foreignKeyViolation):
+		return domain.ErrUnknownArtist
+	case hasCode(err, uniqueViolation):
+		return domain.ErrTrackPosition
+	default:
+		return err
+	}
+}
@@ -0,0 +1,183 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+)
+
+const apiKeyColumns = "id, name, prefix, permissions, created_by, created_at, expires_at, last_used_at, revoked_at"
+
+type APIKeysStorage struct {
+	db *sql.DB
+}
+
+func NewAPIKeysStorage(connection *sql.DB) *APIKeysStorage {
+	return &APIKeysStorage{
+		db: connection,
+	}
+}
+
+func (s *APIKeysStorage) CreateKey(ctx context.Context, account *domain.APIKeyAccount) (*domain.APIKey, error) {
+	query :=
+		`INSERT INTO api_keys (name, prefix, key_hash, permissions, created_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + apiKeyColumns + `;`
+
+	return scanAPIKey(s.db.QueryRowContext(
+		ctx,
+		query,
+		account.Name,
+		account.Prefix,
+		account.Hash,
+		pq.Array(account.Permissions),
+		account.CreatedBy,
+		account.ExpiresAt,
+	))
+}
+
+// Keys are ordered by creation, revoked and expired keys are listed too.
+func (s *APIKeysStorage) Keys(ctx context.Context, batch *domain.Batch) ([]*domain.APIKey, error) {
+	keys := make([]*domain.APIKey, 0)
+
+	query :=
+		`SELECT ` + apiKeyColumns + `
+		FROM api_keys
+		ORDER BY created_at, id
+		LIMIT $1 OFFSET $2;`
+
+	rows, err := s.db.QueryContext(ctx, query, batch.Limit, batch.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+func (s *APIKeysStorage) KeyByPrefix(ctx context.Context, prefix string) (*domain.APIKeyAccount, error) {
+	account := &domain.APIKeyAccount{}
+
+	query :=
+		`SELECT ` + apiKeyColumns + `, key_hash
+		FROM api_keys
+		WHERE prefix = $1;`
+
+	err := s.db.QueryRowContext(ctx, query, prefix).Scan(append(apiKeyFields(&account.APIKey), &account.Hash)...)
+	if errors.Is(err, sql.ErrNoRows) {
+		slog.Debug(err.Error(), "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// Replaces the key, the old one is rejected right after it.
+// Returns domain.ErrKeyRevoked if the key is revoked.
+func (s *APIKeysStorage) RotateKey(ctx context.Context, id uuid.UUID, prefix string, hash []byte) (*domain.APIKey, error) {
+	query :=
+		`UPDATE api_keys SET prefix = $2, key_hash = $3, last_used_at = NULL
+		WHERE id = $1 AND revoked_at IS NULL
+		RETURNING ` + apiKeyColumns + `;`
+
+	key, err := scanAPIKey(s.db.QueryRowContext(ctx, query, id, prefix, hash))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, s.keyError(ctx, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// Revoking revoked key is not an error.
+func (s *APIKeysStorage) RevokeKey(ctx context.Context, id uuid.UUID) (*domain.APIKey, error) {
+	query :=
+		`UPDATE api_keys SET revoked_at = COALESCE(revoked_at, now())
+		WHERE id = $1
+		RETURNING ` + apiKeyColumns + `;`
+
+	key, err := scanAPIKey(s.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		slog.Debug(err.Error(), "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// Sets time of the last use, earlier time doesn't replace later one.
+func (s *APIKeysStorage) TouchKey(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	_, err := s.db.ExecContext(
+		ctx,
+		"UPDATE api_keys SET last_used_at = $2 WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < $2);",
+		id,
+		usedAt,
+	)
+	return err
+}
+
+// Returns why the key wasn't rotated: it is unknown or revoked, revoked keys stay revoked.
+func (s *APIKeysStorage) keyError(ctx context.Context, id uuid.UUID) error {
+	var exists bool
+
+	err := s.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM api_keys WHERE id = $1);", id).Scan(&exists)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		slog.Debug("api key not found", "operation", logmsg.ExtractOperationID(ctx))
+		return domain.ErrUnknownResourse
+	}
+
+	return domain.ErrKeyRevoked
+}
+
+// Columns must be selected in apiKeyColumns order.
+func scanAPIKey(row scanner) (*domain.APIKey, error) {
+	key := &domain.APIKey{}
+
+	err := row.Scan(apiKeyFields(key)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func apiKeyFields(key *domain.APIKey) []any {
+	return []any{
+		&key.ID,
+		&key.Name,
+		&key.Prefix,
+		pq.Array(&key.Permissions),
+		&key.CreatedBy,
+		&key.CreatedAt,
+		&key.ExpiresAt,
+		&key.LastUsedAt,
+		&key.RevokedAt,
+	}
+}
@@ -0,0 +1,196 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+)
+
+const artistColumns = "id, name, aliases, COALESCE(country, ''), formed, disbanded, COALESCE(bio, '')"
+
+type ArtistsStorage struct {
+	db *sql.DB
+}
+
+func NewArtistsStorage(connection *sql.DB) *ArtistsStorage {
+	return &ArtistsStorage{
+		db: connection,
+	}
+}
+
+func (s *ArtistsStorage) Create(ctx context.Context, artist *domain.Artist) (*domain.Artist, error) {
+	aliases := artist.Aliases
+	if aliases == nil {
+		aliases = []string{}
+	}
+
+	query :=
+		`INSERT INTO artists (name, aliases, country, formed, disbanded, bio)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5, NULLIF($6, ''))
+		RETURNING ` + artistColumns + ";"
+
+	row := s.db.QueryRowContext(
+		ctx,
+		query,
+		artist.Name,
+		pq.Array(aliases),
+		artist.Country,
+		artist.Formed,
+		artist.Disbanded,
+		artist.Bio,
+	)
+
+	created, err := scanArtist(row)
+	if err != nil {
+		return nil, artistError(err)
+	}
+
+	return created, nil
+}
+
+func (s *ArtistsStorage) Get(ctx context.Context, id uuid.UUID) (*domain.Artist, error) {
+	return getArtist(ctx, s.db, id)
+}
+
+// Artists are filtered by name or alias and ordered by name.
+func (s *ArtistsStorage) List(ctx context.Context, search *domain.ArtistSearch) ([]*domain.Artist, error) {
+	artists := make([]*domain.Artist, 0)
+
+	query :=
+		`SELECT ` + artistColumns + ` FROM artists
+		WHERE $1 = '' OR name ILIKE '%' || $1 || '%'
+			OR EXISTS (SELECT 1 FROM unnest(aliases) alias WHERE alias ILIKE '%' || $1 || '%')
+		ORDER BY name
+		LIMIT $2 OFFSET $3;`
+
+	rows, err := s.db.QueryContext(ctx, query, search.ByName, search.Limit, search.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		artist, err := scanArtist(rows)
+		if err != nil {
+			return nil, err
+		}
+		artists = append(artists, artist)
+	}
+
+	return artists, rows.Err()
+}
+
+// Renaming the artist renames the group of all its songs.
+func (s *ArtistsStorage) Update(ctx context.Context, id uuid.UUID, update *domain.ArtistUpdate) (*domain.Artist, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	updateQuery, err := getUpdateQuery("artists", id, update.ToArtistSchema())
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := tx.ExecContext(ctx, updateQuery.query, updateQuery.args...)
+	if err != nil {
+		return nil, artistError(err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil || n == 0 {
+		slog.Debug("no rows affected", "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+
+	artist, err := getArtist(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return artist, tx.Commit()
+}
+
+// Artist can't be deleted while it has songs, albums or credits.
+func (s *ArtistsStorage) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := s.db.ExecContext(ctx, "DELETE FROM artists WHERE id = $1;", id)
+	if err != nil {
+		return artistError(err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil || n == 0 {
+		slog.Debug("no rows affected", "operation", logmsg.ExtractOperationID(ctx))
+		return domain.ErrUnknownResourse
+	}
+
+	return nil
+}
+
+func getArtist(ctx context.Context, q querier, id uuid.UUID) (*domain.Artist, error) {
+	row := q.QueryRowContext(ctx, "SELECT "+artistColumns+" FROM artists WHERE id = $1;", id)
+
+	artist, err := scanArtist(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		slog.Debug(err.Error(), "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return artist, nil
+}
+
+type scanner interface {
+	Scan(...any) error
+}
+
+// Columns must be selected in artistColumns order.
+func scanArtist(row scanner) (*domain.Artist, error) {
+	artist := &domain.Artist{}
+	var formed, disbanded sql.NullTime
+
+	err := row.Scan(
+		&artist.ID,
+		&artist.Name,
+		pq.Array(&artist.Aliases),
+		&artist.Country,
+		&formed,
+		&disbanded,
+		&artist.Bio,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if formed.Valid {
+		artist.Formed = &formed.Time
+	}
+	if disbanded.Valid {
+		artist.Disbanded = &disbanded.Time
+	}
+
+	return artist, nil
+}
+
+// Converts constraint violations to domain errors.
+func artistError(err error) error {
+	switch {
+	case hasCode(err, uniqueViolation):
+		return domain.ErrArtistExists
+	case hasCode(err, checkViolation):
+		return domain.ErrArtistDates
+	case hasCode(err, foreignKeyViolation):
+		return domain.ErrArtistHasSongs
+	default:
+		return err
+	}
+}
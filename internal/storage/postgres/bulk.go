@@ -0,0 +1,212 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/qreaqtor/music-library/internal/domain"
+)
+
+// Changes of bulk import which are written after all songs of the batch are imported.
+// before is nil for created songs, order is the order songs were touched in.
+// translations are kept translations of songs which lyrics are replaced.
+type bulkImport struct {
+	lyrics       map[uuid.UUID][]*domain.Verse
+	translations map[uuid.UUID][]*domain.Translation
+	before       map[uuid.UUID]*domain.SongSnapshot
+	order        []uuid.UUID
+}
+
+// Imports songs in a single transaction, results are in the order of songs.
+// Every song is imported under a savepoint, so a failed song doesn't affect others.
+// Verses of all songs are inserted by COPY at the end with kept translations, then revisions are written.
+// In dry run the transaction is rolled back.
+func (s *SongsStorage) Import(ctx context.Context, songs []*domain.BulkSong, options *domain.BulkOptions) ([]*domain.BulkResult, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	imp := &bulkImport{
+		lyrics:       make(map[uuid.UUID][]*domain.Verse),
+		translations: make(map[uuid.UUID][]*domain.Translation),
+		before:       make(map[uuid.UUID]*domain.SongSnapshot),
+		order:        make([]uuid.UUID, 0, len(songs)),
+	}
+
+	results := make([]*domain.BulkResult, 0, len(songs))
+
+	for _, song := range songs {
+		_, err = tx.ExecContext(ctx, "SAVEPOINT bulk_song;")
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := s.importSong(ctx, tx, song, options.Mode, imp)
+		if err != nil {
+			_, rollbackErr := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT bulk_song;")
+			if rollbackErr != nil {
+				return nil, rollbackErr
+			}
+
+			result = &domain.BulkResult{Status: domain.BulkError, Error: err.Error()}
+		}
+
+		// songs created in dry run have no real id
+		if before, touched := imp.before[result.ID]; options.DryRun && touched && before == nil {
+			result.ID = uuid.Nil
+		}
+
+		results = append(results, result)
+	}
+
+	err = copyVerses(ctx, tx, s.language, imp)
+	if err != nil {
+		return nil, err
+	}
+
+	for songID, translations := range imp.translations {
+		err = insertTranslations(ctx, tx, songID, translations)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, songID := range imp.order {
+		err = writeRevision(ctx, tx, songID, imp.before[songID])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if options.DryRun {
+		return results, nil
+	}
+
+	return results, tx.Commit()
+}
+
+// Creates the song or updates the existing one in upsert mode.
+// imp is changed only if the song is imported successfully.
+func (s *SongsStorage) importSong(ctx context.Context, tx *sql.Tx, song *domain.BulkSong, mode string, imp *bulkImport) (*domain.BulkResult, error) {
+	schema := song.ToSongSchema()
+	lyrics := song.ToLyricsSchema().Lyrics
+
+	artistID, err := getOrCreateArtist(ctx, tx, schema.Group)
+	if err != nil {
+		return nil, err
+	}
+
+	var songID uuid.UUID
+
+	err = tx.QueryRowContext(
+		ctx,
+		"SELECT id FROM songs WHERE artist_id = $1 AND name_key(song) = name_key($2) AND deleted_at IS NULL FOR UPDATE;",
+		artistID, schema.SongName,
+	).Scan(&songID)
+
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		query :=
+			`INSERT INTO songs (artist_id, song, releaseDate, link)
+			VALUES ($1, $2, COALESCE($3, current_date), NULLIF($4, ''))
+			RETURNING id;`
+
+		err = tx.QueryRowContext(ctx, query, artistID, schema.SongName, nullDate(schema.ReleaseDate), schema.Link).
+			Scan(&songID)
+		if err != nil {
+			return nil, songError(ctx, s.db, err, uuid.Nil, artistID, schema.SongName)
+		}
+
+		imp.lyrics[songID] = lyrics
+		imp.before[songID] = nil
+		imp.order = append(imp.order, songID)
+
+		return &domain.BulkResult{Status: domain.BulkCreated, ID: songID}, nil
+	case err != nil:
+		return nil, err
+	case mode == domain.BulkInsert:
+		return &domain.BulkResult{Status: domain.BulkSkipped, ID: songID}, nil
+	}
+
+	// song may be touched by previous item of the batch, then its state before import is known
+	before, touched := imp.before[songID]
+	if !touched {
+		before, err = getSnapshot(ctx, tx, songID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	query :=
+		`UPDATE songs SET link = COALESCE(NULLIF($2, ''), link), releaseDate = COALESCE($3, releaseDate), updated_at = now()
+		WHERE id = $1;`
+
+	_, err = tx.ExecContext(ctx, query, songID, schema.Link, nullDate(schema.ReleaseDate))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(lyrics) != 0 {
+		_, err = tx.ExecContext(ctx, "DELETE FROM verses WHERE song_id = $1;", songID)
+		if err != nil {
+			return nil, err
+		}
+		imp.lyrics[songID] = lyrics
+
+		// translations are deleted with verses only by the first replacement of lyrics,
+		// songs created by previous items of the batch have no translations
+		translations, replaced := imp.translations[songID]
+		if !replaced && before != nil {
+			translations = before.Translations
+		}
+		imp.translations[songID] = domain.KeepTranslations(translations, len(lyrics))
+	}
+
+	if !touched {
+		imp.before[songID] = before
+		imp.order = append(imp.order, songID)
+	}
+
+	return &domain.BulkResult{Status: domain.BulkUpdated, ID: songID}, nil
+}
+
+// Inserts verses of imported songs with a single COPY.
+func copyVerses(ctx context.Context, tx *sql.Tx, language string, imp *bulkImport) error {
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn("verses", "song_id", "lang", "position", "section", "language", "verse", "lines"))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, songID := range imp.order {
+		for _, verse := range imp.lyrics[songID] {
+			// unsynced verses and verses without language are stored as NULL
+			var lines, lang any
+			if len(verse.Lines) != 0 {
+				data, err := json.Marshal(verse.Lines)
+				if err != nil {
+					return err
+				}
+				lines = string(data)
+			}
+			if verse.Language != "" {
+				lang = verse.Language
+			}
+
+			_, err = stmt.ExecContext(ctx, songID, language, verse.Position, verse.Section, lang, verse.Text, lines)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// flushes buffered rows
+	_, err = stmt.ExecContext(ctx)
+	return err
+}
@@ -0,0 +1,240 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+)
+
+// PostgreSQL error codes, see https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	foreignKeyViolation = "23503"
+	uniqueViolation     = "23505"
+	checkViolation      = "23514"
+)
+
+// querier is implemented by both *sql.DB and *sql.Tx.
+type querier interface {
+	ExecContext(context.Context, string, ...any) (sql.Result, error)
+	QueryContext(context.Context, string, ...any) (*sql.Rows, error)
+	QueryRowContext(context.Context, string, ...any) *sql.Row
+}
+
+// Song is found by its id if it is set,
+// otherwise by its name and the name or alias of its artist. Songs in trash are not found.
+func findSongID(ctx context.Context, q querier, song *domain.Song) (uuid.UUID, error) {
+	var songID uuid.UUID
+
+	query :=
+		`SELECT s.id FROM songs s JOIN artists a ON a.id = s.artist_id
+		WHERE ` + artistCondition("a", 1) + ` AND name_key(s.song) = name_key($2) AND s.deleted_at IS NULL
+		LIMIT 1;`
+	args := []any{song.Group, song.SongName}
+
+	if song.ID != uuid.Nil {
+		query = "SELECT id FROM songs WHERE id = $1 AND deleted_at IS NULL;"
+		args = []any{song.ID}
+	}
+
+	err := q.QueryRowContext(ctx, query, args...).Scan(&songID)
+	if errors.Is(err, sql.ErrNoRows) {
+		slog.Debug(err.Error(), "operation", logmsg.ExtractOperationID(ctx))
+		return uuid.Nil, domain.ErrUnknownResourse
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return songID, nil
+}
+
+// Returns id of the artist with this name or alias, new artist is created if there is no such.
+// Names are unique case insensitively, so "muse" resolves to existing "Muse".
+func getOrCreateArtist(ctx context.Context, q querier, name string) (uuid.UUID, error) {
+	var artistID uuid.UUID
+
+	err := q.QueryRowContext(
+		ctx,
+		"SELECT a.id FROM artists a WHERE "+artistCondition("a", 1)+" LIMIT 1;",
+		name,
+	).Scan(&artistID)
+	if err == nil {
+		return artistID, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return uuid.Nil, err
+	}
+
+	// no-op update makes RETURNING work for the existing row
+	query :=
+		`INSERT INTO artists (name) VALUES ($1)
+		ON CONFLICT ((lower(name))) DO UPDATE SET name = artists.name
+		RETURNING id;`
+
+	err = q.QueryRowContext(ctx, query, name).Scan(&artistID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return artistID, nil
+}
+
+// Returns condition matching the artist by name or alias case insensitively, as names are unique.
+// alias is the alias of the artists table in the query, param is the number of the name parameter.
+func artistCondition(alias string, param int) string {
+	return fmt.Sprintf(
+		"(lower(%[1]s.name) = lower($%[2]d) OR EXISTS (SELECT 1 FROM unnest(%[1]s.aliases) alias WHERE lower(alias) = lower($%[2]d)))",
+		alias, param,
+	)
+}
+
+// Returns domain.SongExistsError if the artist has another song with the same name key.
+// Unique index would reject the song anyway, but without id of the existing one.
+func checkUnique(ctx context.Context, q querier, artistID uuid.UUID, name string) error {
+	var existingID uuid.UUID
+
+	err := q.QueryRowContext(
+		ctx,
+		"SELECT id FROM songs WHERE artist_id = $1 AND name_key(song) = name_key($2) AND deleted_at IS NULL LIMIT 1;",
+		artistID, name,
+	).Scan(&existingID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return &domain.SongExistsError{ID: existingID}
+}
+
+// Same as checkUnique for the existing song which gets the artist and the name,
+// zero artistID and empty name are replaced with the current ones.
+func checkUniqueRenamed(ctx context.Context, q querier, songID, artistID uuid.UUID, name string) error {
+	var existingID uuid.UUID
+
+	query :=
+		`SELECT d.id FROM songs s
+			JOIN songs d ON d.artist_id = COALESCE($2, s.artist_id) AND name_key(d.song) = name_key(COALESCE(NULLIF($3, ''), s.song))
+		WHERE s.id = $1 AND d.id <> s.id AND d.deleted_at IS NULL
+		LIMIT 1;`
+
+	err := q.QueryRowContext(ctx, query, songID, uuid.NullUUID{UUID: artistID, Valid: artistID != uuid.Nil}, name).
+		Scan(&existingID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return &domain.SongExistsError{ID: existingID}
+}
+
+// Concurrent insert of the same song is caught only by the unique index, which aborts the transaction,
+// so the existing song is looked up with q outside of it like checkUnique or, for non-zero songID, checkUniqueRenamed do.
+// Returns domain.ErrSongExists if the existing song is not found, for example it is deleted meanwhile.
+func songError(ctx context.Context, q querier, err error, songID, artistID uuid.UUID, name string) error {
+	if !hasCode(err, uniqueViolation) {
+		return err
+	}
+
+	if songID == uuid.Nil {
+		err = checkUnique(ctx, q, artistID, name)
+	} else {
+		err = checkUniqueRenamed(ctx, q, songID, artistID, name)
+	}
+
+	var existsErr *domain.SongExistsError
+	if errors.As(err, &existsErr) {
+		return existsErr
+	}
+
+	return domain.ErrSongExists
+}
+
+func hasCode(err error, code pq.ErrorCode) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == code
+}
+
+// Max number of group and song names returned as suggestions.
+const maxSuggestions = 3
+
+// Sets threshold of the word similarity operator <% until the end of the transaction.
+func setSimilarityThreshold(ctx context.Context, tx *sql.Tx, threshold float64) error {
+	_, err := tx.ExecContext(
+		ctx,
+		"SELECT set_config('pg_trgm.word_similarity_threshold', $1, true);",
+		strconv.FormatFloat(threshold, 'f', -1, 64),
+	)
+	return err
+}
+
+// Selects a single text column.
+func selectStrings(ctx context.Context, q querier, query string, args ...any) ([]string, error) {
+	result := make([]string, 0)
+
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var value string
+		err = rows.Scan(&value)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, value)
+	}
+
+	return result, rows.Err()
+}
+
+// Selects a single text column, query takes a value and a limit.
+func selectNames(ctx context.Context, q querier, query, value string, limit int) ([]string, error) {
+	return selectStrings(ctx, q, query, value, limit)
+}
+
+// Selects verses, query must return position, section, language, verse and lines columns.
+func selectVerses(ctx context.Context, q querier, query string, args ...any) ([]*domain.Verse, error) {
+	verses := make([]*domain.Verse, 0)
+
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		verse := &domain.Verse{}
+		var lines []byte
+
+		err = rows.Scan(&verse.Position, &verse.Section, &verse.Language, &verse.Text, &lines)
+		if err != nil {
+			return nil, err
+		}
+
+		if lines != nil {
+			err = json.Unmarshal(lines, &verse.Lines)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		verses = append(verses, verse)
+	}
+
+	return verses, rows.Err()
+}
@@ -0,0 +1,76 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+)
+
+// Credited artists are found by name or alias, new artists are created.
+// Duplicate credits are ignored, the first one keeps its position.
+func (s *SongsStorage) SetCredits(ctx context.Context, song *domain.Song, credits []domain.Credit) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	songID, err := findSongID(ctx, tx, song)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM song_credits WHERE song_id = $1;", songID)
+	if err != nil {
+		return err
+	}
+
+	for i, credit := range credits {
+		artistID, err := getOrCreateArtist(ctx, tx, credit.Artist)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			`INSERT INTO song_credits (song_id, artist_id, role, position) VALUES ($1, $2, $3, $4)
+			ON CONFLICT DO NOTHING;`,
+			songID, artistID, credit.Role, i,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Returns credits of the song in display order.
+func getSongCredits(ctx context.Context, q querier, songID uuid.UUID) ([]domain.Credit, error) {
+	credits := make([]domain.Credit, 0)
+
+	rows, err := q.QueryContext(
+		ctx,
+		`SELECT a.name, c.role FROM song_credits c JOIN artists a ON a.id = c.artist_id
+		WHERE c.song_id = $1 ORDER BY c.position;`,
+		songID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var credit domain.Credit
+
+		err = rows.Scan(&credit.Artist, &credit.Role)
+		if err != nil {
+			return nil, err
+		}
+
+		credits = append(credits, credit)
+	}
+
+	return credits, rows.Err()
+}
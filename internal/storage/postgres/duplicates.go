@@ -0,0 +1,164 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/qreaqtor/music-library/internal/domain"
+)
+
+// Same as name_key function of the database, it is inlined because duplicates
+// are looked for before the migration which creates the function.
+func nameKey(column string) string {
+	return fmt.Sprintf(`lower(regexp_replace(btrim(%s), '\s+', ' ', 'g'))`, column)
+}
+
+// Returns songs of the same artist with the same name key, songs in trash are skipped.
+// The oldest song of the group is kept, others are ordered by creation time.
+func (s *SongsStorage) Duplicates(ctx context.Context) ([]*domain.Duplicates, error) {
+	query := fmt.Sprintf(
+		`SELECT s.id, a.name, s.song, ROW_NUMBER() OVER w
+		FROM songs s JOIN artists a ON a.id = s.artist_id
+		WHERE s.deleted_at IS NULL AND (s.artist_id, %[1]s) IN (
+			SELECT artist_id, %[2]s FROM songs
+			WHERE deleted_at IS NULL
+			GROUP BY 1, 2 HAVING COUNT(*) > 1
+		)
+		WINDOW w AS (PARTITION BY s.artist_id, %[1]s ORDER BY s.created_at, s.id)
+		ORDER BY a.name, %[1]s, 4;`,
+		nameKey("s.song"), nameKey("song"),
+	)
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	duplicates := make([]*domain.Duplicates, 0)
+
+	for rows.Next() {
+		var (
+			song domain.Song
+			n    int
+		)
+
+		err = rows.Scan(&song.ID, &song.Group, &song.SongName, &n)
+		if err != nil {
+			return nil, err
+		}
+
+		if n == 1 {
+			duplicates = append(duplicates, &domain.Duplicates{Keep: song, Merge: make([]domain.Song, 0)})
+			continue
+		}
+
+		last := duplicates[len(duplicates)-1]
+		last.Merge = append(last.Merge, song)
+	}
+
+	return duplicates, rows.Err()
+}
+
+// Merges songs into the kept one and deletes them permanently. Missing link is taken from
+// the first merged song which has it, release date is the earliest one. Lyrics of the first merged song
+// with lyrics are copied if the kept song has none. Album tracks are moved to the kept song.
+// Merge is written as a revision of the kept song.
+func (s *SongsStorage) MergeDuplicates(ctx context.Context, duplicates *domain.Duplicates) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	keepID := duplicates.Keep.ID
+
+	ids := []string{keepID.String()}
+	for _, song := range duplicates.Merge {
+		ids = append(ids, song.ID.String())
+	}
+
+	var found int
+	err = tx.QueryRowContext(
+		ctx,
+		"SELECT COUNT(*) FROM (SELECT 1 FROM songs WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL FOR UPDATE) s;",
+		pq.Array(ids),
+	).Scan(&found)
+	if err != nil {
+		return err
+	}
+	if found != len(ids) {
+		return domain.ErrUnknownResourse
+	}
+
+	before, err := getSnapshot(ctx, tx, keepID)
+	if err != nil {
+		return err
+	}
+
+	for _, song := range duplicates.Merge {
+		err = mergeSong(ctx, tx, keepID, song.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE songs SET updated_at = now() WHERE id = $1;", keepID)
+	if err != nil {
+		return err
+	}
+
+	err = writeRevision(ctx, tx, keepID, before)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func mergeSong(ctx context.Context, tx *sql.Tx, keepID, songID uuid.UUID) error {
+	var hasLyrics bool
+
+	err := tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM verses WHERE song_id = $1);", keepID).Scan(&hasLyrics)
+	if err != nil {
+		return err
+	}
+
+	queries := []string{
+		`UPDATE songs k SET link = d.link FROM songs d
+		WHERE k.id = $1 AND d.id = $2 AND k.link IS NULL;`,
+
+		`UPDATE songs k SET releaseDate = d.releaseDate FROM songs d
+		WHERE k.id = $1 AND d.id = $2 AND (k.releaseDate IS NULL OR d.releaseDate < k.releaseDate);`,
+	}
+
+	// translations are aligned with verses, so they are copied only together
+	if !hasLyrics {
+		queries = append(
+			queries,
+			`INSERT INTO verses (song_id, lang, position, section, language, verse, lines)
+			SELECT $1, lang, position, section, language, verse, lines FROM verses WHERE song_id = $2;`,
+
+			`INSERT INTO verse_translations (song_id, position, language, verse)
+			SELECT $1, position, language, verse FROM verse_translations WHERE song_id = $2;`,
+		)
+	}
+
+	queries = append(
+		queries,
+		`UPDATE album_tracks SET song_id = $1 WHERE song_id = $2;`,
+		`DELETE FROM songs WHERE id = $2;`,
+	)
+
+	for _, query := range queries {
+		_, err = tx.ExecContext(ctx, query, keepID, songID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/qreaqtor/music-library/internal/domain"
+)
+
+// Number of songs fetched from the export cursor at once.
+const exportFetchSize = 200
+
+// Passes found songs to fn in search order, pagination is ignored.
+// Songs are read from a server-side cursor exportFetchSize at a time and verses are selected
+// for each fetched chunk, so memory doesn't grow with the catalogue. The whole export
+// reads a single snapshot of the database.
+func (s *SongsStorage) Export(ctx context.Context, search *domain.SongSearch, fn func(*domain.ExportedSong) error) error {
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if search.Fuzzy {
+		err = setSimilarityThreshold(ctx, tx, search.Threshold)
+		if err != nil {
+			return err
+		}
+	}
+
+	exportQuery := getExportQuery(search, s.language)
+
+	_, err = tx.ExecContext(ctx, "DECLARE export_songs NO SCROLL CURSOR FOR "+exportQuery.query+";", exportQuery.args...)
+	if err != nil {
+		return err
+	}
+
+	for {
+		songs, err := fetchExported(ctx, tx)
+		if err != nil {
+			return err
+		}
+		if len(songs) == 0 {
+			break
+		}
+
+		err = selectExportedVerses(ctx, tx, songs)
+		if err != nil {
+			return err
+		}
+
+		for _, song := range songs {
+			err = fn(song)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Fetches the next chunk of songs from export_songs cursor, lyrics are not set.
+func fetchExported(ctx context.Context, tx *sql.Tx) ([]*domain.ExportedSong, error) {
+	songs := make([]*domain.ExportedSong, 0, exportFetchSize)
+
+	// FETCH doesn't take parameters
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf("FETCH %d FROM export_songs;", exportFetchSize))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		song := &domain.ExportedSong{
+			Lyrics: make([]*domain.Verse, 0),
+		}
+
+		err = rows.Scan(
+			&song.ID,
+			&song.Group,
+			&song.SongName,
+			&song.ReleaseDate,
+			&song.Link,
+			&song.CreatedAt,
+			&song.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		songs = append(songs, song)
+	}
+
+	return songs, rows.Err()
+}
+
+// Sets lyrics of the songs with a single query.
+func selectExportedVerses(ctx context.Context, tx *sql.Tx, songs []*domain.ExportedSong) error {
+	byID := make(map[uuid.UUID]*domain.ExportedSong, len(songs))
+	ids := make([]string, 0, len(songs))
+	for _, song := range songs {
+		byID[song.ID] = song
+		ids = append(ids, song.ID.String())
+	}
+
+	rows, err := tx.QueryContext(
+		ctx,
+		`SELECT song_id, position, section, COALESCE(language, ''), verse, lines
+		FROM verses
+		WHERE song_id = ANY($1::uuid[])
+		ORDER BY song_id, position;`,
+		pq.Array(ids),
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var songID uuid.UUID
+		var lines []byte
+		verse := &domain.Verse{}
+
+		err = rows.Scan(&songID, &verse.Position, &verse.Section, &verse.Language, &verse.Text, &lines)
+		if err != nil {
+			return err
+		}
+
+		if lines != nil {
+			err = json.Unmarshal(lines, &verse.Lines)
+			if err != nil {
+				return err
+			}
+		}
+
+		song := byID[songID]
+		song.Lyrics = append(song.Lyrics, verse)
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,327 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+)
+
+// Adding favorite song again is not an error, the time it was added is kept.
+func (s *SongsStorage) AddFavorite(ctx context.Context, song *domain.Song, userID uuid.UUID) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	songID, err := findSongID(ctx, tx, song)
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(
+		ctx,
+		"INSERT INTO favorites (user_id, song_id) VALUES ($1, $2) ON CONFLICT DO NOTHING;",
+		userID, songID,
+	)
+	if err != nil {
+		return err
+	}
+
+	err = addCounter(ctx, tx, res, songID, "favorite_count = favorite_count + 1")
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Removing song which is not favorite is not an error.
+func (s *SongsStorage) RemoveFavorite(ctx context.Context, song *domain.Song, userID uuid.UUID) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	songID, err := findSongID(ctx, tx, song)
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(
+		ctx,
+		"DELETE FROM favorites WHERE user_id = $1 AND song_id = $2;",
+		userID, songID,
+	)
+	if err != nil {
+		return err
+	}
+
+	err = addCounter(ctx, tx, res, songID, "favorite_count = favorite_count - 1")
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Favorite songs of the user, recently added first. Songs in trash are skipped.
+func (s *SongsStorage) Favorites(ctx context.Context, userID uuid.UUID, batch *domain.Batch) ([]*domain.FavoriteSong, error) {
+	favorites := make([]*domain.FavoriteSong, 0)
+
+	query :=
+		`SELECT s.id, a.name, s.song, f.created_at
+		FROM favorites f JOIN songs s ON s.id = f.song_id JOIN artists a ON a.id = s.artist_id
+		WHERE f.user_id = $1 AND s.deleted_at IS NULL
+		ORDER BY f.created_at DESC, s.id
+		LIMIT $2 OFFSET $3;`
+
+	rows, err := s.db.QueryContext(ctx, query, userID, batch.Limit, batch.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		favorite := &domain.FavoriteSong{}
+		err = rows.Scan(&favorite.ID, &favorite.Group, &favorite.SongName, &favorite.AddedAt)
+		if err != nil {
+			return nil, err
+		}
+		favorites = append(favorites, favorite)
+	}
+
+	return favorites, rows.Err()
+}
+
+// Replaces the previous rating of the user.
+func (s *SongsStorage) Rate(ctx context.Context, song *domain.Song, userID uuid.UUID, rating int) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	songID, err := findSongID(ctx, tx, song)
+	if err != nil {
+		return err
+	}
+
+	previous := 0
+	err = tx.QueryRowContext(
+		ctx,
+		"SELECT rating FROM ratings WHERE user_id = $1 AND song_id = $2 FOR UPDATE;",
+		userID, songID,
+	).Scan(&previous)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		`INSERT INTO ratings (user_id, song_id, rating) VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, song_id) DO UPDATE SET rating = EXCLUDED.rating, rated_at = now();`,
+		userID, songID, rating,
+	)
+	if err != nil {
+		return err
+	}
+
+	// previous rating is replaced, so the number of ratings changes only for the first one
+	rated := 0
+	if previous == 0 {
+		rated = 1
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		"UPDATE songs SET rating_count = rating_count + $2, rating_sum = rating_sum + $3 WHERE id = $1;",
+		songID, rated, rating-previous,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Removing rating of song which is not rated is not an error.
+func (s *SongsStorage) Unrate(ctx context.Context, song *domain.Song, userID uuid.UUID) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	songID, err := findSongID(ctx, tx, song)
+	if err != nil {
+		return err
+	}
+
+	var rating int
+	err = tx.QueryRowContext(
+		ctx,
+		"DELETE FROM ratings WHERE user_id = $1 AND song_id = $2 RETURNING rating;",
+		userID, songID,
+	).Scan(&rating)
+	if errors.Is(err, sql.ErrNoRows) {
+		return tx.Commit()
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		"UPDATE songs SET rating_count = rating_count - 1, rating_sum = rating_sum - $2 WHERE id = $1;",
+		songID, rating,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *SongsStorage) AddPlay(ctx context.Context, song *domain.Song, userID uuid.UUID, playedAt time.Time) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	songID, err := findSongID(ctx, tx, song)
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(
+		ctx,
+		"INSERT INTO plays (user_id, song_id, played_at) VALUES ($1, $2, $3);",
+		userID, songID, playedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	err = addCounter(ctx, tx, res, songID, "play_count = play_count + 1")
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Listening history of the user, recent plays first. Plays of songs in trash are skipped.
+func (s *SongsStorage) Plays(ctx context.Context, userID uuid.UUID, batch *domain.Batch) ([]*domain.Play, error) {
+	plays := make([]*domain.Play, 0)
+
+	query :=
+		`SELECT s.id, a.name, s.song, p.played_at
+		FROM plays p JOIN songs s ON s.id = p.song_id JOIN artists a ON a.id = s.artist_id
+		WHERE p.user_id = $1 AND s.deleted_at IS NULL
+		ORDER BY p.played_at DESC, s.id
+		LIMIT $2 OFFSET $3;`
+
+	rows, err := s.db.QueryContext(ctx, query, userID, batch.Limit, batch.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		play := &domain.Play{}
+		err = rows.Scan(&play.ID, &play.Group, &play.SongName, &play.PlayedAt)
+		if err != nil {
+			return nil, err
+		}
+		plays = append(plays, play)
+	}
+
+	return plays, rows.Err()
+}
+
+// Songs with most plays since the time, zero time counts all plays. Songs without plays are not listed,
+// songs with the same number of plays are ordered by id. Songs in trash are skipped.
+func (s *SongsStorage) TopSongs(ctx context.Context, since time.Time, batch *domain.Batch) ([]*domain.TopSong, error) {
+	top := make([]*domain.TopSong, 0)
+
+	// all time top is read from the counters
+	query :=
+		`SELECT s.id, a.name, s.song, s.play_count
+		FROM songs s JOIN artists a ON a.id = s.artist_id
+		WHERE s.play_count > 0 AND s.deleted_at IS NULL
+		ORDER BY s.play_count DESC, s.id
+		LIMIT $1 OFFSET $2;`
+	args := []any{batch.Limit, batch.Offset}
+
+	if !since.IsZero() {
+		query =
+			`SELECT s.id, a.name, s.song, p.plays
+			FROM (
+				SELECT song_id, COUNT(*) AS plays FROM plays WHERE played_at >= $1 GROUP BY song_id
+			) p JOIN songs s ON s.id = p.song_id JOIN artists a ON a.id = s.artist_id
+			WHERE s.deleted_at IS NULL
+			ORDER BY p.plays DESC, s.id
+			LIMIT $2 OFFSET $3;`
+		args = []any{since, batch.Limit, batch.Offset}
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		song := &domain.TopSong{}
+		err = rows.Scan(&song.ID, &song.Group, &song.SongName, &song.Plays)
+		if err != nil {
+			return nil, err
+		}
+		top = append(top, song)
+	}
+
+	return top, rows.Err()
+}
+
+// Feedback of the user on the song with the id, the song may be in trash.
+func (s *SongsStorage) Feedback(ctx context.Context, songID, userID uuid.UUID) (*domain.SongFeedback, error) {
+	feedback := &domain.SongFeedback{}
+
+	query :=
+		`SELECT EXISTS (SELECT 1 FROM favorites WHERE user_id = $2 AND song_id = s.id),
+			COALESCE((SELECT rating FROM ratings WHERE user_id = $2 AND song_id = s.id), 0)
+		FROM songs s
+		WHERE s.id = $1;`
+
+	err := s.db.QueryRowContext(ctx, query, songID, userID).Scan(&feedback.Favorite, &feedback.Rating)
+	if errors.Is(err, sql.ErrNoRows) {
+		slog.Debug(err.Error(), "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return feedback, nil
+}
+
+// Applies the counter update to the song if the result has affected rows,
+// so repeated favorites and removals of missing ones keep counters in sync.
+func addCounter(ctx context.Context, q querier, res sql.Result, songID uuid.UUID, update string) error {
+	n, err := res.RowsAffected()
+	if err != nil || n == 0 {
+		return err
+	}
+
+	_, err = q.ExecContext(ctx, "UPDATE songs SET "+update+" WHERE id = $1;", songID)
+	return err
+}
@@ -0,0 +1,335 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+)
+
+// Max number of genres and tags in facets.
+const maxFacets = 20
+
+// Returns domain.ErrUnknownGenre if the parent doesn't exist.
+func (s *SongsStorage) CreateGenre(ctx context.Context, genre *domain.Genre) (*domain.Genre, error) {
+	created := &domain.Genre{}
+
+	err := s.db.QueryRowContext(
+		ctx,
+		"INSERT INTO genres (name, parent_id) VALUES ($1, $2) RETURNING id, name, parent_id;",
+		genre.Name, genre.ParentID,
+	).Scan(&created.ID, &created.Name, &created.ParentID)
+	if err != nil {
+		return nil, genreError(err)
+	}
+
+	return created, nil
+}
+
+// Genres are ordered by name.
+func (s *SongsStorage) Genres(ctx context.Context) ([]*domain.Genre, error) {
+	genres := make([]*domain.Genre, 0)
+
+	rows, err := s.db.QueryContext(ctx, "SELECT id, name, parent_id FROM genres ORDER BY name;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		genre := &domain.Genre{}
+		err = rows.Scan(&genre.ID, &genre.Name, &genre.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		genres = append(genres, genre)
+	}
+
+	return genres, rows.Err()
+}
+
+// Replaces name and parent of the genre with the id.
+// Returns domain.ErrGenreCycle if the new parent is the genre itself or its descendant.
+func (s *SongsStorage) UpdateGenre(ctx context.Context, genre *domain.Genre) (*domain.Genre, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// taxonomy is locked, so concurrent moves can't make a cycle
+	_, err = tx.ExecContext(ctx, "LOCK TABLE genres IN SHARE ROW EXCLUSIVE MODE;")
+	if err != nil {
+		return nil, err
+	}
+
+	if genre.ParentID != nil {
+		var cycle bool
+
+		query :=
+			`WITH RECURSIVE descendants AS (
+				SELECT id FROM genres WHERE id = $1
+				UNION ALL
+				SELECT g.id FROM genres g JOIN descendants d ON g.parent_id = d.id
+			)
+			SELECT EXISTS (SELECT 1 FROM descendants WHERE id = $2);`
+
+		err = tx.QueryRowContext(ctx, query, genre.ID, genre.ParentID).Scan(&cycle)
+		if err != nil {
+			return nil, err
+		}
+		if cycle {
+			return nil, domain.ErrGenreCycle
+		}
+	}
+
+	updated := &domain.Genre{}
+
+	err = tx.QueryRowContext(
+		ctx,
+		"UPDATE genres SET name = $2, parent_id = $3 WHERE id = $1 RETURNING id, name, parent_id;",
+		genre.ID, genre.Name, genre.ParentID,
+	).Scan(&updated.ID, &updated.Name, &updated.ParentID)
+	if errors.Is(err, sql.ErrNoRows) {
+		slog.Debug(err.Error(), "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+	if err != nil {
+		return nil, genreError(err)
+	}
+
+	return updated, tx.Commit()
+}
+
+// Songs lose the deleted genre. Returns domain.ErrGenreHasChildren if the genre has child genres.
+func (s *SongsStorage) DeleteGenre(ctx context.Context, id uuid.UUID) error {
+	res, err := s.db.ExecContext(ctx, "DELETE FROM genres WHERE id = $1;", id)
+	if hasCode(err, foreignKeyViolation) {
+		return domain.ErrGenreHasChildren
+	}
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil || n == 0 {
+		slog.Debug("no rows affected", "operation", logmsg.ExtractOperationID(ctx))
+		return domain.ErrUnknownResourse
+	}
+
+	return nil
+}
+
+// Genres are found by name case insensitively, returns domain.ErrUnknownGenre if some of them don't exist.
+func (s *SongsStorage) SetGenres(ctx context.Context, song *domain.Song, names []string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	songID, err := findSongID(ctx, tx, song)
+	if err != nil {
+		return err
+	}
+
+	lower := make([]string, 0, len(names))
+	for _, name := range names {
+		lower = append(lower, strings.ToLower(name))
+	}
+
+	found, err := selectStrings(
+		ctx, tx,
+		"SELECT lower(name) FROM genres WHERE lower(name) = ANY($1) FOR SHARE;",
+		pq.Array(lower),
+	)
+	if err != nil {
+		return err
+	}
+
+	for i, name := range lower {
+		if !slices.Contains(found, name) {
+			return fmt.Errorf("%w: %s", domain.ErrUnknownGenre, names[i])
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM song_genres WHERE song_id = $1;", songID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		"INSERT INTO song_genres (song_id, genre_id) SELECT $1, id FROM genres WHERE lower(name) = ANY($2);",
+		songID, pq.Array(lower),
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Tags must be normalized by domain.NormalizeTags.
+func (s *SongsStorage) SetTags(ctx context.Context, song *domain.Song, tags []string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	songID, err := findSongID(ctx, tx, song)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM song_tags WHERE song_id = $1;", songID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		"INSERT INTO song_tags (song_id, tag) SELECT $1, UNNEST($2::text[]);",
+		songID, pq.Array(tags),
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Counts all found songs per genre, tag and decade, pagination is ignored.
+// Songs of a genre are counted with songs of its descendants.
+func (s *SongsStorage) Facets(ctx context.Context, search *domain.SongSearch) (*domain.Facets, error) {
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if search.Fuzzy {
+		err = setSimilarityThreshold(ctx, tx, search.Threshold)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	searchQuery := getSearchQuery(search, s.language)
+	facets := &domain.Facets{}
+
+	// ancestors of genres of found songs, UNION removes songs counted twice for the same genre
+	facets.Genres, err = selectFacets(
+		ctx, tx,
+		fmt.Sprintf(
+			`WITH RECURSIVE found AS (%s),
+			song_genre AS (
+				SELECT sg.song_id, sg.genre_id FROM song_genres sg JOIN found f ON f.id = sg.song_id
+				UNION
+				SELECT sg.song_id, g.parent_id FROM song_genre sg JOIN genres g ON g.id = sg.genre_id
+				WHERE g.parent_id IS NOT NULL
+			)
+			SELECT g.name, COUNT(*) AS count
+			FROM song_genre sg JOIN genres g ON g.id = sg.genre_id
+			GROUP BY g.name
+			ORDER BY count DESC, g.name
+			LIMIT %d;`,
+			searchQuery.query, maxFacets,
+		),
+		searchQuery.args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	facets.Tags, err = selectFacets(
+		ctx, tx,
+		fmt.Sprintf(
+			`SELECT t.tag, COUNT(*) AS count
+			FROM (%s) found JOIN song_tags t ON t.song_id = found.id
+			GROUP BY t.tag
+			ORDER BY count DESC, t.tag
+			LIMIT %d;`,
+			searchQuery.query, maxFacets,
+		),
+		searchQuery.args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	facets.Decades, err = selectFacets(
+		ctx, tx,
+		fmt.Sprintf(
+			`SELECT (d.decade || 's'), COUNT(*)
+			FROM (
+				SELECT EXTRACT(YEAR FROM release_date)::int / 10 * 10 AS decade FROM (%s) found
+			) d
+			GROUP BY d.decade
+			ORDER BY d.decade;`,
+			searchQuery.query,
+		),
+		searchQuery.args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return facets, tx.Commit()
+}
+
+// Returns names of genres of the song ordered by name.
+func getSongGenres(ctx context.Context, q querier, songID uuid.UUID) ([]string, error) {
+	return selectStrings(
+		ctx, q,
+		"SELECT g.name FROM song_genres sg JOIN genres g ON g.id = sg.genre_id WHERE sg.song_id = $1 ORDER BY g.name;",
+		songID,
+	)
+}
+
+func getSongTags(ctx context.Context, q querier, songID uuid.UUID) ([]string, error) {
+	return selectStrings(ctx, q, "SELECT tag FROM song_tags WHERE song_id = $1 ORDER BY tag;", songID)
+}
+
+// Selects value and count columns.
+func selectFacets(ctx context.Context, q querier, query string, args ...any) ([]domain.Facet, error) {
+	facets := make([]domain.Facet, 0)
+
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		facet := domain.Facet{}
+		err = rows.Scan(&facet.Value, &facet.Count)
+		if err != nil {
+			return nil, err
+		}
+		facets = append(facets, facet)
+	}
+
+	return facets, rows.Err()
+}
+
+// Converts violations of the unique index and of the parent foreign key.
+func genreError(err error) error {
+	switch {
+	case hasCode(err, uniqueViolation):
+		return domain.ErrGenreExists
+	case hasCode(err, foreignKeyViolation):
+		return domain.ErrUnknownGenre
+	default:
+		return err
+	}
+}
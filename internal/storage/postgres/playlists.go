@@ -0,0 +1,541 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+)
+
+// Songs in trash are not counted.
+const playlistColumns = `p.id, p.name, COALESCE(p.description, ''), p.created_at, p.updated_at,
+	(SELECT COUNT(*) FROM playlist_items i JOIN songs s ON s.id = i.song_id
+		WHERE i.playlist_id = p.id AND s.deleted_at IS NULL)`
+
+// Identifier of imported entry which refers to the song by its id.
+const songURNPrefix = "urn:uuid:"
+
+type PlaylistsStorage struct {
+	db *sql.DB
+}
+
+func NewPlaylistsStorage(connection *sql.DB) *PlaylistsStorage {
+	return &PlaylistsStorage{
+		db: connection,
+	}
+}
+
+func (s *PlaylistsStorage) Create(ctx context.Context, playlist *domain.Playlist) (*domain.Playlist, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	playlistID, err := insertPlaylist(ctx, tx, playlist)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := getPlaylist(ctx, tx, playlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	return created, tx.Commit()
+}
+
+func (s *PlaylistsStorage) Get(ctx context.Context, id uuid.UUID) (*domain.Playlist, error) {
+	return getPlaylist(ctx, s.db, id)
+}
+
+// Playlists are filtered by name and ordered by the last change, recently changed go first.
+func (s *PlaylistsStorage) List(ctx context.Context, search *domain.PlaylistSearch) ([]*domain.Playlist, error) {
+	playlists := make([]*domain.Playlist, 0)
+
+	query :=
+		`SELECT ` + playlistColumns + `
+		FROM playlists p
+		WHERE $1 = '' OR p.name ILIKE '%' || $1 || '%'
+		ORDER BY p.updated_at DESC, p.id
+		LIMIT $2 OFFSET $3;`
+
+	rows, err := s.db.QueryContext(ctx, query, search.ByName, search.Limit, search.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		playlist, err := scanPlaylist(rows)
+		if err != nil {
+			return nil, err
+		}
+		playlists = append(playlists, playlist)
+	}
+
+	return playlists, rows.Err()
+}
+
+func (s *PlaylistsStorage) Update(ctx context.Context, id uuid.UUID, update *domain.PlaylistUpdate) (*domain.Playlist, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	updateQuery, err := getUpdateQuery("playlists", id, update.ToPlaylistSchema())
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := tx.ExecContext(ctx, updateQuery.query, updateQuery.args...)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil || n == 0 {
+		slog.Debug("no rows affected", "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+
+	err = touchPlaylist(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	playlist, err := getPlaylist(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return playlist, tx.Commit()
+}
+
+// Items of the playlist are deleted with it, songs are kept.
+func (s *PlaylistsStorage) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := s.db.ExecContext(ctx, "DELETE FROM playlists WHERE id = $1;", id)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil || n == 0 {
+		slog.Debug("no rows affected", "operation", logmsg.ExtractOperationID(ctx))
+		return domain.ErrUnknownResourse
+	}
+
+	return nil
+}
+
+// Items are ordered by position.
+func (s *PlaylistsStorage) GetItems(ctx context.Context, id uuid.UUID) ([]*domain.PlaylistItem, error) {
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	_, err = getPlaylist(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := getPlaylistItems(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return items, tx.Commit()
+}
+
+// Inserts the song at the position, items from it are shifted down.
+// The song is appended if the position is 0 or after the last item.
+func (s *PlaylistsStorage) InsertItem(ctx context.Context, id uuid.UUID, item *domain.PlaylistItemInsert) ([]*domain.PlaylistItem, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	count, err := lockPlaylist(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	song := item.ToSong()
+
+	songID, err := findSongID(ctx, tx, song)
+	if errors.Is(err, domain.ErrUnknownResourse) && song.ID == uuid.Nil {
+		return nil, fmt.Errorf("%w: %s - %s", err, song.Group, song.SongName)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	position := item.Position
+	if position == 0 || position > count {
+		position = count + 1
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		"UPDATE playlist_items SET position = position + 1 WHERE playlist_id = $1 AND position >= $2;",
+		id,
+		position,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		"INSERT INTO playlist_items (playlist_id, position, song_id) VALUES ($1, $2, $3);",
+		id,
+		position,
+		songID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return commitPlaylistItems(ctx, tx, id)
+}
+
+// Moves the item to the position, items between them are shifted. The item is moved to the end if to is too big.
+func (s *PlaylistsStorage) MoveItem(ctx context.Context, id uuid.UUID, from, to int) ([]*domain.PlaylistItem, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	count, err := lockPlaylist(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if from < 1 || from > count {
+		slog.Debug("no item at position", "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+
+	to = min(to, count)
+
+	query :=
+		`UPDATE playlist_items
+		SET position = CASE
+			WHEN position = $2 THEN $3
+			WHEN $2 < $3 THEN position - 1
+			ELSE position + 1
+		END
+		WHERE playlist_id = $1 AND position BETWEEN LEAST($2::integer, $3::integer) AND GREATEST($2::integer, $3::integer);`
+
+	_, err = tx.ExecContext(ctx, query, id, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return commitPlaylistItems(ctx, tx, id)
+}
+
+// Removes the item at the position, items after it are shifted up.
+func (s *PlaylistsStorage) RemoveItem(ctx context.Context, id uuid.UUID, position int) ([]*domain.PlaylistItem, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	_, err = lockPlaylist(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := tx.ExecContext(ctx, "DELETE FROM playlist_items WHERE playlist_id = $1 AND position = $2;", id, position)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil || n == 0 {
+		slog.Debug("no rows affected", "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		"UPDATE playlist_items SET position = position - 1 WHERE playlist_id = $1 AND position > $2;",
+		id,
+		position,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return commitPlaylistItems(ctx, tx, id)
+}
+
+// Copies the playlist with its items. Copy is named "<name> (copy)" if name is empty.
+func (s *PlaylistsStorage) Duplicate(ctx context.Context, id uuid.UUID, name string) (*domain.Playlist, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var copyID uuid.UUID
+
+	query :=
+		`INSERT INTO playlists (name, description)
+		SELECT COALESCE(NULLIF($2, ''), LEFT(name || ' (copy)', 200)), description
+		FROM playlists WHERE id = $1
+		RETURNING id;`
+
+	err = tx.QueryRowContext(ctx, query, id, name).Scan(&copyID)
+	if errors.Is(err, sql.ErrNoRows) {
+		slog.Debug(err.Error(), "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		`INSERT INTO playlist_items (playlist_id, position, song_id)
+		SELECT $2, position, song_id FROM playlist_items WHERE playlist_id = $1;`,
+		id,
+		copyID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	playlist, err := getPlaylist(ctx, tx, copyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return playlist, tx.Commit()
+}
+
+// Creates the playlist with songs of the entries in their order.
+// Entries without songs in the library are returned as unmatched.
+func (s *PlaylistsStorage) Import(ctx context.Context, playlist *domain.Playlist, entries []*domain.PlaylistEntry) (*domain.PlaylistImport, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	playlistID, err := insertPlaylist(ctx, tx, playlist)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.PlaylistImport{
+		Unmatched: make([]*domain.PlaylistEntry, 0),
+	}
+
+	values := make([]string, 0, len(entries))
+	args := make([]any, 0, len(entries)+1)
+	args = append(args, playlistID)
+
+	for _, entry := range entries {
+		songID, err := findEntrySongID(ctx, tx, entry)
+		if errors.Is(err, domain.ErrUnknownResourse) {
+			result.Unmatched = append(result.Unmatched, entry)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, fmt.Sprintf("($1, %d, $%d)", len(values)+1, len(args)+1))
+		args = append(args, songID)
+	}
+
+	if len(values) != 0 {
+		query := fmt.Sprintf(
+			"INSERT INTO playlist_items (playlist_id, position, song_id) VALUES %s;",
+			strings.Join(values, ","),
+		)
+
+		_, err = tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result.Playlist, err = getPlaylist(ctx, tx, playlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, tx.Commit()
+}
+
+func insertPlaylist(ctx context.Context, q querier, playlist *domain.Playlist) (uuid.UUID, error) {
+	var playlistID uuid.UUID
+
+	query :=
+		`INSERT INTO playlists (name, description)
+		VALUES ($1, NULLIF($2, ''))
+		RETURNING id;`
+
+	err := q.QueryRowContext(ctx, query, playlist.Name, playlist.Description).Scan(&playlistID)
+
+	return playlistID, err
+}
+
+func getPlaylist(ctx context.Context, q querier, id uuid.UUID) (*domain.Playlist, error) {
+	query :=
+		`SELECT ` + playlistColumns + `
+		FROM playlists p
+		WHERE p.id = $1;`
+
+	playlist, err := scanPlaylist(q.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		slog.Debug(err.Error(), "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return playlist, nil
+}
+
+// Locks the playlist until the end of transaction, so concurrent changes of items don't mix positions.
+// Returns number of items including songs in trash, they are numbered from 1 without gaps.
+func lockPlaylist(ctx context.Context, q querier, id uuid.UUID) (int, error) {
+	err := q.QueryRowContext(ctx, "SELECT id FROM playlists WHERE id = $1 FOR UPDATE;", id).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		slog.Debug(err.Error(), "operation", logmsg.ExtractOperationID(ctx))
+		return 0, domain.ErrUnknownResourse
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+
+	err = q.QueryRowContext(ctx, "SELECT COUNT(*) FROM playlist_items WHERE playlist_id = $1;", id).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func touchPlaylist(ctx context.Context, q querier, id uuid.UUID) error {
+	_, err := q.ExecContext(ctx, "UPDATE playlists SET updated_at = now() WHERE id = $1;", id)
+	return err
+}
+
+// Marks the playlist as changed and returns its items after the change.
+func commitPlaylistItems(ctx context.Context, tx *sql.Tx, id uuid.UUID) ([]*domain.PlaylistItem, error) {
+	err := touchPlaylist(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := getPlaylistItems(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return items, tx.Commit()
+}
+
+func getPlaylistItems(ctx context.Context, q querier, id uuid.UUID) ([]*domain.PlaylistItem, error) {
+	items := make([]*domain.PlaylistItem, 0)
+
+	query :=
+		`SELECT i.position, s.id, a.name, s.song, COALESCE(s.link, '')
+		FROM playlist_items i
+			JOIN songs s ON s.id = i.song_id
+			JOIN artists a ON a.id = s.artist_id
+		WHERE i.playlist_id = $1 AND s.deleted_at IS NULL
+		ORDER BY i.position;`
+
+	rows, err := q.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := &domain.PlaylistItem{}
+
+		err = rows.Scan(&item.Position, &item.ID, &item.Group, &item.SongName, &item.Link)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// Song of imported entry is found by urn:uuid identifier, then by link and then by group and name.
+func findEntrySongID(ctx context.Context, q querier, entry *domain.PlaylistEntry) (uuid.UUID, error) {
+	if urn, ok := strings.CutPrefix(entry.Identifier, songURNPrefix); ok {
+		if id, err := uuid.Parse(urn); err == nil {
+			songID, err := findSongID(ctx, q, &domain.Song{ID: id})
+			if !errors.Is(err, domain.ErrUnknownResourse) {
+				return songID, err
+			}
+		}
+	}
+
+	if entry.Link != "" {
+		var songID uuid.UUID
+
+		err := q.QueryRowContext(
+			ctx,
+			"SELECT id FROM songs WHERE link = $1 AND deleted_at IS NULL ORDER BY created_at LIMIT 1;",
+			entry.Link,
+		).Scan(&songID)
+		if err == nil {
+			return songID, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, err
+		}
+	}
+
+	if entry.Group == "" || entry.SongName == "" {
+		return uuid.Nil, domain.ErrUnknownResourse
+	}
+
+	return findSongID(ctx, q, &domain.Song{Group: entry.Group, SongName: entry.SongName})
+}
+
+// Columns must be selected in playlistColumns order.
+func scanPlaylist(row scanner) (*domain.Playlist, error) {
+	playlist := &domain.Playlist{}
+
+	err := row.Scan(
+		&playlist.ID,
+		&playlist.Name,
+		&playlist.Description,
+		&playlist.CreatedAt,
+		&playlist.UpdatedAt,
+		&playlist.Songs,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return playlist, nil
+}
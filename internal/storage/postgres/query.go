@@ -1,13 +1,17 @@
 package storage
 
 import (
+	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/qreaqtor/music-library/internal/domain"
 )
 
@@ -17,15 +21,15 @@ type query struct {
 }
 
 // using reflect for getting tag for column name in database.
-// support only string and time.Time types for update.SongSchema fields
-func getSongUpdateQuery(song *domain.Song, update domain.SongSchema) (*query, error) {
-	q := "UPDATE songs SET"
+// support only string, []string, uuid.UUID and time.Time types for schema fields
+func getUpdateQuery(table string, id uuid.UUID, schema any) (*query, error) {
+	q := fmt.Sprintf("UPDATE %s SET", table)
 	args := make([]any, 0)
 
-	updateVal := reflect.ValueOf(update)
+	schemaVal := reflect.ValueOf(schema)
 
-	for _, field := range reflect.VisibleFields(reflect.TypeOf(update)) {
-		value := updateVal.FieldByName(field.Name)
+	for _, field := range reflect.VisibleFields(reflect.TypeOf(schema)) {
+		value := schemaVal.FieldByName(field.Name)
 		columnTag := field.Tag.Get("db")
 
 		if value.IsZero() || columnTag == "-" {
@@ -35,26 +39,22 @@ func getSongUpdateQuery(song *domain.Song, update domain.SongSchema) (*query, er
 		current := fmt.Sprintf(" %s = $%d,", columnTag, len(args)+1)
 		q = fmt.Sprint(q, current)
 
-		switch value.Interface().(type) {
-		case string:
-			args = append(args, value.Interface().(string))
-		case time.Time:
-			args = append(args, value.Interface().(time.Time))
+		switch v := value.Interface().(type) {
+		case string, time.Time, uuid.UUID:
+			args = append(args, v)
+		case []string:
+			args = append(args, pq.Array(v))
 		default:
 			return nil, errors.ErrUnsupported
 		}
 	}
 
 	if len(args) == 0 {
-		return nil, ErrEmptySongUpdate
+		return nil, domain.ErrEmptyUpdate
 	}
 
-	q = fmt.Sprintf("%s WHERE group_name = $%d AND song = $%d;",
-		q[:len(q)-1],
-		len(args)+1,
-		len(args)+2,
-	)
-	args = append(args, song.Group, song.SongName)
+	q = fmt.Sprintf("%s WHERE id = $%d;", q[:len(q)-1], len(args)+1)
+	args = append(args, id)
 
 	return &query{
 		query: q,
@@ -62,22 +62,37 @@ func getSongUpdateQuery(song *domain.Song, update domain.SongSchema) (*query, er
 	}, nil
 }
 
-func getLyricsUpdateQuery(songID uuid.UUID, update domain.LyricsSchema) (*query, error) {
+// verses are indexed for full text search with the language text search configuration
+func getLyricsUpdateQuery(songID uuid.UUID, language string, update domain.LyricsSchema) (*query, error) {
 	if len(update.Lyrics) == 0 {
 		return nil, ErrEmptyLyricsUpdate
 	}
 
 	numbers := make([]string, 0, len(update.Lyrics))
-	args := make([]any, 0, len(update.Lyrics)+1)
-	args = append(args, songID)
+	args := make([]any, 0, 5*len(update.Lyrics)+2)
+	args = append(args, songID, language)
 
-	for i, verse := range update.Lyrics {
-		numbers = append(numbers, fmt.Sprintf("($1, $%d)", i+2))
-		args = append(args, verse)
+	for _, verse := range update.Lyrics {
+		// lines of unsynced verses are stored as NULL
+		lines := sql.NullString{}
+		if len(verse.Lines) != 0 {
+			data, err := json.Marshal(verse.Lines)
+			if err != nil {
+				return nil, err
+			}
+			lines = sql.NullString{String: string(data), Valid: true}
+		}
+
+		n := len(args)
+		numbers = append(numbers, fmt.Sprintf(
+			"($1, $2::regconfig, $%d, $%d, NULLIF($%d, ''), $%d, $%d::jsonb)",
+			n+1, n+2, n+3, n+4, n+5,
+		))
+		args = append(args, verse.Position, verse.Section, verse.Language, verse.Text, lines)
 	}
 
 	q := fmt.Sprintf(
-		`INSERT INTO verses (song_id, verse) VALUES %s`,
+		`INSERT INTO verses (song_id, lang, position, section, language, verse, lines) VALUES %s`,
 		strings.Join(numbers, ","),
 	)
 
@@ -87,60 +102,298 @@ func getLyricsUpdateQuery(songID uuid.UUID, update domain.LyricsSchema) (*query,
 	}, nil
 }
 
-func getSearchQuery(search *domain.SongSearch) *query {
-	q := "WHERE"
+// i-th verse of the translation is inserted at position i+1.
+func getTranslationInsertQuery(songID uuid.UUID, translation *domain.Translation) *query {
+	numbers := make([]string, 0, len(translation.Verses))
+	args := make([]any, 0, 2*len(translation.Verses)+2)
+	args = append(args, songID, translation.Language)
+
+	for i, verse := range translation.Verses {
+		n := len(args)
+		numbers = append(numbers, fmt.Sprintf("($1, $2, $%d, $%d)", n+1, n+2))
+		args = append(args, i+1, verse)
+	}
+
+	return &query{
+		query: fmt.Sprintf(
+			`INSERT INTO verse_translations (song_id, language, position, verse) VALUES %s`,
+			strings.Join(numbers, ","),
+		),
+		args: args,
+	}
+}
+
+// Sort fields of the search query, they are output columns of getSearchQuery.
+var sortColumns = map[string][]string{
+	domain.SortByGroup:       {"group_name"},
+	domain.SortBySongName:    {"song"},
+	domain.SortByReleaseDate: {"release_date"},
+	domain.SortByCreatedAt:   {"created_at"},
+	domain.SortByUpdatedAt:   {"updated_at"},
+	domain.SortByRelevance:   {"similarity", "rank"},
+	domain.SortByPlays:       {"plays"},
+	domain.SortByRating:      {"rating"},
+}
+
+// Average rating of the song s from its counters, 0 if it is not rated.
+const averageRating = "COALESCE(s.rating_sum::real / NULLIF(s.rating_count, 0), 0)::real"
+
+// Returns values of the key in sortColumns order.
+func sortValues(field string, key *domain.SearchKey) []any {
+	switch field {
+	case domain.SortByGroup:
+		return []any{key.Group}
+	case domain.SortBySongName:
+		return []any{key.SongName}
+	case domain.SortByReleaseDate:
+		return []any{key.ReleaseDate.Format(time.DateOnly)}
+	case domain.SortByCreatedAt:
+		return []any{key.CreatedAt}
+	case domain.SortByUpdatedAt:
+		return []any{key.UpdatedAt}
+	case domain.SortByPlays:
+		return []any{key.Plays}
+	case domain.SortByRating:
+		return []any{key.Rating}
+	default:
+		return []any{key.Similarity, key.Rank}
+	}
+}
+
+// Selects all found songs with their sort fields, getSearchPageQuery adds order and limit.
+// Lyrics are searched with full text search in the language of every verse, rank of a song is rank
+// of its best matching verse and matched verses are highlighted in the current language. With fuzzy search group and song name are matched by word similarity,
+// threshold of the <% operator must be set in the same transaction. Genre matches songs of its descendant genres,
+// tags of the search must be normalized. Credit matches name or alias of the credited artist.
+// Other criteria are case insensitive substring matches. Songs in trash are never found.
+func getSearchQuery(search *domain.SongSearch, language string) *query {
+	rank, headline, similarity := "0::real", "''", "0::real"
+	from := "songs s JOIN artists a ON a.id = s.artist_id"
+
+	conditions := []string{"s.deleted_at IS NULL"}
+	similarities := make([]string, 0)
 	args := make([]any, 0)
 
-	if search.ByGroup != "" {
-		q = fmt.Sprintf("%s s.group_name ILIKE $%d", q, len(args)+1)
-		args = append(args, "%"+search.ByGroup+"%")
+	if search.ByLyrics != "" {
+		// lateral subquery returns no rows for songs without matching verses,
+		// query is parsed with the configuration of every verse, because verses written
+		// before SEARCH_LANGUAGE was changed keep their tsvector of the old configuration
+		rank, headline = "l.rank", "l.headline"
+		from += `
+		JOIN LATERAL (
+			SELECT MAX(ts_rank(v.tsv, websearch_to_tsquery(v.lang, $2))) AS rank,
+				ts_headline($1::regconfig, STRING_AGG(v.verse, E'\n' ORDER BY v.position), websearch_to_tsquery($1::regconfig, $2),
+					'MaxFragments=2, MaxWords=15, MinWords=5') AS headline
+			FROM verses v
+			WHERE v.song_id = s.id AND v.tsv @@ websearch_to_tsquery(v.lang, $2)
+			HAVING COUNT(*) > 0
+		) l ON true`
+		args = append(args, language, search.ByLyrics)
 	}
 
-	if search.BySongName != "" {
-		if len(args) != 0 {
-			q += " AND"
+	if search.ByGroup != "" {
+		if search.Fuzzy {
+			conditions = append(conditions, fmt.Sprintf("$%d <%% a.name", len(args)+1))
+			similarities = append(similarities, fmt.Sprintf("word_similarity($%d, a.name)", len(args)+1))
+			args = append(args, search.ByGroup)
+		} else {
+			conditions = append(conditions, fmt.Sprintf("a.name ILIKE $%d", len(args)+1))
+			args = append(args, "%"+search.ByGroup+"%")
 		}
-		q = fmt.Sprintf("%s s.song ILIKE $%d", q, len(args)+1)
-		args = append(args, "%"+search.BySongName+"%")
 	}
 
-	if search.ByLyrics != "" {
-		if len(args) != 0 {
-			q += " AND"
+	if search.BySongName != "" {
+		if search.Fuzzy {
+			conditions = append(conditions, fmt.Sprintf("$%d <%% s.song", len(args)+1))
+			similarities = append(similarities, fmt.Sprintf("word_similarity($%d, s.song)", len(args)+1))
+			args = append(args, search.BySongName)
+		} else {
+			conditions = append(conditions, fmt.Sprintf("s.song ILIKE $%d", len(args)+1))
+			args = append(args, "%"+search.BySongName+"%")
 		}
-		q = fmt.Sprintf("%s EXISTS (SELECT 1 FROM verses v WHERE v.song_id = s.id AND v.verse ILIKE $%d", q, len(args)+1)
-		args = append(args, "%"+search.ByLyrics+"%")
 	}
 
 	if search.ByLink != "" {
-		if len(args) != 0 {
-			q += " AND"
-		}
-		q = fmt.Sprintf("%s s.link ILIKE $%d", q, len(args)+1)
+		conditions = append(conditions, fmt.Sprintf("s.link ILIKE $%d", len(args)+1))
 		args = append(args, "%"+search.ByLink+"%")
 	}
 
 	if !search.DateFrom.IsZero() {
-		if len(args) != 0 {
-			q += " AND"
-		}
-		q = fmt.Sprintf("%s s.releaseDate >= $%d", q, len(args)+1)
+		conditions = append(conditions, fmt.Sprintf("s.releaseDate >= $%d", len(args)+1))
 		args = append(args, search.DateFrom)
 	}
 	if !search.DateTo.IsZero() {
-		if len(args) != 0 {
-			q += " AND"
-		}
-		q = fmt.Sprintf("%s s.releaseDate <= $%d", q, len(args)+1)
+		conditions = append(conditions, fmt.Sprintf("s.releaseDate <= $%d", len(args)+1))
 		args = append(args, search.DateTo)
 	}
 
-	if len(args) == 0 {
-		q = ""
+	if search.Genre != "" {
+		conditions = append(conditions, fmt.Sprintf(
+			`s.id IN (
+				WITH RECURSIVE genre AS (
+					SELECT id FROM genres WHERE lower(name) = lower($%d)
+					UNION ALL
+					SELECT g.id FROM genres g JOIN genre ON g.parent_id = genre.id
+				)
+				SELECT sg.song_id FROM song_genres sg JOIN genre ON genre.id = sg.genre_id
+			)`,
+			len(args)+1,
+		))
+		args = append(args, search.Genre)
+	}
+
+	if len(search.Tags) != 0 {
+		// tags of the search are distinct, so all of them match if the song has as many of them
+		condition := "EXISTS (SELECT 1 FROM song_tags t WHERE t.song_id = s.id AND t.tag = ANY($%[1]d::text[]))"
+		if search.TagMatch == domain.TagMatchAll {
+			condition = "(SELECT COUNT(*) FROM song_tags t WHERE t.song_id = s.id AND t.tag = ANY($%[1]d::text[])) = cardinality($%[1]d::text[])"
+		}
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)+1))
+		args = append(args, pq.Array(search.Tags))
 	}
 
+	if search.Credit != "" || search.CreditRole != "" {
+		credit := []string{"c.song_id = s.id"}
+		if search.Credit != "" {
+			credit = append(credit, artistCondition("ca", len(args)+1))
+			args = append(args, search.Credit)
+		}
+		if search.CreditRole != "" {
+			credit = append(credit, fmt.Sprintf("c.role = $%d", len(args)+1))
+			args = append(args, search.CreditRole)
+		}
+
+		conditions = append(conditions, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM song_credits c JOIN artists ca ON ca.id = c.artist_id WHERE %s)",
+			strings.Join(credit, " AND "),
+		))
+	}
+
+	if len(similarities) != 0 {
+		similarity = fmt.Sprintf("(%s) / %d", strings.Join(similarities, " + "), len(similarities))
+	}
+
+	q := fmt.Sprintf(
+		`SELECT s.id AS id, a.name AS group_name, s.song AS song,
+			s.releaseDate AS release_date, s.created_at AS created_at, s.updated_at AS updated_at,
+			s.play_count AS plays, %s AS rating,
+			%s AS rank, %s AS headline, %s AS similarity
+		FROM %s
+		WHERE %s`,
+		averageRating,
+		rank,
+		headline,
+		similarity,
+		from,
+		strings.Join(conditions, " AND "),
+	)
+
 	return &query{
 		query: q,
 		args:  args,
 	}
 }
+
+// Selects a page of found songs. Songs are sorted by the sort field and then by id in the same direction,
+// so keyset condition is a row comparison. Page before the key is selected in reverse order
+// and must be reversed by the caller.
+func getSearchPageQuery(search *domain.SongSearch, language string) *query {
+	searchQuery := getSearchQuery(search, language)
+	args := searchQuery.args
+
+	field, order := search.Sorting()
+	columns := append(slices.Clone(sortColumns[field]), "id")
+
+	key := search.After
+	if search.Before != nil {
+		key = search.Before
+		order = reverse(order)
+	}
+
+	where := ""
+	if key != nil {
+		values := append(sortValues(field, key), key.ID)
+		placeholders := make([]string, 0, len(values))
+		for _, value := range values {
+			placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)+1))
+			args = append(args, value)
+		}
+
+		operator := ">"
+		if order == domain.OrderDesc {
+			operator = "<"
+		}
+
+		where = fmt.Sprintf(
+			"WHERE (%s) %s (%s)",
+			strings.Join(columns, ", "),
+			operator,
+			strings.Join(placeholders, ", "),
+		)
+	}
+
+	orderBy := make([]string, 0, len(columns))
+	for _, column := range columns {
+		orderBy = append(orderBy, column+" "+strings.ToUpper(order))
+	}
+
+	q := fmt.Sprintf(
+		`SELECT id, group_name, song, release_date, created_at, updated_at, plays, rating, rank, headline, similarity
+		FROM (%s) found
+		%s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d;`,
+		searchQuery.query,
+		where,
+		strings.Join(orderBy, ", "),
+		len(args)+1,
+		len(args)+2,
+	)
+	args = append(args, search.Limit, search.Offset)
+
+	return &query{
+		query: q,
+		args:  args,
+	}
+}
+
+// Selects all found songs with link in sort order of the search, pagination is ignored.
+func getExportQuery(search *domain.SongSearch, language string) *query {
+	searchQuery := getSearchQuery(search, language)
+
+	field, order := search.Sorting()
+	columns := append(slices.Clone(sortColumns[field]), "id")
+
+	orderBy := make([]string, 0, len(columns))
+	for _, column := range columns {
+		orderBy = append(orderBy, "f."+column+" "+strings.ToUpper(order))
+	}
+
+	q := fmt.Sprintf(
+		`SELECT f.id, f.group_name, f.song, f.release_date, COALESCE(s.link, ''), f.created_at, f.updated_at
+		FROM (%s) f JOIN songs s ON s.id = f.id
+		ORDER BY %s`,
+		searchQuery.query,
+		strings.Join(orderBy, ", "),
+	)
+
+	return &query{
+		query: q,
+		args:  searchQuery.args,
+	}
+}
+
+func reverse(order string) string {
+	if order == domain.OrderDesc {
+		return domain.OrderAsc
+	}
+	return domain.OrderDesc
+}
+
+// returns nil for zero time, so NULL is written to the database
+func nullDate(date time.Time) *time.Time {
+	if date.IsZero() {
+		return nil
+	}
+	return &date
+}
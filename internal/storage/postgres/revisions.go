@@ -0,0 +1,261 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+)
+
+// Returns revisions of the song without snapshots, newest first.
+func (s *SongsStorage) Revisions(ctx context.Context, song *domain.Song, batch *domain.Batch) ([]*domain.Revision, error) {
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	songID, err := findSongID(ctx, tx, song)
+	if err != nil {
+		return nil, err
+	}
+
+	query :=
+		`SELECT number, COALESCE(author, ''), created_at, changes, lyrics_changed, NULL FROM song_revisions
+		WHERE song_id = $1
+		ORDER BY number DESC LIMIT $2 OFFSET $3;`
+
+	rows, err := tx.QueryContext(ctx, query, songID, batch.Limit, batch.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := make([]*domain.Revision, 0)
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return revisions, tx.Commit()
+}
+
+// Returns the revision with the state of the song after it.
+func (s *SongsStorage) Revision(ctx context.Context, song *domain.Song, number int) (*domain.Revision, error) {
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	songID, err := findSongID(ctx, tx, song)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := getRevision(ctx, tx, songID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	return revision, tx.Commit()
+}
+
+// Sets metadata, lyrics and translations of the song to the state after the revision.
+// Restore is written as a new revision, so it can be reverted too.
+func (s *SongsStorage) Restore(ctx context.Context, song *domain.Song, number int) error {
+	opID := logmsg.ExtractOperationID(ctx)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	songID, err := findSongID(ctx, tx, song)
+	if err != nil {
+		return err
+	}
+
+	before, err := getSnapshot(ctx, tx, songID)
+	if err != nil {
+		return err
+	}
+
+	revision, err := getRevision(ctx, tx, songID, number)
+	if err != nil {
+		return err
+	}
+	target := revision.Song
+
+	artistID, err := getOrCreateArtist(ctx, tx, target.Group)
+	if err != nil {
+		return err
+	}
+
+	err = checkUniqueRenamed(ctx, tx, songID, artistID, target.SongName)
+	if err != nil {
+		return err
+	}
+
+	query :=
+		`UPDATE songs SET artist_id = $2, song = $3, link = NULLIF($4, ''), releaseDate = COALESCE($5, releaseDate), updated_at = now()
+		WHERE id = $1;`
+
+	_, err = tx.ExecContext(ctx, query, songID, artistID, target.SongName, target.Link, nullDate(target.ReleaseDate))
+	if err != nil {
+		return songError(ctx, s.db, err, songID, artistID, target.SongName)
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM verses WHERE song_id = $1;`, songID)
+	if err != nil {
+		return err
+	}
+
+	versesQuery, err := getLyricsUpdateQuery(songID, s.language, domain.LyricsSchema{Lyrics: target.Lyrics})
+	if err != nil {
+		slog.Debug(err.Error(), "operation", opID)
+	} else {
+		_, err = tx.ExecContext(ctx, versesQuery.query, versesQuery.args...)
+		if err != nil {
+			return err
+		}
+	}
+
+	// snapshots written before translations were saved keep current translations
+	translations := target.Translations
+	if translations == nil {
+		translations = before.Translations
+	}
+
+	err = insertTranslations(ctx, tx, songID, domain.KeepTranslations(translations, len(target.Lyrics)))
+	if err != nil {
+		return err
+	}
+
+	err = writeRevision(ctx, tx, songID, before)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Returns current state of the song, the song row is locked until the end of the transaction.
+func getSnapshot(ctx context.Context, q querier, songID uuid.UUID) (*domain.SongSnapshot, error) {
+	snapshot := &domain.SongSnapshot{}
+
+	query :=
+		`SELECT a.name, s.song, COALESCE(s.link, ''), s.releaseDate
+		FROM songs s JOIN artists a ON a.id = s.artist_id
+		WHERE s.id = $1
+		FOR UPDATE OF s;`
+
+	err := q.QueryRowContext(ctx, query, songID).
+		Scan(&snapshot.Group, &snapshot.SongName, &snapshot.Link, &snapshot.ReleaseDate)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrUnknownResourse
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot.Lyrics, err = selectVerses(
+		ctx, q,
+		"SELECT position, section, COALESCE(language, ''), verse, lines FROM verses WHERE song_id = $1 ORDER BY position;",
+		songID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot.Translations, err = getTranslations(ctx, q, songID)
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// Writes the next revision of the song from before to its current state.
+// before is nil for the created song.
+func writeRevision(ctx context.Context, q querier, songID uuid.UUID, before *domain.SongSnapshot) error {
+	after, err := getSnapshot(ctx, q, songID)
+	if err != nil {
+		return err
+	}
+
+	revision := domain.NewRevision(domain.Author(ctx), before, after)
+
+	changes, err := json.Marshal(revision.Changes)
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := json.Marshal(after)
+	if err != nil {
+		return err
+	}
+
+	query :=
+		`INSERT INTO song_revisions (song_id, number, author, changes, lyrics_changed, snapshot)
+		SELECT $1, COALESCE(MAX(number), 0) + 1, NULLIF($2, ''), $3::jsonb, $4, $5::jsonb
+		FROM song_revisions WHERE song_id = $1;`
+
+	_, err = q.ExecContext(ctx, query, songID, revision.Author, string(changes), revision.LyricsChanged, string(snapshot))
+	return err
+}
+
+func getRevision(ctx context.Context, q querier, songID uuid.UUID, number int) (*domain.Revision, error) {
+	query :=
+		`SELECT number, COALESCE(author, ''), created_at, changes, lyrics_changed, snapshot FROM song_revisions
+		WHERE song_id = $1 AND number = $2;`
+
+	revision, err := scanRevision(q.QueryRowContext(ctx, query, songID, number))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrUnknownResourse
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return revision, nil
+}
+
+// Snapshot column may be NULL, then Song of the revision is not set.
+func scanRevision(row scanner) (*domain.Revision, error) {
+	revision := &domain.Revision{}
+
+	var changes, snapshot []byte
+
+	err := row.Scan(&revision.Number, &revision.Author, &revision.CreatedAt, &changes, &revision.LyricsChanged, &snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(changes, &revision.Changes)
+	if err != nil {
+		return nil, err
+	}
+
+	if snapshot != nil {
+		revision.Song = &domain.SongSnapshot{}
+		err = json.Unmarshal(snapshot, revision.Song)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return revision, nil
+}
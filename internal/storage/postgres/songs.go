@@ -3,9 +3,8 @@ package storage
 import (
 	"context"
 	"database/sql"
-	"errors"
-	"fmt"
 	"log/slog"
+	"slices"
 
 	"github.com/google/uuid"
 	"github.com/qreaqtor/music-library/internal/domain"
@@ -14,142 +13,376 @@ import (
 
 type SongsStorage struct {
 	db *sql.DB
+
+	// text search configuration for lyrics, e.g. english or russian
+	language string
 }
 
-func NewSongsStorage(connection *sql.DB) *SongsStorage {
+func NewSongsStorage(connection *sql.DB, language string) *SongsStorage {
 	return &SongsStorage{
-		db: connection,
+		db:       connection,
+		language: language,
 	}
 }
 
 func (s *SongsStorage) Info(ctx context.Context, song *domain.Song) (*domain.SongInfo, error) {
-	opID := logmsg.ExtractOperationID(ctx)
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	songID, err := findSongID(ctx, tx, song)
+	if err != nil {
+		return nil, err
+	}
 
 	songInfo := &domain.SongInfo{}
 
 	query :=
-		`SELECT s.group_name, s.song, COALESCE(STRING_AGG(v.verse, '\n'), ''), s.releaseDate, COALESCE(s.link, '')
-		FROM songs s LEFT JOIN verses v ON s.id = v.song_id
-		WHERE s.group_name = $1 AND s.song = $2
-		GROUP BY s.id, s.group_name, s.song, s.releaseDate, s.link;`
+		`SELECT s.id, a.name, s.song, COALESCE(STRING_AGG(v.verse, E'\n' ORDER BY v.position), ''), s.releaseDate, COALESCE(s.link, ''), s.created_at, s.updated_at,
+			s.play_count, s.favorite_count, s.rating_count, ` + averageRating + `
+		FROM songs s JOIN artists a ON a.id = s.artist_id LEFT JOIN verses v ON s.id = v.song_id
+		WHERE s.id = $1
+		GROUP BY s.id, a.name, s.song, s.releaseDate, s.link, s.created_at, s.updated_at;`
 
-	err := s.db.QueryRow(query, song.Group, song.SongName).
+	err = tx.QueryRowContext(ctx, query, songID).
 		Scan(
+			&songInfo.ID,
 			&songInfo.Group,
 			&songInfo.SongName,
 			&songInfo.Lyrics,
 			&songInfo.ReleaseDate,
 			&songInfo.Link,
+			&songInfo.CreatedAt,
+			&songInfo.UpdatedAt,
+			&songInfo.Stats.Plays,
+			&songInfo.Stats.Favorites,
+			&songInfo.Stats.Ratings,
+			&songInfo.Stats.AverageRating,
 		)
-	if errors.Is(err, sql.ErrNoRows) {
-		slog.Debug(err.Error(), "operation", opID)
-		return nil, ErrUnknownResourse
+	if err != nil {
+		return nil, err
+	}
+
+	songInfo.Albums, err = getSongAlbums(ctx, tx, songID)
+	if err != nil {
+		return nil, err
+	}
+
+	songInfo.Genres, err = getSongGenres(ctx, tx, songID)
+	if err != nil {
+		return nil, err
 	}
+
+	songInfo.Tags, err = getSongTags(ctx, tx, songID)
 	if err != nil {
 		return nil, err
 	}
 
-	return songInfo, nil
+	songInfo.Credits, err = getSongCredits(ctx, tx, songID)
+	if err != nil {
+		return nil, err
+	}
+
+	return songInfo, tx.Commit()
 }
 
-func (s *SongsStorage) Search(ctx context.Context, search *domain.SongSearch) ([]*domain.Song, error) {
-	songs := make([]*domain.Song, 0, search.Limit)
-	var song domain.Song
-
-	searchQuery := getSearchQuery(search)
-
-	searchQuery.query = fmt.Sprintf(
-		`SELECT s.group_name, s.song
-		FROM songs s LEFT JOIN verses v ON s.id = v.song_id
-		%s
-		GROUP BY s.group_name, s.song
-		LIMIT $%d OFFSET $%d;`,
-		searchQuery.query,
-		len(searchQuery.args)+1,
-		len(searchQuery.args)+2,
-	)
-	searchQuery.args = append(searchQuery.args, search.Limit, search.Offset)
+func (s *SongsStorage) Search(ctx context.Context, search *domain.SongSearch) ([]*domain.FoundSong, error) {
+	songs := make([]*domain.FoundSong, 0)
 
-	rows, err := s.db.Query(searchQuery.query, searchQuery.args...)
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
 	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 
-	for rows.Next() {
-		err = rows.Scan(&song.Group, &song.SongName)
+	if search.Fuzzy {
+		err = setSimilarityThreshold(ctx, tx, search.Threshold)
 		if err != nil {
 			return nil, err
 		}
-		songs = append(songs, &song)
 	}
 
-	return songs, nil
-}
+	searchQuery := getSearchPageQuery(search, s.language)
 
-func (s *SongsStorage) GetLyrics(ctx context.Context, song *domain.Song, batch *domain.Batch) ([]string, error) {
-	opID := logmsg.ExtractOperationID(ctx)
+	rows, err := tx.QueryContext(ctx, searchQuery.query, searchQuery.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-	lyrics := make([]string, 0, batch.Limit)
-	var songID uuid.UUID
-	var verse string
+	for rows.Next() {
+		song := &domain.FoundSong{}
+		err = rows.Scan(
+			&song.ID,
+			&song.Group,
+			&song.SongName,
+			&song.ReleaseDate,
+			&song.CreatedAt,
+			&song.UpdatedAt,
+			&song.Plays,
+			&song.Rating,
+			&song.Rank,
+			&song.Headline,
+			&song.Similarity,
+		)
+		if err != nil {
+			return nil, err
+		}
+		songs = append(songs, song)
+	}
 
-	tx, err := s.db.BeginTx(ctx, nil)
+	err = rows.Err()
 	if err != nil {
 		return nil, err
 	}
+
+	// page before the key is selected in reverse order
+	if search.Before != nil {
+		slices.Reverse(songs)
+	}
+
+	return songs, tx.Commit()
+}
+
+// Counts all found songs, pagination is ignored.
+func (s *SongsStorage) Count(ctx context.Context, search *domain.SongSearch) (int, error) {
+	var total int
+
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return 0, err
+	}
 	defer tx.Rollback()
 
-	query := "SELECT id FROM songs WHERE group_name = $1 AND song = $2"
+	if search.Fuzzy {
+		err = setSimilarityThreshold(ctx, tx, search.Threshold)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	searchQuery := getSearchQuery(search, s.language)
 
-	err = tx.QueryRow(query, song.Group, song.SongName).Scan(&songID)
-	if errors.Is(err, sql.ErrNoRows) {
-		slog.Debug(err.Error(), "operation", opID)
-		return nil, ErrUnknownResourse
+	err = tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM ("+searchQuery.query+") found;", searchQuery.args...).
+		Scan(&total)
+	if err != nil {
+		return 0, err
 	}
+
+	return total, tx.Commit()
+}
+
+// Returns group and song names similar to the searched ones, at most maxSuggestions of each.
+func (s *SongsStorage) Suggest(ctx context.Context, search *domain.SongSearch) (*domain.Suggestions, error) {
+	suggestions := &domain.Suggestions{}
+
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
 	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 
-	query = "SELECT verse FROM verses WHERE song_id = $1 LIMIT $2 OFFSET $3;"
-	rows, err := tx.Query(query, songID, batch.Limit, batch.Offset)
-	if errors.Is(err, sql.ErrNoRows) {
-		slog.Debug("no lyrics", "operation", opID)
-		return nil, ErrUnknownResourse
-	}
+	err = setSimilarityThreshold(ctx, tx, search.Threshold)
 	if err != nil {
 		return nil, err
 	}
 
-	for rows.Next() {
-		err = rows.Scan(&verse)
+	if search.ByGroup != "" {
+		query :=
+			`SELECT name FROM artists
+			WHERE $1 <% name
+			ORDER BY word_similarity($1, name) DESC, name
+			LIMIT $2;`
+
+		suggestions.Groups, err = selectNames(ctx, tx, query, search.ByGroup, maxSuggestions)
 		if err != nil {
 			return nil, err
 		}
+	}
 
-		lyrics = append(lyrics, verse)
+	if search.BySongName != "" {
+		query :=
+			`SELECT song FROM songs
+			WHERE $1 <% song AND deleted_at IS NULL
+			GROUP BY song
+			ORDER BY word_similarity($1, song) DESC, song
+			LIMIT $2;`
+
+		suggestions.Songs, err = selectNames(ctx, tx, query, search.BySongName, maxSuggestions)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	err = tx.Commit()
+	return suggestions, tx.Commit()
+}
+
+// Verses are ordered by position, page before batch.Before is selected in reverse order and then reversed.
+// Empty section matches verses of all sections.
+func (s *SongsStorage) GetLyrics(ctx context.Context, song *domain.Song, batch *domain.LyricsBatch) ([]*domain.Verse, error) {
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	songID, err := findSongID(ctx, tx, song)
 	if err != nil {
 		return nil, err
 	}
 
-	return lyrics, nil
+	query := `SELECT position, section, COALESCE(language, ''), verse, lines FROM verses
+		WHERE song_id = $1 AND ($2 = '' OR section = $2) AND position > $3
+		ORDER BY position LIMIT $4 OFFSET $5;`
+	args := []any{songID, batch.Section, batch.After, batch.Limit, batch.Offset}
+
+	if batch.Before != 0 {
+		query = `SELECT position, section, COALESCE(language, ''), verse, lines FROM verses
+			WHERE song_id = $1 AND ($2 = '' OR section = $2) AND position < $3
+			ORDER BY position DESC LIMIT $4;`
+		args = []any{songID, batch.Section, batch.Before, batch.Limit}
+	}
+
+	lyrics, err := selectVerses(ctx, tx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if batch.Before != 0 {
+		slices.Reverse(lyrics)
+	}
+
+	return lyrics, tx.Commit()
 }
 
-func (s *SongsStorage) Create(ctx context.Context, song *domain.Song) error {
-	query := "INSERT INTO songs (group_name, song) values ($1, $2);"
-	_, err := s.db.Exec(query, song.Group, song.SongName)
+// Returns all verses of the song ordered by position.
+func (s *SongsStorage) Verses(ctx context.Context, song *domain.Song) ([]*domain.Verse, error) {
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+	defer tx.Rollback()
+
+	songID, err := findSongID(ctx, tx, song)
+	if err != nil {
+		return nil, err
+	}
+
+	verses, err := selectVerses(
+		ctx, tx,
+		"SELECT position, section, COALESCE(language, ''), verse, lines FROM verses WHERE song_id = $1 ORDER BY position;",
+		songID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return verses, tx.Commit()
+}
+
+// Counts verses of batch.Section, empty section matches verses of all sections.
+func (s *SongsStorage) CountLyrics(ctx context.Context, song *domain.Song, batch *domain.LyricsBatch) (int, error) {
+	var total int
+
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	songID, err := findSongID(ctx, tx, song)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.QueryRowContext(
+		ctx,
+		"SELECT COUNT(*) FROM verses WHERE song_id = $1 AND ($2 = '' OR section = $2);",
+		songID, batch.Section,
+	).Scan(&total)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, tx.Commit()
+}
+
+// details may be nil, then only group and song name are inserted.
+// Artist is created if there is no artist with the group name yet.
+// Returns id of the created song.
+func (s *SongsStorage) Create(ctx context.Context, song *domain.Song, details *domain.SongDetails) (uuid.UUID, error) {
+	opID := logmsg.ExtractOperationID(ctx)
+
+	if details == nil {
+		details = &domain.SongDetails{}
+	}
+
+	var songID uuid.UUID
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	defer tx.Rollback()
+
+	schema := details.ToSongSchema(song)
+
+	schema.ArtistID, err = getOrCreateArtist(ctx, tx, schema.Group)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	err = checkUnique(ctx, tx, schema.ArtistID, schema.SongName)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	// zero release date is replaced with the current date, empty link is stored as NULL
+	query :=
+		`INSERT INTO songs (artist_id, song, releaseDate, link)
+		VALUES ($1, $2, COALESCE($3, current_date), NULLIF($4, ''))
+		RETURNING id;`
+
+	err = tx.QueryRowContext(ctx, query, schema.ArtistID, schema.SongName, nullDate(schema.ReleaseDate), schema.Link).
+		Scan(&songID)
+	if err != nil {
+		return uuid.Nil, songError(ctx, s.db, err, uuid.Nil, schema.ArtistID, schema.SongName)
+	}
+
+	versesQuery, err := getLyricsUpdateQuery(songID, s.language, details.ToLyricsSchema())
+	if err != nil {
+		slog.Debug(err.Error(), "operation", opID)
+	} else {
+		_, err = tx.ExecContext(ctx, versesQuery.query, versesQuery.args...)
+		if err != nil {
+			return uuid.Nil, err
+		}
+	}
+
+	err = writeRevision(ctx, tx, songID, nil)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return songID, tx.Commit()
 }
 
+// Moves the song with the id to trash if it is set,
+// otherwise all songs with the same group and name.
 func (s *SongsStorage) Delete(ctx context.Context, song *domain.Song) error {
-	query := "DELETE FROM songs WHERE group_name = $1 AND song = $2;"
+	query :=
+		`UPDATE songs s SET deleted_at = now() FROM artists a
+		WHERE a.id = s.artist_id AND ` + artistCondition("a", 1) + ` AND name_key(s.song) = name_key($2) AND s.deleted_at IS NULL;`
+	args := []any{song.Group, song.SongName}
+
+	if song.ID != uuid.Nil {
+		query = "UPDATE songs SET deleted_at = now() WHERE id = $1 AND deleted_at IS NULL;"
+		args = []any{song.ID}
+	}
 
-	res, err := s.db.Exec(query, song.Group, song.SongName)
+	res, err := s.db.ExecContext(ctx, query, args...)
 	if err != nil {
 		return err
 	}
@@ -157,7 +390,7 @@ func (s *SongsStorage) Delete(ctx context.Context, song *domain.Song) error {
 	n, err := res.RowsAffected()
 	if err != nil || n == 0 {
 		slog.Debug("no rows affected", "operation", logmsg.ExtractOperationID(ctx))
-		return ErrUnknownResourse
+		return domain.ErrUnknownResourse
 	}
 
 	return nil
@@ -166,47 +399,80 @@ func (s *SongsStorage) Delete(ctx context.Context, song *domain.Song) error {
 func (s *SongsStorage) Update(ctx context.Context, song *domain.Song, update *domain.SongUpdate) error {
 	opID := logmsg.ExtractOperationID(ctx)
 
-	var songID uuid.UUID
-
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	row := tx.QueryRow("SELECT id FROM songs WHERE group_name = $1 AND song = $2;", song.Group, song.SongName)
-	err = row.Scan(&songID)
-	if errors.Is(err, sql.ErrNoRows) {
-		slog.Debug(err.Error(), "operation", opID)
-		return ErrUnknownResourse
-	}
+	songID, err := findSongID(ctx, tx, song)
 	if err != nil {
 		return err
 	}
 
-	songQuery, err := getSongUpdateQuery(song, update.ToSongSchema())
+	// locks the song, so revisions are written in the order of updates
+	before, err := getSnapshot(ctx, tx, songID)
 	if err != nil {
-		slog.Debug(err.Error(), "operation", opID)
-	} else {
-		_, err = tx.Exec(songQuery.query, songQuery.args...)
+		return err
+	}
+
+	schema := update.ToSongSchema()
+	if schema.Group != "" {
+		schema.ArtistID, err = getOrCreateArtist(ctx, tx, schema.Group)
 		if err != nil {
 			return err
 		}
 	}
 
-	_, err = tx.Exec(`DELETE FROM verses WHERE song_id = $1;`, songID)
+	if schema.ArtistID != uuid.Nil || schema.SongName != "" {
+		err = checkUniqueRenamed(ctx, tx, songID, schema.ArtistID, schema.SongName)
+		if err != nil {
+			return err
+		}
+	}
+
+	songQuery, err := getUpdateQuery("songs", songID, schema)
 	if err != nil {
-		return err
+		slog.Debug(err.Error(), "operation", opID)
+	} else {
+		_, err = tx.Exec(songQuery.query, songQuery.args...)
+		if err != nil {
+			return songError(ctx, s.db, err, songID, schema.ArtistID, schema.SongName)
+		}
 	}
 
-	versesQuery, err := getLyricsUpdateQuery(songID, update.ToLyricsSchema())
+	// lyrics are replaced only if new ones are provided
+	lyrics := update.ToLyricsSchema()
+	versesQuery, err := getLyricsUpdateQuery(songID, s.language, lyrics)
 	if err != nil {
 		slog.Debug(err.Error(), "operation", opID)
 	} else {
+		_, err = tx.Exec(`DELETE FROM verses WHERE song_id = $1;`, songID)
+		if err != nil {
+			return err
+		}
+
 		_, err = tx.Exec(versesQuery.query, versesQuery.args...)
 		if err != nil {
 			return err
 		}
+
+		// translations are deleted with verses, so translations of remaining positions are inserted back
+		err = insertTranslations(ctx, tx, songID, domain.KeepTranslations(before.Translations, len(lyrics.Lyrics)))
+		if err != nil {
+			return err
+		}
+	}
+
+	// lyrics are stored separately, so update time is set even if only lyrics are changed
+	_, err = tx.ExecContext(ctx, "UPDATE songs SET updated_at = now() WHERE id = $1;", songID)
+	if err != nil {
+		return err
+	}
+
+	err = writeRevision(ctx, tx, songID, before)
+	if err != nil {
+		return err
 	}
 
 	err = tx.Commit()
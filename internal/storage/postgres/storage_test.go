@@ -0,0 +1,186 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+	"github.com/qreaqtor/music-library/internal/storage/storagetest"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+)
+
+// Conformance tests run only if PG_TEST_URL is set. The database is reset: public schema is recreated
+// and goose migrations are applied once, every test case starts with empty tables.
+const testURLEnv = "PG_TEST_URL"
+
+var (
+	migrateOnce sync.Once
+	migrateErr  error
+)
+
+func TestConformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storagetest.Storage {
+		return NewSongsStorage(testDB(t), "simple")
+	})
+}
+
+func TestUsersConformance(t *testing.T) {
+	storagetest.RunUsers(t, func(t *testing.T) storagetest.UsersStorage {
+		return NewUsersStorage(testDB(t))
+	})
+}
+
+func TestAPIKeysConformance(t *testing.T) {
+	storagetest.RunAPIKeys(t, func(t *testing.T) storagetest.APIKeysStorage {
+		return NewAPIKeysStorage(testDB(t))
+	})
+}
+
+func TestArtistsConformance(t *testing.T) {
+	storagetest.RunArtists(t, func(t *testing.T) (storagetest.ArtistsStorage, storagetest.Storage) {
+		db := testDB(t)
+		return NewArtistsStorage(db), NewSongsStorage(db, "simple")
+	})
+}
+
+func TestAlbumsConformance(t *testing.T) {
+	storagetest.RunAlbums(t, func(t *testing.T) (storagetest.AlbumsStorage, storagetest.ArtistsStorage, storagetest.Storage) {
+		db := testDB(t)
+		return NewAlbumsStorage(db), NewArtistsStorage(db), NewSongsStorage(db, "simple")
+	})
+}
+
+func TestPlaylistsConformance(t *testing.T) {
+	storagetest.RunPlaylists(t, func(t *testing.T) (storagetest.PlaylistsStorage, storagetest.Storage) {
+		db := testDB(t)
+		return NewPlaylistsStorage(db), NewSongsStorage(db, "simple")
+	})
+}
+
+func TestFeedbackConformance(t *testing.T) {
+	storagetest.RunFeedback(t, func(t *testing.T) (storagetest.FeedbackStorage, storagetest.UsersStorage) {
+		db := testDB(t)
+		return NewSongsStorage(db, "simple"), NewUsersStorage(db)
+	})
+}
+
+// Verses keep the text search configuration they were written with, so they must be found after SEARCH_LANGUAGE is changed.
+func TestSearchAfterLanguageChange(t *testing.T) {
+	db := testDB(t)
+	ctx := context.WithValue(context.Background(), logmsg.OperationID, uuid.New())
+
+	_, err := NewSongsStorage(db, "simple").Create(
+		ctx,
+		&domain.Song{Group: "Muse", SongName: "Uprising"},
+		&domain.SongDetails{ReleaseDate: time.Now(), Text: "They will not force us\nThey will stop degrading us"},
+	)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	// english configuration stems "degrading" to "degrad", simple one keeps the word
+	found, err := NewSongsStorage(db, "english").Search(ctx, &domain.SongSearch{
+		ByLyrics: "degrading",
+		Batch:    domain.Batch{Limit: 10},
+	})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(found) != 1 || found[0].SongName != "Uprising" {
+		t.Errorf("Search returned %+v, want Uprising", found)
+	}
+}
+
+// Returns connection to the migrated test database with truncated tables.
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	url := os.Getenv(testURLEnv)
+	if url == "" {
+		t.Skipf("%s is not set", testURLEnv)
+	}
+
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migrateOnce.Do(func() { migrateErr = migrate(db) })
+	if migrateErr != nil {
+		t.Fatalf("migrate: %v", migrateErr)
+	}
+
+	err = truncate(db)
+	if err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+
+	return db
+}
+
+// Applies Up sections of goose migrations in version order, each one in its own transaction like goose does.
+func migrate(db *sql.DB) error {
+	_, err := db.Exec("DROP SCHEMA public CASCADE; CREATE SCHEMA public;")
+	if err != nil {
+		return err
+	}
+
+	files, err := filepath.Glob(filepath.Join("..", "..", "..", "migrations", "*.sql"))
+	if err != nil {
+		return err
+	}
+	slices.Sort(files)
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		up, _, _ := strings.Cut(string(content), "-- +goose Down")
+
+		err = execTx(db, up)
+		if err != nil {
+			return fmt.Errorf("%s: %w", filepath.Base(file), err)
+		}
+	}
+
+	return nil
+}
+
+func execTx(db *sql.DB, query string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(query)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func truncate(db *sql.DB) error {
+	var tables sql.NullString
+
+	err := db.QueryRow("SELECT string_agg(quote_ident(tablename), ', ') FROM pg_tables WHERE schemaname = 'public';").Scan(&tables)
+	if err != nil || !tables.Valid {
+		return err
+	}
+
+	_, err = db.Exec("TRUNCATE " + tables.String + " CASCADE;")
+	return err
+}
@@ -0,0 +1,204 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+)
+
+// Languages are ordered alphabetically, verses without language are skipped.
+func (s *SongsStorage) Languages(ctx context.Context, song *domain.Song) (*domain.Languages, error) {
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	songID, err := findSongID(ctx, tx, song)
+	if err != nil {
+		return nil, err
+	}
+
+	original, err := selectStrings(
+		ctx, tx,
+		"SELECT DISTINCT language FROM verses WHERE song_id = $1 AND language IS NOT NULL ORDER BY language;",
+		songID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	translations, err := selectStrings(
+		ctx, tx,
+		"SELECT DISTINCT language FROM verse_translations WHERE song_id = $1 ORDER BY language;",
+		songID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.Languages{
+		Original:     original,
+		Translations: translations,
+	}, tx.Commit()
+}
+
+// Returns domain.ErrUnknownResourse if the song has no translation to the language.
+func (s *SongsStorage) Translation(ctx context.Context, song *domain.Song, language string) (*domain.Translation, error) {
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	songID, err := findSongID(ctx, tx, song)
+	if err != nil {
+		return nil, err
+	}
+
+	verses, err := selectStrings(
+		ctx, tx,
+		"SELECT verse FROM verse_translations WHERE song_id = $1 AND language = $2 ORDER BY position;",
+		songID, language,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(verses) == 0 {
+		slog.Debug("translation not found", "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+
+	return &domain.Translation{
+		Language: language,
+		Verses:   verses,
+	}, tx.Commit()
+}
+
+// Replaces translation to the same language.
+// Returns domain.ErrTranslationVerses if number of verses differs from the lyrics.
+func (s *SongsStorage) SetTranslation(ctx context.Context, song *domain.Song, translation *domain.Translation) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	songID, err := findSongID(ctx, tx, song)
+	if err != nil {
+		return err
+	}
+
+	// locks verses, so they can't be replaced until the translation is inserted
+	var verses int
+	err = tx.QueryRowContext(
+		ctx,
+		"SELECT COUNT(*) FROM (SELECT 1 FROM verses WHERE song_id = $1 FOR SHARE) v;",
+		songID,
+	).Scan(&verses)
+	if err != nil {
+		return err
+	}
+
+	if verses != len(translation.Verses) {
+		return domain.ErrTranslationVerses
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		"DELETE FROM verse_translations WHERE song_id = $1 AND language = $2;",
+		songID, translation.Language,
+	)
+	if err != nil {
+		return err
+	}
+
+	insert := getTranslationInsertQuery(songID, translation)
+
+	_, err = tx.ExecContext(ctx, insert.query, insert.args...)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *SongsStorage) DeleteTranslation(ctx context.Context, song *domain.Song, language string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	songID, err := findSongID(ctx, tx, song)
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(
+		ctx,
+		"DELETE FROM verse_translations WHERE song_id = $1 AND language = $2;",
+		songID, language,
+	)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil || n == 0 {
+		slog.Debug("no rows affected", "operation", logmsg.ExtractOperationID(ctx))
+		return domain.ErrUnknownResourse
+	}
+
+	return tx.Commit()
+}
+
+// Returns translations of the song ordered by language.
+func getTranslations(ctx context.Context, q querier, songID uuid.UUID) ([]*domain.Translation, error) {
+	translations := make([]*domain.Translation, 0)
+
+	rows, err := q.QueryContext(
+		ctx,
+		"SELECT language, verse FROM verse_translations WHERE song_id = $1 ORDER BY language, position;",
+		songID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var language, verse string
+
+		err = rows.Scan(&language, &verse)
+		if err != nil {
+			return nil, err
+		}
+
+		if n := len(translations); n == 0 || translations[n-1].Language != language {
+			translations = append(translations, &domain.Translation{Language: language, Verses: make([]string, 0)})
+		}
+		last := translations[len(translations)-1]
+		last.Verses = append(last.Verses, verse)
+	}
+
+	return translations, rows.Err()
+}
+
+// Inserts translations of the song, verses of the song must exist.
+func insertTranslations(ctx context.Context, q querier, songID uuid.UUID, translations []*domain.Translation) error {
+	for _, translation := range translations {
+		insert := getTranslationInsertQuery(songID, translation)
+
+		_, err := q.ExecContext(ctx, insert.query, insert.args...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
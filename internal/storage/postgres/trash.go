@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+)
+
+// Returns songs in trash, recently deleted first.
+func (s *SongsStorage) Trash(ctx context.Context, batch *domain.Batch) ([]*domain.TrashedSong, error) {
+	songs := make([]*domain.TrashedSong, 0)
+
+	query :=
+		`SELECT s.id, a.name, s.song, s.deleted_at
+		FROM songs s JOIN artists a ON a.id = s.artist_id
+		WHERE s.deleted_at IS NOT NULL
+		ORDER BY s.deleted_at DESC, s.id
+		LIMIT $1 OFFSET $2;`
+
+	rows, err := s.db.QueryContext(ctx, query, batch.Limit, batch.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		song := &domain.TrashedSong{}
+
+		err = rows.Scan(&song.ID, &song.Group, &song.SongName, &song.DeletedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		songs = append(songs, song)
+	}
+
+	return songs, rows.Err()
+}
+
+// Moves the song with the id out of trash if it is set,
+// otherwise the most recently deleted song with the same group and name.
+// Returns domain.SongExistsError if the artist already has another song with the same name.
+func (s *SongsStorage) Untrash(ctx context.Context, song *domain.Song) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query :=
+		`SELECT s.id FROM songs s JOIN artists a ON a.id = s.artist_id
+		WHERE ` + artistCondition("a", 1) + ` AND name_key(s.song) = name_key($2) AND s.deleted_at IS NOT NULL
+		ORDER BY s.deleted_at DESC
+		LIMIT 1
+		FOR UPDATE OF s;`
+	args := []any{song.Group, song.SongName}
+
+	if song.ID != uuid.Nil {
+		query = "SELECT id FROM songs WHERE id = $1 AND deleted_at IS NOT NULL FOR UPDATE;"
+		args = []any{song.ID}
+	}
+
+	var songID uuid.UUID
+
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&songID)
+	if errors.Is(err, sql.ErrNoRows) {
+		slog.Debug(err.Error(), "operation", logmsg.ExtractOperationID(ctx))
+		return domain.ErrUnknownResourse
+	}
+	if err != nil {
+		return err
+	}
+
+	err = checkUniqueRenamed(ctx, tx, songID, uuid.Nil, "")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE songs SET deleted_at = NULL WHERE id = $1;", songID)
+	if err != nil {
+		return songError(ctx, s.db, err, songID, uuid.Nil, "")
+	}
+
+	return tx.Commit()
+}
+
+// Permanently deletes songs moved to trash before the time, verses and revisions are deleted with them.
+// Playlist items of the songs are deleted too, so the rest are renumbered without gaps.
+// Returns number of deleted songs.
+func (s *SongsStorage) Purge(ctx context.Context, before time.Time) (int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, "DELETE FROM songs WHERE deleted_at < $1;", before)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	if n != 0 {
+		query :=
+			`UPDATE playlist_items i SET position = r.n
+			FROM (
+				SELECT playlist_id, position, row_number() OVER (PARTITION BY playlist_id ORDER BY position) AS n
+				FROM playlist_items
+			) r
+			WHERE i.playlist_id = r.playlist_id AND i.position = r.position AND r.n <> r.position;`
+
+		_, err = tx.ExecContext(ctx, query)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return int(n), tx.Commit()
+}
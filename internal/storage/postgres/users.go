@@ -0,0 +1,261 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+)
+
+const userColumns = "id, name, role, created_at"
+
+type UsersStorage struct {
+	db *sql.DB
+}
+
+func NewUsersStorage(connection *sql.DB) *UsersStorage {
+	return &UsersStorage{
+		db: connection,
+	}
+}
+
+// Names are unique case insensitively.
+func (s *UsersStorage) CreateUser(ctx context.Context, account *domain.UserAccount) (*domain.User, error) {
+	query :=
+		`INSERT INTO users (name, password_hash, role)
+		VALUES ($1, $2, $3)
+		RETURNING ` + userColumns + `;`
+
+	user, err := scanUser(s.db.QueryRowContext(ctx, query, account.Name, account.PasswordHash, account.Role))
+	if hasCode(err, uniqueViolation) {
+		return nil, domain.ErrUserExists
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (s *UsersStorage) User(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	return getUser(ctx, s.db, id)
+}
+
+// Users are ordered by registration.
+func (s *UsersStorage) Users(ctx context.Context, batch *domain.Batch) ([]*domain.User, error) {
+	users := make([]*domain.User, 0)
+
+	query :=
+		`SELECT ` + userColumns + `
+		FROM users
+		ORDER BY created_at, id
+		LIMIT $1 OFFSET $2;`
+
+	rows, err := s.db.QueryContext(ctx, query, batch.Limit, batch.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
+
+func (s *UsersStorage) SetRole(ctx context.Context, id uuid.UUID, role string) (*domain.User, error) {
+	query :=
+		`UPDATE users SET role = $2
+		WHERE id = $1
+		RETURNING ` + userColumns + `;`
+
+	user, err := scanUser(s.db.QueryRowContext(ctx, query, id, role))
+	if errors.Is(err, sql.ErrNoRows) {
+		slog.Debug(err.Error(), "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// User is found by name case insensitively.
+func (s *UsersStorage) UserByName(ctx context.Context, name string) (*domain.UserAccount, error) {
+	account := &domain.UserAccount{}
+
+	query :=
+		`SELECT id, name, role, created_at, password_hash
+		FROM users
+		WHERE lower(name) = lower($1);`
+
+	err := s.db.QueryRowContext(ctx, query, name).
+		Scan(&account.ID, &account.Name, &account.Role, &account.CreatedAt, &account.PasswordHash)
+	if errors.Is(err, sql.ErrNoRows) {
+		slog.Debug(err.Error(), "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// Expired and revoked sessions of the user are deleted, so they don't pile up.
+func (s *UsersStorage) CreateSession(ctx context.Context, session *domain.Session) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(
+		ctx,
+		"DELETE FROM sessions WHERE user_id = $1 AND (expires_at < now() OR revoked_at IS NOT NULL);",
+		session.UserID,
+	)
+	if err != nil {
+		return err
+	}
+
+	query :=
+		`INSERT INTO sessions (id, user_id, refresh_id, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at;`
+
+	err = tx.QueryRowContext(ctx, query, session.ID, session.UserID, session.RefreshID, session.ExpiresAt).
+		Scan(&session.CreatedAt)
+	if hasCode(err, foreignKeyViolation) {
+		return domain.ErrUnknownResourse
+	}
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *UsersStorage) Session(ctx context.Context, id uuid.UUID) (*domain.Session, error) {
+	session := &domain.Session{}
+
+	query :=
+		`SELECT id, user_id, refresh_id, created_at, expires_at, revoked_at IS NOT NULL
+		FROM sessions
+		WHERE id = $1;`
+
+	err := s.db.QueryRowContext(ctx, query, id).Scan(
+		&session.ID,
+		&session.UserID,
+		&session.RefreshID,
+		&session.CreatedAt,
+		&session.ExpiresAt,
+		&session.Revoked,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		slog.Debug(err.Error(), "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// Replaces refresh token of the session if refreshID is still the last issued one.
+// Returns domain.ErrInvalidToken if the session was revoked or refreshed concurrently.
+func (s *UsersStorage) RotateSession(ctx context.Context, id, refreshID, newRefreshID uuid.UUID, expiresAt time.Time) error {
+	query :=
+		`UPDATE sessions
+		SET refresh_id = $3, expires_at = $4
+		WHERE id = $1 AND refresh_id = $2 AND revoked_at IS NULL AND expires_at > now();`
+
+	res, err := s.db.ExecContext(ctx, query, id, refreshID, newRefreshID, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil || n == 0 {
+		slog.Debug("no rows affected", "operation", logmsg.ExtractOperationID(ctx))
+		return domain.ErrInvalidToken
+	}
+
+	return nil
+}
+
+// Revoking revoked session is not an error.
+func (s *UsersStorage) RevokeSession(ctx context.Context, id uuid.UUID) error {
+	res, err := s.db.ExecContext(
+		ctx,
+		"UPDATE sessions SET revoked_at = COALESCE(revoked_at, now()) WHERE id = $1;",
+		id,
+	)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil || n == 0 {
+		slog.Debug("no rows affected", "operation", logmsg.ExtractOperationID(ctx))
+		return domain.ErrUnknownResourse
+	}
+
+	return nil
+}
+
+// Returns number of revoked sessions, already revoked ones are not counted.
+func (s *UsersStorage) RevokeSessions(ctx context.Context, userID uuid.UUID) (int, error) {
+	res, err := s.db.ExecContext(
+		ctx,
+		"UPDATE sessions SET revoked_at = now() WHERE user_id = $1 AND revoked_at IS NULL;",
+		userID,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(n), nil
+}
+
+func getUser(ctx context.Context, q querier, id uuid.UUID) (*domain.User, error) {
+	user, err := scanUser(q.QueryRowContext(ctx, "SELECT "+userColumns+" FROM users WHERE id = $1;", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		slog.Debug(err.Error(), "operation", logmsg.ExtractOperationID(ctx))
+		return nil, domain.ErrUnknownResourse
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// Columns must be selected in userColumns order.
+func scanUser(row scanner) (*domain.User, error) {
+	user := &domain.User{}
+
+	err := row.Scan(&user.ID, &user.Name, &user.Role, &user.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
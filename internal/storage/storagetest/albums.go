@@ -0,0 +1,433 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+)
+
+// AlbumsStorage keeps albums of artists and their tracklists of songs.
+type AlbumsStorage interface {
+	Create(context.Context, *domain.Album) (*domain.Album, error)
+	Get(context.Context, uuid.UUID) (*domain.Album, error)
+	List(context.Context, *domain.AlbumSearch) ([]*domain.Album, error)
+	Update(context.Context, uuid.UUID, *domain.AlbumUpdate) (*domain.Album, error)
+	Delete(context.Context, uuid.UUID) error
+	GetTracks(context.Context, uuid.UUID) ([]*domain.Track, error)
+	SetTracks(context.Context, uuid.UUID, *domain.Tracklist) ([]*domain.Track, error)
+}
+
+// Albums refer to artists and songs, so all storages must share the database.
+type NewAlbums func(t *testing.T) (AlbumsStorage, ArtistsStorage, Storage)
+
+// Runs conformance tests of albums against storages returned by newStorage.
+func RunAlbums(t *testing.T, newStorage NewAlbums) {
+	tests := []struct {
+		name string
+		test func(*testing.T, AlbumsStorage, ArtistsStorage, Storage)
+	}{
+		{"CreateAndGet", testCreateAlbum},
+		{"ListAlbums", testListAlbums},
+		{"UpdateAlbum", testUpdateAlbum},
+		{"DeleteAlbum", testDeleteAlbum},
+		{"Tracks", testTracks},
+		{"TracksOfDeletedSongs", testTracksOfDeletedSongs},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			albums, artists, songs := newStorage(t)
+			tt.test(t, albums, artists, songs)
+		})
+	}
+}
+
+func testCreateAlbum(t *testing.T, albums AlbumsStorage, artists ArtistsStorage, _ Storage) {
+	ctx := newContext()
+
+	artist := mustCreateArtist(t, artists, &domain.Artist{Name: "Muse"})
+	released := date(2006, time.July, 3)
+
+	created, err := albums.Create(ctx, &domain.Album{
+		Title:       "Black Holes and Revelations",
+		ArtistID:    artist.ID,
+		ReleaseDate: &released,
+		Label:       "Warner Bros.",
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if created.ID == uuid.Nil || created.Artist != "Muse" {
+		t.Errorf("Create returned %+v, want new album of Muse", created)
+	}
+
+	got, err := albums.Get(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Title != "Black Holes and Revelations" || got.ArtistID != artist.ID || got.Artist != "Muse" ||
+		got.ReleaseDate == nil || !sameDate(*got.ReleaseDate, released) || got.Label != "Warner Bros." || got.CoverURL != "" {
+		t.Errorf("Get returned %+v", got)
+	}
+
+	_, err = albums.Create(ctx, &domain.Album{Title: "Unknown", ArtistID: uuid.New()})
+	if !errors.Is(err, domain.ErrUnknownArtist) {
+		t.Errorf("Create with unknown artist returned %v, want %v", err, domain.ErrUnknownArtist)
+	}
+
+	_, err = albums.Get(ctx, uuid.New())
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("Get of unknown album returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+}
+
+func testListAlbums(t *testing.T, albums AlbumsStorage, artists ArtistsStorage, _ Storage) {
+	ctx := newContext()
+
+	muse := mustCreateArtist(t, artists, &domain.Artist{Name: "Muse"})
+	queen := mustCreateArtist(t, artists, &domain.Artist{Name: "Queen"})
+
+	mustCreateAlbum(t, albums, "Drones", muse.ID, nil)
+	mustCreateAlbum(t, albums, "Absolution", muse.ID, &domain.Album{ReleaseDate: ptr(date(2003, time.September, 15))})
+	mustCreateAlbum(t, albums, "A Night at the Opera", queen.ID, &domain.Album{ReleaseDate: ptr(date(1975, time.November, 21))})
+
+	tests := []struct {
+		name   string
+		search domain.AlbumSearch
+		want   []string
+	}{
+		{"All", domain.AlbumSearch{Batch: domain.Batch{Limit: 10}}, []string{"A Night at the Opera", "Absolution", "Drones"}},
+		{"Page", domain.AlbumSearch{Batch: domain.Batch{Offset: 1, Limit: 1}}, []string{"Absolution"}},
+		{"ByTitle", domain.AlbumSearch{ByTitle: "OPERA", Batch: domain.Batch{Limit: 10}}, []string{"A Night at the Opera"}},
+		{"ByArtist", domain.AlbumSearch{ArtistID: muse.ID, Batch: domain.Batch{Limit: 10}}, []string{"Absolution", "Drones"}},
+		{"NoMatch", domain.AlbumSearch{ByTitle: "Nevermind", Batch: domain.Batch{Limit: 10}}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := albums.List(ctx, &tt.search)
+			if err != nil {
+				t.Fatalf("List: %v", err)
+			}
+			if titles := albumTitles(got); !slices.Equal(titles, tt.want) {
+				t.Errorf("List returned %v, want %v", titles, tt.want)
+			}
+		})
+	}
+}
+
+func testUpdateAlbum(t *testing.T, albums AlbumsStorage, artists ArtistsStorage, _ Storage) {
+	ctx := newContext()
+
+	muse := mustCreateArtist(t, artists, &domain.Artist{Name: "Muse"})
+	queen := mustCreateArtist(t, artists, &domain.Artist{Name: "Queen"})
+	album := mustCreateAlbum(t, albums, "Opera", muse.ID, nil)
+
+	updated, err := albums.Update(ctx, album.ID, &domain.AlbumUpdate{
+		Title:       "A Night at the Opera",
+		ArtistID:    queen.ID,
+		ReleaseDate: date(1975, time.November, 21),
+	})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if updated.Title != "A Night at the Opera" || updated.ArtistID != queen.ID || updated.Artist != "Queen" ||
+		updated.ReleaseDate == nil || !sameDate(*updated.ReleaseDate, date(1975, time.November, 21)) {
+		t.Errorf("Update returned %+v", updated)
+	}
+
+	// renaming the artist renames the artist of its albums
+	_, err = artists.Update(ctx, queen.ID, &domain.ArtistUpdate{Name: "QUEEN"})
+	if err != nil {
+		t.Fatalf("Update artist: %v", err)
+	}
+
+	got, err := albums.Get(ctx, album.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Artist != "QUEEN" {
+		t.Errorf("Get returned artist %q, want QUEEN", got.Artist)
+	}
+
+	_, err = albums.Update(ctx, album.ID, &domain.AlbumUpdate{ArtistID: uuid.New()})
+	if !errors.Is(err, domain.ErrUnknownArtist) {
+		t.Errorf("Update to unknown artist returned %v, want %v", err, domain.ErrUnknownArtist)
+	}
+
+	_, err = albums.Update(ctx, album.ID, &domain.AlbumUpdate{})
+	if !errors.Is(err, domain.ErrEmptyUpdate) {
+		t.Errorf("empty Update returned %v, want %v", err, domain.ErrEmptyUpdate)
+	}
+
+	_, err = albums.Update(ctx, uuid.New(), &domain.AlbumUpdate{Label: "EMI"})
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("Update of unknown album returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+}
+
+func testDeleteAlbum(t *testing.T, albums AlbumsStorage, artists ArtistsStorage, songs Storage) {
+	ctx := newContext()
+
+	artist := mustCreateArtist(t, artists, &domain.Artist{Name: "Muse"})
+	album := mustCreateAlbum(t, albums, "Black Holes and Revelations", artist.ID, nil)
+	id := mustCreate(t, songs, muse, nil)
+	mustSetTracks(t, albums, album.ID, &domain.Track{Song: *muse, TrackNumber: 2})
+
+	// artist of the album can't be deleted
+	err := songs.Delete(ctx, muse)
+	if err != nil {
+		t.Fatalf("Delete song: %v", err)
+	}
+	_, err = songs.Purge(ctx, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+
+	err = artists.Delete(ctx, artist.ID)
+	if !errors.Is(err, domain.ErrArtistHasSongs) {
+		t.Errorf("Delete of artist with albums returned %v, want %v", err, domain.ErrArtistHasSongs)
+	}
+
+	err = albums.Delete(ctx, album.ID)
+	if err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	_, err = albums.Get(ctx, album.ID)
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("Get of deleted album returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+
+	err = albums.Delete(ctx, album.ID)
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("Delete of deleted album returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+
+	err = artists.Delete(ctx, artist.ID)
+	if err != nil {
+		t.Errorf("Delete of artist without albums: %v", err)
+	}
+
+	// songs are kept after their album is deleted
+	second := mustCreateAlbum(t, albums, "Queen", mustCreateArtist(t, artists, &domain.Artist{Name: "Queen"}).ID, nil)
+	mustCreate(t, songs, queen, nil)
+	mustSetTracks(t, albums, second.ID, &domain.Track{Song: *queen, TrackNumber: 1})
+
+	err = albums.Delete(ctx, second.ID)
+	if err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	info, err := songs.Info(ctx, queen)
+	if err != nil {
+		t.Fatalf("Info of song of deleted album: %v", err)
+	}
+	if len(info.Albums) != 0 {
+		t.Errorf("Info returned albums %+v of deleted album", info.Albums)
+	}
+
+	_, err = songs.Info(ctx, &domain.Song{ID: id})
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("Info of purged song returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+}
+
+func testTracks(t *testing.T, albums AlbumsStorage, artists ArtistsStorage, songs Storage) {
+	ctx := newContext()
+
+	first := mustCreateAlbum(t, albums, "Greatest Hits", mustCreateArtist(t, artists, &domain.Artist{Name: "Various Artists"}).ID,
+		&domain.Album{ReleaseDate: ptr(date(2000, time.January, 1))})
+	second := mustCreateAlbum(t, albums, "Best Of", first.ArtistID, nil)
+	museID := mustCreate(t, songs, muse, nil)
+	queenID := mustCreate(t, songs, queen, nil)
+
+	// tracks are ordered by disc and track number, songs are found by id or by group and name
+	tracks, err := albums.SetTracks(ctx, first.ID, &domain.Tracklist{Tracks: []*domain.Track{
+		{Song: domain.Song{Group: "muse", SongName: muse.SongName}, DiscNumber: 2, TrackNumber: 1, Duration: 212},
+		{Song: domain.Song{ID: queenID}, TrackNumber: 3},
+	}})
+	if err != nil {
+		t.Fatalf("SetTracks: %v", err)
+	}
+
+	want := []domain.Track{
+		{Song: domain.Song{ID: queenID, Group: "Queen", SongName: queen.SongName}, DiscNumber: 1, TrackNumber: 3},
+		{Song: domain.Song{ID: museID, Group: "Muse", SongName: muse.SongName}, DiscNumber: 2, TrackNumber: 1, Duration: 212},
+	}
+	checkTracks(t, "SetTracks", tracks, want)
+
+	got, err := albums.GetTracks(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("GetTracks: %v", err)
+	}
+	checkTracks(t, "GetTracks", got, want)
+
+	mustSetTracks(t, albums, second.ID, &domain.Track{Song: *muse, TrackNumber: 5})
+
+	info, err := songs.Info(ctx, muse)
+	if err != nil {
+		t.Fatalf("Info: %v", err)
+	}
+	wantRefs := []domain.AlbumRef{
+		{ID: first.ID, Title: first.Title, DiscNumber: 2, TrackNumber: 1},
+		{ID: second.ID, Title: second.Title, DiscNumber: 1, TrackNumber: 5},
+	}
+	if len(info.Albums) != len(wantRefs) {
+		t.Fatalf("Info returned albums %+v, want %+v", info.Albums, wantRefs)
+	}
+	for i, ref := range info.Albums {
+		if ref.ID != wantRefs[i].ID || ref.Title != wantRefs[i].Title ||
+			ref.DiscNumber != wantRefs[i].DiscNumber || ref.TrackNumber != wantRefs[i].TrackNumber {
+			t.Errorf("Info returned album %+v, want %+v", ref, wantRefs[i])
+		}
+	}
+
+	// tracklist is replaced as a whole
+	tracks, err = albums.SetTracks(ctx, first.ID, &domain.Tracklist{Tracks: []*domain.Track{}})
+	if err != nil {
+		t.Fatalf("SetTracks: %v", err)
+	}
+	if len(tracks) != 0 {
+		t.Errorf("SetTracks of empty tracklist returned %+v", tracks)
+	}
+
+	_, err = albums.SetTracks(ctx, first.ID, &domain.Tracklist{Tracks: []*domain.Track{
+		{Song: *muse, TrackNumber: 1},
+		{Song: *queen, DiscNumber: 1, TrackNumber: 1},
+	}})
+	if !errors.Is(err, domain.ErrTrackPosition) {
+		t.Errorf("SetTracks with the same position returned %v, want %v", err, domain.ErrTrackPosition)
+	}
+
+	_, err = albums.SetTracks(ctx, first.ID, &domain.Tracklist{Tracks: []*domain.Track{
+		{Song: *beatles, TrackNumber: 1},
+	}})
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("SetTracks with unknown song returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+
+	// failed replacement keeps the tracklist
+	got, err = albums.GetTracks(ctx, second.ID)
+	if err != nil {
+		t.Fatalf("GetTracks: %v", err)
+	}
+	if len(got) != 1 {
+		t.Errorf("GetTracks returned %+v, want one track", got)
+	}
+
+	_, err = albums.GetTracks(ctx, uuid.New())
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("GetTracks of unknown album returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+
+	_, err = albums.SetTracks(ctx, uuid.New(), &domain.Tracklist{Tracks: []*domain.Track{}})
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("SetTracks of unknown album returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+}
+
+func testTracksOfDeletedSongs(t *testing.T, albums AlbumsStorage, artists ArtistsStorage, songs Storage) {
+	ctx := newContext()
+
+	album := mustCreateAlbum(t, albums, "Hits", mustCreateArtist(t, artists, &domain.Artist{Name: "Various Artists"}).ID, nil)
+	mustCreate(t, songs, muse, nil)
+	mustCreate(t, songs, queen, nil)
+	mustSetTracks(t, albums, album.ID,
+		&domain.Track{Song: *muse, TrackNumber: 1},
+		&domain.Track{Song: *queen, TrackNumber: 2},
+	)
+
+	// tracks of songs in trash are hidden and shown again after restore
+	err := songs.Delete(ctx, muse)
+	if err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	checkTrackCount(t, albums, album.ID, 1)
+
+	err = songs.Untrash(ctx, muse)
+	if err != nil {
+		t.Fatalf("Untrash: %v", err)
+	}
+	checkTrackCount(t, albums, album.ID, 2)
+
+	// tracks of purged songs are deleted
+	err = songs.Delete(ctx, muse)
+	if err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	_, err = songs.Purge(ctx, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+	mustCreate(t, songs, muse, nil)
+	checkTrackCount(t, albums, album.ID, 1)
+}
+
+func mustCreateAlbum(t *testing.T, st AlbumsStorage, title string, artistID uuid.UUID, album *domain.Album) *domain.Album {
+	t.Helper()
+
+	if album == nil {
+		album = &domain.Album{}
+	}
+	album.Title = title
+	album.ArtistID = artistID
+
+	created, err := st.Create(newContext(), album)
+	if err != nil {
+		t.Fatalf("Create album %s: %v", title, err)
+	}
+
+	return created
+}
+
+func mustSetTracks(t *testing.T, st AlbumsStorage, albumID uuid.UUID, tracks ...*domain.Track) {
+	t.Helper()
+
+	_, err := st.SetTracks(newContext(), albumID, &domain.Tracklist{Tracks: tracks})
+	if err != nil {
+		t.Fatalf("SetTracks: %v", err)
+	}
+}
+
+func checkTracks(t *testing.T, method string, got []*domain.Track, want []domain.Track) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("%s returned %d tracks, want %d", method, len(got), len(want))
+	}
+	for i := range want {
+		if *got[i] != want[i] {
+			t.Errorf("%s returned track %+v, want %+v", method, *got[i], want[i])
+		}
+	}
+}
+
+func checkTrackCount(t *testing.T, st AlbumsStorage, albumID uuid.UUID, want int) {
+	t.Helper()
+
+	tracks, err := st.GetTracks(newContext(), albumID)
+	if err != nil {
+		t.Fatalf("GetTracks: %v", err)
+	}
+	if len(tracks) != want {
+		t.Errorf("GetTracks returned %d tracks, want %d", len(tracks), want)
+	}
+}
+
+func albumTitles(albums []*domain.Album) []string {
+	titles := make([]string, 0, len(albums))
+	for _, album := range albums {
+		titles = append(titles, album.Title)
+	}
+	return titles
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
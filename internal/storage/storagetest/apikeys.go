@@ -0,0 +1,249 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+)
+
+// APIKeysStorage keeps API keys of service clients.
+type APIKeysStorage interface {
+	CreateKey(context.Context, *domain.APIKeyAccount) (*domain.APIKey, error)
+	Keys(context.Context, *domain.Batch) ([]*domain.APIKey, error)
+	KeyByPrefix(context.Context, string) (*domain.APIKeyAccount, error)
+	RotateKey(ctx context.Context, id uuid.UUID, prefix string, hash []byte) (*domain.APIKey, error)
+	RevokeKey(context.Context, uuid.UUID) (*domain.APIKey, error)
+	TouchKey(ctx context.Context, id uuid.UUID, usedAt time.Time) error
+}
+
+type NewAPIKeys func(t *testing.T) APIKeysStorage
+
+// Runs conformance tests of API keys storage against storages returned by newStorage.
+func RunAPIKeys(t *testing.T, newStorage NewAPIKeys) {
+	tests := []struct {
+		name string
+		test func(*testing.T, APIKeysStorage)
+	}{
+		{"CreateKey", testCreateKey},
+		{"RotateKey", testRotateKey},
+		{"RevokeKey", testRevokeKey},
+		{"TouchKey", testTouchKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newStorage(t))
+		})
+	}
+}
+
+func testCreateKey(t *testing.T, st APIKeysStorage) {
+	ctx := newContext()
+
+	createdBy := uuid.New()
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	key, err := st.CreateKey(ctx, &domain.APIKeyAccount{
+		APIKey: domain.APIKey{
+			Name:        "ingest",
+			Prefix:      "0123456789ab",
+			Permissions: []string{domain.PermissionSongsRead, domain.PermissionSongsCreate},
+			CreatedBy:   createdBy,
+			ExpiresAt:   &expiresAt,
+		},
+		Hash: []byte("hash"),
+	})
+	if err != nil {
+		t.Fatalf("CreateKey: %v", err)
+	}
+	if key.ID == uuid.Nil || key.Name != "ingest" || key.Prefix != "0123456789ab" || key.CreatedBy != createdBy || key.CreatedAt.IsZero() {
+		t.Fatalf("CreateKey returned %+v", key)
+	}
+	if !slices.Equal(key.Permissions, []string{domain.PermissionSongsRead, domain.PermissionSongsCreate}) {
+		t.Fatalf("permissions = %v, want songs:read and songs:create", key.Permissions)
+	}
+	if key.ExpiresAt == nil || !key.ExpiresAt.Equal(expiresAt) || key.LastUsedAt != nil || key.RevokedAt != nil {
+		t.Fatalf("CreateKey returned times %v, %v, %v", key.ExpiresAt, key.LastUsedAt, key.RevokedAt)
+	}
+
+	account, err := st.KeyByPrefix(ctx, "0123456789ab")
+	if err != nil {
+		t.Fatalf("KeyByPrefix: %v", err)
+	}
+	if account.ID != key.ID || string(account.Hash) != "hash" {
+		t.Fatalf("KeyByPrefix returned %+v", account)
+	}
+
+	_, err = st.KeyByPrefix(ctx, "ffffffffffff")
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Fatalf("KeyByPrefix of unknown prefix: got %v, want ErrUnknownResourse", err)
+	}
+
+	_, err = st.CreateKey(ctx, &domain.APIKeyAccount{
+		APIKey: domain.APIKey{
+			Name:        "duplicate",
+			Prefix:      "0123456789ab",
+			Permissions: []string{domain.PermissionSongsRead},
+			CreatedBy:   createdBy,
+		},
+		Hash: []byte("other"),
+	})
+	if err == nil {
+		t.Fatal("CreateKey with existing prefix succeeded")
+	}
+
+	second := mustCreateKey(t, st, "export", "ba9876543210")
+	if second.ExpiresAt != nil {
+		t.Fatalf("key without expiration expires at %v", second.ExpiresAt)
+	}
+
+	keys, err := st.Keys(ctx, &domain.Batch{Limit: 10})
+	if err != nil {
+		t.Fatalf("Keys: %v", err)
+	}
+	if len(keys) != 2 || keys[0].ID != key.ID || keys[1].ID != second.ID {
+		t.Fatalf("Keys returned %+v, want ingest and export in order of creation", keys)
+	}
+
+	keys, err = st.Keys(ctx, &domain.Batch{Offset: 1, Limit: 10})
+	if err != nil {
+		t.Fatalf("Keys with offset: %v", err)
+	}
+	if len(keys) != 1 || keys[0].ID != second.ID {
+		t.Fatalf("Keys with offset returned %+v, want export", keys)
+	}
+}
+
+func testRotateKey(t *testing.T, st APIKeysStorage) {
+	ctx := newContext()
+
+	key := mustCreateKey(t, st, "ingest", "0123456789ab")
+
+	err := st.TouchKey(ctx, key.ID, time.Now())
+	if err != nil {
+		t.Fatalf("TouchKey: %v", err)
+	}
+
+	rotated, err := st.RotateKey(ctx, key.ID, "ba9876543210", []byte("new hash"))
+	if err != nil {
+		t.Fatalf("RotateKey: %v", err)
+	}
+	if rotated.ID != key.ID || rotated.Prefix != "ba9876543210" || rotated.Name != "ingest" || rotated.LastUsedAt != nil {
+		t.Fatalf("RotateKey returned %+v", rotated)
+	}
+
+	_, err = st.KeyByPrefix(ctx, "0123456789ab")
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Fatalf("KeyByPrefix of replaced prefix: got %v, want ErrUnknownResourse", err)
+	}
+
+	account, err := st.KeyByPrefix(ctx, "ba9876543210")
+	if err != nil {
+		t.Fatalf("KeyByPrefix of new prefix: %v", err)
+	}
+	if account.ID != key.ID || string(account.Hash) != "new hash" {
+		t.Fatalf("KeyByPrefix returned %+v", account)
+	}
+
+	_, err = st.RotateKey(ctx, uuid.New(), "aaaaaaaaaaaa", []byte("hash"))
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Fatalf("RotateKey of unknown key: got %v, want ErrUnknownResourse", err)
+	}
+
+	_, err = st.RevokeKey(ctx, key.ID)
+	if err != nil {
+		t.Fatalf("RevokeKey: %v", err)
+	}
+
+	_, err = st.RotateKey(ctx, key.ID, "aaaaaaaaaaaa", []byte("hash"))
+	if !errors.Is(err, domain.ErrKeyRevoked) {
+		t.Fatalf("RotateKey of revoked key: got %v, want ErrKeyRevoked", err)
+	}
+}
+
+func testRevokeKey(t *testing.T, st APIKeysStorage) {
+	ctx := newContext()
+
+	key := mustCreateKey(t, st, "ingest", "0123456789ab")
+
+	revoked, err := st.RevokeKey(ctx, key.ID)
+	if err != nil {
+		t.Fatalf("RevokeKey: %v", err)
+	}
+	if revoked.RevokedAt == nil {
+		t.Fatal("key is not revoked")
+	}
+
+	// revoking twice is not an error and keeps the time of revocation
+	again, err := st.RevokeKey(ctx, key.ID)
+	if err != nil {
+		t.Fatalf("RevokeKey of revoked key: %v", err)
+	}
+	if again.RevokedAt == nil || !again.RevokedAt.Equal(*revoked.RevokedAt) {
+		t.Fatalf("revoked at %v after second revoke, want %v", again.RevokedAt, revoked.RevokedAt)
+	}
+
+	account, err := st.KeyByPrefix(ctx, "0123456789ab")
+	if err != nil {
+		t.Fatalf("KeyByPrefix of revoked key: %v", err)
+	}
+	if account.RevokedAt == nil || account.Active(time.Now()) {
+		t.Fatalf("KeyByPrefix returned active key %+v", account)
+	}
+
+	_, err = st.RevokeKey(ctx, uuid.New())
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Fatalf("RevokeKey of unknown key: got %v, want ErrUnknownResourse", err)
+	}
+}
+
+func testTouchKey(t *testing.T, st APIKeysStorage) {
+	ctx := newContext()
+
+	key := mustCreateKey(t, st, "ingest", "0123456789ab")
+
+	usedAt := time.Now().Truncate(time.Second)
+
+	err := st.TouchKey(ctx, key.ID, usedAt)
+	if err != nil {
+		t.Fatalf("TouchKey: %v", err)
+	}
+
+	// earlier use doesn't replace the later one
+	err = st.TouchKey(ctx, key.ID, usedAt.Add(-time.Minute))
+	if err != nil {
+		t.Fatalf("TouchKey with earlier time: %v", err)
+	}
+
+	account, err := st.KeyByPrefix(ctx, "0123456789ab")
+	if err != nil {
+		t.Fatalf("KeyByPrefix: %v", err)
+	}
+	if account.LastUsedAt == nil || !account.LastUsedAt.Equal(usedAt) {
+		t.Fatalf("last used at %v, want %v", account.LastUsedAt, usedAt)
+	}
+}
+
+func mustCreateKey(t *testing.T, st APIKeysStorage, name, prefix string) *domain.APIKey {
+	t.Helper()
+
+	key, err := st.CreateKey(newContext(), &domain.APIKeyAccount{
+		APIKey: domain.APIKey{
+			Name:        name,
+			Prefix:      prefix,
+			Permissions: []string{domain.PermissionSongsRead},
+			CreatedBy:   uuid.New(),
+		},
+		Hash: []byte("hash"),
+	})
+	if err != nil {
+		t.Fatalf("CreateKey(%s): %v", name, err)
+	}
+
+	return key
+}
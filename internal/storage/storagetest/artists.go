@@ -0,0 +1,282 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+)
+
+// ArtistsStorage keeps artists, which are groups and credited artists of songs.
+type ArtistsStorage interface {
+	Create(context.Context, *domain.Artist) (*domain.Artist, error)
+	Get(context.Context, uuid.UUID) (*domain.Artist, error)
+	List(context.Context, *domain.ArtistSearch) ([]*domain.Artist, error)
+	Update(context.Context, uuid.UUID, *domain.ArtistUpdate) (*domain.Artist, error)
+	Delete(context.Context, uuid.UUID) error
+}
+
+// Songs refer to artists, so both storages must share the database.
+type NewArtists func(t *testing.T) (ArtistsStorage, Storage)
+
+// Runs conformance tests of artists against storages returned by newStorage.
+func RunArtists(t *testing.T, newStorage NewArtists) {
+	tests := []struct {
+		name string
+		test func(*testing.T, ArtistsStorage, Storage)
+	}{
+		{"CreateAndGet", testCreateArtist},
+		{"ListArtists", testListArtists},
+		{"UpdateArtist", testUpdateArtist},
+		{"DeleteArtist", testDeleteArtist},
+		{"SongsOfArtists", testSongsOfArtists},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			artists, songs := newStorage(t)
+			tt.test(t, artists, songs)
+		})
+	}
+}
+
+func testCreateArtist(t *testing.T, artists ArtistsStorage, _ Storage) {
+	ctx := newContext()
+
+	formed := date(1994, time.January, 1)
+
+	created, err := artists.Create(ctx, &domain.Artist{Name: "Muse", Country: "GB", Formed: &formed, Bio: "Rock band"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if created.ID == uuid.Nil || created.Name != "Muse" || created.Aliases == nil || len(created.Aliases) != 0 {
+		t.Errorf("Create returned %+v, want new Muse without aliases", created)
+	}
+
+	got, err := artists.Get(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Name != "Muse" || got.Country != "GB" || got.Bio != "Rock band" ||
+		got.Formed == nil || !sameDate(*got.Formed, formed) || got.Disbanded != nil {
+		t.Errorf("Get returned %+v", got)
+	}
+
+	_, err = artists.Create(ctx, &domain.Artist{Name: "MUSE"})
+	if !errors.Is(err, domain.ErrArtistExists) {
+		t.Errorf("Create with existing name returned %v, want %v", err, domain.ErrArtistExists)
+	}
+
+	disbanded := date(1990, time.January, 1)
+	_, err = artists.Create(ctx, &domain.Artist{Name: "Queen", Formed: &formed, Disbanded: &disbanded})
+	if !errors.Is(err, domain.ErrArtistDates) {
+		t.Errorf("Create disbanded before formed returned %v, want %v", err, domain.ErrArtistDates)
+	}
+
+	_, err = artists.Get(ctx, uuid.New())
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("Get of unknown artist returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+}
+
+func testListArtists(t *testing.T, artists ArtistsStorage, _ Storage) {
+	ctx := newContext()
+
+	mustCreateArtist(t, artists, &domain.Artist{Name: "The Beatles", Aliases: []string{"Fab Four"}})
+	mustCreateArtist(t, artists, &domain.Artist{Name: "Queen"})
+	mustCreateArtist(t, artists, &domain.Artist{Name: "Muse"})
+
+	tests := []struct {
+		name   string
+		search domain.ArtistSearch
+		want   []string
+	}{
+		{"All", domain.ArtistSearch{Batch: domain.Batch{Limit: 10}}, []string{"Muse", "Queen", "The Beatles"}},
+		{"Page", domain.ArtistSearch{Batch: domain.Batch{Offset: 1, Limit: 1}}, []string{"Queen"}},
+		{"ByName", domain.ArtistSearch{ByName: "UE", Batch: domain.Batch{Limit: 10}}, []string{"Queen"}},
+		{"ByAlias", domain.ArtistSearch{ByName: "fab", Batch: domain.Batch{Limit: 10}}, []string{"The Beatles"}},
+		{"NoMatch", domain.ArtistSearch{ByName: "Nirvana", Batch: domain.Batch{Limit: 10}}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := artists.List(ctx, &tt.search)
+			if err != nil {
+				t.Fatalf("List: %v", err)
+			}
+			if names := artistNames(got); !slices.Equal(names, tt.want) {
+				t.Errorf("List returned %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func testUpdateArtist(t *testing.T, artists ArtistsStorage, songs Storage) {
+	ctx := newContext()
+
+	id := mustCreate(t, songs, muse, nil)
+	artist := songArtist(t, artists, muse.Group)
+	mustCreateArtist(t, artists, &domain.Artist{Name: "Queen"})
+
+	updated, err := artists.Update(ctx, artist.ID, &domain.ArtistUpdate{Name: "MUSE", Aliases: []string{"Rocket Baby Dolls"}})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if updated.Name != "MUSE" || !slices.Equal(updated.Aliases, []string{"Rocket Baby Dolls"}) {
+		t.Errorf("Update returned %+v", updated)
+	}
+
+	// renaming the artist renames the group of its songs
+	info, err := songs.Info(ctx, &domain.Song{ID: id})
+	if err != nil {
+		t.Fatalf("Info: %v", err)
+	}
+	if info.Group != "MUSE" {
+		t.Errorf("Info returned group %q, want MUSE", info.Group)
+	}
+
+	_, err = songs.Info(ctx, &domain.Song{Group: "Rocket Baby Dolls", SongName: muse.SongName})
+	if err != nil {
+		t.Errorf("Info by alias of the group: %v", err)
+	}
+
+	_, err = artists.Update(ctx, artist.ID, &domain.ArtistUpdate{Name: "queen"})
+	if !errors.Is(err, domain.ErrArtistExists) {
+		t.Errorf("Update to existing name returned %v, want %v", err, domain.ErrArtistExists)
+	}
+
+	_, err = artists.Update(ctx, artist.ID, &domain.ArtistUpdate{
+		Formed:    date(2000, time.January, 1),
+		Disbanded: date(1999, time.January, 1),
+	})
+	if !errors.Is(err, domain.ErrArtistDates) {
+		t.Errorf("Update disbanded before formed returned %v, want %v", err, domain.ErrArtistDates)
+	}
+
+	_, err = artists.Update(ctx, artist.ID, &domain.ArtistUpdate{})
+	if !errors.Is(err, domain.ErrEmptyUpdate) {
+		t.Errorf("empty Update returned %v, want %v", err, domain.ErrEmptyUpdate)
+	}
+
+	_, err = artists.Update(ctx, uuid.New(), &domain.ArtistUpdate{Bio: "unknown"})
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("Update of unknown artist returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+}
+
+func testDeleteArtist(t *testing.T, artists ArtistsStorage, songs Storage) {
+	ctx := newContext()
+
+	mustCreate(t, songs, muse, nil)
+	group := songArtist(t, artists, muse.Group)
+
+	err := artists.Delete(ctx, group.ID)
+	if !errors.Is(err, domain.ErrArtistHasSongs) {
+		t.Errorf("Delete of artist with songs returned %v, want %v", err, domain.ErrArtistHasSongs)
+	}
+
+	// songs in trash still refer to the artist
+	err = songs.Delete(ctx, muse)
+	if err != nil {
+		t.Fatalf("Delete song: %v", err)
+	}
+
+	err = artists.Delete(ctx, group.ID)
+	if !errors.Is(err, domain.ErrArtistHasSongs) {
+		t.Errorf("Delete of artist with songs in trash returned %v, want %v", err, domain.ErrArtistHasSongs)
+	}
+
+	mustCreate(t, songs, queen, nil)
+	mustSetCredits(t, songs, queen, domain.Credit{Artist: "Freddie Mercury", Role: domain.CreditLyricist})
+	credited := songArtist(t, artists, "Freddie Mercury")
+
+	err = artists.Delete(ctx, credited.ID)
+	if !errors.Is(err, domain.ErrArtistHasSongs) {
+		t.Errorf("Delete of credited artist returned %v, want %v", err, domain.ErrArtistHasSongs)
+	}
+
+	mustSetCredits(t, songs, queen)
+
+	err = artists.Delete(ctx, credited.ID)
+	if err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	_, err = artists.Get(ctx, credited.ID)
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("Get of deleted artist returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+
+	err = artists.Delete(ctx, credited.ID)
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("Delete of deleted artist returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+}
+
+func testSongsOfArtists(t *testing.T, artists ArtistsStorage, songs Storage) {
+	ctx := newContext()
+
+	mustCreateArtist(t, artists, &domain.Artist{Name: "The Beatles", Aliases: []string{"Fab Four"}})
+
+	// song of the alias belongs to the artist
+	id := mustCreate(t, songs, &domain.Song{Group: "fab four", SongName: "Let It Be"}, nil)
+
+	info, err := songs.Info(ctx, &domain.Song{ID: id})
+	if err != nil {
+		t.Fatalf("Info: %v", err)
+	}
+	if info.Group != "The Beatles" {
+		t.Errorf("Info returned group %q, want The Beatles", info.Group)
+	}
+
+	// artists of new groups are created with songs
+	mustCreate(t, songs, muse, nil)
+
+	list, err := artists.List(ctx, &domain.ArtistSearch{Batch: domain.Batch{Limit: 10}})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if names := artistNames(list); !slices.Equal(names, []string{"Muse", "The Beatles"}) {
+		t.Errorf("List returned %v, want [Muse The Beatles]", names)
+	}
+}
+
+func mustCreateArtist(t *testing.T, st ArtistsStorage, artist *domain.Artist) *domain.Artist {
+	t.Helper()
+
+	created, err := st.Create(newContext(), artist)
+	if err != nil {
+		t.Fatalf("Create artist %s: %v", artist.Name, err)
+	}
+
+	return created
+}
+
+// Returns the artist with exactly this name.
+func songArtist(t *testing.T, st ArtistsStorage, name string) *domain.Artist {
+	t.Helper()
+
+	list, err := st.List(newContext(), &domain.ArtistSearch{ByName: name, Batch: domain.Batch{Limit: 10}})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+
+	i := slices.IndexFunc(list, func(artist *domain.Artist) bool { return artist.Name == name })
+	if i == -1 {
+		t.Fatalf("artist %s not found", name)
+	}
+
+	return list[i]
+}
+
+func artistNames(artists []*domain.Artist) []string {
+	names := make([]string, 0, len(artists))
+	for _, artist := range artists {
+		names = append(names, artist.Name)
+	}
+	return names
+}
@@ -0,0 +1,137 @@
+package storagetest
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/qreaqtor/music-library/internal/domain"
+)
+
+func testCredits(t *testing.T, st Storage) {
+	ctx := newContext()
+
+	mustCreate(t, st, muse, nil)
+
+	info, err := st.Info(ctx, muse)
+	if err != nil {
+		t.Fatalf("Info: %v", err)
+	}
+	if len(info.Credits) != 0 {
+		t.Errorf("Info of new song returned credits %v, want none", info.Credits)
+	}
+
+	// artist of the group keeps its spelling, duplicates are ignored
+	credits := []domain.Credit{
+		{Artist: "muse", Role: domain.CreditPrimary},
+		{Artist: "Matt Bellamy", Role: domain.CreditComposer},
+		{Artist: "matt bellamy", Role: domain.CreditLyricist},
+		{Artist: "Rich Costey", Role: domain.CreditProducer},
+		{Artist: "MATT BELLAMY", Role: domain.CreditComposer},
+	}
+
+	err = st.SetCredits(ctx, muse, credits)
+	if err != nil {
+		t.Fatalf("SetCredits: %v", err)
+	}
+
+	info, err = st.Info(ctx, muse)
+	if err != nil {
+		t.Fatalf("Info: %v", err)
+	}
+
+	want := []domain.Credit{
+		{Artist: "Muse", Role: domain.CreditPrimary},
+		{Artist: "Matt Bellamy", Role: domain.CreditComposer},
+		{Artist: "Matt Bellamy", Role: domain.CreditLyricist},
+		{Artist: "Rich Costey", Role: domain.CreditProducer},
+	}
+	if !slices.Equal(info.Credits, want) {
+		t.Errorf("Info returned credits %v, want %v", info.Credits, want)
+	}
+
+	// credits are replaced
+	err = st.SetCredits(ctx, muse, []domain.Credit{{Artist: "Rich Costey", Role: domain.CreditProducer}})
+	if err != nil {
+		t.Fatalf("SetCredits: %v", err)
+	}
+
+	info, err = st.Info(ctx, muse)
+	if err != nil {
+		t.Fatalf("Info: %v", err)
+	}
+	if want := []domain.Credit{{Artist: "Rich Costey", Role: domain.CreditProducer}}; !slices.Equal(info.Credits, want) {
+		t.Errorf("Info returned credits %v, want %v", info.Credits, want)
+	}
+
+	err = st.SetCredits(ctx, queen, credits)
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("SetCredits of unknown song returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+}
+
+func testSearchByCredit(t *testing.T, st Storage) {
+	ctx := newContext()
+
+	mustCreate(t, st, muse, museDetails)
+	mustCreate(t, st, queen, queenDetails)
+	mustCreate(t, st, beatles, beatlesDetails)
+
+	mustSetCredits(t, st, muse,
+		domain.Credit{Artist: "Matt Bellamy", Role: domain.CreditComposer},
+		domain.Credit{Artist: "Matt Bellamy", Role: domain.CreditLyricist},
+	)
+	mustSetCredits(t, st, queen,
+		domain.Credit{Artist: "Freddie Mercury", Role: domain.CreditLyricist},
+		domain.Credit{Artist: "Roy Thomas Baker", Role: domain.CreditProducer},
+	)
+	mustSetCredits(t, st, beatles,
+		domain.Credit{Artist: "Paul McCartney", Role: domain.CreditLyricist},
+		domain.Credit{Artist: "Matt Bellamy", Role: domain.CreditFeaturing},
+	)
+
+	tests := []struct {
+		name   string
+		search domain.SongSearch
+		want   []*domain.Song
+	}{
+		{"Artist", domain.SongSearch{Credit: "matt bellamy"}, []*domain.Song{muse, beatles}},
+		{"ArtistInRole", domain.SongSearch{Credit: "Matt Bellamy", CreditRole: domain.CreditLyricist}, []*domain.Song{muse}},
+		{"ArtistNotInRole", domain.SongSearch{Credit: "Freddie Mercury", CreditRole: domain.CreditProducer}, []*domain.Song{}},
+		{"Role", domain.SongSearch{CreditRole: domain.CreditProducer}, []*domain.Song{queen}},
+		{"NotSubstring", domain.SongSearch{Credit: "Bellamy"}, []*domain.Song{}},
+		{"WithGroup", domain.SongSearch{ByGroup: "beatles", Credit: "Matt Bellamy"}, []*domain.Song{beatles}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			search := tt.search
+			search.Batch = domain.Batch{Offset: 0, Limit: 10}
+
+			got, err := st.Search(ctx, &search)
+			if err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+			if !sameSongs(got, tt.want) {
+				t.Errorf("Search returned %v, want %v", foundNames(got), songNames(tt.want))
+			}
+
+			total, err := st.Count(ctx, &search)
+			if err != nil {
+				t.Fatalf("Count: %v", err)
+			}
+			if total != len(tt.want) {
+				t.Errorf("Count returned %d, want %d", total, len(tt.want))
+			}
+		})
+	}
+}
+
+func mustSetCredits(t *testing.T, st Storage, song *domain.Song, credits ...domain.Credit) {
+	t.Helper()
+
+	err := st.SetCredits(newContext(), song, credits)
+	if err != nil {
+		t.Fatalf("SetCredits(%s): %v", song.SongName, err)
+	}
+}
@@ -0,0 +1,446 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+)
+
+// FeedbackStorage is songs storage with favorites, ratings and plays of users.
+type FeedbackStorage interface {
+	Storage
+
+	AddFavorite(ctx context.Context, song *domain.Song, userID uuid.UUID) error
+	RemoveFavorite(ctx context.Context, song *domain.Song, userID uuid.UUID) error
+	Favorites(ctx context.Context, userID uuid.UUID, batch *domain.Batch) ([]*domain.FavoriteSong, error)
+	Rate(ctx context.Context, song *domain.Song, userID uuid.UUID, rating int) error
+	Unrate(ctx context.Context, song *domain.Song, userID uuid.UUID) error
+	AddPlay(ctx context.Context, song *domain.Song, userID uuid.UUID, playedAt time.Time) error
+	Plays(ctx context.Context, userID uuid.UUID, batch *domain.Batch) ([]*domain.Play, error)
+	TopSongs(ctx context.Context, since time.Time, batch *domain.Batch) ([]*domain.TopSong, error)
+	Feedback(ctx context.Context, songID, userID uuid.UUID) (*domain.SongFeedback, error)
+}
+
+// Feedback references users, so both storages must share the database.
+type NewFeedback func(t *testing.T) (FeedbackStorage, UsersStorage)
+
+// Runs conformance tests of feedback against storages returned by newStorage.
+func RunFeedback(t *testing.T, newStorage NewFeedback) {
+	tests := []struct {
+		name string
+		test func(*testing.T, FeedbackStorage, UsersStorage)
+	}{
+		{"Favorites", testFavorites},
+		{"Ratings", testRatings},
+		{"Plays", testPlays},
+		{"TopSongs", testTopSongs},
+		{"SearchByFeedback", testSearchByFeedback},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, users := newStorage(t)
+			tt.test(t, st, users)
+		})
+	}
+}
+
+func testFavorites(t *testing.T, st FeedbackStorage, users UsersStorage) {
+	ctx := newContext()
+	batch := &domain.Batch{Offset: 0, Limit: 10}
+
+	alice := mustCreateUser(t, users, "alice")
+	bob := mustCreateUser(t, users, "bob")
+
+	museID := mustCreate(t, st, muse, nil)
+	mustCreate(t, st, queen, nil)
+
+	for _, favorite := range []struct {
+		song   *domain.Song
+		userID uuid.UUID
+	}{
+		{muse, alice.ID},
+		{queen, alice.ID},
+		// adding again is not an error and doesn't count twice
+		{muse, alice.ID},
+		{muse, bob.ID},
+	} {
+		err := st.AddFavorite(ctx, favorite.song, favorite.userID)
+		if err != nil {
+			t.Fatalf("AddFavorite(%s): %v", favorite.song.SongName, err)
+		}
+
+		// favorites are ordered by time of adding, so it must differ
+		time.Sleep(time.Millisecond)
+	}
+
+	err := st.AddFavorite(ctx, beatles, alice.ID)
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("AddFavorite of unknown song returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+
+	favorites, err := st.Favorites(ctx, alice.ID, batch)
+	if err != nil {
+		t.Fatalf("Favorites: %v", err)
+	}
+	if len(favorites) != 2 || favorites[0].SongName != queen.SongName || favorites[1].SongName != muse.SongName {
+		t.Errorf("Favorites returned %v, want %s and %s", favorites, queen.SongName, muse.SongName)
+	}
+
+	info, err := st.Info(ctx, muse)
+	if err != nil {
+		t.Fatalf("Info: %v", err)
+	}
+	if info.Stats.Favorites != 2 {
+		t.Errorf("Info returned %d favorites, want 2", info.Stats.Favorites)
+	}
+
+	err = st.RemoveFavorite(ctx, muse, alice.ID)
+	if err != nil {
+		t.Fatalf("RemoveFavorite: %v", err)
+	}
+	err = st.RemoveFavorite(ctx, muse, alice.ID)
+	if err != nil {
+		t.Fatalf("RemoveFavorite of removed favorite: %v", err)
+	}
+
+	feedback, err := st.Feedback(ctx, museID, alice.ID)
+	if err != nil {
+		t.Fatalf("Feedback: %v", err)
+	}
+	if feedback.Favorite {
+		t.Error("Feedback returned removed favorite")
+	}
+
+	info, err = st.Info(ctx, muse)
+	if err != nil {
+		t.Fatalf("Info: %v", err)
+	}
+	if info.Stats.Favorites != 1 {
+		t.Errorf("Info returned %d favorites after removal, want 1", info.Stats.Favorites)
+	}
+
+	// songs in trash are not listed
+	err = st.Delete(ctx, queen)
+	if err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	favorites, err = st.Favorites(ctx, alice.ID, batch)
+	if err != nil {
+		t.Fatalf("Favorites: %v", err)
+	}
+	if len(favorites) != 0 {
+		t.Errorf("Favorites returned %v, want none", favorites)
+	}
+}
+
+func testRatings(t *testing.T, st FeedbackStorage, users UsersStorage) {
+	ctx := newContext()
+
+	alice := mustCreateUser(t, users, "alice")
+	bob := mustCreateUser(t, users, "bob")
+
+	museID := mustCreate(t, st, muse, nil)
+
+	info, err := st.Info(ctx, muse)
+	if err != nil {
+		t.Fatalf("Info: %v", err)
+	}
+	if info.Stats.Ratings != 0 || info.Stats.AverageRating != 0 {
+		t.Errorf("Info returned %+v for song without ratings", info.Stats)
+	}
+
+	for _, rate := range []struct {
+		userID uuid.UUID
+		rating int
+	}{
+		{alice.ID, 2},
+		{bob.ID, 5},
+		// the previous rating is replaced
+		{alice.ID, 4},
+	} {
+		err = st.Rate(ctx, muse, rate.userID, rate.rating)
+		if err != nil {
+			t.Fatalf("Rate: %v", err)
+		}
+	}
+
+	info, err = st.Info(ctx, muse)
+	if err != nil {
+		t.Fatalf("Info: %v", err)
+	}
+	if info.Stats.Ratings != 2 || info.Stats.AverageRating != 4.5 {
+		t.Errorf("Info returned %+v, want 2 ratings with average 4.5", info.Stats)
+	}
+
+	feedback, err := st.Feedback(ctx, museID, alice.ID)
+	if err != nil {
+		t.Fatalf("Feedback: %v", err)
+	}
+	if feedback.Rating != 4 {
+		t.Errorf("Feedback returned rating %d, want 4", feedback.Rating)
+	}
+
+	err = st.Unrate(ctx, muse, bob.ID)
+	if err != nil {
+		t.Fatalf("Unrate: %v", err)
+	}
+	err = st.Unrate(ctx, muse, bob.ID)
+	if err != nil {
+		t.Fatalf("Unrate of removed rating: %v", err)
+	}
+
+	info, err = st.Info(ctx, muse)
+	if err != nil {
+		t.Fatalf("Info: %v", err)
+	}
+	if info.Stats.Ratings != 1 || info.Stats.AverageRating != 4 {
+		t.Errorf("Info returned %+v after removal, want 1 rating with average 4", info.Stats)
+	}
+
+	err = st.Rate(ctx, queen, alice.ID, 3)
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("Rate of unknown song returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+
+	_, err = st.Feedback(ctx, uuid.New(), alice.ID)
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("Feedback of unknown song returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+}
+
+func testPlays(t *testing.T, st FeedbackStorage, users UsersStorage) {
+	ctx := newContext()
+	batch := &domain.Batch{Offset: 0, Limit: 10}
+	now := time.Now().Truncate(time.Second)
+
+	alice := mustCreateUser(t, users, "alice")
+	bob := mustCreateUser(t, users, "bob")
+
+	mustCreate(t, st, muse, nil)
+	mustCreate(t, st, queen, nil)
+
+	for _, play := range []struct {
+		song     *domain.Song
+		userID   uuid.UUID
+		playedAt time.Time
+	}{
+		{muse, alice.ID, now.Add(-3 * time.Hour)},
+		{queen, alice.ID, now.Add(-time.Hour)},
+		{muse, alice.ID, now.Add(-2 * time.Hour)},
+		{queen, bob.ID, now},
+	} {
+		err := st.AddPlay(ctx, play.song, play.userID, play.playedAt)
+		if err != nil {
+			t.Fatalf("AddPlay(%s): %v", play.song.SongName, err)
+		}
+	}
+
+	plays, err := st.Plays(ctx, alice.ID, batch)
+	if err != nil {
+		t.Fatalf("Plays: %v", err)
+	}
+
+	got := make([]string, 0, len(plays))
+	for _, play := range plays {
+		got = append(got, play.SongName)
+	}
+	want := []string{queen.SongName, muse.SongName, muse.SongName}
+	if !slices.Equal(got, want) {
+		t.Errorf("Plays returned %v, want %v", got, want)
+	}
+	if len(plays) != 0 && !plays[0].PlayedAt.Equal(now.Add(-time.Hour)) {
+		t.Errorf("Plays returned the last play at %v, want %v", plays[0].PlayedAt, now.Add(-time.Hour))
+	}
+
+	plays, err = st.Plays(ctx, alice.ID, &domain.Batch{Offset: 1, Limit: 1})
+	if err != nil {
+		t.Fatalf("Plays: %v", err)
+	}
+	if len(plays) != 1 || plays[0].SongName != muse.SongName {
+		t.Errorf("Plays with offset returned %v, want %s", plays, muse.SongName)
+	}
+
+	info, err := st.Info(ctx, muse)
+	if err != nil {
+		t.Fatalf("Info: %v", err)
+	}
+	if info.Stats.Plays != 2 {
+		t.Errorf("Info returned %d plays, want 2", info.Stats.Plays)
+	}
+
+	err = st.AddPlay(ctx, beatles, alice.ID, now)
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("AddPlay of unknown song returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+}
+
+func testTopSongs(t *testing.T, st FeedbackStorage, users UsersStorage) {
+	ctx := newContext()
+	batch := &domain.Batch{Offset: 0, Limit: 10}
+	now := time.Now()
+
+	alice := mustCreateUser(t, users, "alice")
+
+	mustCreate(t, st, muse, nil)
+	mustCreate(t, st, queen, nil)
+	mustCreate(t, st, beatles, nil)
+
+	// muse was played more, but long ago
+	for _, play := range []struct {
+		song     *domain.Song
+		playedAt time.Time
+	}{
+		{muse, now.AddDate(0, 0, -30)},
+		{muse, now.AddDate(0, 0, -30)},
+		{muse, now.AddDate(0, 0, -30)},
+		{queen, now.Add(-time.Hour)},
+		{queen, now.Add(-time.Hour)},
+		{muse, now.Add(-time.Hour)},
+	} {
+		err := st.AddPlay(ctx, play.song, alice.ID, play.playedAt)
+		if err != nil {
+			t.Fatalf("AddPlay(%s): %v", play.song.SongName, err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		since time.Time
+		want  []string
+		plays []int
+	}{
+		{"All", time.Time{}, []string{muse.SongName, queen.SongName}, []int{4, 2}},
+		{"Week", now.AddDate(0, 0, -7), []string{queen.SongName, muse.SongName}, []int{2, 1}},
+		{"Future", now.Add(time.Hour), []string{}, []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			top, err := st.TopSongs(ctx, tt.since, batch)
+			if err != nil {
+				t.Fatalf("TopSongs: %v", err)
+			}
+
+			names := make([]string, 0, len(top))
+			plays := make([]int, 0, len(top))
+			for _, song := range top {
+				names = append(names, song.SongName)
+				plays = append(plays, song.Plays)
+			}
+
+			if !slices.Equal(names, tt.want) || !slices.Equal(plays, tt.plays) {
+				t.Errorf("TopSongs returned %v with plays %v, want %v with %v", names, plays, tt.want, tt.plays)
+			}
+		})
+	}
+
+	// songs in trash are not listed
+	err := st.Delete(ctx, muse)
+	if err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	top, err := st.TopSongs(ctx, time.Time{}, batch)
+	if err != nil {
+		t.Fatalf("TopSongs: %v", err)
+	}
+	if len(top) != 1 || top[0].SongName != queen.SongName {
+		t.Errorf("TopSongs returned %v after delete, want %s", top, queen.SongName)
+	}
+}
+
+func testSearchByFeedback(t *testing.T, st FeedbackStorage, users UsersStorage) {
+	ctx := newContext()
+
+	alice := mustCreateUser(t, users, "alice")
+	bob := mustCreateUser(t, users, "bob")
+
+	mustCreate(t, st, muse, museDetails)
+	mustCreate(t, st, queen, queenDetails)
+	mustCreate(t, st, beatles, beatlesDetails)
+
+	for _, song := range []*domain.Song{queen, queen, beatles} {
+		err := st.AddPlay(ctx, song, alice.ID, time.Now())
+		if err != nil {
+			t.Fatalf("AddPlay(%s): %v", song.SongName, err)
+		}
+	}
+
+	for _, rate := range []struct {
+		song   *domain.Song
+		userID uuid.UUID
+		rating int
+	}{
+		{muse, alice.ID, 5},
+		{muse, bob.ID, 4},
+		{beatles, alice.ID, 3},
+	} {
+		err := st.Rate(ctx, rate.song, rate.userID, rate.rating)
+		if err != nil {
+			t.Fatalf("Rate(%s): %v", rate.song.SongName, err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		search domain.SongSearch
+		want   []*domain.Song
+	}{
+		{"Plays", domain.SongSearch{Sort: domain.SortByPlays}, []*domain.Song{queen, beatles, muse}},
+		{"Rating", domain.SongSearch{Sort: domain.SortByRating}, []*domain.Song{muse, beatles, queen}},
+		{"RatingAsc", domain.SongSearch{Sort: domain.SortByRating, Order: domain.OrderAsc}, []*domain.Song{queen, beatles, muse}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			search := tt.search
+			search.Batch = domain.Batch{Offset: 0, Limit: 10}
+
+			got, err := st.Search(ctx, &search)
+			if err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+
+			gotNames := make([]string, 0, len(got))
+			for _, song := range got {
+				gotNames = append(gotNames, song.SongName)
+			}
+			wantNames := make([]string, 0, len(tt.want))
+			for _, song := range tt.want {
+				wantNames = append(wantNames, song.SongName)
+			}
+
+			if !slices.Equal(gotNames, wantNames) {
+				t.Errorf("Search returned %v, want %v", gotNames, wantNames)
+			}
+		})
+	}
+
+	// keyset pagination continues after the key of the sort field
+	search := &domain.SongSearch{Sort: domain.SortByRating, Batch: domain.Batch{Offset: 0, Limit: 1}}
+
+	first, err := st.Search(ctx, search)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(first) != 1 || first[0].Rating != 4.5 || first[0].Plays != 0 {
+		t.Fatalf("Search returned %v, want %s with rating 4.5", foundNames(first), muse.SongName)
+	}
+
+	search.After = first[0].Key()
+
+	next, err := st.Search(ctx, search)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if !sameSongs(next, []*domain.Song{beatles}) {
+		t.Errorf("Search after %s returned %v, want %s", muse.SongName, foundNames(next), beatles.SongName)
+	}
+}
@@ -0,0 +1,289 @@
+package storagetest
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+)
+
+func testGenres(t *testing.T, st Storage) {
+	ctx := newContext()
+
+	rock := mustCreateGenre(t, st, "Rock", nil)
+	altRock := mustCreateGenre(t, st, "Alt-Rock", rock)
+	grunge := mustCreateGenre(t, st, "Grunge", altRock)
+
+	_, err := st.CreateGenre(ctx, &domain.Genre{Name: "rock"})
+	if !errors.Is(err, domain.ErrGenreExists) {
+		t.Errorf("CreateGenre with existing name returned %v, want %v", err, domain.ErrGenreExists)
+	}
+
+	unknownID := uuid.New()
+	_, err = st.CreateGenre(ctx, &domain.Genre{Name: "Pop", ParentID: &unknownID})
+	if !errors.Is(err, domain.ErrUnknownGenre) {
+		t.Errorf("CreateGenre with unknown parent returned %v, want %v", err, domain.ErrUnknownGenre)
+	}
+
+	genres, err := st.Genres(ctx)
+	if err != nil {
+		t.Fatalf("Genres: %v", err)
+	}
+	if !reflect.DeepEqual(genres, []*domain.Genre{altRock, grunge, rock}) {
+		t.Errorf("Genres returned %v, want Alt-Rock, Grunge and Rock", genres)
+	}
+
+	// rock can't be moved under its own descendant
+	_, err = st.UpdateGenre(ctx, &domain.Genre{ID: rock.ID, Name: rock.Name, ParentID: &grunge.ID})
+	if !errors.Is(err, domain.ErrGenreCycle) {
+		t.Errorf("UpdateGenre to descendant returned %v, want %v", err, domain.ErrGenreCycle)
+	}
+	_, err = st.UpdateGenre(ctx, &domain.Genre{ID: rock.ID, Name: rock.Name, ParentID: &rock.ID})
+	if !errors.Is(err, domain.ErrGenreCycle) {
+		t.Errorf("UpdateGenre to itself returned %v, want %v", err, domain.ErrGenreCycle)
+	}
+	_, err = st.UpdateGenre(ctx, &domain.Genre{ID: grunge.ID, Name: "ROCK"})
+	if !errors.Is(err, domain.ErrGenreExists) {
+		t.Errorf("UpdateGenre to existing name returned %v, want %v", err, domain.ErrGenreExists)
+	}
+
+	updated, err := st.UpdateGenre(ctx, &domain.Genre{ID: grunge.ID, Name: "Seattle Grunge"})
+	if err != nil {
+		t.Fatalf("UpdateGenre: %v", err)
+	}
+	if updated.Name != "Seattle Grunge" || updated.ParentID != nil {
+		t.Errorf("UpdateGenre returned %+v, want top level Seattle Grunge", updated)
+	}
+
+	_, err = st.UpdateGenre(ctx, &domain.Genre{ID: unknownID, Name: "Pop"})
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("UpdateGenre of unknown genre returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+
+	err = st.DeleteGenre(ctx, rock.ID)
+	if !errors.Is(err, domain.ErrGenreHasChildren) {
+		t.Errorf("DeleteGenre with children returned %v, want %v", err, domain.ErrGenreHasChildren)
+	}
+
+	// songs lose deleted genre
+	mustCreate(t, st, muse, nil)
+
+	err = st.SetGenres(ctx, muse, []string{"alt-rock", "Rock"})
+	if err != nil {
+		t.Fatalf("SetGenres: %v", err)
+	}
+
+	err = st.DeleteGenre(ctx, altRock.ID)
+	if err != nil {
+		t.Fatalf("DeleteGenre: %v", err)
+	}
+
+	info, err := st.Info(ctx, muse)
+	if err != nil {
+		t.Fatalf("Info: %v", err)
+	}
+	if !slices.Equal(info.Genres, []string{"Rock"}) {
+		t.Errorf("Info returned genres %v, want [Rock]", info.Genres)
+	}
+
+	err = st.DeleteGenre(ctx, altRock.ID)
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("DeleteGenre of deleted genre returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+}
+
+func testSongGenresAndTags(t *testing.T, st Storage) {
+	ctx := newContext()
+
+	rock := mustCreateGenre(t, st, "Rock", nil)
+	mustCreateGenre(t, st, "Alt-Rock", rock)
+
+	mustCreate(t, st, muse, nil)
+
+	err := st.SetGenres(ctx, muse, []string{"Rock", "alt-rock", "ROCK"})
+	if err != nil {
+		t.Fatalf("SetGenres: %v", err)
+	}
+
+	err = st.SetTags(ctx, muse, []string{"live", "stadium"})
+	if err != nil {
+		t.Fatalf("SetTags: %v", err)
+	}
+
+	info, err := st.Info(ctx, muse)
+	if err != nil {
+		t.Fatalf("Info: %v", err)
+	}
+	if !slices.Equal(info.Genres, []string{"Alt-Rock", "Rock"}) || !slices.Equal(info.Tags, []string{"live", "stadium"}) {
+		t.Errorf("Info returned genres %v and tags %v, want [Alt-Rock Rock] and [live stadium]", info.Genres, info.Tags)
+	}
+
+	// genres and tags are replaced
+	err = st.SetGenres(ctx, muse, []string{"Rock"})
+	if err != nil {
+		t.Fatalf("SetGenres: %v", err)
+	}
+	err = st.SetTags(ctx, muse, []string{})
+	if err != nil {
+		t.Fatalf("SetTags: %v", err)
+	}
+
+	info, err = st.Info(ctx, muse)
+	if err != nil {
+		t.Fatalf("Info: %v", err)
+	}
+	if !slices.Equal(info.Genres, []string{"Rock"}) || len(info.Tags) != 0 {
+		t.Errorf("Info returned genres %v and tags %v, want [Rock] and no tags", info.Genres, info.Tags)
+	}
+
+	err = st.SetGenres(ctx, muse, []string{"Rock", "Jazz"})
+	if !errors.Is(err, domain.ErrUnknownGenre) {
+		t.Errorf("SetGenres with unknown genre returned %v, want %v", err, domain.ErrUnknownGenre)
+	}
+
+	err = st.SetTags(ctx, queen, []string{"live"})
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("SetTags of unknown song returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+}
+
+func testSearchByGenreAndTags(t *testing.T, st Storage) {
+	ctx := newContext()
+
+	rock := mustCreateGenre(t, st, "Rock", nil)
+	mustCreateGenre(t, st, "Alt-Rock", rock)
+	mustCreateGenre(t, st, "Pop", nil)
+
+	mustCreate(t, st, muse, museDetails)
+	mustCreate(t, st, queen, queenDetails)
+	mustCreate(t, st, beatles, beatlesDetails)
+
+	mustSetGenres(t, st, muse, "Alt-Rock")
+	mustSetGenres(t, st, queen, "Rock")
+	mustSetGenres(t, st, beatles, "Pop")
+
+	mustSetTags(t, st, muse, "live", "stadium")
+	mustSetTags(t, st, queen, "live")
+
+	tests := []struct {
+		name   string
+		search domain.SongSearch
+		want   []*domain.Song
+	}{
+		{"GenreWithDescendants", domain.SongSearch{Genre: "rock"}, []*domain.Song{muse, queen}},
+		{"Subgenre", domain.SongSearch{Genre: "Alt-Rock"}, []*domain.Song{muse}},
+		{"UnknownGenre", domain.SongSearch{Genre: "Jazz"}, []*domain.Song{}},
+		{"AnyTag", domain.SongSearch{Tags: []string{"live", "stadium"}}, []*domain.Song{muse, queen}},
+		{"AllTags", domain.SongSearch{Tags: []string{"live", "stadium"}, TagMatch: domain.TagMatchAll}, []*domain.Song{muse}},
+		{"GenreAndTag", domain.SongSearch{Genre: "Rock", Tags: []string{"stadium"}}, []*domain.Song{muse}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			search := tt.search
+			search.Batch = domain.Batch{Offset: 0, Limit: 10}
+
+			got, err := st.Search(ctx, &search)
+			if err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+			if !sameSongs(got, tt.want) {
+				t.Errorf("Search returned %v, want %v", foundNames(got), songNames(tt.want))
+			}
+
+			total, err := st.Count(ctx, &search)
+			if err != nil {
+				t.Fatalf("Count: %v", err)
+			}
+			if total != len(tt.want) {
+				t.Errorf("Count returned %d, want %d", total, len(tt.want))
+			}
+		})
+	}
+}
+
+func testFacets(t *testing.T, st Storage) {
+	ctx := newContext()
+
+	rock := mustCreateGenre(t, st, "Rock", nil)
+	mustCreateGenre(t, st, "Alt-Rock", rock)
+	mustCreateGenre(t, st, "Pop", nil)
+
+	mustCreate(t, st, muse, museDetails)
+	mustCreate(t, st, queen, queenDetails)
+	mustCreate(t, st, beatles, beatlesDetails)
+
+	// muse is counted once for rock, though it has both rock and its subgenre
+	mustSetGenres(t, st, muse, "Alt-Rock", "Rock")
+	mustSetGenres(t, st, queen, "Rock")
+	mustSetGenres(t, st, beatles, "Pop")
+
+	mustSetTags(t, st, muse, "live", "stadium")
+	mustSetTags(t, st, queen, "live")
+
+	facets, err := st.Facets(ctx, &domain.SongSearch{Batch: domain.Batch{Offset: 0, Limit: 1}})
+	if err != nil {
+		t.Fatalf("Facets: %v", err)
+	}
+
+	want := &domain.Facets{
+		Genres:  []domain.Facet{{Value: "Rock", Count: 2}, {Value: "Alt-Rock", Count: 1}, {Value: "Pop", Count: 1}},
+		Tags:    []domain.Facet{{Value: "live", Count: 2}, {Value: "stadium", Count: 1}},
+		Decades: []domain.Facet{{Value: "1970s", Count: 2}, {Value: "2000s", Count: 1}},
+	}
+	if !reflect.DeepEqual(facets, want) {
+		t.Errorf("Facets returned %+v, want %+v", facets, want)
+	}
+
+	// facets are counted for found songs only
+	facets, err = st.Facets(ctx, &domain.SongSearch{Genre: "Alt-Rock", Batch: domain.Batch{Offset: 0, Limit: 10}})
+	if err != nil {
+		t.Fatalf("Facets: %v", err)
+	}
+
+	want = &domain.Facets{
+		Genres:  []domain.Facet{{Value: "Alt-Rock", Count: 1}, {Value: "Rock", Count: 1}},
+		Tags:    []domain.Facet{{Value: "live", Count: 1}, {Value: "stadium", Count: 1}},
+		Decades: []domain.Facet{{Value: "2000s", Count: 1}},
+	}
+	if !reflect.DeepEqual(facets, want) {
+		t.Errorf("Facets of Alt-Rock returned %+v, want %+v", facets, want)
+	}
+}
+
+func mustCreateGenre(t *testing.T, st Storage, name string, parent *domain.Genre) *domain.Genre {
+	t.Helper()
+
+	genre := &domain.Genre{Name: name}
+	if parent != nil {
+		genre.ParentID = &parent.ID
+	}
+
+	created, err := st.CreateGenre(newContext(), genre)
+	if err != nil {
+		t.Fatalf("CreateGenre(%s): %v", name, err)
+	}
+
+	return created
+}
+
+func mustSetGenres(t *testing.T, st Storage, song *domain.Song, genres ...string) {
+	t.Helper()
+
+	err := st.SetGenres(newContext(), song, genres)
+	if err != nil {
+		t.Fatalf("SetGenres(%s): %v", song.SongName, err)
+	}
+}
+
+func mustSetTags(t *testing.T, st Storage, song *domain.Song, tags ...string) {
+	t.Helper()
+
+	err := st.SetTags(newContext(), song, tags)
+	if err != nil {
+		t.Fatalf("SetTags(%s): %v", song.SongName, err)
+	}
+}
@@ -0,0 +1,502 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+)
+
+// PlaylistsStorage keeps ordered lists of songs.
+type PlaylistsStorage interface {
+	Create(context.Context, *domain.Playlist) (*domain.Playlist, error)
+	Get(context.Context, uuid.UUID) (*domain.Playlist, error)
+	List(context.Context, *domain.PlaylistSearch) ([]*domain.Playlist, error)
+	Update(context.Context, uuid.UUID, *domain.PlaylistUpdate) (*domain.Playlist, error)
+	Delete(context.Context, uuid.UUID) error
+	GetItems(context.Context, uuid.UUID) ([]*domain.PlaylistItem, error)
+	InsertItem(context.Context, uuid.UUID, *domain.PlaylistItemInsert) ([]*domain.PlaylistItem, error)
+	MoveItem(ctx context.Context, id uuid.UUID, from, to int) ([]*domain.PlaylistItem, error)
+	RemoveItem(ctx context.Context, id uuid.UUID, position int) ([]*domain.PlaylistItem, error)
+	Duplicate(ctx context.Context, id uuid.UUID, name string) (*domain.Playlist, error)
+	Import(context.Context, *domain.Playlist, []*domain.PlaylistEntry) (*domain.PlaylistImport, error)
+}
+
+// Items of playlists refer to songs, so both storages must share the database.
+type NewPlaylists func(t *testing.T) (PlaylistsStorage, Storage)
+
+// Runs conformance tests of playlists against storages returned by newStorage.
+func RunPlaylists(t *testing.T, newStorage NewPlaylists) {
+	tests := []struct {
+		name string
+		test func(*testing.T, PlaylistsStorage, Storage)
+	}{
+		{"CreateAndGet", testCreatePlaylist},
+		{"ListPlaylists", testListPlaylists},
+		{"UpdatePlaylist", testUpdatePlaylist},
+		{"DeletePlaylist", testDeletePlaylist},
+		{"Items", testPlaylistItems},
+		{"ItemsOfDeletedSongs", testItemsOfDeletedSongs},
+		{"Duplicate", testDuplicatePlaylist},
+		{"ImportPlaylist", testImportPlaylist},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			playlists, songs := newStorage(t)
+			tt.test(t, playlists, songs)
+		})
+	}
+}
+
+func testCreatePlaylist(t *testing.T, playlists PlaylistsStorage, _ Storage) {
+	ctx := newContext()
+
+	created, err := playlists.Create(ctx, &domain.Playlist{Name: "Road trip", Description: "Songs for the road"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if created.ID == uuid.Nil || created.Songs != 0 || created.CreatedAt.IsZero() || !created.UpdatedAt.Equal(created.CreatedAt) {
+		t.Errorf("Create returned %+v, want new empty playlist", created)
+	}
+
+	got, err := playlists.Get(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Name != "Road trip" || got.Description != "Songs for the road" || !got.CreatedAt.Equal(created.CreatedAt) {
+		t.Errorf("Get returned %+v", got)
+	}
+
+	_, err = playlists.Get(ctx, uuid.New())
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("Get of unknown playlist returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+}
+
+func testListPlaylists(t *testing.T, playlists PlaylistsStorage, _ Storage) {
+	ctx := newContext()
+
+	// playlists are ordered by time of the last change, so it must differ
+	first := mustCreatePlaylist(t, playlists, "Road trip")
+	time.Sleep(time.Millisecond)
+	mustCreatePlaylist(t, playlists, "Workout")
+	time.Sleep(time.Millisecond)
+	mustCreatePlaylist(t, playlists, "Evening")
+	time.Sleep(time.Millisecond)
+
+	_, err := playlists.Update(ctx, first.ID, &domain.PlaylistUpdate{Description: "Changed"})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		search domain.PlaylistSearch
+		want   []string
+	}{
+		{"All", domain.PlaylistSearch{Batch: domain.Batch{Limit: 10}}, []string{"Road trip", "Evening", "Workout"}},
+		{"Page", domain.PlaylistSearch{Batch: domain.Batch{Offset: 1, Limit: 1}}, []string{"Evening"}},
+		{"ByName", domain.PlaylistSearch{ByName: "OUT", Batch: domain.Batch{Limit: 10}}, []string{"Workout"}},
+		{"NoMatch", domain.PlaylistSearch{ByName: "Party", Batch: domain.Batch{Limit: 10}}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := playlists.List(ctx, &tt.search)
+			if err != nil {
+				t.Fatalf("List: %v", err)
+			}
+
+			names := make([]string, 0, len(got))
+			for _, playlist := range got {
+				names = append(names, playlist.Name)
+			}
+			if !slices.Equal(names, tt.want) {
+				t.Errorf("List returned %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func testUpdatePlaylist(t *testing.T, playlists PlaylistsStorage, _ Storage) {
+	ctx := newContext()
+
+	created, err := playlists.Create(ctx, &domain.Playlist{Name: "Road trip", Description: "Songs for the road"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	updated, err := playlists.Update(ctx, created.ID, &domain.PlaylistUpdate{Name: "Long road trip"})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if updated.Name != "Long road trip" || updated.Description != "Songs for the road" ||
+		!updated.CreatedAt.Equal(created.CreatedAt) || updated.UpdatedAt.Before(created.UpdatedAt) {
+		t.Errorf("Update returned %+v", updated)
+	}
+
+	_, err = playlists.Update(ctx, created.ID, &domain.PlaylistUpdate{})
+	if !errors.Is(err, domain.ErrEmptyUpdate) {
+		t.Errorf("empty Update returned %v, want %v", err, domain.ErrEmptyUpdate)
+	}
+
+	_, err = playlists.Update(ctx, uuid.New(), &domain.PlaylistUpdate{Name: "Unknown"})
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("Update of unknown playlist returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+}
+
+func testDeletePlaylist(t *testing.T, playlists PlaylistsStorage, songs Storage) {
+	ctx := newContext()
+
+	playlist := mustCreatePlaylist(t, playlists, "Road trip")
+	mustCreate(t, songs, muse, nil)
+	mustInsertItem(t, playlists, playlist.ID, muse, 0)
+
+	err := playlists.Delete(ctx, playlist.ID)
+	if err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	_, err = playlists.Get(ctx, playlist.ID)
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("Get of deleted playlist returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+
+	_, err = playlists.GetItems(ctx, playlist.ID)
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("GetItems of deleted playlist returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+
+	err = playlists.Delete(ctx, playlist.ID)
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("Delete of deleted playlist returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+
+	// songs are kept after their playlist is deleted
+	_, err = songs.Info(ctx, muse)
+	if err != nil {
+		t.Errorf("Info of song of deleted playlist: %v", err)
+	}
+}
+
+func testPlaylistItems(t *testing.T, playlists PlaylistsStorage, songs Storage) {
+	ctx := newContext()
+
+	playlist := mustCreatePlaylist(t, playlists, "Road trip")
+	museID := mustCreate(t, songs, muse, museDetails)
+	mustCreate(t, songs, queen, nil)
+	mustCreate(t, songs, beatles, nil)
+
+	// positions 0 and after the last item append the song, the same song can be added several times
+	mustInsertItem(t, playlists, playlist.ID, queen, 0)
+	mustInsertItem(t, playlists, playlist.ID, beatles, 10)
+	mustInsertItem(t, playlists, playlist.ID, queen, 0)
+
+	items, err := playlists.InsertItem(ctx, playlist.ID, &domain.PlaylistItemInsert{SongID: museID, Position: 1})
+	if err != nil {
+		t.Fatalf("InsertItem: %v", err)
+	}
+	checkItems(t, "InsertItem", items, muse, queen, beatles, queen)
+	if items[0].ID != museID || items[0].Link != museDetails.Link || items[1].Link != "" {
+		t.Errorf("InsertItem returned item %+v, want song with link", *items[0])
+	}
+
+	// group is found case insensitively like in other song lookups
+	items, err = playlists.InsertItem(ctx, playlist.ID, &domain.PlaylistItemInsert{Group: "the beatles", SongName: beatles.SongName, Position: 2})
+	if err != nil {
+		t.Fatalf("InsertItem: %v", err)
+	}
+	checkItems(t, "InsertItem", items, muse, beatles, queen, beatles, queen)
+
+	got, err := playlists.Get(ctx, playlist.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Songs != 5 || got.UpdatedAt.Before(playlist.UpdatedAt) {
+		t.Errorf("Get returned %+v, want 5 songs", got)
+	}
+
+	items, err = playlists.MoveItem(ctx, playlist.ID, 1, 3)
+	if err != nil {
+		t.Fatalf("MoveItem: %v", err)
+	}
+	checkItems(t, "MoveItem", items, beatles, queen, muse, beatles, queen)
+
+	items, err = playlists.MoveItem(ctx, playlist.ID, 4, 1)
+	if err != nil {
+		t.Fatalf("MoveItem: %v", err)
+	}
+	checkItems(t, "MoveItem", items, beatles, beatles, queen, muse, queen)
+
+	// too big position moves the item to the end
+	items, err = playlists.MoveItem(ctx, playlist.ID, 3, 100)
+	if err != nil {
+		t.Fatalf("MoveItem: %v", err)
+	}
+	checkItems(t, "MoveItem", items, beatles, beatles, muse, queen, queen)
+
+	items, err = playlists.RemoveItem(ctx, playlist.ID, 2)
+	if err != nil {
+		t.Fatalf("RemoveItem: %v", err)
+	}
+	checkItems(t, "RemoveItem", items, beatles, muse, queen, queen)
+
+	got, err = playlists.Get(ctx, playlist.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Songs != 4 {
+		t.Errorf("Get returned %d songs, want 4", got.Songs)
+	}
+
+	_, err = playlists.InsertItem(ctx, playlist.ID, &domain.PlaylistItemInsert{Group: "Nirvana", SongName: "Lithium"})
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("InsertItem of unknown song returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+
+	_, err = playlists.InsertItem(ctx, playlist.ID, &domain.PlaylistItemInsert{SongID: uuid.New()})
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("InsertItem of unknown song id returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+
+	_, err = playlists.InsertItem(ctx, uuid.New(), &domain.PlaylistItemInsert{SongID: museID})
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("InsertItem into unknown playlist returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+
+	_, err = playlists.MoveItem(ctx, playlist.ID, 5, 1)
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("MoveItem from unknown position returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+
+	_, err = playlists.RemoveItem(ctx, playlist.ID, 5)
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("RemoveItem at unknown position returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+
+	// failed changes keep the items
+	items, err = playlists.GetItems(ctx, playlist.ID)
+	if err != nil {
+		t.Fatalf("GetItems: %v", err)
+	}
+	checkItems(t, "GetItems", items, beatles, muse, queen, queen)
+}
+
+func testItemsOfDeletedSongs(t *testing.T, playlists PlaylistsStorage, songs Storage) {
+	ctx := newContext()
+
+	playlist := mustCreatePlaylist(t, playlists, "Road trip")
+	mustCreate(t, songs, muse, nil)
+	mustCreate(t, songs, queen, nil)
+	mustCreate(t, songs, beatles, nil)
+	mustInsertItem(t, playlists, playlist.ID, muse, 0)
+	mustInsertItem(t, playlists, playlist.ID, queen, 0)
+	mustInsertItem(t, playlists, playlist.ID, beatles, 0)
+
+	// songs in trash are hidden, but keep their positions until they are restored
+	err := songs.Delete(ctx, queen)
+	if err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	items, err := playlists.GetItems(ctx, playlist.ID)
+	if err != nil {
+		t.Fatalf("GetItems: %v", err)
+	}
+	checkItems(t, "GetItems", items, muse, beatles)
+	if positions := itemPositions(items); !slices.Equal(positions, []int{1, 3}) {
+		t.Errorf("GetItems returned positions %v, want [1 3]", positions)
+	}
+
+	got, err := playlists.Get(ctx, playlist.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Songs != 2 {
+		t.Errorf("Get returned %d songs, want 2 without songs in trash", got.Songs)
+	}
+
+	err = songs.Untrash(ctx, queen)
+	if err != nil {
+		t.Fatalf("Untrash: %v", err)
+	}
+
+	items, err = playlists.GetItems(ctx, playlist.ID)
+	if err != nil {
+		t.Fatalf("GetItems: %v", err)
+	}
+	checkItems(t, "GetItems", items, muse, queen, beatles)
+
+	// items of purged songs are deleted and the rest are renumbered
+	err = songs.Delete(ctx, queen)
+	if err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	_, err = songs.Purge(ctx, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+
+	items, err = playlists.GetItems(ctx, playlist.ID)
+	if err != nil {
+		t.Fatalf("GetItems: %v", err)
+	}
+	checkItems(t, "GetItems", items, muse, beatles)
+	if positions := itemPositions(items); !slices.Equal(positions, []int{1, 2}) {
+		t.Errorf("GetItems after purge returned positions %v, want [1 2]", positions)
+	}
+
+	items = mustInsertItem(t, playlists, playlist.ID, muse, 0)
+	checkItems(t, "InsertItem after purge", items, muse, beatles, muse)
+}
+
+func testDuplicatePlaylist(t *testing.T, playlists PlaylistsStorage, songs Storage) {
+	ctx := newContext()
+
+	original, err := playlists.Create(ctx, &domain.Playlist{Name: "Road trip", Description: "Songs for the road"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	mustCreate(t, songs, muse, nil)
+	mustCreate(t, songs, queen, nil)
+	mustInsertItem(t, playlists, original.ID, queen, 0)
+	mustInsertItem(t, playlists, original.ID, muse, 0)
+
+	copied, err := playlists.Duplicate(ctx, original.ID, "")
+	if err != nil {
+		t.Fatalf("Duplicate: %v", err)
+	}
+	if copied.ID == original.ID || copied.Name != "Road trip (copy)" || copied.Description != "Songs for the road" || copied.Songs != 2 {
+		t.Errorf("Duplicate returned %+v", copied)
+	}
+
+	items, err := playlists.GetItems(ctx, copied.ID)
+	if err != nil {
+		t.Fatalf("GetItems: %v", err)
+	}
+	checkItems(t, "GetItems of copy", items, queen, muse)
+
+	// copy is independent of the original
+	_, err = playlists.RemoveItem(ctx, copied.ID, 1)
+	if err != nil {
+		t.Fatalf("RemoveItem: %v", err)
+	}
+
+	items, err = playlists.GetItems(ctx, original.ID)
+	if err != nil {
+		t.Fatalf("GetItems: %v", err)
+	}
+	checkItems(t, "GetItems of original", items, queen, muse)
+
+	named, err := playlists.Duplicate(ctx, original.ID, "Another trip")
+	if err != nil {
+		t.Fatalf("Duplicate: %v", err)
+	}
+	if named.Name != "Another trip" {
+		t.Errorf("Duplicate returned name %q, want Another trip", named.Name)
+	}
+
+	// default name is cut to the maximum length
+	long := mustCreatePlaylist(t, playlists, strings.Repeat("я", domain.MaxPlaylistName))
+
+	copied, err = playlists.Duplicate(ctx, long.ID, "")
+	if err != nil {
+		t.Fatalf("Duplicate: %v", err)
+	}
+	if copied.Name != long.Name {
+		t.Errorf("Duplicate returned name of %d characters, want %d", len([]rune(copied.Name)), domain.MaxPlaylistName)
+	}
+
+	_, err = playlists.Duplicate(ctx, uuid.New(), "")
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("Duplicate of unknown playlist returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+}
+
+func testImportPlaylist(t *testing.T, playlists PlaylistsStorage, songs Storage) {
+	ctx := newContext()
+
+	museID := mustCreate(t, songs, muse, nil)
+	mustCreate(t, songs, queen, queenDetails)
+	mustCreate(t, songs, beatles, nil)
+
+	unknown := &domain.PlaylistEntry{Group: "Nirvana", SongName: "Lithium"}
+
+	result, err := playlists.Import(ctx, &domain.Playlist{Name: "Imported"}, []*domain.PlaylistEntry{
+		{Identifier: "urn:uuid:" + museID.String(), Group: "Queen", SongName: queen.SongName},
+		{Link: queenDetails.Link},
+		unknown,
+		{Group: "the beatles", SongName: beatles.SongName},
+		// unknown identifier falls back to the link
+		{Identifier: "urn:uuid:" + uuid.NewString(), Link: queenDetails.Link},
+	})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if result.Playlist.Name != "Imported" || result.Playlist.Songs != 4 {
+		t.Errorf("Import returned playlist %+v, want 4 songs", result.Playlist)
+	}
+	if len(result.Unmatched) != 1 || *result.Unmatched[0] != *unknown {
+		t.Errorf("Import returned unmatched %+v, want only %+v", result.Unmatched, unknown)
+	}
+
+	items, err := playlists.GetItems(ctx, result.Playlist.ID)
+	if err != nil {
+		t.Fatalf("GetItems: %v", err)
+	}
+	checkItems(t, "GetItems", items, muse, queen, beatles, queen)
+	if positions := itemPositions(items); !slices.Equal(positions, []int{1, 2, 3, 4}) {
+		t.Errorf("GetItems returned positions %v, want [1 2 3 4]", positions)
+	}
+}
+
+func mustCreatePlaylist(t *testing.T, st PlaylistsStorage, name string) *domain.Playlist {
+	t.Helper()
+
+	created, err := st.Create(newContext(), &domain.Playlist{Name: name})
+	if err != nil {
+		t.Fatalf("Create playlist %s: %v", name, err)
+	}
+
+	return created
+}
+
+func mustInsertItem(t *testing.T, st PlaylistsStorage, id uuid.UUID, song *domain.Song, position int) []*domain.PlaylistItem {
+	t.Helper()
+
+	items, err := st.InsertItem(newContext(), id, &domain.PlaylistItemInsert{Group: song.Group, SongName: song.SongName, Position: position})
+	if err != nil {
+		t.Fatalf("InsertItem %s - %s: %v", song.Group, song.SongName, err)
+	}
+
+	return items
+}
+
+// Checks songs of the items in order.
+func checkItems(t *testing.T, method string, items []*domain.PlaylistItem, want ...*domain.Song) {
+	t.Helper()
+
+	if len(items) != len(want) {
+		t.Fatalf("%s returned %d items, want %d", method, len(items), len(want))
+	}
+	for i, item := range items {
+		if item.Group != want[i].Group || item.SongName != want[i].SongName {
+			t.Errorf("%s returned %s - %s at %d, want %s - %s", method, item.Group, item.SongName, i, want[i].Group, want[i].SongName)
+		}
+	}
+}
+
+func itemPositions(items []*domain.PlaylistItem) []int {
+	positions := make([]int, 0, len(items))
+	for _, item := range items {
+		positions = append(positions, item.Position)
+	}
+	return positions
+}
@@ -0,0 +1,1446 @@
+// Package storagetest contains conformance tests, which every songs storage implementation must pass.
+//
+// Usage in the storage package tests:
+//
+//	func TestConformance(t *testing.T) {
+//		storagetest.Run(t, func(t *testing.T) storagetest.Storage {
+//			return NewSongsStorage()
+//		})
+//	}
+//
+// New is called for every test case and must return an empty storage.
+// Users, API keys, artists, albums and playlists storages are checked by RunUsers, RunAPIKeys, RunArtists,
+// RunAlbums and RunPlaylists the same way,
+// feedback of users is checked by RunFeedback with songs and users storages of the same database.
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+)
+
+type Storage interface {
+	Info(context.Context, *domain.Song) (*domain.SongInfo, error)
+	Create(context.Context, *domain.Song, *domain.SongDetails) (uuid.UUID, error)
+	Delete(context.Context, *domain.Song) error
+	Update(context.Context, *domain.Song, *domain.SongUpdate) error
+	GetLyrics(context.Context, *domain.Song, *domain.LyricsBatch) ([]*domain.Verse, error)
+	CountLyrics(context.Context, *domain.Song, *domain.LyricsBatch) (int, error)
+	Verses(context.Context, *domain.Song) ([]*domain.Verse, error)
+	Search(context.Context, *domain.SongSearch) ([]*domain.FoundSong, error)
+	Count(context.Context, *domain.SongSearch) (int, error)
+	Suggest(context.Context, *domain.SongSearch) (*domain.Suggestions, error)
+	Languages(context.Context, *domain.Song) (*domain.Languages, error)
+	Translation(context.Context, *domain.Song, string) (*domain.Translation, error)
+	SetTranslation(context.Context, *domain.Song, *domain.Translation) error
+	DeleteTranslation(context.Context, *domain.Song, string) error
+	Revisions(context.Context, *domain.Song, *domain.Batch) ([]*domain.Revision, error)
+	Revision(context.Context, *domain.Song, int) (*domain.Revision, error)
+	Restore(context.Context, *domain.Song, int) error
+	Trash(context.Context, *domain.Batch) ([]*domain.TrashedSong, error)
+	Untrash(context.Context, *domain.Song) error
+	Purge(context.Context, time.Time) (int, error)
+	Import(context.Context, []*domain.BulkSong, *domain.BulkOptions) ([]*domain.BulkResult, error)
+	Export(context.Context, *domain.SongSearch, func(*domain.ExportedSong) error) error
+	CreateGenre(context.Context, *domain.Genre) (*domain.Genre, error)
+	Genres(context.Context) ([]*domain.Genre, error)
+	UpdateGenre(context.Context, *domain.Genre) (*domain.Genre, error)
+	DeleteGenre(context.Context, uuid.UUID) error
+	SetGenres(context.Context, *domain.Song, []string) error
+	SetTags(context.Context, *domain.Song, []string) error
+	SetCredits(context.Context, *domain.Song, []domain.Credit) error
+	Facets(context.Context, *domain.SongSearch) (*domain.Facets, error)
+}
+
+type New func(t *testing.T) Storage
+
+var (
+	muse    = &domain.Song{Group: "Muse", SongName: "Supermassive Black Hole"}
+	queen   = &domain.Song{Group: "Queen", SongName: "Bohemian Rhapsody"}
+	beatles = &domain.Song{Group: "The Beatles", SongName: "Let It Be"}
+
+	museDetails = &domain.SongDetails{
+		ReleaseDate: date(2006, time.July, 16),
+		Text:        "Ooh baby, don't you know I suffer?\nOoh baby, can you hear me moan?\n\nOoh\nYou set my soul alight",
+		Link:        "https://www.youtube.com/watch?v=Xsp3_a-PMTw",
+	}
+	queenDetails = &domain.SongDetails{
+		ReleaseDate: date(1975, time.October, 31),
+		Text:        "Is this the real life?\nIs this just fantasy?\n\nMama, just killed a man",
+		Link:        "https://www.youtube.com/watch?v=fJ9rUzIMcZQ",
+	}
+	beatlesDetails = &domain.SongDetails{
+		ReleaseDate: date(1970, time.March, 6),
+		Text:        "When I find myself in times of trouble\n\nLet it be, let it be",
+		Link:        "https://www.youtube.com/watch?v=QDYfEBY9NM4",
+	}
+)
+
+// Runs all conformance tests against storages returned by newStorage.
+func Run(t *testing.T, newStorage New) {
+	tests := []struct {
+		name string
+		test func(*testing.T, Storage)
+	}{
+		{"CreateAndInfo", testCreateAndInfo},
+		{"CreateWithDetails", testCreateWithDetails},
+		{"InfoUnknown", testInfoUnknown},
+		{"LookupByID", testLookupByID},
+		{"LookupByGroupCase", testLookupByGroupCase},
+		{"Delete", testDelete},
+		{"Trash", testTrash},
+		{"Unique", testUnique},
+		{"BulkImport", testBulkImport},
+		{"Export", testExport},
+		{"UpdateMetadata", testUpdateMetadata},
+		{"UpdateLyrics", testUpdateLyrics},
+		{"UpdateUnknown", testUpdateUnknown},
+		{"GetLyrics", testGetLyrics},
+		{"LyricsKeyset", testLyricsKeyset},
+		{"LyricsSections", testLyricsSections},
+		{"SyncedLyrics", testSyncedLyrics},
+		{"Translations", testTranslations},
+		{"TranslationsOfReplacedLyrics", testTranslationsOfReplacedLyrics},
+		{"Revisions", testRevisions},
+		{"Search", testSearch},
+		{"SearchPagination", testSearchPagination},
+		{"SearchByLyricsRank", testSearchByLyricsRank},
+		{"FuzzySearch", testFuzzySearch},
+		{"SearchSort", testSearchSort},
+		{"SearchKeyset", testSearchKeyset},
+		{"Suggest", testSuggest},
+		{"Genres", testGenres},
+		{"SongGenresAndTags", testSongGenresAndTags},
+		{"SearchByGenreAndTags", testSearchByGenreAndTags},
+		{"Facets", testFacets},
+		{"Credits", testCredits},
+		{"SearchByCredit", testSearchByCredit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newStorage(t))
+		})
+	}
+}
+
+func testCreateAndInfo(t *testing.T, st Storage) {
+	ctx := newContext()
+
+	mustCreate(t, st, muse, nil)
+
+	info, err := st.Info(ctx, muse)
+	if err != nil {
+		t.Fatalf("Info: %v", err)
+	}
+
+	if info.Group != muse.Group || info.SongName != muse.SongName {
+		t.Errorf("Info returned %q - %q, want %q - %q", info.Group, info.SongName, muse.Group, muse.SongName)
+	}
+	if info.Lyrics != "" || info.Link != "" {
+		t.Errorf("Info returned lyrics %q and link %q, want empty", info.Lyrics, info.Link)
+	}
+	if info.ReleaseDate.IsZero() {
+		t.Error("Info returned zero release date, want current date")
+	}
+}
+
+func testCreateWithDetails(t *testing.T, st Storage) {
+	ctx := newContext()
+
+	mustCreate(t, st, muse, museDetails)
+
+	info, err := st.Info(ctx, muse)
+	if err != nil {
+		t.Fatalf("Info: %v", err)
+	}
+
+	wantLyrics := strings.Join(texts(museDetails.ToLyricsSchema().Lyrics), "\n")
+	if info.Lyrics != wantLyrics {
+		t.Errorf("Info returned lyrics %q, want %q", info.Lyrics, wantLyrics)
+	}
+	if info.Link != museDetails.Link {
+		t.Errorf("Info returned link %q, want %q", info.Link, museDetails.Link)
+	}
+	if !sameDate(info.ReleaseDate, museDetails.ReleaseDate) {
+		t.Errorf("Info returned release date %v, want %v", info.ReleaseDate, museDetails.ReleaseDate)
+	}
+}
+
+func testInfoUnknown(t *testing.T, st Storage) {
+	_, err := st.Info(newContext(), muse)
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("Info of unknown song returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+}
+
+func testLookupByID(t *testing.T, st Storage) {
+	ctx := newContext()
+
+	// songs of the same group are told apart by id
+	first := mustCreate(t, st, muse, nil)
+	second := mustCreate(t, st, &domain.Song{Group: muse.Group, SongName: "Uprising"}, museDetails)
+
+	if first == second {
+		t.Fatalf("Create returned the same id %v twice", first)
+	}
+
+	info, err := st.Info(ctx, &domain.Song{ID: second})
+	if err != nil {
+		t.Fatalf("Info by id: %v", err)
+	}
+	if info.ID != second || info.Link != museDetails.Link {
+		t.Errorf("Info by id returned song %v with link %q, want %v with %q", info.ID, info.Link, second, museDetails.Link)
+	}
+
+	err = st.Update(ctx, &domain.Song{ID: first}, &domain.SongUpdate{Link: "https://example.com/first"})
+	if err != nil {
+		t.Fatalf("Update by id: %v", err)
+	}
+
+	info, err = st.Info(ctx, &domain.Song{ID: second})
+	if err != nil {
+		t.Fatalf("Info by id: %v", err)
+	}
+	if info.Link != museDetails.Link {
+		t.Errorf("Update by id changed other song link to %q", info.Link)
+	}
+
+	err = st.Delete(ctx, &domain.Song{ID: first})
+	if err != nil {
+		t.Fatalf("Delete by id: %v", err)
+	}
+
+	_, err = st.Info(ctx, &domain.Song{ID: first})
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("Info of deleted song returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+
+	_, err = st.Info(ctx, &domain.Song{ID: second})
+	if err != nil {
+		t.Errorf("Info of other song returned %v after delete by id", err)
+	}
+
+	_, err = st.Info(ctx, &domain.Song{ID: uuid.New()})
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("Info by unknown id returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+}
+
+func testLookupByGroupCase(t *testing.T, st Storage) {
+	ctx := newContext()
+
+	mustCreate(t, st, muse, nil)
+
+	// the new song belongs to the existing artist and keeps its spelling
+	uprising := &domain.Song{Group: "muse", SongName: "Uprising"}
+	id := mustCreate(t, st, uprising, nil)
+
+	for _, group := range []string{"muse", "MUSE", muse.Group} {
+		info, err := st.Info(ctx, &domain.Song{Group: group, SongName: uprising.SongName})
+		if err != nil {
+			t.Fatalf("Info by group %q: %v", group, err)
+		}
+		if info.ID != id || info.Group != muse.Group {
+			t.Errorf("Info by group %q returned song %v of %q, want %v of %q", group, info.ID, info.Group, id, muse.Group)
+		}
+	}
+
+	// song name is matched by its name key like the uniqueness of songs
+	for _, name := range []string{"uprising", " UPRISING ", "Uprising"} {
+		info, err := st.Info(ctx, &domain.Song{Group: muse.Group, SongName: name})
+		if err != nil {
+			t.Fatalf("Info by song name %q: %v", name, err)
+		}
+		if info.ID != id {
+			t.Errorf("Info by song name %q returned song %v, want %v", name, info.ID, id)
+		}
+	}
+
+	err := st.Delete(ctx, &domain.Song{Group: "mUSE", SongName: "uprising"})
+	if err != nil {
+		t.Fatalf("Delete by group in other case: %v", err)
+	}
+
+	_, err = st.Info(ctx, &domain.Song{ID: id})
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("Info of deleted song returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+}
+
+func testDelete(t *testing.T, st Storage) {
+	ctx := newContext()
+
+	mustCreate(t, st, muse, museDetails)
+	mustCreate(t, st, queen, nil)
+
+	err := st.Delete(ctx, muse)
+	if err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	_, err = st.Info(ctx, muse)
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("Info of deleted song returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+
+	_, err = st.Info(ctx, queen)
+	if err != nil {
+		t.Errorf("Info of other song returned %v after delete", err)
+	}
+
+	err = st.Delete(ctx, muse)
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("second Delete returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+}
+
+func testTrash(t *testing.T, st Storage) {
+	ctx := newContext()
+
+	museID := mustCreate(t, st, muse, museDetails)
+	mustCreate(t, st, queen, queenDetails)
+
+	err := st.Delete(ctx, muse)
+	if err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	found, err := st.Search(ctx, &domain.SongSearch{Batch: domain.Batch{Limit: 10}})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if !sameSongs(found, []*domain.Song{queen}) {
+		t.Errorf("Search after delete returned %v, want only %s", foundNames(found), queen.SongName)
+	}
+
+	trash, err := st.Trash(ctx, &domain.Batch{Limit: 10})
+	if err != nil {
+		t.Fatalf("Trash: %v", err)
+	}
+	if len(trash) != 1 || trash[0].ID != museID || trash[0].Group != muse.Group || trash[0].DeletedAt.IsZero() {
+		t.Fatalf("Trash returned %+v, want deleted %s", trash, muse.SongName)
+	}
+
+	err = st.Untrash(ctx, &domain.Song{ID: museID})
+	if err != nil {
+		t.Fatalf("Untrash: %v", err)
+	}
+
+	verses, err := st.Verses(ctx, muse)
+	if err != nil {
+		t.Fatalf("Verses of restored song: %v", err)
+	}
+	if len(verses) != 2 {
+		t.Errorf("Verses of restored song returned %d verses, want 2", len(verses))
+	}
+
+	err = st.Untrash(ctx, muse)
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("Untrash of song not in trash returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+
+	for _, song := range []*domain.Song{muse, queen} {
+		err = st.Delete(ctx, song)
+		if err != nil {
+			t.Fatalf("Delete(%s): %v", song.SongName, err)
+		}
+	}
+
+	n, err := st.Purge(ctx, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+	if n != 0 {
+		t.Errorf("Purge of songs deleted before an hour ago returned %d, want 0", n)
+	}
+
+	n, err = st.Purge(ctx, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("Purge returned %d, want 2", n)
+	}
+
+	err = st.Untrash(ctx, &domain.Song{ID: museID})
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("Untrash of purged song returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+}
+
+func testUnique(t *testing.T, st Storage) {
+	ctx := newContext()
+
+	museID := mustCreate(t, st, muse, nil)
+	queenID := mustCreate(t, st, queen, nil)
+
+	var existsErr *domain.SongExistsError
+
+	// names are compared up to case and spaces
+	_, err := st.Create(ctx, &domain.Song{Group: "muse", SongName: "Supermassive  black hole "}, nil)
+	if !errors.As(err, &existsErr) || existsErr.ID != museID {
+		t.Fatalf("Create of existing song returned %v, want %v with id %s", err, domain.ErrSongExists, museID)
+	}
+
+	err = st.Update(ctx, &domain.Song{ID: queenID}, &domain.SongUpdate{Group: muse.Group, SongName: muse.SongName})
+	if !errors.As(err, &existsErr) || existsErr.ID != museID {
+		t.Errorf("Update to existing song returned %v, want %v with id %s", err, domain.ErrSongExists, museID)
+	}
+
+	// song in trash doesn't take the name, but can't be restored while the name is taken
+	err = st.Delete(ctx, muse)
+	if err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	newID := mustCreate(t, st, muse, nil)
+
+	err = st.Untrash(ctx, &domain.Song{ID: museID})
+	if !errors.As(err, &existsErr) || existsErr.ID != newID {
+		t.Errorf("Untrash of taken name returned %v, want %v with id %s", err, domain.ErrSongExists, newID)
+	}
+}
+
+func testBulkImport(t *testing.T, st Storage) {
+	ctx := newContext()
+
+	museID := mustCreate(t, st, muse, museDetails)
+
+	songs := []*domain.BulkSong{
+		{Group: "muse", SongName: muse.SongName, Lyrics: verses("new verse"), Link: "https://example.com/muse"},
+		{Group: queen.Group, SongName: queen.SongName, Lyrics: verses("first verse", "second verse"), ReleaseDate: queenDetails.ReleaseDate},
+	}
+
+	statuses := func(results []*domain.BulkResult) []string {
+		got := make([]string, 0, len(results))
+		for _, result := range results {
+			got = append(got, result.Status)
+		}
+		return got
+	}
+
+	// dry run reports results, but saves nothing
+	results, err := st.Import(ctx, songs, &domain.BulkOptions{Mode: domain.BulkUpsert, DryRun: true})
+	if err != nil {
+		t.Fatalf("Import in dry run: %v", err)
+	}
+	if want := []string{domain.BulkUpdated, domain.BulkCreated}; !slices.Equal(statuses(results), want) {
+		t.Errorf("Import in dry run returned %q, want %q", statuses(results), want)
+	}
+
+	_, err = st.Info(ctx, queen)
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("Info of song created in dry run returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+
+	// insert mode skips existing songs
+	results, err = st.Import(ctx, songs, &domain.BulkOptions{Mode: domain.BulkInsert})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if want := []string{domain.BulkSkipped, domain.BulkCreated}; !slices.Equal(statuses(results), want) {
+		t.Fatalf("Import returned %q, want %q", statuses(results), want)
+	}
+	if results[0].ID != museID {
+		t.Errorf("Import of existing song returned id %s, want %s", results[0].ID, museID)
+	}
+
+	info, err := st.Info(ctx, queen)
+	if err != nil {
+		t.Fatalf("Info of imported song: %v", err)
+	}
+	if info.ID != results[1].ID || !sameDate(info.ReleaseDate, queenDetails.ReleaseDate) {
+		t.Errorf("Info of imported song returned %+v, want id %s and release date %s", info, results[1].ID, queenDetails.ReleaseDate)
+	}
+
+	got, err := st.GetLyrics(ctx, queen, &domain.LyricsBatch{Batch: domain.Batch{Offset: 0, Limit: 10}})
+	if err != nil {
+		t.Fatalf("GetLyrics of imported song: %v", err)
+	}
+	if want := []string{"first verse", "second verse"}; !slices.Equal(texts(got), want) {
+		t.Errorf("GetLyrics of imported song returned %q, want %q", texts(got), want)
+	}
+
+	// upsert keeps fields which are not provided
+	results, err = st.Import(ctx, songs[:1], &domain.BulkOptions{Mode: domain.BulkUpsert})
+	if err != nil {
+		t.Fatalf("Import in upsert mode: %v", err)
+	}
+	if len(results) != 1 || results[0].Status != domain.BulkUpdated || results[0].ID != museID {
+		t.Fatalf("Import in upsert mode returned %+v, want updated song %s", results, museID)
+	}
+
+	info, err = st.Info(ctx, muse)
+	if err != nil {
+		t.Fatalf("Info of updated song: %v", err)
+	}
+	if info.Link != songs[0].Link || !sameDate(info.ReleaseDate, museDetails.ReleaseDate) || info.Lyrics != "new verse" {
+		t.Errorf("Info of updated song returned %+v, want link %s, release date %s and new lyrics", info, songs[0].Link, museDetails.ReleaseDate)
+	}
+}
+
+func testExport(t *testing.T, st Storage) {
+	ctx := newContext()
+
+	mustCreate(t, st, muse, museDetails)
+	mustCreate(t, st, queen, queenDetails)
+	beatlesID := mustCreate(t, st, beatles, beatlesDetails)
+
+	err := st.Delete(ctx, queen)
+	if err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	export := func(search *domain.SongSearch) []*domain.ExportedSong {
+		songs := make([]*domain.ExportedSong, 0)
+		err := st.Export(ctx, search, func(song *domain.ExportedSong) error {
+			songs = append(songs, song)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Export: %v", err)
+		}
+		return songs
+	}
+
+	// songs in trash are not exported, pagination is ignored
+	songs := export(&domain.SongSearch{Sort: domain.SortByGroup, Order: domain.OrderDesc, Batch: domain.Batch{Limit: 1}})
+
+	names := make([]string, 0, len(songs))
+	for _, song := range songs {
+		names = append(names, song.Group)
+	}
+	if want := []string{beatles.Group, muse.Group}; !slices.Equal(names, want) {
+		t.Fatalf("Export returned groups %q, want %q", names, want)
+	}
+
+	song := songs[0]
+	if song.ID != beatlesID || song.Link != beatlesDetails.Link || !sameDate(song.ReleaseDate, beatlesDetails.ReleaseDate) {
+		t.Errorf("Export returned %+v, want song %s with details %+v", song, beatlesID, beatlesDetails)
+	}
+	if want := []string{"When I find myself in times of trouble", "Let it be, let it be"}; !slices.Equal(texts(song.Lyrics), want) {
+		t.Errorf("Export returned lyrics %q, want %q", texts(song.Lyrics), want)
+	}
+
+	songs = export(&domain.SongSearch{ByGroup: "mus"})
+	if len(songs) != 1 || songs[0].Group != muse.Group {
+		t.Errorf("Export of found songs returned %d songs, want only %s", len(songs), muse.Group)
+	}
+
+	// export stops at the first error of the callback
+	stop := errors.New("stop")
+	calls := 0
+	err = st.Export(ctx, &domain.SongSearch{}, func(*domain.ExportedSong) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("Export with failing callback returned %v after %d calls, want %v after 1 call", err, calls, stop)
+	}
+}
+
+func testUpdateMetadata(t *testing.T, st Storage) {
+	ctx := newContext()
+
+	mustCreate(t, st, muse, museDetails)
+
+	update := &domain.SongUpdate{
+		SongName:    "Supermassive Black Hole (Live)",
+		Link:        "https://example.com/live",
+		ReleaseDate: date(2008, time.March, 17),
+	}
+
+	err := st.Update(ctx, muse, update)
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	_, err = st.Info(ctx, muse)
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("Info by old name returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+
+	renamed := &domain.Song{Group: muse.Group, SongName: update.SongName}
+
+	info, err := st.Info(ctx, renamed)
+	if err != nil {
+		t.Fatalf("Info by new name: %v", err)
+	}
+
+	if info.Link != update.Link {
+		t.Errorf("Info returned link %q, want %q", info.Link, update.Link)
+	}
+	if !sameDate(info.ReleaseDate, update.ReleaseDate) {
+		t.Errorf("Info returned release date %v, want %v", info.ReleaseDate, update.ReleaseDate)
+	}
+
+	// lyrics must stay untouched if update has no lyrics
+	wantLyrics := strings.Join(texts(museDetails.ToLyricsSchema().Lyrics), "\n")
+	if info.Lyrics != wantLyrics {
+		t.Errorf("Info returned lyrics %q, want %q", info.Lyrics, wantLyrics)
+	}
+}
+
+func testUpdateLyrics(t *testing.T, st Storage) {
+	ctx := newContext()
+
+	mustCreate(t, st, muse, museDetails)
+
+	lyrics := []string{"first verse", "second verse", "third verse"}
+
+	err := st.Update(ctx, muse, &domain.SongUpdate{Lyrics: verses(lyrics...)})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	got, err := st.GetLyrics(ctx, muse, &domain.LyricsBatch{Batch: domain.Batch{Offset: 0, Limit: 10}})
+	if err != nil {
+		t.Fatalf("GetLyrics: %v", err)
+	}
+
+	if !slices.Equal(texts(got), lyrics) {
+		t.Errorf("GetLyrics returned %q, want %q", texts(got), lyrics)
+	}
+}
+
+func testUpdateUnknown(t *testing.T, st Storage) {
+	err := st.Update(newContext(), muse, &domain.SongUpdate{Link: "https://example.com"})
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("Update of unknown song returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+}
+
+func testGetLyrics(t *testing.T, st Storage) {
+	ctx := newContext()
+
+	lyrics := []string{"one", "two", "three", "four", "five"}
+
+	mustCreate(t, st, muse, nil)
+
+	err := st.Update(ctx, muse, &domain.SongUpdate{Lyrics: verses(lyrics...)})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	tests := []struct {
+		batch domain.Batch
+		want  []string
+	}{
+		{domain.Batch{Offset: 0, Limit: 2}, lyrics[:2]},
+		{domain.Batch{Offset: 2, Limit: 2}, lyrics[2:4]},
+		{domain.Batch{Offset: 4, Limit: 2}, lyrics[4:]},
+		{domain.Batch{Offset: 5, Limit: 2}, []string{}},
+	}
+
+	for _, tt := range tests {
+		got, err := st.GetLyrics(ctx, muse, &domain.LyricsBatch{Batch: tt.batch})
+		if err != nil {
+			t.Fatalf("GetLyrics(%+v): %v", tt.batch, err)
+		}
+		if !slices.Equal(texts(got), tt.want) {
+			t.Errorf("GetLyrics(%+v) returned %q, want %q", tt.batch, texts(got), tt.want)
+		}
+	}
+
+	total, err := st.CountLyrics(ctx, muse, &domain.LyricsBatch{})
+	if err != nil {
+		t.Fatalf("CountLyrics: %v", err)
+	}
+	if total != len(lyrics) {
+		t.Errorf("CountLyrics returned %d, want %d", total, len(lyrics))
+	}
+
+	_, err = st.GetLyrics(ctx, queen, &domain.LyricsBatch{Batch: domain.Batch{Offset: 0, Limit: 1}})
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("GetLyrics of unknown song returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+}
+
+func testLyricsKeyset(t *testing.T, st Storage) {
+	ctx := newContext()
+
+	lyrics := []string{"one", "two", "three", "four", "five"}
+
+	mustCreate(t, st, muse, nil)
+
+	err := st.Update(ctx, muse, &domain.SongUpdate{Lyrics: verses(lyrics...)})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	all, err := st.GetLyrics(ctx, muse, &domain.LyricsBatch{Batch: domain.Batch{Offset: 0, Limit: 10}})
+	if err != nil {
+		t.Fatalf("GetLyrics: %v", err)
+	}
+	if len(all) != len(lyrics) {
+		t.Fatalf("GetLyrics returned %q, want %q", texts(all), lyrics)
+	}
+
+	tests := []struct {
+		name  string
+		batch domain.LyricsBatch
+		want  []string
+	}{
+		{"After", domain.LyricsBatch{After: all[1].Position, Batch: domain.Batch{Limit: 2}}, lyrics[2:4]},
+		{"AfterLast", domain.LyricsBatch{After: all[4].Position, Batch: domain.Batch{Limit: 2}}, []string{}},
+		{"Before", domain.LyricsBatch{Before: all[3].Position, Batch: domain.Batch{Limit: 2}}, lyrics[1:3]},
+		{"BeforeSecond", domain.LyricsBatch{Before: all[1].Position, Batch: domain.Batch{Limit: 2}}, lyrics[:1]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := st.GetLyrics(ctx, muse, &tt.batch)
+			if err != nil {
+				t.Fatalf("GetLyrics: %v", err)
+			}
+			if !slices.Equal(texts(got), tt.want) {
+				t.Errorf("GetLyrics returned %q, want %q", texts(got), tt.want)
+			}
+		})
+	}
+}
+
+func testLyricsSections(t *testing.T, st Storage) {
+	ctx := newContext()
+
+	mustCreate(t, st, muse, nil)
+
+	lyrics := []*domain.Verse{
+		{Section: domain.SectionIntro, Text: "intro"},
+		{Text: "first verse"},
+		{Section: domain.SectionChorus, Text: "chorus"},
+		{Section: domain.SectionVerse, Text: "second verse"},
+		{Section: domain.SectionChorus, Text: "chorus again"},
+	}
+
+	err := st.Update(ctx, muse, &domain.SongUpdate{Lyrics: lyrics})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	all, err := st.GetLyrics(ctx, muse, &domain.LyricsBatch{Batch: domain.Batch{Offset: 0, Limit: 10}})
+	if err != nil {
+		t.Fatalf("GetLyrics: %v", err)
+	}
+
+	wantSections := []string{
+		domain.SectionIntro, domain.SectionVerse, domain.SectionChorus, domain.SectionVerse, domain.SectionChorus,
+	}
+	for i, verse := range all {
+		if verse.Position != i+1 || verse.Section != wantSections[i] {
+			t.Errorf("verse %d has position %d and section %q, want %d and %q", i, verse.Position, verse.Section, i+1, wantSections[i])
+		}
+	}
+
+	tests := []struct {
+		name  string
+		batch domain.LyricsBatch
+		want  []string
+	}{
+		{"Chorus", domain.LyricsBatch{Section: domain.SectionChorus, Batch: domain.Batch{Limit: 10}}, []string{"chorus", "chorus again"}},
+		{"ChorusOffset", domain.LyricsBatch{Section: domain.SectionChorus, Batch: domain.Batch{Offset: 1, Limit: 10}}, []string{"chorus again"}},
+		{"ChorusAfter", domain.LyricsBatch{Section: domain.SectionChorus, After: 3, Batch: domain.Batch{Limit: 10}}, []string{"chorus again"}},
+		{"VerseBefore", domain.LyricsBatch{Section: domain.SectionVerse, Before: 4, Batch: domain.Batch{Limit: 10}}, []string{"first verse"}},
+		{"Bridge", domain.LyricsBatch{Section: domain.SectionBridge, Batch: domain.Batch{Limit: 10}}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := st.GetLyrics(ctx, muse, &tt.batch)
+			if err != nil {
+				t.Fatalf("GetLyrics: %v", err)
+			}
+			if !slices.Equal(texts(got), tt.want) {
+				t.Errorf("GetLyrics returned %q, want %q", texts(got), tt.want)
+			}
+		})
+	}
+
+	total, err := st.CountLyrics(ctx, muse, &domain.LyricsBatch{Section: domain.SectionChorus})
+	if err != nil {
+		t.Fatalf("CountLyrics: %v", err)
+	}
+	if total != 2 {
+		t.Errorf("CountLyrics of chorus returned %d, want 2", total)
+	}
+}
+
+func testSyncedLyrics(t *testing.T, st Storage) {
+	ctx := newContext()
+
+	mustCreate(t, st, muse, nil)
+
+	lines := []*domain.Line{
+		{Start: 1000, End: 2500, Text: "Ooh baby"},
+		{
+			Start: 2500,
+			End:   4000,
+			Text:  "don't you know",
+			Words: []*domain.Word{{Start: 2500, Text: "don't"}, {Start: 3000, Text: "you"}, {Start: 3400, Text: "know"}},
+		},
+	}
+
+	lyrics := []*domain.Verse{
+		{Lines: lines},
+		{Text: "not synced"},
+	}
+
+	err := st.Update(ctx, muse, &domain.SongUpdate{Lyrics: lyrics})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	got, err := st.GetLyrics(ctx, muse, &domain.LyricsBatch{Batch: domain.Batch{Offset: 0, Limit: 10}})
+	if err != nil {
+		t.Fatalf("GetLyrics: %v", err)
+	}
+
+	want := []string{"Ooh baby\ndon't you know", "not synced"}
+	if !slices.Equal(texts(got), want) {
+		t.Errorf("GetLyrics returned %q, want %q", texts(got), want)
+	}
+
+	if len(got) == 2 && !reflect.DeepEqual(got[0].Lines, lines) {
+		t.Errorf("GetLyrics returned lines %+v, want %+v", got[0].Lines, lines)
+	}
+	if len(got) == 2 && got[1].Lines != nil {
+		t.Errorf("GetLyrics returned lines %+v of not synced verse, want none", got[1].Lines)
+	}
+
+	all, err := st.Verses(ctx, muse)
+	if err != nil {
+		t.Fatalf("Verses: %v", err)
+	}
+	if !reflect.DeepEqual(all, got) {
+		t.Errorf("Verses returned %q, want %q", texts(all), texts(got))
+	}
+
+	_, err = st.Verses(ctx, queen)
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("Verses of unknown song returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+}
+
+func testTranslations(t *testing.T, st Storage) {
+	ctx := newContext()
+
+	mustCreate(t, st, muse, nil)
+
+	err := st.Update(ctx, muse, &domain.SongUpdate{Lyrics: []*domain.Verse{
+		{Language: "en", Text: "Ooh baby"},
+		{Language: "en", Text: "You set my soul alight"},
+	}})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	err = st.SetTranslation(ctx, muse, &domain.Translation{Language: "ru", Verses: []string{"О, детка"}})
+	if !errors.Is(err, domain.ErrTranslationVerses) {
+		t.Errorf("SetTranslation with missing verse returned %v, want %v", err, domain.ErrTranslationVerses)
+	}
+
+	ru := &domain.Translation{Language: "ru", Verses: []string{"О, детка", "Ты зажигаешь мою душу"}}
+	de := &domain.Translation{Language: "de", Verses: []string{"Oh Baby", "Du entflammst meine Seele"}}
+
+	for _, translation := range []*domain.Translation{ru, de} {
+		err = st.SetTranslation(ctx, muse, translation)
+		if err != nil {
+			t.Fatalf("SetTranslation(%s): %v", translation.Language, err)
+		}
+	}
+
+	languages, err := st.Languages(ctx, muse)
+	if err != nil {
+		t.Fatalf("Languages: %v", err)
+	}
+	if !slices.Equal(languages.Original, []string{"en"}) || !slices.Equal(languages.Translations, []string{"de", "ru"}) {
+		t.Errorf("Languages returned %+v, want original [en] and translations [de ru]", languages)
+	}
+
+	got, err := st.Translation(ctx, muse, "ru")
+	if err != nil {
+		t.Fatalf("Translation: %v", err)
+	}
+	if !reflect.DeepEqual(got, ru) {
+		t.Errorf("Translation returned %+v, want %+v", got, ru)
+	}
+
+	err = st.DeleteTranslation(ctx, muse, "de")
+	if err != nil {
+		t.Fatalf("DeleteTranslation: %v", err)
+	}
+
+	_, err = st.Translation(ctx, muse, "de")
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("Translation after delete returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+
+	err = st.DeleteTranslation(ctx, muse, "de")
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("DeleteTranslation of deleted translation returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+
+	// translations of verses at removed positions are deleted with them
+	err = st.Update(ctx, muse, &domain.SongUpdate{Lyrics: verses("new verse")})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	languages, err = st.Languages(ctx, muse)
+	if err != nil {
+		t.Fatalf("Languages: %v", err)
+	}
+	if len(languages.Original) != 0 || !slices.Equal(languages.Translations, []string{"ru"}) {
+		t.Errorf("Languages after lyrics update returned %+v, want translations [ru]", languages)
+	}
+
+	got, err = st.Translation(ctx, muse, "ru")
+	if err != nil {
+		t.Fatalf("Translation: %v", err)
+	}
+	if !slices.Equal(got.Verses, ru.Verses[:1]) {
+		t.Errorf("Translation after lyrics update returned %q, want %q", got.Verses, ru.Verses[:1])
+	}
+
+	err = st.SetTranslation(ctx, queen, ru)
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("SetTranslation of unknown song returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+}
+
+func testTranslationsOfReplacedLyrics(t *testing.T, st Storage) {
+	ctx := newContext()
+
+	mustCreate(t, st, muse, nil)
+
+	err := st.Update(ctx, muse, &domain.SongUpdate{Lyrics: verses("first", "second")})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	ru := &domain.Translation{Language: "ru", Verses: []string{"первый", "второй"}}
+	err = st.SetTranslation(ctx, muse, ru)
+	if err != nil {
+		t.Fatalf("SetTranslation: %v", err)
+	}
+
+	// verses are appended, so translations of the existing positions are kept
+	err = st.Update(ctx, muse, &domain.SongUpdate{Lyrics: verses("first", "second", "third")})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	checkTranslation(t, st, muse, ru)
+
+	_, err = st.Import(ctx, []*domain.BulkSong{{
+		Group:    muse.Group,
+		SongName: muse.SongName,
+		Lyrics:   verses("first", "second", "third", "fourth"),
+	}}, &domain.BulkOptions{Mode: domain.BulkUpsert})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	checkTranslation(t, st, muse, ru)
+
+	revisions, err := st.Revisions(ctx, muse, &domain.Batch{Limit: 10})
+	if err != nil {
+		t.Fatalf("Revisions: %v", err)
+	}
+
+	last, err := st.Revision(ctx, muse, revisions[0].Number)
+	if err != nil {
+		t.Fatalf("Revision: %v", err)
+	}
+	if !reflect.DeepEqual(last.Song.Translations, []*domain.Translation{ru}) {
+		t.Errorf("Revision snapshot has translations %+v, want %+v", last.Song.Translations, []*domain.Translation{ru})
+	}
+
+	err = st.DeleteTranslation(ctx, muse, "ru")
+	if err != nil {
+		t.Fatalf("DeleteTranslation: %v", err)
+	}
+
+	err = st.Restore(ctx, muse, last.Number)
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	checkTranslation(t, st, muse, ru)
+}
+
+func checkTranslation(t *testing.T, st Storage, song *domain.Song, want *domain.Translation) {
+	t.Helper()
+
+	got, err := st.Translation(newContext(), song, want.Language)
+	if err != nil {
+		t.Fatalf("Translation(%s): %v", want.Language, err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Translation(%s) returned %+v, want %+v", want.Language, got, want)
+	}
+}
+
+func testRevisions(t *testing.T, st Storage) {
+	ctx := domain.WithAuthor(newContext(), "editor")
+
+	mustCreate(t, st, muse, museDetails)
+
+	err := st.Update(ctx, muse, &domain.SongUpdate{
+		Link:   "https://example.com/muse",
+		Lyrics: verses("Ooh baby"),
+	})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	revisions, err := st.Revisions(ctx, muse, &domain.Batch{Limit: 10})
+	if err != nil {
+		t.Fatalf("Revisions: %v", err)
+	}
+	if len(revisions) != 2 || revisions[0].Number != 2 || revisions[1].Number != 1 {
+		t.Fatalf("Revisions returned %d revisions, want revisions 2 and 1", len(revisions))
+	}
+
+	last := revisions[0]
+	if last.Author != "editor" || !last.LyricsChanged || last.Song != nil {
+		t.Errorf("Revisions returned %+v, want lyrics change by editor without snapshot", last)
+	}
+	wantChanges := []*domain.Change{{Field: domain.FieldLink, Old: museDetails.Link, New: "https://example.com/muse"}}
+	if !reflect.DeepEqual(last.Changes, wantChanges) {
+		t.Errorf("Revisions returned changes %+v, want %+v", last.Changes, wantChanges)
+	}
+
+	first, err := st.Revision(ctx, muse, 1)
+	if err != nil {
+		t.Fatalf("Revision: %v", err)
+	}
+	if first.Song == nil || first.Song.Link != museDetails.Link || len(first.Song.Lyrics) != 2 {
+		t.Fatalf("Revision returned %+v, want snapshot of the created song", first)
+	}
+
+	_, err = st.Revision(ctx, muse, 3)
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("Revision of unknown number returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+
+	err = st.Restore(ctx, muse, 1)
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+
+	info, err := st.Info(ctx, muse)
+	if err != nil {
+		t.Fatalf("Info: %v", err)
+	}
+	if info.Link != museDetails.Link || !sameDate(info.ReleaseDate, museDetails.ReleaseDate) {
+		t.Errorf("Info after restore returned %+v, want metadata of revision 1", info)
+	}
+
+	got, err := st.Verses(ctx, muse)
+	if err != nil {
+		t.Fatalf("Verses: %v", err)
+	}
+	if !reflect.DeepEqual(got, first.Song.Lyrics) {
+		t.Errorf("Verses after restore returned %v, want %v", texts(got), texts(first.Song.Lyrics))
+	}
+
+	restored, err := st.Revision(ctx, muse, 3)
+	if err != nil {
+		t.Fatalf("Revision after restore: %v", err)
+	}
+	if !restored.LyricsChanged || len(restored.Changes) != 1 || restored.Changes[0].New != museDetails.Link {
+		t.Errorf("Revision after restore returned %+v, want link and lyrics changed back", restored)
+	}
+
+	page, err := st.Revisions(ctx, muse, &domain.Batch{Offset: 1, Limit: 1})
+	if err != nil {
+		t.Fatalf("Revisions: %v", err)
+	}
+	if len(page) != 1 || page[0].Number != 2 {
+		t.Errorf("Revisions with offset 1 and limit 1 returned %d revisions, want revision 2", len(page))
+	}
+
+	_, err = st.Revisions(ctx, queen, &domain.Batch{Limit: 10})
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Errorf("Revisions of unknown song returned %v, want %v", err, domain.ErrUnknownResourse)
+	}
+}
+
+func testSearch(t *testing.T, st Storage) {
+	mustCreate(t, st, muse, museDetails)
+	mustCreate(t, st, queen, queenDetails)
+	mustCreate(t, st, beatles, beatlesDetails)
+
+	tests := []struct {
+		name   string
+		search domain.SongSearch
+		want   []*domain.Song
+	}{
+		{"All", domain.SongSearch{}, []*domain.Song{muse, queen, beatles}},
+		{"ByGroupCaseInsensitive", domain.SongSearch{ByGroup: "QUE"}, []*domain.Song{queen}},
+		{"BySongName", domain.SongSearch{BySongName: "black"}, []*domain.Song{muse}},
+		{"ByLyrics", domain.SongSearch{ByLyrics: "let it"}, []*domain.Song{beatles}},
+		{"ByLyricsPhrase", domain.SongSearch{ByLyrics: `"times of trouble"`}, []*domain.Song{beatles}},
+		{"ByLyricsOr", domain.SongSearch{ByLyrics: "killed or suffer"}, []*domain.Song{muse, queen}},
+		{"ByLyricsExclude", domain.SongSearch{ByLyrics: "ooh -moan"}, []*domain.Song{muse}},
+		{"ByLink", domain.SongSearch{ByLink: "fJ9rUz"}, []*domain.Song{queen}},
+		{"DateFrom", domain.SongSearch{DateFrom: date(1975, time.October, 31)}, []*domain.Song{muse, queen}},
+		{"DateTo", domain.SongSearch{DateTo: date(1975, time.October, 31)}, []*domain.Song{queen, beatles}},
+		{"DateRange", domain.SongSearch{DateFrom: date(1971, time.January, 1), DateTo: date(2000, time.January, 1)}, []*domain.Song{queen}},
+		{"Combined", domain.SongSearch{ByGroup: "e", ByLyrics: "real"}, []*domain.Song{queen}},
+		{"Wildcard", domain.SongSearch{BySongName: "Let_It"}, []*domain.Song{beatles}},
+		{"NoMatch", domain.SongSearch{ByGroup: "Nirvana"}, []*domain.Song{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			search := tt.search
+			search.Batch = domain.Batch{Offset: 0, Limit: 10}
+
+			got, err := st.Search(newContext(), &search)
+			if err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+
+			if !sameSongs(got, tt.want) {
+				t.Errorf("Search returned %v, want %v", foundNames(got), songNames(tt.want))
+			}
+		})
+	}
+}
+
+func testSearchPagination(t *testing.T, st Storage) {
+	ctx := newContext()
+
+	mustCreate(t, st, muse, museDetails)
+	mustCreate(t, st, queen, queenDetails)
+	mustCreate(t, st, beatles, beatlesDetails)
+
+	mustCreate(t, st, &domain.Song{Group: muse.Group, SongName: "Uprising"}, nil)
+
+	all := make([]*domain.FoundSong, 0)
+
+	for offset := 0; offset < 6; offset += 2 {
+		songs, err := st.Search(ctx, &domain.SongSearch{Batch: domain.Batch{Offset: offset, Limit: 2}})
+		if err != nil {
+			t.Fatalf("Search: %v", err)
+		}
+		if len(songs) > 2 {
+			t.Fatalf("Search returned %d songs, want at most 2", len(songs))
+		}
+		all = append(all, songs...)
+	}
+
+	if len(all) != 4 {
+		t.Errorf("pages returned %d songs, want 4", len(all))
+	}
+
+	ids := make(map[uuid.UUID]struct{})
+	for _, song := range all {
+		ids[song.ID] = struct{}{}
+	}
+	if len(ids) != len(all) {
+		t.Errorf("pages returned %d unique ids, want %d", len(ids), len(all))
+	}
+}
+
+func testSearchByLyricsRank(t *testing.T, st Storage) {
+	mustCreate(t, st, muse, museDetails)
+	mustCreate(t, st, beatles, beatlesDetails)
+
+	found, err := st.Search(newContext(), &domain.SongSearch{
+		ByLyrics: "let",
+		Batch:    domain.Batch{Offset: 0, Limit: 10},
+	})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	if len(found) != 1 {
+		t.Fatalf("Search returned %v, want %v", foundNames(found), songNames([]*domain.Song{beatles}))
+	}
+
+	if found[0].Rank <= 0 {
+		t.Errorf("Search returned rank %v, want positive", found[0].Rank)
+	}
+	if !strings.Contains(found[0].Headline, "<b>") {
+		t.Errorf("Search returned headline %q without highlighted words", found[0].Headline)
+	}
+
+	// the best queen verse repeats the word more times than the beatles one
+	mustCreate(t, st, queen, &domain.SongDetails{Text: "Let me go\n\nLet it be, let it be, let it be"})
+
+	found, err = st.Search(newContext(), &domain.SongSearch{
+		ByLyrics: "let",
+		Batch:    domain.Batch{Offset: 0, Limit: 10},
+	})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	if len(found) != 2 || found[0].SongName != queen.SongName {
+		t.Errorf("Search returned %v, want %s first", foundNames(found), queen.SongName)
+	}
+}
+
+func testFuzzySearch(t *testing.T, st Storage) {
+	mustCreate(t, st, muse, museDetails)
+	mustCreate(t, st, queen, queenDetails)
+	mustCreate(t, st, beatles, beatlesDetails)
+
+	tests := []struct {
+		name   string
+		search domain.SongSearch
+		want   []*domain.Song
+	}{
+		{"ByGroupTypo", domain.SongSearch{ByGroup: "Mse"}, []*domain.Song{muse}},
+		{"BySongNameTypos", domain.SongSearch{BySongName: "Bohemain Rapsody"}, []*domain.Song{queen}},
+		{"Combined", domain.SongSearch{ByGroup: "Beatls", BySongName: "Let It Bee"}, []*domain.Song{beatles}},
+		{"HighThreshold", domain.SongSearch{ByGroup: "Mse", Threshold: 0.9}, []*domain.Song{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			search := tt.search
+			search.Fuzzy = true
+			if search.Threshold == 0 {
+				search.Threshold = 0.3
+			}
+			search.Batch = domain.Batch{Offset: 0, Limit: 10}
+
+			got, err := st.Search(newContext(), &search)
+			if err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+
+			if !sameSongs(got, tt.want) {
+				t.Errorf("Search returned %v, want %v", foundNames(got), songNames(tt.want))
+			}
+
+			for _, song := range got {
+				if song.Similarity < float32(search.Threshold) {
+					t.Errorf("Search returned similarity %v, want at least %v", song.Similarity, search.Threshold)
+				}
+			}
+		})
+	}
+}
+
+func testSearchSort(t *testing.T, st Storage) {
+	mustCreate(t, st, muse, museDetails)
+	mustCreate(t, st, queen, queenDetails)
+	mustCreate(t, st, beatles, beatlesDetails)
+
+	tests := []struct {
+		name   string
+		search domain.SongSearch
+		want   []*domain.Song
+	}{
+		{"Default", domain.SongSearch{}, []*domain.Song{muse, queen, beatles}},
+		{"CreatedAtDesc", domain.SongSearch{Sort: domain.SortByCreatedAt, Order: domain.OrderDesc}, []*domain.Song{beatles, queen, muse}},
+		{"Group", domain.SongSearch{Sort: domain.SortByGroup}, []*domain.Song{muse, queen, beatles}},
+		{"SongNameDesc", domain.SongSearch{Sort: domain.SortBySongName, Order: domain.OrderDesc}, []*domain.Song{muse, beatles, queen}},
+		{"ReleaseDate", domain.SongSearch{Sort: domain.SortByReleaseDate}, []*domain.Song{beatles, queen, muse}},
+		{"RelevanceAsc", domain.SongSearch{ByLyrics: "ooh or real", Sort: domain.SortByRelevance, Order: domain.OrderAsc}, []*domain.Song{queen, muse}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			search := tt.search
+			search.Batch = domain.Batch{Offset: 0, Limit: 10}
+
+			got, err := st.Search(newContext(), &search)
+			if err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+
+			gotNames := make([]string, 0, len(got))
+			for _, song := range got {
+				gotNames = append(gotNames, song.Group+" - "+song.SongName)
+			}
+			wantNames := make([]string, 0, len(tt.want))
+			for _, song := range tt.want {
+				wantNames = append(wantNames, song.Group+" - "+song.SongName)
+			}
+
+			if !slices.Equal(gotNames, wantNames) {
+				t.Errorf("Search returned %v, want %v", gotNames, wantNames)
+			}
+		})
+	}
+
+	// songs updated later are the last ones sorted by update time
+	err := st.Update(newContext(), muse, &domain.SongUpdate{Link: "https://example.com/muse"})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	got, err := st.Search(newContext(), &domain.SongSearch{
+		Sort:  domain.SortByUpdatedAt,
+		Batch: domain.Batch{Offset: 0, Limit: 10},
+	})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	if len(got) != 3 || got[2].SongName != muse.SongName {
+		t.Errorf("Search returned %v, want %s last", foundNames(got), muse.SongName)
+	}
+}
+
+func testSearchKeyset(t *testing.T, st Storage) {
+	ctx := newContext()
+
+	mustCreate(t, st, muse, museDetails)
+	mustCreate(t, st, queen, queenDetails)
+	mustCreate(t, st, beatles, beatlesDetails)
+	mustCreate(t, st, &domain.Song{Group: "Muse", SongName: "Hysteria"}, nil)
+
+	for _, order := range []string{domain.OrderAsc, domain.OrderDesc} {
+		for _, field := range []string{domain.SortByGroup, domain.SortByReleaseDate, domain.SortByCreatedAt} {
+			t.Run(field+" "+order, func(t *testing.T) {
+				search := domain.SongSearch{Sort: field, Order: order, Batch: domain.Batch{Offset: 0, Limit: 10}}
+
+				all, err := st.Search(ctx, &search)
+				if err != nil {
+					t.Fatalf("Search: %v", err)
+				}
+				if len(all) != 4 {
+					t.Fatalf("Search returned %v, want 4 songs", foundNames(all))
+				}
+
+				total, err := st.Count(ctx, &search)
+				if err != nil {
+					t.Fatalf("Count: %v", err)
+				}
+				if total != len(all) {
+					t.Errorf("Count returned %d, want %d", total, len(all))
+				}
+
+				// walk forward by pages of 2 songs
+				search.Limit = 2
+				search.After = all[1].Key()
+
+				got, err := st.Search(ctx, &search)
+				if err != nil {
+					t.Fatalf("Search: %v", err)
+				}
+				if !sameIDs(got, all[2:]) {
+					t.Errorf("Search after the second song returned %v, want %v", foundNames(got), foundNames(all[2:]))
+				}
+
+				// and back from the last song
+				search.After = nil
+				search.Before = all[3].Key()
+
+				got, err = st.Search(ctx, &search)
+				if err != nil {
+					t.Fatalf("Search: %v", err)
+				}
+				if !sameIDs(got, all[1:3]) {
+					t.Errorf("Search before the last song returned %v, want %v", foundNames(got), foundNames(all[1:3]))
+				}
+			})
+		}
+	}
+}
+
+func testSuggest(t *testing.T, st Storage) {
+	mustCreate(t, st, muse, museDetails)
+	mustCreate(t, st, queen, queenDetails)
+	mustCreate(t, st, beatles, beatlesDetails)
+
+	suggestions, err := st.Suggest(newContext(), &domain.SongSearch{
+		ByGroup:    "Qeen",
+		BySongName: "Supermasive",
+		Threshold:  0.3,
+	})
+	if err != nil {
+		t.Fatalf("Suggest: %v", err)
+	}
+
+	if !slices.Equal(suggestions.Groups, []string{queen.Group}) {
+		t.Errorf("Suggest returned groups %q, want %q", suggestions.Groups, []string{queen.Group})
+	}
+	if !slices.Equal(suggestions.Songs, []string{muse.SongName}) {
+		t.Errorf("Suggest returned songs %q, want %q", suggestions.Songs, []string{muse.SongName})
+	}
+}
+
+func mustCreate(t *testing.T, st Storage, song *domain.Song, details *domain.SongDetails) uuid.UUID {
+	t.Helper()
+
+	id, err := st.Create(newContext(), song, details)
+	if err != nil {
+		t.Fatalf("Create(%s - %s): %v", song.Group, song.SongName, err)
+	}
+
+	return id
+}
+
+// storages expect operation id in context for logging
+func newContext() context.Context {
+	return context.WithValue(context.Background(), logmsg.OperationID, uuid.New())
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func sameDate(a, b time.Time) bool {
+	return a.Year() == b.Year() && a.Month() == b.Month() && a.Day() == b.Day()
+}
+
+// order of search results is not defined
+func sameSongs(got []*domain.FoundSong, want []*domain.Song) bool {
+	return slices.Equal(foundNames(got), songNames(want))
+}
+
+func foundNames(found []*domain.FoundSong) []string {
+	songs := make([]*domain.Song, 0, len(found))
+	for _, song := range found {
+		songs = append(songs, &song.Song)
+	}
+	return songNames(songs)
+}
+
+// compares found songs in order
+func sameIDs(got, want []*domain.FoundSong) bool {
+	return slices.EqualFunc(got, want, func(a, b *domain.FoundSong) bool {
+		return a.ID == b.ID
+	})
+}
+
+// Returns verses without section.
+func verses(texts ...string) []*domain.Verse {
+	result := make([]*domain.Verse, 0, len(texts))
+	for _, text := range texts {
+		result = append(result, &domain.Verse{Text: text})
+	}
+	return result
+}
+
+func texts(verses []*domain.Verse) []string {
+	result := make([]string, 0, len(verses))
+	for _, verse := range verses {
+		result = append(result, verse.Text)
+	}
+	return result
+}
+
+func songNames(songs []*domain.Song) []string {
+	names := make([]string, 0, len(songs))
+	for _, song := range songs {
+		names = append(names, song.Group+" - "+song.SongName)
+	}
+	slices.Sort(names)
+	return names
+}
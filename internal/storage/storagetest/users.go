@@ -0,0 +1,306 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/qreaqtor/music-library/internal/domain"
+)
+
+// UsersStorage keeps users and their sessions.
+type UsersStorage interface {
+	CreateUser(context.Context, *domain.UserAccount) (*domain.User, error)
+	User(context.Context, uuid.UUID) (*domain.User, error)
+	UserByName(context.Context, string) (*domain.UserAccount, error)
+	CreateSession(context.Context, *domain.Session) error
+	Session(context.Context, uuid.UUID) (*domain.Session, error)
+	RotateSession(ctx context.Context, id, refreshID, newRefreshID uuid.UUID, expiresAt time.Time) error
+	RevokeSession(context.Context, uuid.UUID) error
+	RevokeSessions(context.Context, uuid.UUID) (int, error)
+	Users(context.Context, *domain.Batch) ([]*domain.User, error)
+	SetRole(ctx context.Context, id uuid.UUID, role string) (*domain.User, error)
+}
+
+type NewUsers func(t *testing.T) UsersStorage
+
+// Runs conformance tests of users storage against storages returned by newStorage.
+func RunUsers(t *testing.T, newStorage NewUsers) {
+	tests := []struct {
+		name string
+		test func(*testing.T, UsersStorage)
+	}{
+		{"CreateUser", testCreateUser},
+		{"Sessions", testSessions},
+		{"RotateSession", testRotateSession},
+		{"Roles", testRoles},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newStorage(t))
+		})
+	}
+}
+
+func testCreateUser(t *testing.T, st UsersStorage) {
+	ctx := newContext()
+
+	user, err := st.CreateUser(ctx, &domain.UserAccount{
+		User:         domain.User{Name: "Alice", Role: domain.RoleViewer},
+		PasswordHash: []byte("hash"),
+	})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	if user.ID == uuid.Nil || user.Name != "Alice" || user.CreatedAt.IsZero() {
+		t.Fatalf("CreateUser returned %+v", user)
+	}
+
+	_, err = st.CreateUser(ctx, &domain.UserAccount{
+		User:         domain.User{Name: "alice", Role: domain.RoleViewer},
+		PasswordHash: []byte("other"),
+	})
+	if !errors.Is(err, domain.ErrUserExists) {
+		t.Fatalf("CreateUser with the same name in another case: got %v, want ErrUserExists", err)
+	}
+
+	account, err := st.UserByName(ctx, "ALICE")
+	if err != nil {
+		t.Fatalf("UserByName: %v", err)
+	}
+	if account.ID != user.ID || string(account.PasswordHash) != "hash" {
+		t.Fatalf("UserByName returned %+v", account)
+	}
+
+	found, err := st.User(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("User: %v", err)
+	}
+	if found.Name != "Alice" {
+		t.Fatalf("User name = %q, want Alice", found.Name)
+	}
+
+	_, err = st.UserByName(ctx, "bob")
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Fatalf("UserByName of unknown user: got %v, want ErrUnknownResourse", err)
+	}
+
+	_, err = st.User(ctx, uuid.New())
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Fatalf("User of unknown id: got %v, want ErrUnknownResourse", err)
+	}
+}
+
+func testSessions(t *testing.T, st UsersStorage) {
+	ctx := newContext()
+
+	user := mustCreateUser(t, st, "alice")
+
+	first := mustCreateSession(t, st, user.ID)
+	second := mustCreateSession(t, st, user.ID)
+
+	session, err := st.Session(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("Session: %v", err)
+	}
+	if session.UserID != user.ID || session.RefreshID != first.RefreshID || session.Revoked {
+		t.Fatalf("Session returned %+v", session)
+	}
+
+	err = st.RevokeSession(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("RevokeSession: %v", err)
+	}
+
+	// revoking twice is not an error
+	err = st.RevokeSession(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("RevokeSession of revoked session: %v", err)
+	}
+
+	session, err = st.Session(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("Session after revoke: %v", err)
+	}
+	if !session.Revoked {
+		t.Fatal("session is not revoked")
+	}
+
+	err = st.RevokeSession(ctx, uuid.New())
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Fatalf("RevokeSession of unknown session: got %v, want ErrUnknownResourse", err)
+	}
+
+	mustCreateSession(t, st, user.ID)
+
+	revoked, err := st.RevokeSessions(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("RevokeSessions: %v", err)
+	}
+	if revoked != 2 {
+		t.Fatalf("RevokeSessions revoked %d sessions, want 2", revoked)
+	}
+
+	session, err = st.Session(ctx, second.ID)
+	if err != nil {
+		t.Fatalf("Session after RevokeSessions: %v", err)
+	}
+	if !session.Revoked {
+		t.Fatal("session is not revoked by RevokeSessions")
+	}
+
+	err = st.CreateSession(ctx, &domain.Session{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		RefreshID: uuid.New(),
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Fatalf("CreateSession of unknown user: got %v, want ErrUnknownResourse", err)
+	}
+}
+
+func testRotateSession(t *testing.T, st UsersStorage) {
+	ctx := newContext()
+
+	user := mustCreateUser(t, st, "alice")
+	session := mustCreateSession(t, st, user.ID)
+
+	refreshID := uuid.New()
+	expiresAt := time.Now().Add(2 * time.Hour).Truncate(time.Second)
+
+	err := st.RotateSession(ctx, session.ID, session.RefreshID, refreshID, expiresAt)
+	if err != nil {
+		t.Fatalf("RotateSession: %v", err)
+	}
+
+	rotated, err := st.Session(ctx, session.ID)
+	if err != nil {
+		t.Fatalf("Session: %v", err)
+	}
+	if rotated.RefreshID != refreshID || !rotated.ExpiresAt.Equal(expiresAt) {
+		t.Fatalf("rotated session is %+v, want refresh id %s expiring at %s", rotated, refreshID, expiresAt)
+	}
+
+	// the replaced refresh token can't be used again
+	err = st.RotateSession(ctx, session.ID, session.RefreshID, uuid.New(), expiresAt)
+	if !errors.Is(err, domain.ErrInvalidToken) {
+		t.Fatalf("RotateSession with replaced refresh id: got %v, want ErrInvalidToken", err)
+	}
+
+	err = st.RevokeSession(ctx, session.ID)
+	if err != nil {
+		t.Fatalf("RevokeSession: %v", err)
+	}
+
+	err = st.RotateSession(ctx, session.ID, refreshID, uuid.New(), expiresAt)
+	if !errors.Is(err, domain.ErrInvalidToken) {
+		t.Fatalf("RotateSession of revoked session: got %v, want ErrInvalidToken", err)
+	}
+}
+
+// Users get the requested role, the first one too.
+func testRoles(t *testing.T, st UsersStorage) {
+	ctx := newContext()
+
+	first := mustCreateUser(t, st, "alice")
+	if first.Role != domain.RoleViewer {
+		t.Fatalf("role of the first user = %q, want %s", first.Role, domain.RoleViewer)
+	}
+
+	second := mustCreateUser(t, st, "bob")
+	if second.Role != domain.RoleViewer {
+		t.Fatalf("role of the second user = %q, want %s", second.Role, domain.RoleViewer)
+	}
+
+	updated, err := st.SetRole(ctx, second.ID, domain.RoleEditor)
+	if err != nil {
+		t.Fatalf("SetRole: %v", err)
+	}
+	if updated.ID != second.ID || updated.Role != domain.RoleEditor {
+		t.Fatalf("SetRole returned %+v", updated)
+	}
+
+	found, err := st.User(ctx, second.ID)
+	if err != nil {
+		t.Fatalf("User: %v", err)
+	}
+	if found.Role != domain.RoleEditor {
+		t.Fatalf("role after SetRole = %q, want %s", found.Role, domain.RoleEditor)
+	}
+
+	account, err := st.UserByName(ctx, "bob")
+	if err != nil {
+		t.Fatalf("UserByName: %v", err)
+	}
+	if account.Role != domain.RoleEditor {
+		t.Fatalf("role of UserByName = %q, want %s", account.Role, domain.RoleEditor)
+	}
+
+	_, err = st.SetRole(ctx, uuid.New(), domain.RoleEditor)
+	if !errors.Is(err, domain.ErrUnknownResourse) {
+		t.Fatalf("SetRole of unknown user: got %v, want ErrUnknownResourse", err)
+	}
+
+	users, err := st.Users(ctx, &domain.Batch{Limit: 10})
+	if err != nil {
+		t.Fatalf("Users: %v", err)
+	}
+	if len(users) != 2 || users[0].ID != first.ID || users[1].ID != second.ID {
+		t.Fatalf("Users returned %+v, want alice and bob in order of registration", users)
+	}
+
+	users, err = st.Users(ctx, &domain.Batch{Offset: 1, Limit: 10})
+	if err != nil {
+		t.Fatalf("Users with offset: %v", err)
+	}
+	if len(users) != 1 || users[0].ID != second.ID {
+		t.Fatalf("Users with offset returned %+v, want bob", users)
+	}
+
+	admin, err := st.CreateUser(ctx, &domain.UserAccount{
+		User:         domain.User{Name: "root", Role: domain.RoleAdmin},
+		PasswordHash: []byte("hash"),
+	})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	if admin.Role != domain.RoleAdmin {
+		t.Fatalf("role of the admin = %q, want %s", admin.Role, domain.RoleAdmin)
+	}
+}
+
+func mustCreateUser(t *testing.T, st UsersStorage, name string) *domain.User {
+	t.Helper()
+
+	user, err := st.CreateUser(newContext(), &domain.UserAccount{
+		User:         domain.User{Name: name, Role: domain.RoleViewer},
+		PasswordHash: []byte("hash"),
+	})
+	if err != nil {
+		t.Fatalf("CreateUser(%s): %v", name, err)
+	}
+
+	return user
+}
+
+func mustCreateSession(t *testing.T, st UsersStorage, userID uuid.UUID) *domain.Session {
+	t.Helper()
+
+	session := &domain.Session{
+		ID:        uuid.New(),
+		UserID:    userID,
+		RefreshID: uuid.New(),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	err := st.CreateSession(newContext(), session)
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+
+	return session
+}
@@ -0,0 +1,57 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+create table artists
+(
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid (),
+    name varchar(100) NOT NULL,
+    aliases text[] NOT NULL DEFAULT '{}',
+    country char(2),
+    formed DATE,
+    disbanded DATE,
+    bio text,
+    CONSTRAINT artist_dates CHECK (disbanded >= formed)
+);
+
+CREATE UNIQUE INDEX idx_artist_name ON artists (lower(name));
+
+CREATE INDEX idx_artist_aliases ON artists USING GIN (aliases);
+
+-- groups which differ only in case become the same artist
+INSERT INTO artists (name)
+SELECT DISTINCT ON (lower(group_name)) group_name
+FROM songs
+ORDER BY lower(group_name), group_name;
+
+ALTER TABLE songs ADD COLUMN artist_id uuid REFERENCES artists (id) ON DELETE RESTRICT;
+
+UPDATE songs s SET artist_id = a.id FROM artists a WHERE lower(a.name) = lower(s.group_name);
+
+ALTER TABLE songs ALTER COLUMN artist_id SET NOT NULL;
+
+DROP INDEX idx_group_song;
+
+ALTER TABLE songs DROP COLUMN group_name;
+
+CREATE INDEX idx_artist_song ON songs (artist_id, song);
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+ALTER TABLE songs ADD COLUMN group_name varchar(100);
+
+UPDATE songs s SET group_name = a.name FROM artists a WHERE a.id = s.artist_id;
+
+ALTER TABLE songs ALTER COLUMN group_name SET NOT NULL;
+
+DROP INDEX idx_artist_song;
+
+ALTER TABLE songs DROP COLUMN artist_id;
+
+CREATE INDEX idx_group_song ON songs (group_name, song);
+
+DROP TABLE artists;
@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+create table albums
+(
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid (),
+    title varchar(200) NOT NULL,
+    artist_id uuid NOT NULL REFERENCES artists (id) ON DELETE RESTRICT,
+    release_date DATE,
+    cover_url text,
+    label varchar(200)
+);
+
+CREATE INDEX idx_album_artist ON albums (artist_id);
+
+create table album_tracks
+(
+    album_id uuid NOT NULL REFERENCES albums (id) ON DELETE CASCADE,
+    song_id uuid NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    disc_number integer NOT NULL DEFAULT 1 CHECK (disc_number > 0),
+    track_number integer NOT NULL CHECK (track_number > 0),
+    duration integer CHECK (duration >= 0),
+    PRIMARY KEY (album_id, disc_number, track_number)
+);
+
+CREATE INDEX idx_track_song ON album_tracks (song_id);
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+DROP TABLE album_tracks;
+
+DROP TABLE albums;
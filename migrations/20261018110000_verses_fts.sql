@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+-- btree index can't serve ILIKE '%...%', full text index is used instead
+DROP INDEX IF EXISTS idx_verse;
+
+-- generated column can't depend on a setting, so text search configuration
+-- (SEARCH_LANGUAGE at the moment of insert) is stored with every verse
+ALTER TABLE verses
+    ADD COLUMN lang regconfig NOT NULL DEFAULT 'simple',
+    ADD COLUMN tsv tsvector GENERATED ALWAYS AS (to_tsvector(lang, verse)) STORED;
+
+CREATE INDEX idx_verse_tsv ON verses USING GIN (tsv);
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+DROP INDEX IF EXISTS idx_verse_tsv;
+
+ALTER TABLE verses
+    DROP COLUMN tsv,
+    DROP COLUMN lang;
+
+CREATE INDEX idx_verse ON verses (verse);
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- serve fuzzy search and suggestions with word similarity operator
+CREATE INDEX idx_artist_name_trgm ON artists USING GIN (name gin_trgm_ops);
+
+CREATE INDEX idx_song_trgm ON songs USING GIN (song gin_trgm_ops);
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+DROP INDEX IF EXISTS idx_song_trgm;
+
+DROP INDEX IF EXISTS idx_artist_name_trgm;
+
+DROP EXTENSION IF EXISTS pg_trgm;
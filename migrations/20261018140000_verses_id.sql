@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+-- verses keep insertion order by id, existing verses are numbered in their physical order
+ALTER TABLE verses ADD COLUMN id bigserial PRIMARY KEY;
+
+-- serves lyrics of a song ordered by id and keyset pagination
+DROP INDEX IF EXISTS idx_song_id;
+
+CREATE INDEX idx_verse_song_id ON verses (song_id, id);
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+DROP INDEX IF EXISTS idx_verse_song_id;
+
+CREATE INDEX idx_song_id ON verses (song_id);
+
+ALTER TABLE verses DROP COLUMN id;
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+-- existing verses are numbered in their insertion order and marked as verse sections
+ALTER TABLE verses
+    ADD COLUMN position int,
+    ADD COLUMN section text NOT NULL DEFAULT 'verse'
+        CONSTRAINT verses_section_check CHECK (section IN ('intro', 'verse', 'pre-chorus', 'chorus', 'bridge', 'outro'));
+
+UPDATE verses v SET position = numbered.position
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY song_id ORDER BY id) AS position
+    FROM verses
+) numbered
+WHERE v.id = numbered.id;
+
+ALTER TABLE verses ALTER COLUMN position SET NOT NULL;
+
+-- serves lyrics of a song ordered by position and keyset pagination
+DROP INDEX IF EXISTS idx_verse_song_id;
+
+CREATE UNIQUE INDEX idx_verse_song_position ON verses (song_id, position);
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+DROP INDEX IF EXISTS idx_verse_song_position;
+
+CREATE INDEX idx_verse_song_id ON verses (song_id, id);
+
+ALTER TABLE verses DROP COLUMN section, DROP COLUMN position;
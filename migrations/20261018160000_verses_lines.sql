@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+-- timed lines of synced lyrics: [{"start": ms, "end": ms, "text": "...", "words": [{"start": ms, "text": "..."}]}]
+ALTER TABLE verses ADD COLUMN lines jsonb;
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+ALTER TABLE verses DROP COLUMN lines;
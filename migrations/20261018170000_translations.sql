@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+-- BCP 47 language code of the verse, NULL if it is unknown
+ALTER TABLE verses ADD COLUMN language text;
+
+-- translations are aligned verse by verse, so they are deleted together with replaced verses
+create table verse_translations
+(
+    song_id uuid NOT NULL,
+    position integer NOT NULL,
+    language text NOT NULL,
+    verse text NOT NULL,
+    PRIMARY KEY (song_id, language, position),
+    FOREIGN KEY (song_id, position) REFERENCES verses (song_id, position) ON DELETE CASCADE
+);
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+DROP TABLE verse_translations;
+
+ALTER TABLE verses DROP COLUMN language;
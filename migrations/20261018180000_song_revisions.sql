@@ -0,0 +1,47 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+-- revisions are immutable, snapshot is the full state of the song after the revision
+create table song_revisions
+(
+    song_id uuid NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    number integer NOT NULL,
+    author text,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    changes jsonb NOT NULL,
+    lyrics_changed boolean NOT NULL,
+    snapshot jsonb NOT NULL,
+    PRIMARY KEY (song_id, number)
+);
+
+-- existing songs get the first revision with their current state
+INSERT INTO song_revisions (song_id, number, created_at, changes, lyrics_changed, snapshot)
+SELECT s.id, 1, s.updated_at, '[]'::jsonb, false,
+    jsonb_build_object(
+        'group', a.name,
+        'song', s.song,
+        'link', COALESCE(s.link, ''),
+        'releaseDate', to_char(s.releaseDate, 'YYYY-MM-DD"T00:00:00Z"'),
+        'lyrics', COALESCE(
+            (SELECT jsonb_agg(
+                jsonb_strip_nulls(jsonb_build_object(
+                    'position', v.position,
+                    'section', v.section,
+                    'language', v.language,
+                    'text', v.verse,
+                    'lines', v.lines
+                )) ORDER BY v.position)
+            FROM verses v WHERE v.song_id = s.id),
+            '[]'::jsonb
+        )
+    )
+FROM songs s JOIN artists a ON a.id = s.artist_id;
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+DROP TABLE song_revisions;
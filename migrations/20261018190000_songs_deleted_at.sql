@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+-- deleted songs are kept in trash until they are purged
+ALTER TABLE songs ADD COLUMN deleted_at timestamptz;
+
+CREATE INDEX idx_songs_deleted_at ON songs (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+DROP INDEX idx_songs_deleted_at;
+
+ALTER TABLE songs DROP COLUMN deleted_at;
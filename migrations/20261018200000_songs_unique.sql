@@ -0,0 +1,148 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+-- names which differ only in case and spaces have the same key, see domain.NameKey
+-- +goose StatementBegin
+CREATE FUNCTION name_key(name text) RETURNS text
+LANGUAGE sql IMMUTABLE PARALLEL SAFE
+AS $$ SELECT lower(regexp_replace(btrim(name), '\s+', ' ', 'g')) $$;
+-- +goose StatementEnd
+
+-- existing duplicates are merged into the oldest song: missing link is taken from duplicates, release date is the earliest one,
+-- lyrics of the oldest duplicate with lyrics are copied if the kept song has none, translations to missing languages
+-- are copied from duplicates with the same number of verses, revisions and album tracks are moved to the kept song.
+-- Unlike `song-dedup -merge` merged songs are moved to trash, song_merges keeps what Down needs to restore them.
+CREATE TEMPORARY TABLE song_duplicates ON COMMIT DROP AS
+SELECT id, FIRST_VALUE(id) OVER w AS keep_id, ROW_NUMBER() OVER w AS n, COUNT(*) OVER w AS total
+FROM songs
+WHERE deleted_at IS NULL
+WINDOW w AS (PARTITION BY artist_id, name_key(song) ORDER BY created_at, id ROWS BETWEEN UNBOUNDED PRECEDING AND UNBOUNDED FOLLOWING);
+
+DELETE FROM song_duplicates WHERE total = 1 OR n = 1;
+
+-- revisions of the merged song have numbers after revision_offset in the kept song,
+-- tracks are keys of album tracks moved from the merged song
+create table song_merges
+(
+    song_id uuid PRIMARY KEY REFERENCES songs (id) ON DELETE CASCADE,
+    keep_id uuid NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    lyrics_copied boolean NOT NULL DEFAULT false,
+    translations text[] NOT NULL DEFAULT '{}',
+    revision_offset integer NOT NULL DEFAULT 0,
+    revisions integer NOT NULL DEFAULT 0,
+    tracks jsonb NOT NULL DEFAULT '[]'
+);
+
+INSERT INTO song_merges (song_id, keep_id)
+SELECT id, keep_id FROM song_duplicates;
+
+UPDATE songs s SET link = COALESCE(s.link, m.link), releaseDate = LEAST(s.releaseDate, m.release_date), updated_at = now()
+FROM (
+    SELECT d.keep_id,
+        (ARRAY_AGG(o.link ORDER BY d.n) FILTER (WHERE o.link IS NOT NULL))[1] AS link,
+        MIN(o.releaseDate) AS release_date
+    FROM song_duplicates d JOIN songs o ON o.id = d.id
+    GROUP BY d.keep_id
+) m
+WHERE s.id = m.keep_id;
+
+UPDATE song_merges m SET lyrics_copied = true
+FROM (
+    SELECT DISTINCT ON (d.keep_id) d.id
+    FROM song_duplicates d
+    WHERE EXISTS (SELECT 1 FROM verses v WHERE v.song_id = d.id)
+        AND NOT EXISTS (SELECT 1 FROM verses v WHERE v.song_id = d.keep_id)
+    ORDER BY d.keep_id, d.n
+) l
+WHERE m.song_id = l.id;
+
+INSERT INTO verses (song_id, lang, position, section, language, verse, lines)
+SELECT m.keep_id, v.lang, v.position, v.section, v.language, v.verse, v.lines
+FROM song_merges m JOIN verses v ON v.song_id = m.song_id
+WHERE m.lyrics_copied;
+
+-- translations are aligned verse by verse, so only duplicates with the same number of verses can give them
+CREATE TEMPORARY TABLE translation_donors ON COMMIT DROP AS
+SELECT DISTINCT ON (d.keep_id, t.language) d.keep_id, d.id AS donor_id, t.language
+FROM song_duplicates d JOIN verse_translations t ON t.song_id = d.id
+WHERE NOT EXISTS (SELECT 1 FROM verse_translations k WHERE k.song_id = d.keep_id AND k.language = t.language)
+    AND (SELECT COUNT(*) FROM verses v WHERE v.song_id = d.id) = (SELECT COUNT(*) FROM verses v WHERE v.song_id = d.keep_id)
+ORDER BY d.keep_id, t.language, d.n;
+
+INSERT INTO verse_translations (song_id, position, language, verse)
+SELECT td.keep_id, t.position, t.language, t.verse
+FROM translation_donors td
+    JOIN verse_translations t ON t.song_id = td.donor_id AND t.language = td.language
+    JOIN verses v ON v.song_id = td.keep_id AND v.position = t.position;
+
+UPDATE song_merges m SET translations = td.languages
+FROM (SELECT donor_id, ARRAY_AGG(language) AS languages FROM translation_donors GROUP BY donor_id) td
+WHERE m.song_id = td.donor_id;
+
+-- revisions of every merged song follow the revisions of the kept song and of the previous merged songs
+UPDATE song_merges m SET revision_offset = o.revision_offset, revisions = o.revisions
+FROM (
+    SELECT d.id, COALESCE(MAX(r.number), 0) AS revisions,
+        (SELECT COALESCE(MAX(k.number), 0) FROM song_revisions k WHERE k.song_id = d.keep_id)
+            + COALESCE(SUM(MAX(r.number)) OVER (PARTITION BY d.keep_id ORDER BY d.n ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING), 0)
+            AS revision_offset
+    FROM song_duplicates d LEFT JOIN song_revisions r ON r.song_id = d.id
+    GROUP BY d.id, d.keep_id, d.n
+) o
+WHERE m.song_id = o.id;
+
+UPDATE song_revisions r SET song_id = m.keep_id, number = r.number + m.revision_offset
+FROM song_merges m
+WHERE r.song_id = m.song_id;
+
+UPDATE song_merges m SET tracks = t.tracks
+FROM (
+    SELECT song_id, JSONB_AGG(JSONB_BUILD_OBJECT('album_id', album_id, 'disc_number', disc_number, 'track_number', track_number)) AS tracks
+    FROM album_tracks
+    GROUP BY song_id
+) t
+WHERE m.song_id = t.song_id;
+
+UPDATE album_tracks t SET song_id = m.keep_id
+FROM song_merges m
+WHERE t.song_id = m.song_id;
+
+UPDATE songs s SET deleted_at = now()
+FROM song_merges m
+WHERE s.id = m.song_id;
+
+-- songs in trash don't take names, so restoring a song may conflict with a new one
+CREATE UNIQUE INDEX idx_song_unique ON songs (artist_id, name_key(song)) WHERE deleted_at IS NULL;
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+DROP INDEX idx_song_unique;
+
+-- merged songs which are not purged yet are restored from trash with their revisions and album tracks,
+-- lyrics and translations copied from them are deleted, link and release date of kept songs stay merged
+UPDATE album_tracks t SET song_id = m.song_id
+FROM song_merges m, JSONB_TO_RECORDSET(m.tracks) AS x(album_id uuid, disc_number integer, track_number integer)
+WHERE t.song_id = m.keep_id AND t.album_id = x.album_id AND t.disc_number = x.disc_number AND t.track_number = x.track_number;
+
+UPDATE song_revisions r SET song_id = m.song_id, number = r.number - m.revision_offset
+FROM song_merges m
+WHERE r.song_id = m.keep_id AND r.number > m.revision_offset AND r.number <= m.revision_offset + m.revisions;
+
+DELETE FROM verse_translations t USING song_merges m
+WHERE t.song_id = m.keep_id AND t.language = ANY(m.translations);
+
+DELETE FROM verses v USING song_merges m
+WHERE v.song_id = m.keep_id AND m.lyrics_copied;
+
+UPDATE songs s SET deleted_at = NULL
+FROM song_merges m
+WHERE s.id = m.song_id;
+
+DROP TABLE song_merges;
+
+DROP FUNCTION name_key(text);
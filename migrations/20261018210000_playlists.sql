@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+create table playlists
+(
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid (),
+    name varchar(200) NOT NULL,
+    description text,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+-- positions are shifted by a single UPDATE on insert, move and remove,
+-- so uniqueness is checked at the end of the statement
+create table playlist_items
+(
+    playlist_id uuid NOT NULL REFERENCES playlists (id) ON DELETE CASCADE,
+    position integer NOT NULL CHECK (position > 0),
+    song_id uuid NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    CONSTRAINT playlist_items_pkey PRIMARY KEY (playlist_id, position) DEFERRABLE INITIALLY IMMEDIATE
+);
+
+CREATE INDEX idx_playlist_item_song ON playlist_items (song_id);
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+DROP TABLE playlist_items;
+
+DROP TABLE playlists;
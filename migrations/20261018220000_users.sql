@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+create table users
+(
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid (),
+    name varchar(50) NOT NULL,
+    password_hash bytea NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX idx_user_name ON users (lower(name));
+
+-- a session is started on login, refresh_id is id of the last issued refresh token
+create table sessions
+(
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid (),
+    user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    refresh_id uuid NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    expires_at timestamptz NOT NULL,
+    revoked_at timestamptz
+);
+
+CREATE INDEX idx_session_user ON sessions (user_id);
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+DROP TABLE sessions;
+
+DROP TABLE users;
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+ALTER TABLE users
+    ADD COLUMN role varchar(20) NOT NULL DEFAULT 'viewer'
+        CHECK (role IN ('viewer', 'editor', 'admin'));
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+ALTER TABLE users DROP COLUMN role;
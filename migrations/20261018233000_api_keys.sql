@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+-- only hash of the key is stored, prefix identifies the key and is a part of it
+create table api_keys
+(
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid (),
+    name varchar(100) NOT NULL,
+    prefix varchar(32) NOT NULL,
+    key_hash bytea NOT NULL,
+    permissions text[] NOT NULL,
+    created_by uuid NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    expires_at timestamptz,
+    last_used_at timestamptz,
+    revoked_at timestamptz
+);
+
+CREATE UNIQUE INDEX idx_api_key_prefix ON api_keys (prefix);
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+DROP TABLE api_keys;
@@ -0,0 +1,63 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+-- counters are kept in the same transaction as feedback, so song info and search don't aggregate it
+ALTER TABLE songs
+    ADD COLUMN play_count integer NOT NULL DEFAULT 0,
+    ADD COLUMN favorite_count integer NOT NULL DEFAULT 0,
+    ADD COLUMN rating_count integer NOT NULL DEFAULT 0,
+    ADD COLUMN rating_sum integer NOT NULL DEFAULT 0;
+
+create table favorites
+(
+    user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    song_id uuid NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, song_id)
+);
+
+CREATE INDEX idx_favorite_user ON favorites (user_id, created_at);
+
+create table ratings
+(
+    user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    song_id uuid NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    rating smallint NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    rated_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, song_id)
+);
+
+create table plays
+(
+    id bigserial PRIMARY KEY,
+    user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    song_id uuid NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    played_at timestamptz NOT NULL DEFAULT now()
+);
+
+-- listening history of a user and top songs of a period
+CREATE INDEX idx_play_user ON plays (user_id, played_at);
+CREATE INDEX idx_play_played_at ON plays (played_at, song_id);
+
+CREATE INDEX idx_song_play_count ON songs (play_count, id);
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+DROP INDEX IF EXISTS idx_song_play_count;
+
+DROP TABLE plays;
+
+DROP TABLE ratings;
+
+DROP TABLE favorites;
+
+ALTER TABLE songs
+    DROP COLUMN rating_sum,
+    DROP COLUMN rating_count,
+    DROP COLUMN favorite_count,
+    DROP COLUMN play_count;
@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+-- genre taxonomy, a genre with children can't be deleted
+create table genres
+(
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid (),
+    name varchar(50) NOT NULL,
+    parent_id uuid REFERENCES genres (id) ON DELETE RESTRICT
+);
+
+CREATE UNIQUE INDEX idx_genre_name ON genres (lower(name));
+
+CREATE INDEX idx_genre_parent ON genres (parent_id);
+
+create table song_genres
+(
+    song_id uuid NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    genre_id uuid NOT NULL REFERENCES genres (id) ON DELETE CASCADE,
+    PRIMARY KEY (song_id, genre_id)
+);
+
+CREATE INDEX idx_song_genre ON song_genres (genre_id);
+
+-- tags are free-form, they are normalized to lower case
+create table song_tags
+(
+    song_id uuid NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    tag varchar(50) NOT NULL,
+    PRIMARY KEY (song_id, tag)
+);
+
+CREATE INDEX idx_song_tag ON song_tags (tag);
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+DROP TABLE song_tags;
+
+DROP TABLE song_genres;
+
+DROP TABLE genres;
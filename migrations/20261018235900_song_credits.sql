@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+-- credited artists of songs, an artist with credits can't be deleted
+create table song_credits
+(
+    song_id uuid NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    artist_id uuid NOT NULL REFERENCES artists (id) ON DELETE RESTRICT,
+    role varchar(20) NOT NULL,
+    position int NOT NULL,
+    PRIMARY KEY (song_id, artist_id, role),
+    CONSTRAINT credit_role CHECK (role IN ('primary', 'featuring', 'composer', 'lyricist', 'producer'))
+);
+
+CREATE INDEX idx_song_credit_artist ON song_credits (artist_id, role);
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+DROP TABLE song_credits;
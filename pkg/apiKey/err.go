@@ -0,0 +1,7 @@
+package apikey
+
+import "errors"
+
+var (
+	ErrInvalid = errors.New("invalid api key")
+)
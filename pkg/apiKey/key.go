@@ -0,0 +1,88 @@
+// Package apikey generates keys of service clients.
+// Key is "<kind>_<prefix>_<secret>", the prefix identifies the key and may be shown,
+// only SHA-256 hash of the whole key is stored, so it can't be restored.
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	prefixSize = 6
+	secretSize = 32
+)
+
+// Key is the generated key, Value is shown to the client once.
+type Key struct {
+	Value  string
+	Prefix string
+	Hash   []byte
+}
+
+type Generator struct {
+	kind string
+}
+
+// kind is the first part of keys, it tells what the key is for in logs and secret scanners.
+func NewGenerator(kind string) *Generator {
+	return &Generator{
+		kind: kind,
+	}
+}
+
+func (g *Generator) Generate() (*Key, error) {
+	prefix := make([]byte, prefixSize)
+	_, err := rand.Read(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := make([]byte, secretSize)
+	_, err = rand.Read(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &Key{
+		Prefix: hex.EncodeToString(prefix),
+	}
+	key.Value = g.kind + "_" + key.Prefix + "_" + base64.RawURLEncoding.EncodeToString(secret)
+	key.Hash = Hash(key.Value)
+
+	return key, nil
+}
+
+// Returns prefix of the key, ErrInvalid if the key is malformed or of another kind.
+func (g *Generator) Prefix(value string) (string, error) {
+	rest, ok := strings.CutPrefix(value, g.kind+"_")
+	if !ok {
+		return "", ErrInvalid
+	}
+
+	prefix, secret, ok := strings.Cut(rest, "_")
+	if !ok || len(prefix) != 2*prefixSize || secret == "" {
+		return "", ErrInvalid
+	}
+
+	_, err := hex.DecodeString(prefix)
+	if err != nil {
+		return "", ErrInvalid
+	}
+
+	return prefix, nil
+}
+
+func Hash(value string) []byte {
+	sum := sha256.Sum256([]byte(value))
+	return sum[:]
+}
+
+// Compares hash of the key in constant time.
+func Verify(value string, hash []byte) bool {
+	return subtle.ConstantTimeCompare(Hash(value), hash) == 1
+}
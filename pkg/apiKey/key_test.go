@@ -0,0 +1,66 @@
+package apikey
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	g := NewGenerator("ml")
+
+	key, err := g.Generate()
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	prefix, err := g.Prefix(key.Value)
+	if err != nil {
+		t.Fatalf("Prefix: %v", err)
+	}
+	if prefix != key.Prefix || !strings.HasPrefix(key.Value, "ml_"+key.Prefix+"_") {
+		t.Errorf("Prefix returned %q for %q, want %q", prefix, key.Value, key.Prefix)
+	}
+
+	if !Verify(key.Value, key.Hash) {
+		t.Errorf("Verify rejected the generated key")
+	}
+	if Verify(key.Value+"x", key.Hash) || Verify(strings.ToUpper(key.Value), key.Hash) {
+		t.Errorf("Verify accepted another key")
+	}
+
+	other, err := g.Generate()
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if other.Prefix == key.Prefix || other.Value == key.Value {
+		t.Errorf("Generate returned the same key twice: %q", key.Value)
+	}
+}
+
+func TestPrefixInvalid(t *testing.T) {
+	g := NewGenerator("ml")
+
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"Empty", ""},
+		{"OtherKind", "sk_0123456789ab_secret"},
+		{"NoKind", "0123456789ab_secret"},
+		{"NoSecret", "ml_0123456789ab"},
+		{"EmptySecret", "ml_0123456789ab_"},
+		{"ShortPrefix", "ml_0123_secret"},
+		{"LongPrefix", "ml_0123456789abcd_secret"},
+		{"PrefixNotHex", "ml_0123456789xy_secret"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := g.Prefix(tt.value)
+			if !errors.Is(err, ErrInvalid) {
+				t.Errorf("Prefix(%q) returned %v, want %v", tt.value, err, ErrInvalid)
+			}
+		})
+	}
+}
@@ -0,0 +1,9 @@
+package audiotag
+
+import "errors"
+
+var (
+	ErrUnknownFormat = errors.New("unknown audio format, ID3v2, FLAC, Ogg or MP4 expected")
+	ErrInvalid       = errors.New("invalid tags")
+	ErrTooLarge      = errors.New("tags are too large")
+)
@@ -0,0 +1,46 @@
+package audiotag
+
+import (
+	"io"
+)
+
+// Type of FLAC metadata block with Vorbis comment.
+const flacVorbisComment = 4
+
+// Reads Vorbis comment from FLAC metadata blocks, other blocks are skipped.
+func readFLAC(r io.ReadSeeker) (*Tags, error) {
+	tags := &Tags{Format: FormatFLAC}
+
+	_, err := r.Seek(4, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		header, err := readBytes(r, 4)
+		if err != nil {
+			return nil, err
+		}
+
+		last, blockType := header[0]&0x80 != 0, header[0]&0x7F
+		size := int64(header[1])<<16 | int64(header[2])<<8 | int64(header[3])
+
+		if blockType == flacVorbisComment {
+			data, err := readBytes(r, size)
+			if err != nil {
+				return nil, err
+			}
+
+			return tags, readVorbisComment(data, tags)
+		}
+
+		if last {
+			return tags, nil
+		}
+
+		_, err = r.Seek(size, io.SeekCurrent)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
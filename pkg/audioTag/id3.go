@@ -0,0 +1,379 @@
+package audiotag
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// Text encodings of ID3v2 frames.
+const (
+	encodingLatin1  = 0
+	encodingUTF16   = 1
+	encodingUTF16BE = 2
+	encodingUTF8    = 3
+)
+
+// Timestamp format of SYLT frame with milliseconds, the other one counts MPEG frames.
+const syltMilliseconds = 2
+
+// Frame ids of ID3v2.3 and ID3v2.4, ID3v2.2 ids are mapped to them.
+var id3v22Frames = map[string]string{
+	"TP1": "TPE1",
+	"TP2": "TPE2",
+	"TT2": "TIT2",
+	"TYE": "TYER",
+	"TDA": "TDAT",
+	"ULT": "USLT",
+	"SLT": "SYLT",
+}
+
+// Reads ID3v2.2, ID3v2.3 and ID3v2.4 tag from the beginning of the file.
+// Compressed frames are inflated, encrypted frames are skipped.
+func readID3(r io.Reader) (*Tags, error) {
+	header, err := readBytes(r, 10)
+	if err != nil {
+		return nil, err
+	}
+
+	version, flags := header[3], header[5]
+	if version < 2 || version > 4 {
+		return nil, ErrInvalid
+	}
+
+	data, err := readBytes(r, int64(syncsafe(header[6:10])))
+	if err != nil {
+		return nil, err
+	}
+
+	// ID3v2.4 marks unsynchronisation in every frame
+	if flags&0x80 != 0 && version < 4 {
+		data = deunsync(data)
+	}
+
+	tags := &Tags{Format: FormatID3v2}
+
+	// ID3v2.2 uses this flag for compression, which was never defined
+	if flags&0x40 != 0 && version == 2 {
+		return tags, nil
+	}
+
+	if flags&0x40 != 0 {
+		if len(data) < 4 {
+			return nil, ErrInvalid
+		}
+
+		size := int(binary.BigEndian.Uint32(data))
+		if version == 3 {
+			size += 4
+		} else {
+			size = int(syncsafe(data[:4]))
+		}
+
+		if size > len(data) {
+			return nil, ErrInvalid
+		}
+		data = data[size:]
+	}
+
+	frames := make(map[string][]byte)
+
+	for {
+		id, body, rest, ok := nextFrame(data, version)
+		if !ok {
+			break
+		}
+		data = rest
+
+		// the first frame is used if there are several ones
+		if _, seen := frames[id]; !seen && body != nil {
+			frames[id] = body
+		}
+	}
+
+	tags.Artist = textFrame(frames["TPE1"])
+	if tags.Artist == "" {
+		tags.Artist = textFrame(frames["TPE2"])
+	}
+	tags.Title = textFrame(frames["TIT2"])
+	tags.Date = id3Date(frames)
+	tags.Lyrics = lyricsFrame(frames["USLT"])
+	tags.SyncedLyrics = syncedLyricsFrame(frames["SYLT"])
+
+	return tags, nil
+}
+
+// Returns id and body of the first frame and data after it. ok is false at the end of frames or padding.
+// Body is nil if the frame is encrypted or can't be decoded.
+func nextFrame(data []byte, version byte) (string, []byte, []byte, bool) {
+	idLen, headerLen := 4, 10
+	if version == 2 {
+		idLen, headerLen = 3, 6
+	}
+
+	if len(data) < headerLen || data[0] == 0 {
+		return "", nil, nil, false
+	}
+
+	id := string(data[:idLen])
+
+	var size int
+	var flags uint16
+
+	switch version {
+	case 2:
+		size = int(data[3])<<16 | int(data[4])<<8 | int(data[5])
+		id = id3v22Frames[id]
+	case 3:
+		size = int(binary.BigEndian.Uint32(data[4:8]))
+		flags = binary.BigEndian.Uint16(data[8:10])
+	default:
+		size = int(syncsafe(data[4:8]))
+		flags = binary.BigEndian.Uint16(data[8:10])
+	}
+
+	if size > len(data)-headerLen {
+		return "", nil, nil, false
+	}
+
+	body := data[headerLen : headerLen+size]
+	rest := data[headerLen+size:]
+
+	if version == 3 {
+		body = frameV23(body, flags)
+	}
+	if version == 4 {
+		body = frameV24(body, flags)
+	}
+
+	return id, body, rest, true
+}
+
+// Strips extra data of ID3v2.3 frame and inflates it.
+func frameV23(body []byte, flags uint16) []byte {
+	compressed, encrypted, grouped := flags&0x0080 != 0, flags&0x0040 != 0, flags&0x0020 != 0
+	if encrypted {
+		return nil
+	}
+
+	// decompressed size goes before group id
+	if compressed {
+		if len(body) < 4 {
+			return nil
+		}
+		body = body[4:]
+	}
+	if grouped {
+		if len(body) < 1 {
+			return nil
+		}
+		body = body[1:]
+	}
+
+	if compressed {
+		return inflate(body)
+	}
+	return body
+}
+
+// Strips extra data of ID3v2.4 frame, removes unsynchronisation and inflates it.
+func frameV24(body []byte, flags uint16) []byte {
+	grouped, compressed, encrypted := flags&0x0040 != 0, flags&0x0008 != 0, flags&0x0004 != 0
+	unsynced, withLength := flags&0x0002 != 0, flags&0x0001 != 0
+	if encrypted {
+		return nil
+	}
+
+	skip := 0
+	if grouped {
+		skip++
+	}
+	if withLength {
+		skip += 4
+	}
+	if len(body) < skip {
+		return nil
+	}
+	body = body[skip:]
+
+	if unsynced {
+		body = deunsync(body)
+	}
+
+	if compressed {
+		return inflate(body)
+	}
+	return body
+}
+
+// Returns text of T*** frame, multiple values are joined with slash as in ID3v2.3.
+func textFrame(body []byte) string {
+	if len(body) < 1 {
+		return ""
+	}
+
+	values := make([]string, 0, 1)
+
+	encoding, data := body[0], body[1:]
+	for len(data) != 0 {
+		var value string
+		value, data = terminated(encoding, data)
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return strings.TrimSpace(strings.Join(values, "/"))
+}
+
+// Returns text of USLT frame, the content descriptor is skipped.
+func lyricsFrame(body []byte) string {
+	if len(body) < 4 {
+		return ""
+	}
+
+	encoding := body[0]
+	_, text := terminated(encoding, body[4:])
+
+	return decodeText(encoding, text)
+}
+
+// Returns lines of SYLT frame with millisecond timestamps, frames timed by MPEG frames are skipped.
+func syncedLyricsFrame(body []byte) []SyncedLine {
+	if len(body) < 6 || body[4] != syltMilliseconds {
+		return nil
+	}
+
+	encoding := body[0]
+	_, data := terminated(encoding, body[6:])
+
+	lines := make([]SyncedLine, 0)
+
+	for len(data) != 0 {
+		var text string
+		text, data = terminated(encoding, data)
+
+		if len(data) < 4 {
+			break
+		}
+		ms := binary.BigEndian.Uint32(data)
+		data = data[4:]
+
+		lines = append(lines, SyncedLine{
+			Time: time.Duration(ms) * time.Millisecond,
+			Text: strings.TrimSpace(text),
+		})
+	}
+
+	return lines
+}
+
+// Returns recording or release date, ID3v2.3 keeps year and day with month in separate frames.
+func id3Date(frames map[string][]byte) string {
+	for _, id := range []string{"TDRL", "TDRC"} {
+		if date := textFrame(frames[id]); date != "" {
+			return date
+		}
+	}
+
+	year, dayMonth := textFrame(frames["TYER"]), textFrame(frames["TDAT"])
+	if len(year) == 4 && len(dayMonth) == 4 {
+		return year + "-" + dayMonth[2:] + "-" + dayMonth[:2]
+	}
+
+	return year
+}
+
+// Splits data at the terminator of the encoding, it is a zero byte or two zero bytes for UTF-16.
+// Returns decoded string and data after the terminator.
+func terminated(encoding byte, data []byte) (string, []byte) {
+	if encoding != encodingUTF16 && encoding != encodingUTF16BE {
+		i := bytes.IndexByte(data, 0)
+		if i == -1 {
+			return decodeText(encoding, data), nil
+		}
+		return decodeText(encoding, data[:i]), data[i+1:]
+	}
+
+	for i := 0; i+1 < len(data); i += 2 {
+		if data[i] == 0 && data[i+1] == 0 {
+			return decodeText(encoding, data[:i]), data[i+2:]
+		}
+	}
+
+	return decodeText(encoding, data), nil
+}
+
+// UTF-16 without byte order mark is treated as little endian.
+func decodeText(encoding byte, data []byte) string {
+	switch encoding {
+	case encodingLatin1:
+		runes := make([]rune, 0, len(data))
+		for _, b := range data {
+			runes = append(runes, rune(b))
+		}
+		return strings.TrimRight(string(runes), "\x00")
+	case encodingUTF16, encodingUTF16BE:
+		order := binary.ByteOrder(binary.LittleEndian)
+		if encoding == encodingUTF16BE {
+			order = binary.BigEndian
+		}
+
+		if len(data) >= 2 && data[0] == 0xFE && data[1] == 0xFF {
+			order, data = binary.BigEndian, data[2:]
+		} else if len(data) >= 2 && data[0] == 0xFF && data[1] == 0xFE {
+			order, data = binary.LittleEndian, data[2:]
+		}
+
+		units := make([]uint16, 0, len(data)/2)
+		for i := 0; i+1 < len(data); i += 2 {
+			units = append(units, order.Uint16(data[i:]))
+		}
+		return strings.TrimRight(string(utf16.Decode(units)), "\x00")
+	default:
+		return strings.TrimRight(string(data), "\x00")
+	}
+}
+
+// Sizes in ID3v2 headers use 7 bits of each byte.
+func syncsafe(data []byte) uint32 {
+	var size uint32
+	for _, b := range data {
+		size = size<<7 | uint32(b&0x7F)
+	}
+	return size
+}
+
+// Removes zero bytes inserted after 0xFF bytes.
+func deunsync(data []byte) []byte {
+	result := make([]byte, 0, len(data))
+
+	for i := 0; i < len(data); i++ {
+		result = append(result, data[i])
+		if data[i] == 0xFF && i+1 < len(data) && data[i+1] == 0 {
+			i++
+		}
+	}
+
+	return result
+}
+
+// Returns nil if the data is not valid zlib stream.
+func inflate(data []byte) []byte {
+	zr, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil
+	}
+	defer zr.Close()
+
+	result, err := io.ReadAll(io.LimitReader(zr, maxTagSize))
+	if err != nil {
+		return nil
+	}
+
+	return result
+}
@@ -0,0 +1,109 @@
+package audiotag
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+// Returns ID3v2 tag with the body, size is written syncsafe.
+func id3Tag(version, flags byte, body []byte) []byte {
+	size := len(body)
+	header := []byte{'I', 'D', '3', version, 0, flags, byte(size >> 21 & 0x7F), byte(size >> 14 & 0x7F), byte(size >> 7 & 0x7F), byte(size & 0x7F)}
+	return append(header, body...)
+}
+
+// Returns ID3v2.3 frame, its size is not syncsafe.
+func id3Frame(id string, flags uint16, body []byte) []byte {
+	frame := []byte(id)
+	frame = binary.BigEndian.AppendUint32(frame, uint32(len(body)))
+	frame = binary.BigEndian.AppendUint16(frame, flags)
+	return append(frame, body...)
+}
+
+func latin1(text string) []byte {
+	return append([]byte{encodingLatin1}, text...)
+}
+
+func TestReadID3(t *testing.T) {
+	body := bytes.Join([][]byte{
+		id3Frame("TPE1", 0, latin1("Queen")),
+		id3Frame("TIT2", 0, latin1("Bohemian Rhapsody")),
+		id3Frame("TYER", 0, latin1("1975")),
+		id3Frame("USLT", 0, append(latin1("eng"), "\x00Is this the real life?"...)),
+		make([]byte, 16), // padding
+	}, nil)
+
+	tags, err := Read(bytes.NewReader(id3Tag(3, 0, body)))
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+
+	want := Tags{Format: FormatID3v2, Artist: "Queen", Title: "Bohemian Rhapsody", Date: "1975", Lyrics: "Is this the real life?"}
+	if tags.Format != want.Format || tags.Artist != want.Artist || tags.Title != want.Title ||
+		tags.Date != want.Date || tags.Lyrics != want.Lyrics || len(tags.SyncedLyrics) != 0 {
+		t.Errorf("Read returned %+v, want %+v", *tags, want)
+	}
+}
+
+func TestReadID3Malformed(t *testing.T) {
+	title := id3Frame("TIT2", 0, latin1("Bohemian Rhapsody"))
+
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"ShortFile", []byte("ID3\x03"), ErrUnknownFormat},
+		{"TruncatedHeader", []byte("ID3\x03\x00\x00\x00\x00"), ErrInvalid},
+		{"UnknownVersion", id3Tag(5, 0, title), ErrInvalid},
+		{"VersionOne", id3Tag(1, 0, title), ErrInvalid},
+		{"SizeAfterEnd", id3Tag(3, 0, title)[:len(title)], ErrInvalid},
+		{"ShortExtendedHeader", id3Tag(3, 0x40, []byte{0, 0}), ErrInvalid},
+		{"ExtendedHeaderAfterEnd", id3Tag(3, 0x40, append([]byte{0, 0, 0x10, 0}, title...)), ErrInvalid},
+		{"ExtendedHeaderV4AfterEnd", id3Tag(4, 0x40, append([]byte{0x7F, 0x7F, 0x7F, 0x7F}, title...)), ErrInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Read(bytes.NewReader(tt.data))
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Read returned %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+// Broken frames are skipped, frames before them are read.
+func TestReadID3BrokenFrames(t *testing.T) {
+	title := id3Frame("TIT2", 0, latin1("Bohemian Rhapsody"))
+
+	tests := []struct {
+		name  string
+		body  []byte
+		title string
+	}{
+		{"FrameAfterEnd", append(append([]byte{}, title...), id3Frame("TPE1", 0, latin1("Queen"))[:12]...), "Bohemian Rhapsody"},
+		{"TruncatedFrameHeader", append(append([]byte{}, title...), "TPE"...), "Bohemian Rhapsody"},
+		{"SizeAfterEnd", append(id3Frame("TPE1", 0, latin1("Queen"))[:7], 0xFF, 0, 0), ""},
+		{"EncryptedFrame", append(id3Frame("TIT2", 0x0040, latin1("Secret")), title...), "Bohemian Rhapsody"},
+		{"InvalidCompression", append(id3Frame("TIT2", 0x0080, []byte{0, 0, 0, 8, 1, 2, 3}), title...), "Bohemian Rhapsody"},
+		{"ShortCompressedFrame", append(id3Frame("TIT2", 0x0080, []byte{0, 0}), title...), "Bohemian Rhapsody"},
+		{"EmptyTextFrame", append(id3Frame("TPE1", 0, nil), title...), "Bohemian Rhapsody"},
+		{"ShortLyricsFrame", append(id3Frame("USLT", 0, []byte{encodingLatin1, 'e'}), title...), "Bohemian Rhapsody"},
+		{"ShortSyncedLyricsFrame", append(id3Frame("SYLT", 0, []byte{encodingLatin1, 'e', 'n', 'g', syltMilliseconds, 1, 'I', 0, 0}), title...), "Bohemian Rhapsody"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tags, err := Read(bytes.NewReader(id3Tag(3, 0, tt.body)))
+			if err != nil {
+				t.Fatalf("Read: %v", err)
+			}
+			if tags.Title != tt.title || tags.Artist != "" || tags.Lyrics != "" || len(tags.SyncedLyrics) != 0 {
+				t.Errorf("Read returned %+v, want only title %q", *tags, tt.title)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package audiotag
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+)
+
+// Items of iTunes metadata list, © is 0xA9 byte.
+const (
+	mp4Artist      = "\xa9ART"
+	mp4AlbumArtist = "aART"
+	mp4Title       = "\xa9nam"
+	mp4Date        = "\xa9day"
+	mp4Lyrics      = "\xa9lyr"
+)
+
+// Type of data atom with UTF-8 text.
+const mp4UTF8 = 1
+
+var errNoAtom = errors.New("no atom")
+
+// Reads iTunes metadata from moov.udta.meta.ilst atom. Media data is skipped by seeking,
+// so moov can be at the end of the file.
+func readMP4(r io.ReadSeeker) (*Tags, error) {
+	tags := &Tags{Format: FormatMP4}
+
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	start := int64(0)
+	for _, name := range []string{"moov", "udta", "meta", "ilst"} {
+		start, end, err = findAtom(r, start, end, name)
+		if errors.Is(err, errNoAtom) {
+			return tags, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if name == "meta" {
+			start, err = skipFullBox(r, start, end)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	_, err = r.Seek(start, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+
+	ilst, err := readBytes(r, end-start)
+	if err != nil {
+		return nil, err
+	}
+
+	for len(ilst) >= 8 {
+		size := int(binary.BigEndian.Uint32(ilst))
+		if size < 8 || size > len(ilst) {
+			return nil, ErrInvalid
+		}
+
+		name, value := string(ilst[4:8]), mp4Text(ilst[8:size])
+		ilst = ilst[size:]
+
+		switch name {
+		case mp4Artist:
+			tags.Artist = value
+		case mp4AlbumArtist:
+			if tags.Artist == "" {
+				tags.Artist = value
+			}
+		case mp4Title:
+			tags.Title = value
+		case mp4Date:
+			tags.Date = value
+		case mp4Lyrics:
+			tags.Lyrics = value
+		}
+	}
+
+	return tags, nil
+}
+
+// Returns bounds of content of the first atom with the name within [start, end).
+func findAtom(r io.ReadSeeker, start, end int64, name string) (int64, int64, error) {
+	for offset := start; offset+8 <= end; {
+		_, err := r.Seek(offset, io.SeekStart)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		header, err := readBytes(r, 8)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		size, headerSize := int64(binary.BigEndian.Uint32(header)), int64(8)
+
+		switch size {
+		case 0:
+			// atom lasts to the end of its parent
+			size = end - offset
+		case 1:
+			large, err := readBytes(r, 8)
+			if err != nil {
+				return 0, 0, err
+			}
+			size, headerSize = int64(binary.BigEndian.Uint64(large)), 16
+		}
+
+		if size < headerSize || size > end-offset {
+			return 0, 0, ErrInvalid
+		}
+
+		if string(header[4:8]) == name {
+			return offset + headerSize, offset + size, nil
+		}
+
+		offset += size
+	}
+
+	return 0, 0, errNoAtom
+}
+
+// meta atom of MP4 starts with version and flags, QuickTime meta atom doesn't.
+func skipFullBox(r io.ReadSeeker, start, end int64) (int64, error) {
+	if end-start < 4 {
+		return 0, ErrInvalid
+	}
+
+	_, err := r.Seek(start, io.SeekStart)
+	if err != nil {
+		return 0, err
+	}
+
+	versionAndFlags, err := readBytes(r, 4)
+	if err != nil {
+		return 0, err
+	}
+
+	if bytes.Equal(versionAndFlags, []byte{0, 0, 0, 0}) {
+		return start + 4, nil
+	}
+	return start, nil
+}
+
+// Returns text of the first data atom of the item, data of other types is skipped.
+func mp4Text(item []byte) string {
+	for len(item) >= 16 {
+		size := int(binary.BigEndian.Uint32(item))
+		if size < 16 || size > len(item) {
+			return ""
+		}
+
+		if string(item[4:8]) == "data" && binary.BigEndian.Uint32(item[8:12])&0xFFFFFF == mp4UTF8 {
+			return strings.TrimSpace(string(item[16:size]))
+		}
+
+		item = item[size:]
+	}
+
+	return ""
+}
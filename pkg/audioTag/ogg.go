@@ -0,0 +1,81 @@
+package audiotag
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+)
+
+// Prefixes of comment header packets, the comment follows the prefix.
+var oggComments = [][]byte{
+	[]byte("\x03vorbis"),
+	[]byte("OpusTags"),
+}
+
+// Reads Vorbis comment from the second packet of the first logical stream,
+// it is the comment header of Vorbis and Opus. Pages of other streams are skipped.
+func readOgg(r io.Reader) (*Tags, error) {
+	tags := &Tags{Format: FormatOgg}
+
+	br := bufio.NewReader(r)
+
+	var serial uint32
+	var packet []byte
+	packets, read := 0, 0
+
+	for first := true; ; first = false {
+		header, err := readBytes(br, 27)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.HasPrefix(header, []byte("OggS")) {
+			return nil, ErrInvalid
+		}
+
+		segments, err := readBytes(br, int64(header[26]))
+		if err != nil {
+			return nil, err
+		}
+
+		pageSerial := binary.LittleEndian.Uint32(header[14:18])
+		if first {
+			serial = pageSerial
+		}
+
+		for _, size := range segments {
+			segment, err := readBytes(br, int64(size))
+			if err != nil {
+				return nil, err
+			}
+
+			if pageSerial != serial {
+				continue
+			}
+
+			read += int(size)
+			if read > maxTagSize {
+				return nil, ErrTooLarge
+			}
+
+			packet = append(packet, segment...)
+
+			// packet ends with a segment shorter than 255 bytes
+			if size == 255 {
+				continue
+			}
+
+			packets++
+			if packets == 2 {
+				for _, prefix := range oggComments {
+					if bytes.HasPrefix(packet, prefix) {
+						return tags, readVorbisComment(packet[len(prefix):], tags)
+					}
+				}
+				return tags, nil
+			}
+
+			packet = nil
+		}
+	}
+}
@@ -0,0 +1,84 @@
+// Package audiotag reads artist, title, date and lyrics from tags of audio files:
+// ID3v2 (MP3), Vorbis comments (FLAC, Ogg Vorbis and Opus) and MP4 atoms (M4A).
+// Other tags, like album or pictures, are skipped.
+package audiotag
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"time"
+)
+
+// Formats of tags.
+const (
+	FormatID3v2 = "id3v2"
+	FormatFLAC  = "flac"
+	FormatOgg   = "ogg"
+	FormatMP4   = "mp4"
+)
+
+// Tags are read into memory only up to this size, pictures included.
+const maxTagSize = 64 << 20
+
+// Fields are empty if the file doesn't have the tag. Date is kept as written, usually YYYY or YYYY-MM-DD.
+// Lyrics are unsynchronized lyrics. SyncedLyrics are ordered by time, line with empty text is a pause.
+type Tags struct {
+	Format       string
+	Artist       string
+	Title        string
+	Date         string
+	Lyrics       string
+	SyncedLyrics []SyncedLine
+}
+
+type SyncedLine struct {
+	Time time.Duration
+	Text string
+}
+
+// Reads tags of the audio file, the format is detected by content.
+// Returns ErrUnknownFormat for other files.
+func Read(r io.ReadSeeker) (*Tags, error) {
+	header := make([]byte, 8)
+	_, err := io.ReadFull(r, header)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, ErrUnknownFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = r.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case bytes.HasPrefix(header, []byte("ID3")):
+		return readID3(r)
+	case bytes.HasPrefix(header, []byte("fLaC")):
+		return readFLAC(r)
+	case bytes.HasPrefix(header, []byte("OggS")):
+		return readOgg(r)
+	case bytes.Equal(header[4:8], []byte("ftyp")):
+		return readMP4(r)
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+// Reads n bytes, unexpected end of data means that tags are invalid.
+func readBytes(r io.Reader, n int64) ([]byte, error) {
+	if n > maxTagSize {
+		return nil, ErrTooLarge
+	}
+
+	data := make([]byte, n)
+	_, err := io.ReadFull(r, data)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, ErrInvalid
+	}
+
+	return data, err
+}
@@ -0,0 +1,71 @@
+package audiotag
+
+import (
+	"encoding/binary"
+	"strings"
+)
+
+// Reads Vorbis comment block of FLAC, Ogg Vorbis or Opus. Field names are case insensitive,
+// values of repeated fields are joined with slash.
+func readVorbisComment(data []byte, tags *Tags) error {
+	// vendor string is skipped
+	_, data, ok := vorbisString(data)
+	if !ok {
+		return ErrInvalid
+	}
+
+	if len(data) < 4 {
+		return ErrInvalid
+	}
+	count := binary.LittleEndian.Uint32(data)
+	data = data[4:]
+
+	fields := make(map[string][]string)
+
+	for range count {
+		var comment string
+		comment, data, ok = vorbisString(data)
+		if !ok {
+			return ErrInvalid
+		}
+
+		key, value, found := strings.Cut(comment, "=")
+		if !found {
+			continue
+		}
+
+		key = strings.ToUpper(key)
+		fields[key] = append(fields[key], strings.TrimSpace(value))
+	}
+
+	first := func(keys ...string) string {
+		for _, key := range keys {
+			if values := fields[key]; len(values) != 0 {
+				return strings.Join(values, "/")
+			}
+		}
+		return ""
+	}
+
+	tags.Artist = first("ARTIST", "ALBUMARTIST", "ALBUM ARTIST")
+	tags.Title = first("TITLE")
+	tags.Date = first("DATE", "YEAR")
+	tags.Lyrics = first("LYRICS", "UNSYNCEDLYRICS", "UNSYNCED LYRICS")
+
+	return nil
+}
+
+// Reads a string with little endian 32-bit length.
+func vorbisString(data []byte) (string, []byte, bool) {
+	if len(data) < 4 {
+		return "", nil, false
+	}
+
+	size := binary.LittleEndian.Uint32(data)
+	data = data[4:]
+	if uint64(size) > uint64(len(data)) {
+		return "", nil, false
+	}
+
+	return string(data[:size]), data[size:], true
+}
@@ -0,0 +1,8 @@
+package authtoken
+
+import "errors"
+
+var (
+	ErrInvalid = errors.New("invalid token")
+	ErrExpired = errors.New("token is expired")
+)
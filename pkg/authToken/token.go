@@ -0,0 +1,115 @@
+// Package authtoken issues and verifies JWT access and refresh tokens signed with HMAC-SHA256.
+package authtoken
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// Types of tokens, a refresh token can't be used for access and vice versa.
+const (
+	TypeAccess  = "access"
+	TypeRefresh = "refresh"
+)
+
+// Claims of the token. ID is generated on issue, ExpiresAt is set from the ttl.
+type Claims struct {
+	ID        uuid.UUID
+	Type      string
+	UserID    uuid.UUID
+	Name      string
+	SessionID uuid.UUID
+	ExpiresAt time.Time
+}
+
+type Issuer struct {
+	secret []byte
+	issuer string
+}
+
+// JSON representation of Claims, names of registered claims follow RFC 7519.
+type jwtClaims struct {
+	jwt.RegisteredClaims
+
+	Type      string `json:"typ"`
+	Name      string `json:"name,omitempty"`
+	SessionID string `json:"sid"`
+}
+
+// issuer is written to iss claim, tokens of other issuers are rejected.
+func NewIssuer(secret, issuer string) *Issuer {
+	return &Issuer{
+		secret: []byte(secret),
+		issuer: issuer,
+	}
+}
+
+// Returns signed token which is valid for ttl. ID and ExpiresAt of the claims are set.
+func (i *Issuer) Issue(claims *Claims, ttl time.Duration) (string, error) {
+	now := time.Now()
+
+	claims.ID = uuid.New()
+	claims.ExpiresAt = now.Add(ttl).Truncate(time.Second)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &jwtClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        claims.ID.String(),
+			Issuer:    i.issuer,
+			Subject:   claims.UserID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(claims.ExpiresAt),
+		},
+		Type:      claims.Type,
+		Name:      claims.Name,
+		SessionID: claims.SessionID.String(),
+	})
+
+	return token.SignedString(i.secret)
+}
+
+// Verifies signature, issuer, expiration and type of the token.
+// Returns ErrExpired for expired tokens and ErrInvalid for any other problem.
+func (i *Issuer) Parse(token, typ string) (*Claims, error) {
+	parsed := &jwtClaims{}
+
+	_, err := jwt.ParseWithClaims(
+		token,
+		parsed,
+		func(*jwt.Token) (any, error) { return i.secret, nil },
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(i.issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if errors.Is(err, jwt.ErrTokenExpired) {
+		return nil, ErrExpired
+	}
+	if err != nil || parsed.Type != typ {
+		return nil, ErrInvalid
+	}
+
+	claims := &Claims{
+		Type:      parsed.Type,
+		Name:      parsed.Name,
+		ExpiresAt: parsed.ExpiresAt.Time,
+	}
+
+	claims.ID, err = uuid.Parse(parsed.ID)
+	if err != nil {
+		return nil, ErrInvalid
+	}
+
+	claims.UserID, err = uuid.Parse(parsed.Subject)
+	if err != nil {
+		return nil, ErrInvalid
+	}
+
+	claims.SessionID, err = uuid.Parse(parsed.SessionID)
+	if err != nil {
+		return nil, ErrInvalid
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,90 @@
+package authtoken
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+func TestRoundTrip(t *testing.T) {
+	i := NewIssuer("secret", "music-library")
+	issued := &Claims{Type: TypeAccess, UserID: uuid.New(), Name: "freddie", SessionID: uuid.New()}
+
+	token, err := i.Issue(issued, time.Minute)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+
+	got, err := i.Parse(token, TypeAccess)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if *got != *issued {
+		t.Errorf("Parse returned %+v, want %+v", *got, *issued)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	i := NewIssuer("secret", "music-library")
+
+	issue := func(i *Issuer, typ string, ttl time.Duration) string {
+		token, err := i.Issue(&Claims{Type: typ, UserID: uuid.New(), SessionID: uuid.New()}, ttl)
+		if err != nil {
+			t.Fatalf("Issue: %v", err)
+		}
+		return token
+	}
+
+	sign := func(method jwt.SigningMethod, key any, claims *jwtClaims) string {
+		token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+		if err != nil {
+			t.Fatalf("SignedString: %v", err)
+		}
+		return token
+	}
+
+	valid := jwtClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Issuer:    "music-library",
+			Subject:   uuid.NewString(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+		Type:      TypeAccess,
+		SessionID: uuid.NewString(),
+	}
+	withoutExpiration, withoutSession, withoutSubject := valid, valid, valid
+	withoutExpiration.ExpiresAt = nil
+	withoutSession.SessionID = ""
+	withoutSubject.Subject = "freddie"
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{"Expired", issue(i, TypeAccess, -time.Minute), ErrExpired},
+		{"RefreshAsAccess", issue(i, TypeRefresh, time.Minute), ErrInvalid},
+		{"OtherSecret", issue(NewIssuer("other secret", "music-library"), TypeAccess, time.Minute), ErrInvalid},
+		{"OtherIssuer", issue(NewIssuer("secret", "other"), TypeAccess, time.Minute), ErrInvalid},
+		{"TamperedSignature", issue(i, TypeAccess, time.Minute) + "x", ErrInvalid},
+		{"OtherMethod", sign(jwt.SigningMethodHS384, []byte("secret"), &valid), ErrInvalid},
+		{"NoneMethod", sign(jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, &valid), ErrInvalid},
+		{"WithoutExpiration", sign(jwt.SigningMethodHS256, []byte("secret"), &withoutExpiration), ErrInvalid},
+		{"WithoutSession", sign(jwt.SigningMethodHS256, []byte("secret"), &withoutSession), ErrInvalid},
+		{"SubjectNotUUID", sign(jwt.SigningMethodHS256, []byte("secret"), &withoutSubject), ErrInvalid},
+		{"Malformed", "garbage", ErrInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := i.Parse(tt.token, TypeAccess)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Parse returned %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package circuitbreaker
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type state int
+
+const (
+	closed state = iota
+	open
+	halfOpen
+)
+
+// CircuitBreaker stops calling a failing dependency for some time.
+// After threshold consecutive failures it opens and rejects calls with ErrOpen,
+// once timeout passed it lets a single trial call through (half-open state).
+type CircuitBreaker struct {
+	mu sync.Mutex
+
+	state    state
+	failures int
+	openedAt time.Time
+
+	threshold int
+	timeout   time.Duration
+}
+
+// threshold is a number of consecutive failures that opens the breaker,
+// timeout is how long the breaker stays open before the trial call.
+func NewCircuitBreaker(threshold int, timeout time.Duration) *CircuitBreaker {
+	if threshold < 1 {
+		threshold = 1
+	}
+
+	return &CircuitBreaker{
+		state:     closed,
+		threshold: threshold,
+		timeout:   timeout,
+	}
+}
+
+// Calls fn if breaker allows it and records the result.
+// Returns ErrOpen without calling fn if the breaker is open.
+func (c *CircuitBreaker) Execute(fn func() error) error {
+	return c.ExecuteContext(context.Background(), fn)
+}
+
+// Same as Execute, but failure is not recorded if ctx is done:
+// the caller gave up, so the dependency is not to blame.
+func (c *CircuitBreaker) ExecuteContext(ctx context.Context, fn func() error) error {
+	if !c.allow() {
+		return ErrOpen
+	}
+
+	err := fn()
+	if err != nil && ctx.Err() != nil {
+		c.release()
+		return err
+	}
+	c.record(err)
+
+	return err
+}
+
+func (c *CircuitBreaker) allow() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch c.state {
+	case open:
+		if time.Since(c.openedAt) < c.timeout {
+			return false
+		}
+		c.state = halfOpen
+		return true
+	case halfOpen:
+		// only one trial call at a time
+		return false
+	default:
+		return true
+	}
+}
+
+func (c *CircuitBreaker) record(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err == nil {
+		c.state = closed
+		c.failures = 0
+		return
+	}
+
+	c.failures++
+	if c.state == halfOpen || c.failures >= c.threshold {
+		c.state = open
+		c.openedAt = time.Now()
+	}
+}
+
+// Trial call without result lets the next call be a trial, failures are kept.
+func (c *CircuitBreaker) release() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state == halfOpen {
+		c.state = open
+	}
+}
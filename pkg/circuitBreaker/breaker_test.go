@@ -0,0 +1,136 @@
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var errFailed = errors.New("failed")
+
+func fail() error    { return errFailed }
+func succeed() error { return nil }
+
+func TestOpensAfterThreshold(t *testing.T) {
+	c := NewCircuitBreaker(3, time.Minute)
+
+	for i := range 3 {
+		if c.state != closed {
+			t.Fatalf("state after %d failures is %v, want closed", i, c.state)
+		}
+
+		err := c.Execute(fail)
+		if !errors.Is(err, errFailed) {
+			t.Fatalf("Execute returned %v, want %v", err, errFailed)
+		}
+	}
+
+	if c.state != open {
+		t.Fatalf("state after threshold failures is %v, want open", c.state)
+	}
+
+	called := false
+	err := c.Execute(func() error { called = true; return nil })
+	if !errors.Is(err, ErrOpen) {
+		t.Errorf("Execute returned %v, want %v", err, ErrOpen)
+	}
+	if called {
+		t.Error("open breaker called fn")
+	}
+}
+
+func TestSuccessResetsFailures(t *testing.T) {
+	c := NewCircuitBreaker(2, time.Minute)
+
+	c.Execute(fail)
+	c.Execute(succeed)
+	c.Execute(fail)
+
+	if c.state != closed {
+		t.Errorf("state is %v, want closed, failures are not consecutive", c.state)
+	}
+}
+
+func TestHalfOpen(t *testing.T) {
+	tests := []struct {
+		name  string
+		trial func() error
+		want  state
+	}{
+		{"TrialSucceeds", succeed, closed},
+		{"TrialFails", fail, open},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCircuitBreaker(1, 20*time.Millisecond)
+			c.Execute(fail)
+
+			time.Sleep(30 * time.Millisecond)
+
+			err := c.Execute(func() error {
+				if c.state != halfOpen {
+					t.Errorf("state during trial call is %v, want half-open", c.state)
+				}
+
+				// only one trial call is let through
+				err := c.Execute(succeed)
+				if !errors.Is(err, ErrOpen) {
+					t.Errorf("concurrent Execute returned %v, want %v", err, ErrOpen)
+				}
+
+				return tt.trial()
+			})
+			if !errors.Is(err, tt.trial()) {
+				t.Errorf("Execute returned %v, want %v", err, tt.trial())
+			}
+
+			if c.state != tt.want {
+				t.Errorf("state after trial is %v, want %v", c.state, tt.want)
+			}
+		})
+	}
+}
+
+func TestReopenedBreakerWaitsTimeout(t *testing.T) {
+	c := NewCircuitBreaker(1, 20*time.Millisecond)
+	c.Execute(fail)
+
+	time.Sleep(30 * time.Millisecond)
+	c.Execute(fail)
+
+	err := c.Execute(succeed)
+	if !errors.Is(err, ErrOpen) {
+		t.Errorf("Execute right after failed trial returned %v, want %v", err, ErrOpen)
+	}
+}
+
+func TestCanceledCallNotRecorded(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c := NewCircuitBreaker(1, 20*time.Millisecond)
+
+	err := c.ExecuteContext(ctx, fail)
+	if !errors.Is(err, errFailed) {
+		t.Fatalf("ExecuteContext returned %v, want %v", err, errFailed)
+	}
+	if c.state != closed || c.failures != 0 {
+		t.Fatalf("state after canceled call is %v with %d failures, want closed without failures", c.state, c.failures)
+	}
+
+	c.Execute(fail)
+	time.Sleep(30 * time.Millisecond)
+
+	// canceled trial call lets the next call be a trial
+	c.ExecuteContext(ctx, fail)
+
+	err = c.Execute(succeed)
+	if err != nil {
+		t.Errorf("Execute after canceled trial returned %v, want trial call", err)
+	}
+	if c.state != closed {
+		t.Errorf("state after trial is %v, want closed", c.state)
+	}
+}
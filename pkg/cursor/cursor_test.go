@@ -0,0 +1,82 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type page struct {
+	Position int    `json:"p"`
+	Query    string `json:"q"`
+}
+
+func TestRoundTrip(t *testing.T) {
+	s := NewSigner("secret")
+
+	token, err := s.Encode(page{Position: 42, Query: "abc"})
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	var got page
+	err = s.Decode(token, &got)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if got != (page{Position: 42, Query: "abc"}) {
+		t.Errorf("Decode returned %+v, want %+v", got, page{Position: 42, Query: "abc"})
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	s := NewSigner("secret")
+
+	token, err := s.Encode(page{Position: 42, Query: "abc"})
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	payload, signature, _ := strings.Cut(token, ".")
+
+	forged, err := s.Encode(page{Position: 43, Query: "abc"})
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	forgedPayload, forgedSignature, _ := strings.Cut(forged, ".")
+
+	foreign, err := NewSigner("other secret").Encode(page{Position: 42, Query: "abc"})
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"Empty", ""},
+		{"NoSignature", payload},
+		{"EmptySignature", payload + "."},
+		{"SignatureNotBase64", payload + ".!!!"},
+		{"TamperedPayload", forgedPayload + "." + signature},
+		{"SignatureOfOtherPayload", payload + "." + forgedSignature},
+		{"OtherSecret", foreign},
+		{"NotJSON", encodeSigned(s, "not json")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got page
+			err := s.Decode(tt.token, &got)
+			if !errors.Is(err, ErrInvalid) {
+				t.Errorf("Decode(%q) returned %v, want %v", tt.token, err, ErrInvalid)
+			}
+		})
+	}
+}
+
+// Returns token with valid signature of the raw payload.
+func encodeSigned(s *Signer, payload string) string {
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(payload))
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(s.sign(encoded))
+}
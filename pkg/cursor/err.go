@@ -0,0 +1,7 @@
+package cursor
+
+import "errors"
+
+var (
+	ErrInvalid = errors.New("invalid cursor")
+)
@@ -0,0 +1,61 @@
+// Package diff finds the shortest line-level difference of two texts by the longest common subsequence.
+package diff
+
+// Operations of edits.
+const (
+	Equal  = "="
+	Delete = "-"
+	Insert = "+"
+)
+
+type Edit struct {
+	Op   string
+	Text string
+}
+
+// Returns edits which turn a into b. Deleted lines go before inserted ones at the same place.
+// Uses O(len(a)*len(b)) memory, so it is intended for texts like lyrics, not for large files.
+func Lines(a, b []string) []Edit {
+	// lcs[i][j] is length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	edits := make([]Edit, 0, max(len(a), len(b)))
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			edits = append(edits, Edit{Op: Equal, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			edits = append(edits, Edit{Op: Delete, Text: a[i]})
+			i++
+		default:
+			edits = append(edits, Edit{Op: Insert, Text: b[j]})
+			j++
+		}
+	}
+
+	for ; i < len(a); i++ {
+		edits = append(edits, Edit{Op: Delete, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		edits = append(edits, Edit{Op: Insert, Text: b[j]})
+	}
+
+	return edits
+}
@@ -0,0 +1,96 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
+)
+
+// User of authenticated request. Credential is id of the session or key which authenticated the request.
+// Permissions limit what the credential allows, they are nil if the role decides it.
+type User struct {
+	ID          uuid.UUID
+	Name        string
+	Role        string
+	Credential  uuid.UUID
+	Permissions []string
+}
+
+// Authenticator checks credentials of Authorization header, e.g. the token of Bearer scheme.
+// Errors wrapping ErrUnauthorized are responded with 401.
+type Authenticator func(ctx context.Context, credentials string) (*User, error)
+
+type userKey struct{}
+
+// Returns middleware which puts user of Authorization header to the request context next to the operation id.
+// Authenticators are chosen by scheme case insensitively. Requests without the header pass anonymously.
+func Authenticate(schemes map[string]Authenticator) Middleware {
+	names := make([]string, 0, len(schemes))
+	for scheme := range schemes {
+		names = append(names, scheme)
+	}
+	slices.Sort(names)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("Authorization")
+			if header == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			user, err := authenticate(r.Context(), schemes, header)
+			if err != nil {
+				status := http.StatusInternalServerError
+				if errors.Is(err, ErrUnauthorized) {
+					status = http.StatusUnauthorized
+					for _, scheme := range names {
+						w.Header().Add("WWW-Authenticate", scheme)
+					}
+				}
+
+				slog.Error(
+					err.Error(),
+					"status", status,
+					"url", r.URL.Path,
+					"method", r.Method,
+					"operation", logmsg.ExtractOperationID(r.Context()),
+				)
+				http.Error(w, err.Error(), status)
+				return
+			}
+
+			slog.Debug("authenticated", "user", user.ID, "operation", logmsg.ExtractOperationID(r.Context()))
+			next.ServeHTTP(w, r.WithContext(WithUser(r.Context(), user)))
+		})
+	}
+}
+
+func authenticate(ctx context.Context, schemes map[string]Authenticator, header string) (*User, error) {
+	scheme, credentials, _ := strings.Cut(header, " ")
+
+	for name, authenticator := range schemes {
+		if strings.EqualFold(name, scheme) {
+			return authenticator(ctx, strings.TrimSpace(credentials))
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %w", ErrUnauthorized, errUnknownScheme)
+}
+
+func WithUser(ctx context.Context, user *User) context.Context {
+	return context.WithValue(ctx, userKey{}, user)
+}
+
+// Returns user of the request, ok is false for anonymous requests.
+func ExtractUser(ctx context.Context) (*User, bool) {
+	user, ok := ctx.Value(userKey{}).(*User)
+	return user, ok
+}
@@ -0,0 +1,10 @@
+package httpserver
+
+import "errors"
+
+var (
+	// Authenticators wrap it for invalid credentials, other errors are responded with 500.
+	ErrUnauthorized = errors.New("unauthorized")
+
+	errUnknownScheme = errors.New("unsupported authorization scheme")
+)
@@ -0,0 +1,7 @@
+package lrc
+
+import "errors"
+
+var (
+	ErrSyntax = errors.New("invalid lrc line")
+)
@@ -0,0 +1,56 @@
+// Package lrc reads and writes lyrics in the LRC format, including enhanced LRC with word timing:
+//
+//	[ar:Queen]
+//	[00:12.00]Is this the real life?
+//	[00:17.35]<00:17.35>Is <00:17.80>this <00:18.10>just <00:18.60>fantasy?<00:21.00>
+package lrc
+
+import "time"
+
+// ID tags which are known by most players.
+const (
+	TagArtist = "ar"
+	TagTitle  = "ti"
+	TagAlbum  = "al"
+	TagAuthor = "au"
+	TagLength = "length"
+	TagBy     = "by"
+
+	// Offset in milliseconds, positive offset shows lyrics earlier.
+	// It is applied by Parse and isn't kept in File.Tags.
+	TagOffset = "offset"
+)
+
+// Lines are ordered by time, line with empty text is a pause.
+type File struct {
+	Tags  []Tag
+	Lines []Line
+}
+
+type Tag struct {
+	Key   string
+	Value string
+}
+
+// End is set only if the line of enhanced LRC ends with a word timestamp without text.
+type Line struct {
+	Time  time.Duration
+	End   time.Duration
+	Text  string
+	Words []Word
+}
+
+type Word struct {
+	Time time.Duration
+	Text string
+}
+
+// Returns value of the first tag with the key or empty string.
+func (f *File) Tag(key string) string {
+	for _, tag := range f.Tags {
+		if tag.Key == key {
+			return tag.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,229 @@
+package lrc
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Reads LRC file. Every line must have at least one time tag or be an ID tag, empty lines are skipped.
+// Line with several time tags is repeated at every time. Lines are sorted by time,
+// lines with the same time keep their order. Offset tag is applied to all timestamps.
+func Parse(r io.Reader) (*File, error) {
+	file := &File{
+		Tags:  make([]Tag, 0),
+		Lines: make([]Line, 0),
+	}
+
+	var offset time.Duration
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		text := strings.TrimSpace(scanner.Text())
+		if n == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		if text == "" {
+			continue
+		}
+
+		if tag, ok := parseIDTag(text); ok {
+			if tag.Key != TagOffset {
+				file.Tags = append(file.Tags, tag)
+				continue
+			}
+
+			ms, err := strconv.Atoi(strings.TrimPrefix(tag.Value, "+"))
+			if err != nil {
+				return nil, fmt.Errorf("%w %d: %s", ErrSyntax, n, text)
+			}
+			offset = time.Duration(ms) * time.Millisecond
+			continue
+		}
+
+		lines, err := parseLine(text)
+		if err != nil {
+			return nil, fmt.Errorf("%w %d: %s", ErrSyntax, n, text)
+		}
+
+		file.Lines = append(file.Lines, lines...)
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range file.Lines {
+		file.Lines[i].shift(-offset)
+	}
+
+	slices.SortStableFunc(file.Lines, func(a, b Line) int {
+		return cmp.Compare(a.Time, b.Time)
+	})
+
+	return file, nil
+}
+
+// Returns tag of `[key:value]` line, key must start with a letter.
+func parseIDTag(text string) (Tag, bool) {
+	if !strings.HasPrefix(text, "[") || !strings.HasSuffix(text, "]") {
+		return Tag{}, false
+	}
+
+	key, value, ok := strings.Cut(text[1:len(text)-1], ":")
+	if !ok || key == "" || !isLetter(key[0]) {
+		return Tag{}, false
+	}
+
+	return Tag{Key: strings.ToLower(strings.TrimSpace(key)), Value: strings.TrimSpace(value)}, true
+}
+
+// Parses `[time][time]text`, text may contain word timestamps `<time>word`.
+func parseLine(text string) ([]Line, error) {
+	times := make([]time.Duration, 0, 1)
+
+	for strings.HasPrefix(text, "[") {
+		end := strings.IndexByte(text, ']')
+		if end == -1 {
+			return nil, ErrSyntax
+		}
+
+		t, err := parseTime(text[1:end])
+		if err != nil {
+			return nil, err
+		}
+
+		times = append(times, t)
+		text = text[end+1:]
+	}
+
+	if len(times) == 0 {
+		return nil, ErrSyntax
+	}
+
+	line, err := parseWords(strings.TrimSpace(text))
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]Line, 0, len(times))
+	for _, t := range times {
+		// words are timed absolutely, so repeated line keeps words only at its first time
+		repeated := line
+		if t != times[0] {
+			repeated.Words, repeated.End = nil, 0
+		}
+		repeated.Time = t
+		lines = append(lines, repeated)
+	}
+
+	return lines, nil
+}
+
+// Splits enhanced LRC text to timed words, timestamp without a word is end of the line.
+func parseWords(text string) (Line, error) {
+	if !strings.Contains(text, "<") {
+		return Line{Text: text}, nil
+	}
+
+	line := Line{
+		Words: make([]Word, 0),
+	}
+	texts := make([]string, 0)
+
+	for text != "" {
+		if !strings.HasPrefix(text, "<") {
+			return Line{}, ErrSyntax
+		}
+
+		end := strings.IndexByte(text, '>')
+		if end == -1 {
+			return Line{}, ErrSyntax
+		}
+
+		t, err := parseTime(text[1:end])
+		if err != nil {
+			return Line{}, err
+		}
+
+		text = text[end+1:]
+
+		next := strings.IndexByte(text, '<')
+		if next == -1 {
+			next = len(text)
+		}
+
+		word := strings.TrimSpace(text[:next])
+		text = text[next:]
+
+		if word == "" {
+			line.End = t
+			continue
+		}
+
+		line.Words = append(line.Words, Word{Time: t, Text: word})
+		texts = append(texts, word)
+	}
+
+	line.Text = strings.Join(texts, " ")
+
+	return line, nil
+}
+
+// Parses mm:ss, mm:ss.xx or mm:ss.xxx, minutes are not limited.
+func parseTime(text string) (time.Duration, error) {
+	minutes, rest, ok := strings.Cut(strings.TrimSpace(text), ":")
+	if !ok {
+		return 0, ErrSyntax
+	}
+
+	seconds, fraction, _ := strings.Cut(rest, ".")
+
+	m, err := strconv.Atoi(minutes)
+	if err != nil || m < 0 {
+		return 0, ErrSyntax
+	}
+
+	s, err := strconv.Atoi(seconds)
+	if err != nil || s < 0 || s > 59 || len(seconds) != 2 {
+		return 0, ErrSyntax
+	}
+
+	var ms int
+	if fraction != "" {
+		if len(fraction) > 3 {
+			return 0, ErrSyntax
+		}
+
+		ms, err = strconv.Atoi(fraction)
+		if err != nil || ms < 0 {
+			return 0, ErrSyntax
+		}
+
+		for i := len(fraction); i < 3; i++ {
+			ms *= 10
+		}
+	}
+
+	return time.Duration(m)*time.Minute + time.Duration(s)*time.Second + time.Duration(ms)*time.Millisecond, nil
+}
+
+func (l *Line) shift(d time.Duration) {
+	l.Time = max(0, l.Time+d)
+	if l.End != 0 {
+		l.End = max(0, l.End+d)
+	}
+	for i := range l.Words {
+		l.Words[i].Time = max(0, l.Words[i].Time+d)
+	}
+}
+
+func isLetter(b byte) bool {
+	return 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z'
+}
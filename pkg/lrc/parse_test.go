@@ -0,0 +1,70 @@
+package lrc
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	input := "\ufeff[ar:Queen]\n[offset:+500]\n\n[00:17.35]<00:17.35>Is <00:17.80>this<00:21.00>\n[00:12.00][01:00.5]Is this the real life?\n"
+
+	file, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	if file.Tag(TagArtist) != "Queen" || file.Tag(TagOffset) != "" {
+		t.Errorf("tags %+v, want only artist Queen", file.Tags)
+	}
+
+	want := []Line{
+		{Time: 11500 * time.Millisecond, Text: "Is this the real life?"},
+		{
+			Time:  16850 * time.Millisecond,
+			End:   20500 * time.Millisecond,
+			Text:  "Is this",
+			Words: []Word{{16850 * time.Millisecond, "Is"}, {17300 * time.Millisecond, "this"}},
+		},
+		{Time: time.Minute, Text: "Is this the real life?"},
+	}
+
+	if !slices.EqualFunc(file.Lines, want, func(a, b Line) bool {
+		return a.Time == b.Time && a.End == b.End && a.Text == b.Text && slices.Equal(a.Words, b.Words)
+	}) {
+		t.Errorf("lines %+v, want %+v", file.Lines, want)
+	}
+}
+
+func TestParseMalformed(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"NoTimeTag", "Is this the real life?"},
+		{"UnclosedTimeTag", "[00:12.00 Is this the real life?"},
+		{"TimeWithoutColon", "[0012.00]Is this the real life?"},
+		{"MinutesNotNumber", "[ab:12.00]Is this the real life?"},
+		{"NegativeMinutes", "[-1:12.00]Is this the real life?"},
+		{"SecondsOutOfRange", "[00:60.00]Is this the real life?"},
+		{"OneDigitSeconds", "[00:5.00]Is this the real life?"},
+		{"LongFraction", "[00:12.0000]Is this the real life?"},
+		{"FractionNotNumber", "[00:12.xx]Is this the real life?"},
+		{"TextBeforeWordTime", "[00:12.00]Is <00:12.50>this"},
+		{"UnclosedWordTime", "[00:12.00]<00:12.00 Is this"},
+		{"InvalidWordTime", "[00:12.00]<00:99.00>Is this"},
+		{"OffsetNotNumber", "[offset:soon]"},
+		{"MalformedAfterValid", "[00:12.00]Is this the real life?\n[00:17.35"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.input))
+			if !errors.Is(err, ErrSyntax) {
+				t.Errorf("Parse(%q) returned %v, want %v", tt.input, err, ErrSyntax)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package lrc
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Writes tags and lines in the order they are in the file.
+// Words are written as enhanced LRC, End is written only for lines with words.
+func Write(w io.Writer, file *File) error {
+	bw := bufio.NewWriter(w)
+
+	for _, tag := range file.Tags {
+		fmt.Fprintf(bw, "[%s:%s]\n", tag.Key, tag.Value)
+	}
+
+	for _, line := range file.Lines {
+		bw.WriteString("[" + formatTime(line.Time) + "]")
+
+		if len(line.Words) == 0 {
+			bw.WriteString(line.Text + "\n")
+			continue
+		}
+
+		for i, word := range line.Words {
+			if i != 0 {
+				bw.WriteString(" ")
+			}
+			bw.WriteString("<" + formatTime(word.Time) + ">" + word.Text)
+		}
+		if line.End != 0 {
+			bw.WriteString("<" + formatTime(line.End) + ">")
+		}
+		bw.WriteString("\n")
+	}
+
+	return bw.Flush()
+}
+
+// Returns mm:ss.xx, or mm:ss.xxx if the time is not a whole number of hundredths.
+func formatTime(t time.Duration) string {
+	ms := t.Milliseconds()
+	minutes, seconds, ms := ms/60000, ms/1000%60, ms%1000
+
+	if ms%10 == 0 {
+		return fmt.Sprintf("%02d:%02d.%02d", minutes, seconds, ms/10)
+	}
+	return fmt.Sprintf("%02d:%02d.%03d", minutes, seconds, ms)
+}
@@ -0,0 +1,8 @@
+package playlist
+
+import "errors"
+
+var (
+	ErrUnknownFormat = errors.New("unknown playlist format, use m3u, m3u8 or xspf")
+	ErrSyntax        = errors.New("invalid playlist")
+)
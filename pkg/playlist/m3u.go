@@ -0,0 +1,140 @@
+package playlist
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+const (
+	m3uHeader   = "#EXTM3U"
+	m3uInfo     = "#EXTINF:"
+	m3uPlaylist = "#PLAYLIST:"
+)
+
+// Reads M3U or M3U8 playlist, extended directives are optional. Other comments are skipped.
+// Data which is not valid UTF-8 is decoded as Latin-1.
+func ReadM3U(r io.Reader) (*Playlist, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if !utf8.Valid(data) {
+		data, err = charmap.ISO8859_1.NewDecoder().Bytes(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	playlist := &Playlist{
+		Entries: make([]Entry, 0),
+	}
+
+	// info of the next location
+	var info *Entry
+
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if n == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, m3uPlaylist):
+			playlist.Title = strings.TrimSpace(strings.TrimPrefix(line, m3uPlaylist))
+		case strings.HasPrefix(line, m3uInfo):
+			entry, err := parseInfo(strings.TrimPrefix(line, m3uInfo))
+			if err != nil {
+				return nil, fmt.Errorf("%w %d: %s", ErrSyntax, n, line)
+			}
+			info = entry
+		case strings.HasPrefix(line, "#"):
+		default:
+			entry := Entry{Location: line}
+			if info != nil {
+				entry.Artist, entry.Title, entry.Duration = info.Artist, info.Title, info.Duration
+				info = nil
+			}
+			playlist.Entries = append(playlist.Entries, entry)
+		}
+	}
+
+	return playlist, scanner.Err()
+}
+
+// Writes extended M3U, M3U8 is the same but in UTF-8. Entries without location are skipped.
+func WriteM3U(w io.Writer, playlist *Playlist, format string) error {
+	if format == FormatM3U {
+		w = charmap.ISO8859_1.NewEncoder().Writer(w)
+	}
+
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintln(bw, m3uHeader)
+	if playlist.Title != "" {
+		fmt.Fprintln(bw, m3uPlaylist+oneLine(playlist.Title))
+	}
+
+	for _, entry := range playlist.Entries {
+		if entry.Location == "" {
+			continue
+		}
+
+		seconds := int64(-1)
+		if entry.Duration != 0 {
+			seconds = int64(entry.Duration / time.Second)
+		}
+
+		title := entry.Title
+		if entry.Artist != "" {
+			title = entry.Artist + " - " + title
+		}
+
+		fmt.Fprintf(bw, "%s%d,%s\n", m3uInfo, seconds, oneLine(title))
+		fmt.Fprintln(bw, oneLine(entry.Location))
+	}
+
+	return bw.Flush()
+}
+
+// Parses "duration attributes,Artist - Title", attributes like tvg-id="..." are skipped.
+func parseInfo(info string) (*Entry, error) {
+	attributes, title, found := strings.Cut(info, ",")
+	if !found {
+		return nil, ErrSyntax
+	}
+
+	durationStr, _, _ := strings.Cut(strings.TrimSpace(attributes), " ")
+	seconds, err := strconv.ParseFloat(durationStr, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &Entry{
+		Title: strings.TrimSpace(title),
+	}
+
+	if seconds > 0 {
+		entry.Duration = time.Duration(seconds * float64(time.Second))
+	}
+
+	if artist, title, found := strings.Cut(entry.Title, " - "); found {
+		entry.Artist, entry.Title = strings.TrimSpace(artist), strings.TrimSpace(title)
+	}
+
+	return entry, nil
+}
+
+// Line breaks would start a new entry.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
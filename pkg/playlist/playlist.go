@@ -0,0 +1,74 @@
+// Package playlist reads and writes playlists in M3U, M3U8 and XSPF formats:
+//
+//	#EXTM3U
+//	#PLAYLIST:Setlist
+//	#EXTINF:-1,Muse - Uprising
+//	https://www.youtube.com/watch?v=w8KQmps-Sog
+package playlist
+
+import (
+	"io"
+	"strings"
+	"time"
+)
+
+// Formats of playlists. M3U is written in Latin-1, M3U8 and XSPF in UTF-8.
+const (
+	FormatM3U  = "m3u"
+	FormatM3U8 = "m3u8"
+	FormatXSPF = "xspf"
+)
+
+// Content types of the formats.
+var ContentTypes = map[string]string{
+	FormatM3U:  "audio/x-mpegurl",
+	FormatM3U8: "audio/x-mpegurl; charset=utf-8",
+	FormatXSPF: "application/xspf+xml",
+}
+
+type Playlist struct {
+	Title   string
+	Entries []Entry
+}
+
+// Location is URL of the track, entries of M3U always have it. Identifier is a URI which identifies the track,
+// like urn:uuid:..., only XSPF keeps it. Duration is zero if it is unknown.
+type Entry struct {
+	Location   string
+	Identifier string
+	Artist     string
+	Title      string
+	Duration   time.Duration
+}
+
+// Reads playlist in the format, M3U and M3U8 are read the same way.
+func Read(r io.Reader, format string) (*Playlist, error) {
+	switch format {
+	case FormatM3U, FormatM3U8:
+		return ReadM3U(r)
+	case FormatXSPF:
+		return ReadXSPF(r)
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+func Write(w io.Writer, playlist *Playlist, format string) error {
+	switch format {
+	case FormatM3U, FormatM3U8:
+		return WriteM3U(w, playlist, format)
+	case FormatXSPF:
+		return WriteXSPF(w, playlist)
+	default:
+		return ErrUnknownFormat
+	}
+}
+
+// Detects format by content: XSPF is XML, anything else is read as M3U8.
+func Detect(data []byte) string {
+	trimmed := strings.TrimSpace(strings.TrimPrefix(string(data[:min(len(data), 512)]), "\ufeff"))
+	if strings.HasPrefix(trimmed, "<") {
+		return FormatXSPF
+	}
+	return FormatM3U8
+}
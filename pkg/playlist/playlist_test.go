@@ -0,0 +1,55 @@
+package playlist
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadM3U(t *testing.T) {
+	input := "\ufeff#EXTM3U\n#PLAYLIST:Setlist\n#EXTINF:-1 tvg-id=\"1\",Muse - Uprising\nhttps://example.com/uprising\n\n# comment\n#EXTINF:354.5,Bohemian Rhapsody\nhttps://example.com/rhapsody\nhttps://example.com/plain\n"
+
+	playlist, err := ReadM3U(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadM3U: %v", err)
+	}
+
+	want := []Entry{
+		{Location: "https://example.com/uprising", Artist: "Muse", Title: "Uprising"},
+		{Location: "https://example.com/rhapsody", Title: "Bohemian Rhapsody", Duration: 354500 * time.Millisecond},
+		{Location: "https://example.com/plain"},
+	}
+
+	if playlist.Title != "Setlist" || !slices.Equal(playlist.Entries, want) {
+		t.Errorf("ReadM3U returned %+v, want title Setlist and entries %+v", *playlist, want)
+	}
+}
+
+func TestReadMalformed(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		input  string
+	}{
+		{"InfoWithoutTitle", FormatM3U8, "#EXTM3U\n#EXTINF:-1\nhttps://example.com/uprising"},
+		{"DurationNotNumber", FormatM3U8, "#EXTM3U\n#EXTINF:long,Muse - Uprising\nhttps://example.com/uprising"},
+		{"EmptyDuration", FormatM3U, "#EXTINF:,Muse - Uprising\nhttps://example.com/uprising"},
+		{"MalformedAfterValid", FormatM3U8, "#EXTINF:-1,Muse - Uprising\nhttps://example.com/uprising\n#EXTINF:x,Queen"},
+		{"NotXML", FormatXSPF, "#EXTM3U"},
+		{"UnclosedElement", FormatXSPF, `<playlist xmlns="http://xspf.org/ns/0/" version="1"><trackList>`},
+		{"OtherNamespace", FormatXSPF, `<playlist version="1"><trackList></trackList></playlist>`},
+		{"OtherRoot", FormatXSPF, `<rss xmlns="http://xspf.org/ns/0/"></rss>`},
+		{"DurationNotNumber", FormatXSPF, `<playlist xmlns="http://xspf.org/ns/0/" version="1"><trackList><track><duration>long</duration></track></trackList></playlist>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format+tt.name, func(t *testing.T) {
+			_, err := Read(strings.NewReader(tt.input), tt.format)
+			if !errors.Is(err, ErrSyntax) {
+				t.Errorf("Read(%q) returned %v, want %v", tt.input, err, ErrSyntax)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package playlist
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type xspfPlaylist struct {
+	XMLName xml.Name    `xml:"http://xspf.org/ns/0/ playlist"`
+	Version string      `xml:"version,attr"`
+	Title   string      `xml:"title,omitempty"`
+	Tracks  []xspfTrack `xml:"trackList>track"`
+}
+
+// Duration is in milliseconds.
+type xspfTrack struct {
+	Location   string `xml:"location,omitempty"`
+	Identifier string `xml:"identifier,omitempty"`
+	Title      string `xml:"title,omitempty"`
+	Creator    string `xml:"creator,omitempty"`
+	Duration   int64  `xml:"duration,omitempty"`
+}
+
+// Reads XSPF playlist, only the first location and identifier of a track are kept.
+func ReadXSPF(r io.Reader) (*Playlist, error) {
+	doc := &xspfPlaylist{}
+
+	err := xml.NewDecoder(r).Decode(doc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSyntax, err)
+	}
+
+	playlist := &Playlist{
+		Title:   strings.TrimSpace(doc.Title),
+		Entries: make([]Entry, 0, len(doc.Tracks)),
+	}
+
+	for _, track := range doc.Tracks {
+		playlist.Entries = append(playlist.Entries, Entry{
+			Location:   strings.TrimSpace(track.Location),
+			Identifier: strings.TrimSpace(track.Identifier),
+			Artist:     strings.TrimSpace(track.Creator),
+			Title:      strings.TrimSpace(track.Title),
+			Duration:   time.Duration(track.Duration) * time.Millisecond,
+		})
+	}
+
+	return playlist, nil
+}
+
+func WriteXSPF(w io.Writer, playlist *Playlist) error {
+	doc := &xspfPlaylist{
+		Version: "1",
+		Title:   playlist.Title,
+		Tracks:  make([]xspfTrack, 0, len(playlist.Entries)),
+	}
+
+	for _, entry := range playlist.Entries {
+		doc.Tracks = append(doc.Tracks, xspfTrack{
+			Location:   entry.Location,
+			Identifier: entry.Identifier,
+			Title:      entry.Title,
+			Creator:    entry.Artist,
+			Duration:   entry.Duration.Milliseconds(),
+		})
+	}
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	err = enc.Encode(doc)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "\n")
+	return err
+}
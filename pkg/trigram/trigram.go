@@ -0,0 +1,62 @@
+// Package trigram implements trigram similarity the same way as the PostgreSQL pg_trgm extension does.
+package trigram
+
+import (
+	"strings"
+	"unicode"
+)
+
+type set map[string]struct{}
+
+// Returns trigrams of the text: text is lower cased and split into words on non-alphanumeric characters,
+// every word is prefixed with two spaces and suffixed with one space.
+func trigrams(text string) set {
+	result := make(set)
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for _, word := range words {
+		padded := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			result[string(padded[i:i+3])] = struct{}{}
+		}
+	}
+
+	return result
+}
+
+// Equivalent of similarity(a, b): number of shared trigrams divided by number of trigrams in both texts.
+func Similarity(a, b string) float64 {
+	trigramsA, trigramsB := trigrams(a), trigrams(b)
+
+	common := countCommon(trigramsA, trigramsB)
+	total := len(trigramsA) + len(trigramsB) - common
+	if total == 0 {
+		return 0
+	}
+
+	return float64(common) / float64(total)
+}
+
+// Approximation of word_similarity(query, text): part of the query trigrams found in the text,
+// so the query matches a part of a longer text.
+func WordSimilarity(query, text string) float64 {
+	trigramsQuery := trigrams(query)
+	if len(trigramsQuery) == 0 {
+		return 0
+	}
+
+	return float64(countCommon(trigramsQuery, trigrams(text))) / float64(len(trigramsQuery))
+}
+
+func countCommon(a, b set) int {
+	common := 0
+	for trigram := range a {
+		if _, ok := b[trigram]; ok {
+			common++
+		}
+	}
+	return common
+}
@@ -1,5 +1,8 @@
 package web
 
 const (
-	ContentTypeJSON      = "application/json"
+	ContentTypeJSON   = "application/json"
+	ContentTypeText   = "text/plain; charset=utf-8"
+	ContentTypeNDJSON = "application/x-ndjson"
+	ContentTypeCSV    = "text/csv; charset=utf-8"
 )
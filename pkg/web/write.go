@@ -2,6 +2,7 @@ package web
 
 import (
 	"encoding/json"
+	"mime"
 	"net/http"
 
 	logmsg "github.com/qreaqtor/music-library/pkg/logging/message"
@@ -19,6 +20,7 @@ func WriteError(w http.ResponseWriter, msg *logmsg.LogMsg) {
 
 /*
 Выполняет сериализацию data и пишет в w.
+Статус ответа достает из msg.
 В случаае появления ошибки вызывает writeError().
 */
 func WriteData(w http.ResponseWriter, msg *logmsg.LogMsg, data any) {
@@ -29,6 +31,7 @@ func WriteData(w http.ResponseWriter, msg *logmsg.LogMsg, data any) {
 	}
 
 	w.Header().Set("Content-Type", ContentTypeJSON)
+	w.WriteHeader(msg.Status)
 	_, err = w.Write(response)
 	if err != nil {
 		WriteError(w, msg.With(err.Error(), http.StatusInternalServerError))
@@ -37,3 +40,32 @@ func WriteData(w http.ResponseWriter, msg *logmsg.LogMsg, data any) {
 
 	msg.Info()
 }
+
+/*
+Пишет в w только статус ответа из msg, без тела.
+Используется для ответов вроде 204 No Content.
+*/
+func WriteStatus(w http.ResponseWriter, msg *logmsg.LogMsg) {
+	w.WriteHeader(msg.Status)
+	msg.Info()
+}
+
+/*
+Пишет data в w как есть с указанным Content-Type.
+Если filename не пустой, ответ отдается как вложение с этим именем файла.
+*/
+func WriteBytes(w http.ResponseWriter, msg *logmsg.LogMsg, contentType, filename string, data []byte) {
+	w.Header().Set("Content-Type", contentType)
+	if filename != "" {
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	}
+
+	w.WriteHeader(msg.Status)
+	_, err := w.Write(data)
+	if err != nil {
+		WriteError(w, msg.With(err.Error(), http.StatusInternalServerError))
+		return
+	}
+
+	msg.Info()
+}